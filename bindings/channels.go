@@ -404,8 +404,9 @@ func LoadChannelsManagerMobile(cmixID int, storageTag, dbFilePath string,
 	}
 
 	// Construct new channels manager
-	m, err := channels.LoadManager(storageTag, channelsKV, user.api.GetCmix(),
-		user.api.GetRng(), model, extensionBuilders, notif.manager, wrap)
+	m, err := channels.LoadManagerWithScheduledSends(storageTag, channelsKV,
+		user.api.GetCmix(), user.api.GetRng(), model, extensionBuilders,
+		user.api.AddService, notif.manager, wrap)
 	if err != nil {
 		return nil, err
 	}
@@ -541,9 +542,9 @@ func LoadChannelsManager(cmixID int, storageTag string,
 	}
 
 	// Construct new channels manager
-	m, err := channels.LoadManagerBuilder(storageTag, channelsKV,
-		user.api.GetCmix(), user.api.GetRng(), eb, extensionBuilders,
-		notif.manager, wrap)
+	m, err := channels.LoadManagerBuilderWithScheduledSends(storageTag,
+		channelsKV, user.api.GetCmix(), user.api.GetRng(), eb,
+		extensionBuilders, user.api.AddService, notif.manager, wrap)
 	if err != nil {
 		return nil, err
 	}
@@ -669,9 +670,9 @@ func LoadChannelsManagerGoEventModel(cmixID int, storageTag string,
 	}
 
 	// Construct new channels manager
-	m, err := channels.LoadManagerBuilder(storageTag, channelsKV,
-		user.api.GetCmix(), user.api.GetRng(), goEventBuilder,
-		extensionBuilders, notif.manager, wrap)
+	m, err := channels.LoadManagerBuilderWithScheduledSends(storageTag,
+		channelsKV, user.api.GetCmix(), user.api.GetRng(), goEventBuilder,
+		extensionBuilders, user.api.AddService, notif.manager, wrap)
 	if err != nil {
 		return nil, err
 	}
@@ -1666,6 +1667,151 @@ func (cm *ChannelsManager) DeleteMessage(channelIdBytes,
 	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
 }
 
+// SendEdit replaces the text of a message previously sent by this user. Only
+// the original sender of a message may edit it and only text messages can be
+// edited.
+//
+// Clients will drop the edit if they do not recognize the target message or if
+// it was not sent by the same user.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - targetMessageIdBytes - The marshalled [channel.MessageID] of the message
+//     you want to edit.
+//   - newText - The new text of the message.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) SendEdit(channelIdBytes,
+	targetMessageIdBytes []byte, newText string, cmixParamsJSON []byte) (
+	[]byte, error) {
+
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal message ID
+	targetedMessageID := cryptoMessage.ID{}
+	copy(targetedMessageID[:], targetMessageIdBytes)
+
+	// Send message edit
+	messageID, rnd, ephID, err := cm.api.SendEdit(
+		channelID, targetedMessageID, newText, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// SendPoll sends a poll to a channel that members can vote on using SendVote.
+// Each option must be unique and there must be between 2 and 16 options.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - question - The question asked by the poll.
+//   - optionsJSON - JSON of a slice of the options that can be voted on.
+//   - validUntilMS - The lease of the message. This will be how long the
+//     message is available from the network, in milliseconds. Use
+//     [channels.ValidForever] to last the max message life.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be
+//     empty, and [GetDefaultCMixParams] will be used internally.
+//
+// Example optionsJSON:
+//
+//	[
+//	  "Yes",
+//	  "No",
+//	  "Maybe"
+//	]
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) SendPoll(channelIdBytes []byte, question string,
+	optionsJSON []byte, validUntilMS int64, cmixParamsJSON []byte) (
+	[]byte, error) {
+
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal options
+	var options []string
+	if err = json.Unmarshal(optionsJSON, &options); err != nil {
+		return nil, errors.Errorf(
+			"failed to JSON unmarshal poll options: %+v", err)
+	}
+
+	// Calculate lease
+	lease := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		lease = channels.ValidForever
+	}
+
+	// Send poll
+	messageID, rnd, ephID, err := cm.api.SendPoll(
+		channelID, question, options, lease, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// SendVote votes for an option of a poll. Only the most recent vote from each
+// user is counted, so a vote can be changed by voting again.
+//
+// Clients will drop the vote if they do not recognize the poll or if the poll
+// was closed before the vote was sent.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - pollMessageIdBytes - The marshalled [channel.MessageID] of the poll.
+//   - option - The index of the chosen option in the poll.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) SendVote(channelIdBytes, pollMessageIdBytes []byte,
+	option int, cmixParamsJSON []byte) ([]byte, error) {
+
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if option < 0 {
+		return nil, errors.Errorf("poll option %d cannot be negative", option)
+	}
+
+	// Unmarshal message ID
+	pollID := cryptoMessage.ID{}
+	copy(pollID[:], pollMessageIdBytes)
+
+	// Send vote
+	messageID, rnd, ephID, err :=
+		cm.api.SendVote(channelID, pollID, uint32(option), params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
 // PinMessage pins the target message to the top of a channel view for all users
 // in the specified channel. Only the channel admin can pin user messages; if
 // the user is not an admin of the channel, then the error
@@ -1772,6 +1918,216 @@ func (cm *ChannelsManager) MuteUser(channelIdBytes, mutedUserPubKeyBytes []byte,
 	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
 }
 
+// ClosePoll closes a poll so that votes sent at or after the closing time are
+// no longer counted. Only the channel admin can close a poll; if the user is
+// not an admin of the channel, then the error [channels.NotAnAdminErr] is
+// returned.
+//
+// Closing a poll again replaces the previous closing time, so a poll can be
+// reopened by closing it at a time in the future.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - pollMessageIdBytes - The marshalled [channel.MessageID] of the poll.
+//   - closingTime - The time after which votes are no longer counted;
+//     represented as nanoseconds since unix epoch.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) ClosePoll(channelIdBytes, pollMessageIdBytes []byte,
+	closingTime int64, cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal message ID
+	pollID := cryptoMessage.ID{}
+	copy(pollID[:], pollMessageIdBytes)
+
+	// Send message to close poll
+	messageID, rnd, ephID, err := cm.api.ClosePoll(
+		channelID, pollID, time.Unix(0, closingTime), params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// GrantModerator grants the user permission to perform the given actions in
+// the channel on behalf of the admin. Granting permissions to an existing
+// moderator replaces their previous grant. Only the channel admin can grant
+// moderator permissions; if the user is not an admin of the channel, then the
+// error [channels.NotAnAdminErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - moderatorPubKeyBytes - The [ed25519.PublicKey] of the user you want to
+//     make a moderator.
+//   - permissions - The bit flags of the [channels.ModeratorPermission]
+//     granted. Delete is 1, pin is 2, and mute is 4.
+//   - validUntilMS - The time, in milliseconds, that the grant is valid. To
+//     grant indefinitely, use [ValidForever].
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) GrantModerator(channelIdBytes,
+	moderatorPubKeyBytes []byte, permissions int, validUntilMS int,
+	cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal Ed25519 public key
+	if len(moderatorPubKeyBytes) != ed25519.PublicKeySize {
+		return nil, errors.Errorf(
+			"user ED25519 public key must be %d bytes, received %d bytes",
+			ed25519.PublicKeySize, len(moderatorPubKeyBytes))
+	}
+
+	// Calculate lease
+	validUntil := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		validUntil = channels.ValidForever
+	}
+
+	// Send message to grant moderator permissions
+	messageID, rnd, ephID, err := cm.api.GrantModerator(channelID,
+		moderatorPubKeyBytes, channels.ModeratorPermission(permissions),
+		validUntil, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// RevokeModerator revokes all moderator permissions of the user in the
+// channel. Only the channel admin can revoke moderator permissions; if the
+// user is not an admin of the channel, then the error [channels.NotAnAdminErr]
+// is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - moderatorPubKeyBytes - The [ed25519.PublicKey] of the moderator.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) RevokeModerator(channelIdBytes,
+	moderatorPubKeyBytes, cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal Ed25519 public key
+	if len(moderatorPubKeyBytes) != ed25519.PublicKeySize {
+		return nil, errors.Errorf(
+			"user ED25519 public key must be %d bytes, received %d bytes",
+			ed25519.PublicKeySize, len(moderatorPubKeyBytes))
+	}
+
+	// Send message to revoke moderator permissions
+	messageID, rnd, ephID, err := cm.api.RevokeModerator(
+		channelID, moderatorPubKeyBytes, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// SetSlowMode puts the channel in slow mode, limiting each user to one post
+// per interval. Posts sent faster are hidden by every client. Moderators and
+// messages sent as the admin are not limited. Setting slow mode replaces any
+// previous slow mode in the channel. Only the channel admin can set slow mode;
+// if the user is not an admin of the channel, then the error
+// [channels.NotAnAdminErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - intervalMS - The minimum time, in milliseconds, between posts from each
+//     user.
+//   - validUntilMS - The time, in milliseconds, that slow mode is active. To
+//     never expire, use [ValidForever].
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) SetSlowMode(channelIdBytes []byte, intervalMS,
+	validUntilMS int, cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate lease
+	validUntil := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		validUntil = channels.ValidForever
+	}
+
+	// Send message to set slow mode
+	messageID, rnd, ephID, err := cm.api.SetSlowMode(channelID,
+		time.Duration(intervalMS)*time.Millisecond, validUntil, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
+// DisableSlowMode turns off slow mode in the channel. Only the channel admin
+// can disable slow mode; if the user is not an admin of the channel, then the
+// error [channels.NotAnAdminErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of channel [id.ID].
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. This may be empty, and
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [ChannelSendReport].
+func (cm *ChannelsManager) DisableSlowMode(
+	channelIdBytes, cmixParamsJSON []byte) ([]byte, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send message to disable slow mode
+	messageID, rnd, ephID, err := cm.api.DisableSlowMode(channelID, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructChannelSendReport(&messageID, rnd.ID, &ephID)
+}
+
 // parseChannelsParameters is a helper function for the Send functions. It
 // parses the channel ID and the passed in parameters into their respective
 // objects. These objects are passed into the API via the internal send
@@ -1816,6 +2172,181 @@ func constructChannelSendReport(messageID *cryptoMessage.ID,
 	return json.Marshal(chanSendReport)
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// Scheduled Sending                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// ScheduleSend schedules a raw message to be sent over a channel at the given
+// time using [ChannelsManager.SendGeneric]. Scheduled sends are saved to
+// storage and survive restarts; sends that become due while the client is
+// offline are sent once it restarts.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - messageType - The message type of the message. This will be a valid
+//     [channels.MessageType].
+//   - message - The contents of the message.
+//   - validUntilMS - The lease of the message, in milliseconds, starting when
+//     it is sent. Use [ValidForeverBindings] to last the max message life.
+//   - tracked - Set tracked to true if the message should be tracked in the
+//     sendTracker once it is sent.
+//   - sendTime - The time the message will be sent; represented as
+//     nanoseconds since unix epoch.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and [GetDefaultCMixParams] will be used internally.
+//   - pingsMapJSON - JSON of a map of slices of [ed25519.PublicKey] of users
+//     that should receive mobile notifications for the message. See
+//     [ChannelsManager.SendGeneric] for an example.
+//
+// Returns:
+//   - int64 - The ID of the scheduled send.
+func (cm *ChannelsManager) ScheduleSend(channelIdBytes []byte, messageType int,
+	message []byte, validUntilMS int64, tracked bool, sendTime int64,
+	cmixParamsJSON []byte, pingsMapJSON []byte) (int64, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return 0, err
+	}
+
+	// Calculate lease
+	lease := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		lease = channels.ValidForever
+	}
+
+	pingsMap, err := unmarshalPingsMapJson(pingsMapJSON)
+	if err != nil {
+		return 0, err
+	}
+
+	sendID, err := cm.api.ScheduleSend(channelID,
+		channels.MessageType(messageType), message, lease, tracked,
+		time.Unix(0, sendTime), params.CMIX, pingsMap)
+	return int64(sendID), err
+}
+
+// ScheduleAdminSend schedules a raw message to be sent over a channel as the
+// channel admin at the given time using [ChannelsManager.SendAdminGeneric].
+//
+// If the user is not an admin of the channel, then the error
+// [channels.NotAnAdminErr] is returned.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID].
+//   - messageType - The message type of the message. This will be a valid
+//     [channels.MessageType].
+//   - message - The contents of the message. The message should be at most 510
+//     bytes.
+//   - validUntilMS - The lease of the message, in milliseconds, starting when
+//     it is sent. Use [ValidForeverBindings] to last the max message life.
+//   - tracked - Set tracked to true if the message should be tracked in the
+//     sendTracker once it is sent.
+//   - sendTime - The time the message will be sent; represented as
+//     nanoseconds since unix epoch.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - int64 - The ID of the scheduled send.
+func (cm *ChannelsManager) ScheduleAdminSend(channelIdBytes []byte,
+	messageType int, message []byte, validUntilMS int64, tracked bool,
+	sendTime int64, cmixParamsJSON []byte) (int64, error) {
+	// Unmarshal channel ID and parameters
+	channelID, params, err :=
+		parseChannelsParameters(channelIdBytes, cmixParamsJSON)
+	if err != nil {
+		return 0, err
+	}
+
+	// Calculate lease
+	lease := time.Duration(validUntilMS) * time.Millisecond
+	if validUntilMS == ValidForeverBindings {
+		lease = channels.ValidForever
+	}
+
+	sendID, err := cm.api.ScheduleAdminSend(channelID,
+		channels.MessageType(messageType), message, lease, tracked,
+		time.Unix(0, sendTime), params.CMIX)
+	return int64(sendID), err
+}
+
+// GetScheduledSends returns the pending and failed scheduled sends to the
+// channel sorted by send time.
+//
+// Parameters:
+//   - channelIdBytes - Marshalled bytes of the channel's [id.ID]. If empty,
+//     the sends for all channels are returned.
+//
+// Returns:
+//   - []byte - JSON of an array of [channels.ScheduledSend].
+func (cm *ChannelsManager) GetScheduledSends(
+	channelIdBytes []byte) ([]byte, error) {
+	var channelID *id.ID
+	if len(channelIdBytes) != 0 {
+		var err error
+		channelID, err = id.Unmarshal(channelIdBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(cm.api.GetScheduledSends(channelID))
+}
+
+// CancelScheduledSend cancels the pending or failed scheduled send with the
+// given ID. Returns [channels.ScheduledSendNotFoundErr] if the send does not
+// exist, is being sent, or was already sent.
+//
+// Parameters:
+//   - sendID - The ID of the scheduled send.
+func (cm *ChannelsManager) CancelScheduledSend(sendID int64) error {
+	return cm.api.CancelScheduledSend(uint64(sendID))
+}
+
+// RescheduleSend changes the send time of the pending or failed scheduled send
+// with the given ID. A failed send is sent again at the new time. Returns
+// [channels.ScheduledSendNotFoundErr] if the send does not exist, is being
+// sent, or was already sent.
+//
+// Parameters:
+//   - sendID - The ID of the scheduled send.
+//   - sendTime - The new time the message will be sent; represented as
+//     nanoseconds since unix epoch.
+func (cm *ChannelsManager) RescheduleSend(sendID, sendTime int64) error {
+	return cm.api.RescheduleSend(uint64(sendID), time.Unix(0, sendTime))
+}
+
+// ScheduledSendFailedCallback is called when a scheduled send fails.
+//
+// Parameters:
+//   - scheduledSendJSON - JSON of the failed [channels.ScheduledSend].
+//   - err - The error returned by the send.
+type ScheduledSendFailedCallback interface {
+	Callback(scheduledSendJSON []byte, err error)
+}
+
+// RegisterScheduledSendFailedCallback registers the callback that is called
+// when a scheduled send fails. A failed send is kept, marked as failed, until
+// it is cancelled with [ChannelsManager.CancelScheduledSend] or sent again
+// with [ChannelsManager.RescheduleSend].
+//
+// Parameters:
+//   - cb - The [ScheduledSendFailedCallback] called on failure.
+func (cm *ChannelsManager) RegisterScheduledSendFailedCallback(
+	cb ScheduledSendFailedCallback) {
+	cm.api.RegisterScheduledSendFailedCallback(
+		func(ss channels.ScheduledSend, err error) {
+			ssJSON, jsonErr := json.Marshal(ss)
+			if jsonErr != nil {
+				jww.ERROR.Printf("[CH] Failed to marshal scheduled send %d: "+
+					"%+v", ss.ID, jsonErr)
+			}
+			cb.Callback(ssJSON, err)
+		})
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Other Channel Actions                                                      //
 ////////////////////////////////////////////////////////////////////////////////
@@ -1946,6 +2477,135 @@ func (cm *ChannelsManager) GetMutedUsers(channelIDBytes []byte) ([]byte, error)
 	return json.Marshal(cm.api.GetMutedUsers(channelID))
 }
 
+// GetModerators returns the moderators of the channel and the permissions
+// granted to them. Expired grants are not included. If there are no moderators
+// or if the channel does not exist, an empty list is returned.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//
+// Returns:
+//   - []byte - JSON of an array of [channels.ModeratorGrant]. Look below for
+//     an example.
+//
+// Example return:
+//
+//	[{"pubKey":"k2IrybDXjJtqxjS6Tx/6m3bXvT/4zFYOJnACNWTvESE=","permissions":3,"expiry":"0001-01-01T00:00:00Z"},{"pubKey":"ocELv7KyeCskLz4cm0klLWhmFLYvQL2FMDco79GTXYw=","permissions":4,"expiry":"2023-06-01T12:00:00Z"}]
+func (cm *ChannelsManager) GetModerators(channelIDBytes []byte) ([]byte, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(cm.api.GetModerators(channelID))
+}
+
+// GetSlowMode returns the minimum time between posts from each user in the
+// channel.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//
+// Returns:
+//   - int - The slow mode interval, in milliseconds. Zero is returned if the
+//     channel is not in slow mode.
+func (cm *ChannelsManager) GetSlowMode(channelIDBytes []byte) (int, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(cm.api.GetSlowMode(channelID).Milliseconds()), nil
+}
+
+// GetMembers returns every user that has been seen sending messages in the
+// channel, sorted from most to least recently seen. Changes to members are
+// reported via [ChannelUICallbacks] with the event type [MemberUpdate].
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//
+// Returns:
+//   - []byte - JSON of an array of [channels.Member]. Look below for an
+//     example.
+//
+// Example return:
+//
+//	[{"pubKey":"hClzdWkMI+LM7KDFxC/iuyIc0oiMzcBXBFgH0haZAjc=","nickname":"billNyeTheScienceGuy","codesetVersion":0,"dmToken":1234567,"firstSeen":"2023-05-01T12:00:00Z","lastSeen":"2023-05-03T09:30:00Z","muted":false}]
+func (cm *ChannelsManager) GetMembers(channelIDBytes []byte) ([]byte, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := cm.api.GetMembers(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(members)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Read Markers                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+// MarkRead marks all messages in the channel with a timestamp at or before the
+// given timestamp as read. The read marker is synced to all the user's devices
+// and only ever moves forward. Changes are reported via [ChannelUICallbacks]
+// with the event type [ReadMarkerUpdate].
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//   - timestamp - The timestamp of the last read message; represented as
+//     nanoseconds since unix epoch.
+func (cm *ChannelsManager) MarkRead(channelIDBytes []byte, timestamp int64) error {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return err
+	}
+
+	return cm.api.MarkRead(channelID, time.Unix(0, timestamp))
+}
+
+// GetReadMarker returns the timestamp of the last read message in the channel.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+//
+// Returns:
+//   - int64 - The timestamp of the last read message; represented as
+//     nanoseconds since unix epoch. Zero is returned if no messages in the
+//     channel have been marked read.
+func (cm *ChannelsManager) GetReadMarker(channelIDBytes []byte) (int64, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	lastRead, exists := cm.api.GetReadMarker(channelID)
+	if !exists {
+		return 0, nil
+	}
+	return lastRead.UnixNano(), nil
+}
+
+// GetUnreadCount returns the number of messages in the channel received after
+// the read marker. Returns an error if the event model does not support read
+// markers.
+//
+// Parameters:
+//   - channelIDBytes - The marshalled bytes of the channel's [id.ID].
+func (cm *ChannelsManager) GetUnreadCount(channelIDBytes []byte) (int64, error) {
+	channelID, err := id.Unmarshal(channelIDBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := cm.api.GetUnreadCount(channelID)
+	return int64(count), err
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Notifications                                                              //
 ////////////////////////////////////////////////////////////////////////////////
@@ -2335,6 +2995,81 @@ func (cm *ChannelsManager) RegisterReceiveHandler(messageType int,
 			name, cb, userSpace, adminSpace, mutedSpace))
 }
 
+// ChannelMessageFilter is run on every message received from another user in a
+// channel before it is passed to the event model.
+//
+// Filter is passed the JSON of [channels.FilterMessage] and must return the
+// JSON of [channels.FilterResult]. The action in the result is one of:
+//   - 0 - Accept the message.
+//   - 1 - Pass the message to the event model as hidden.
+//   - 2 - Drop the message.
+//
+// Example FilterMessage JSON:
+//
+//	{
+//	  "channelID": "wkbrpPsVTXz9nWb2t5eX8U0HZfGc4EUCd3f1uS5R5fsD",
+//	  "messageID": "sT3Lq3I4Y1ZQp6vFpN1QmK3Bm1TcJq3P0vS+2p3Qh1k=",
+//	  "messageType": 1,
+//	  "nickname": "",
+//	  "codename": "realisticMarshmallow",
+//	  "pubKey": "UF6rT2MROBaT1TeiLQS62UcfZuuAoq4JIQTBB5u7D5M=",
+//	  "content": "CgVoZWxsbw==",
+//	  "text": "hello",
+//	  "timestamp": "2023-05-09T14:12:41.0981232-07:00"
+//	}
+//
+// Example FilterResult JSON:
+//
+//	{"action":1,"score":5}
+//
+// If the returned JSON cannot be parsed, then the message is accepted.
+type ChannelMessageFilter interface {
+	Filter(filterMessageJSON []byte) (filterResultJSON []byte)
+}
+
+// RegisterMessageFilter adds a filter to the end of the filter chain run on
+// every message received from another user before it is passed to the event
+// model. Each filter can drop, hide, or score the message. The total score is
+// passed to the event model if it supports scores.
+//
+// Parameters:
+//   - name - A unique name for the filter. Used to remove the filter.
+//   - filter - The [ChannelMessageFilter] to run on received messages.
+//
+// Returns:
+//   - [channels.MessageFilterAlreadyRegistered] if a filter with the same name
+//     is already registered.
+func (cm *ChannelsManager) RegisterMessageFilter(
+	name string, filter ChannelMessageFilter) error {
+	return cm.api.RegisterMessageFilter(name,
+		func(msg channels.FilterMessage) channels.FilterResult {
+			var fr channels.FilterResult
+			msgJSON, err := json.Marshal(msg)
+			if err != nil {
+				jww.ERROR.Printf("[CH] Failed to JSON marshal message %s for "+
+					"filter %q: %+v", msg.MessageID, name, err)
+				return fr
+			}
+
+			err = json.Unmarshal(filter.Filter(msgJSON), &fr)
+			if err != nil {
+				jww.ERROR.Printf("[CH] Failed to JSON unmarshal result of "+
+					"filter %q: %+v", name, err)
+				return channels.FilterResult{}
+			}
+			return fr
+		})
+}
+
+// RemoveMessageFilter removes the filter with the given name from the filter
+// chain. Does nothing if no filter with the name is registered.
+//
+// Parameters:
+//   - name - The name the filter was registered with.
+func (cm *ChannelsManager) RemoveMessageFilter(name string) {
+	cm.api.RemoveMessageFilter(name)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Event Model Logic                                                          //
 ////////////////////////////////////////////////////////////////////////////////
@@ -2570,6 +3305,73 @@ type EventModel interface {
 	MuteUser(channelID, pubkey []byte, unmute bool)
 }
 
+// EditEventModel is an optional extension of EventModel. If the EventModel
+// passed to the channel manager also implements EditEventModel, then edits are
+// passed to EditMessage. Otherwise, received edits are dropped and sending an
+// edit returns an error.
+type EditEventModel interface {
+	// EditMessage is called whenever the original sender of a message replaces
+	// its text. The previous text should be kept so that the UI can mark the
+	// message as edited and show earlier versions. Edits may arrive out of
+	// order; the text of the edit with the latest timestamp must be displayed.
+	//
+	// Parameters:
+	//  - messageID - The bytes of the [channel.MessageID] of the edited
+	//    message.
+	//  - editMessageID - The bytes of the [channel.MessageID] of the edit.
+	//  - text - The new text of the message.
+	//  - timestamp - Time the edit was sent; represented as nanoseconds since
+	//    unix epoch.
+	//
+	// Returns:
+	//  - int64 - The UUID of the edited message.
+	//  - Returns an error if the message cannot be edited. It must return the
+	//	  error from GetNoMessageErr if the message does not exist.
+	EditMessage(messageID, editMessageID []byte, text string,
+		timestamp int64) (int64, error)
+}
+
+// PollEventModel is an optional extension of EventModel. If the EventModel
+// passed to the channel manager also implements PollEventModel, then votes and
+// closed polls are passed to ReceiveVote and ClosePoll. Otherwise, they are
+// dropped and sending a poll, vote, or poll closure returns an error.
+type PollEventModel interface {
+	// ReceiveVote is called whenever a vote for a poll is received. Only the
+	// most recent vote from each user (by timestamp) is counted. Votes with a
+	// timestamp at or after the closing time of the poll are not counted.
+	//
+	// Parameters:
+	//  - pollID - The bytes of the [channel.MessageID] of the poll.
+	//  - voteID - The bytes of the [channel.MessageID] of the vote.
+	//  - pubKey - The voter's Ed25519 public key.
+	//  - option - The index of the chosen option in the poll.
+	//  - timestamp - Time the vote was sent; represented as nanoseconds since
+	//    unix epoch.
+	//
+	// Returns:
+	//  - int64 - The UUID of the poll.
+	//  - Returns an error if the vote cannot be saved. It must return the
+	//	  error from GetNoMessageErr if the poll does not exist.
+	ReceiveVote(pollID, voteID, pubKey []byte, option int,
+		timestamp int64) (int64, error)
+
+	// ClosePoll is called whenever the channel admin closes a poll. Votes with
+	// a timestamp at or after the closing time are not counted, including
+	// those received before the poll was closed. The closing time replaces any
+	// previously set closing time.
+	//
+	// Parameters:
+	//  - pollID - The bytes of the [channel.MessageID] of the poll.
+	//  - closingTime - The time after which votes are not counted;
+	//    represented as nanoseconds since unix epoch.
+	//
+	// Returns:
+	//  - int64 - The UUID of the poll.
+	//  - Returns an error if the poll cannot be closed. It must return the
+	//	  error from GetNoMessageErr if the poll does not exist.
+	ClosePoll(pollID []byte, closingTime int64) (int64, error)
+}
+
 // GetNoMessageErr returns the error channels.NoMessageErr, which must be
 // returned by EventModel methods (such as EventModel.UpdateFromUUID,
 // EventModel.UpdateFromMessageID, and EventModel.GetMessage) when the message
@@ -2623,9 +3425,33 @@ type toEventModel struct {
 }
 
 // newEventModel is a constructor for a toEventModel. This will take in an
-// EventModel and wraps it around the toEventModel.
+// EventModel and wraps it around the toEventModel. If em also implements
+// EditEventModel or PollEventModel, then the returned model implements
+// channels.EditEventModel or channels.PollEventModel, respectively.
 func newEventModel(em EventModel) channels.EventModel {
-	return &toEventModel{em: em}
+	tem := &toEventModel{em: em}
+	eem, canEdit := em.(EditEventModel)
+	pem, canPoll := em.(PollEventModel)
+	switch {
+	case canEdit && canPoll:
+		return &struct {
+			*toEventModel
+			*toEditEventModel
+			*toPollEventModel
+		}{tem, &toEditEventModel{em: eem}, &toPollEventModel{em: pem}}
+	case canEdit:
+		return &struct {
+			*toEventModel
+			*toEditEventModel
+		}{tem, &toEditEventModel{em: eem}}
+	case canPoll:
+		return &struct {
+			*toEventModel
+			*toPollEventModel
+		}{tem, &toPollEventModel{em: pem}}
+	default:
+		return tem
+	}
 }
 
 // JoinChannel is called whenever a channel is joined locally.
@@ -2857,6 +3683,53 @@ func (tem *toEventModel) MuteUser(
 	tem.em.MuteUser(channelID.Marshal(), pubKey, unmute)
 }
 
+// toEditEventModel is a wrapper which wraps an EditEventModel so that it
+// implements channels.EditEventModel.
+type toEditEventModel struct {
+	em EditEventModel
+}
+
+// EditMessage is called whenever the original sender of a message replaces its
+// text.
+//
+// Returns an error if the message cannot be edited. It must return the error
+// from GetNoMessageErr if the message does not exist.
+func (tem *toEditEventModel) EditMessage(
+	messageID, editMessageID cryptoMessage.ID, text string,
+	timestamp time.Time) (uint64, error) {
+	uuid, err := tem.em.EditMessage(messageID.Marshal(),
+		editMessageID.Marshal(), text, timestamp.UnixNano())
+	return uint64(uuid), err
+}
+
+// toPollEventModel is a wrapper which wraps a PollEventModel so that it
+// implements channels.PollEventModel.
+type toPollEventModel struct {
+	em PollEventModel
+}
+
+// ReceiveVote is called whenever a vote for a poll is received.
+//
+// Returns an error if the vote cannot be saved. It must return the error from
+// GetNoMessageErr if the poll does not exist.
+func (tem *toPollEventModel) ReceiveVote(pollID, voteID cryptoMessage.ID,
+	pubKey ed25519.PublicKey, option uint32, timestamp time.Time) (
+	uint64, error) {
+	uuid, err := tem.em.ReceiveVote(pollID.Marshal(), voteID.Marshal(), pubKey,
+		int(option), timestamp.UnixNano())
+	return uint64(uuid), err
+}
+
+// ClosePoll is called whenever the channel admin closes a poll.
+//
+// Returns an error if the poll cannot be closed. It must return the error from
+// GetNoMessageErr if the poll does not exist.
+func (tem *toPollEventModel) ClosePoll(
+	pollID cryptoMessage.ID, closingTime time.Time) (uint64, error) {
+	uuid, err := tem.em.ClosePoll(pollID.Marshal(), closingTime.UnixNano())
+	return uint64(uuid), err
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Extension Builder Tracker                                                  //
 ////////////////////////////////////////////////////////////////////////////////
@@ -10,6 +10,7 @@ package bindings
 import (
 	"crypto/ed25519"
 	"encoding/json"
+	"time"
 
 	jww "github.com/spf13/jwalterweatherman"
 
@@ -54,6 +55,12 @@ const (
 
 	// ChannelUpdate indicates the data is [ChannelUpdateJSON].
 	ChannelUpdate int64 = 8000
+
+	// MemberUpdate indicates the data is [MemberUpdateJSON].
+	MemberUpdate int64 = 9000
+
+	// ReadMarkerUpdate indicates the data is [ReadMarkerUpdateJSON].
+	ReadMarkerUpdate int64 = 10000
 )
 
 // channelUICallbacks is a simple wrapper for [channels.UiCallbacks].
@@ -142,6 +149,22 @@ func (cuiCB *channelUICallbacks) MessageDeleted(messageID message.ID) {
 	})
 }
 
+func (cuiCB *channelUICallbacks) MemberUpdate(
+	channelID *id.ID, member channels.Member) {
+	cuiCB.eventUpdate(MemberUpdate, MemberUpdateJSON{
+		ChannelID: channelID,
+		Member:    member,
+	})
+}
+
+func (cuiCB *channelUICallbacks) ReadMarkerUpdate(
+	channelID *id.ID, lastRead time.Time) {
+	cuiCB.eventUpdate(ReadMarkerUpdate, ReadMarkerUpdateJSON{
+		ChannelID: channelID,
+		LastRead:  lastRead.UnixNano(),
+	})
+}
+
 func unmarshalPingsJson(b []byte) ([]ed25519.PublicKey, error) {
 	var pings []ed25519.PublicKey
 	if b != nil && len(b) > 0 {
@@ -325,3 +348,40 @@ type UserMutedJSON struct {
 type MessageDeletedJSON struct {
 	MessageID message.ID `json:"messageID"`
 }
+
+// MemberUpdateJSON is returned any time a new member is seen in a channel or
+// the nickname, DM token, codeset, or muted state of a member changes.
+//
+// Example JSON:
+//
+//	{
+//	  "channelID":"YSc2bDijXIVhmIsJk2OZQjU9ei2Dn6MS8tOpXlIaUpSV",
+//	  "member":{
+//	    "pubKey":"hClzdWkMI+LM7KDFxC/iuyIc0oiMzcBXBFgH0haZAjc=",
+//	    "nickname":"billNyeTheScienceGuy",
+//	    "codesetVersion":0,
+//	    "dmToken":1234567,
+//	    "firstSeen":"2023-05-01T12:00:00Z",
+//	    "lastSeen":"2023-05-03T09:30:00Z",
+//	    "muted":false
+//	  }
+//	}
+type MemberUpdateJSON struct {
+	ChannelID *id.ID          `json:"channelID"`
+	Member    channels.Member `json:"member"`
+}
+
+// ReadMarkerUpdateJSON is returned any time the read marker of a channel is
+// loaded or advances on this or another of the user's devices. All messages
+// with a timestamp at or before lastRead have been read.
+//
+// Example JSON:
+//
+//	{
+//	  "channelID":"x5lZ2PebbgXodM2hita4P5KOB0uNsEhY//xII/UztSID",
+//	  "lastRead":1683117000000000000
+//	}
+type ReadMarkerUpdateJSON struct {
+	ChannelID *id.ID `json:"channelID"`
+	LastRead  int64  `json:"lastRead"`
+}
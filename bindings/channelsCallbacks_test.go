@@ -10,6 +10,7 @@ import (
 	"gitlab.com/xx_network/primitives/id"
 	"math/rand"
 	"testing"
+	"time"
 )
 
 // Produces example JSON of NickNameUpdateJSON to be used for documentation.
@@ -239,3 +240,48 @@ func Test_BuildJSON_MessageDeletedJSON(t *testing.T) {
 		fmt.Printf("//  %s\n", data)
 	}
 }
+
+// Produces example JSON of MemberUpdateJSON to be used for documentation.
+func Test_BuildJSON_MemberUpdateJSON(t *testing.T) {
+	rng := rand.New(rand.NewSource(72928915))
+
+	channelID, _ := id.NewRandomID(rng, id.User)
+	pubKey, _, _ := ed25519.GenerateKey(rng)
+
+	jsonable := MemberUpdateJSON{
+		ChannelID: channelID,
+		Member: channels.Member{
+			PubKey:    pubKey,
+			Nickname:  "billNyeTheScienceGuy",
+			DmToken:   1234567,
+			FirstSeen: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+			LastSeen:  time.Date(2023, 5, 3, 9, 30, 0, 0, time.UTC),
+		},
+	}
+
+	data, err := json.MarshalIndent(jsonable, "//  ", "  ")
+	if err != nil {
+		t.Errorf("Failed to JSON %T: %+v", jsonable, err)
+	} else {
+		fmt.Printf("//  %s\n", data)
+	}
+}
+
+// Produces example JSON of ReadMarkerUpdateJSON to be used for documentation.
+func Test_BuildJSON_ReadMarkerUpdateJSON(t *testing.T) {
+	rng := rand.New(rand.NewSource(72928915))
+
+	channelID, _ := id.NewRandomID(rng, id.User)
+
+	jsonable := ReadMarkerUpdateJSON{
+		ChannelID: channelID,
+		LastRead:  time.Date(2023, 5, 3, 12, 30, 0, 0, time.UTC).UnixNano(),
+	}
+
+	data, err := json.Marshal(jsonable)
+	if err != nil {
+		t.Errorf("Failed to JSON %T: %+v", jsonable, err)
+	} else {
+		fmt.Printf("//  %s\n", data)
+	}
+}
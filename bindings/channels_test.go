@@ -0,0 +1,72 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package bindings
+
+import (
+	"testing"
+
+	"gitlab.com/elixxir/client/v4/channels"
+)
+
+// Tests that newEventModel returns a channels.EditEventModel only when the
+// EventModel also implements EditEventModel.
+func Test_newEventModel_EditEventModel(t *testing.T) {
+	em := newEventModel(struct{ EventModel }{})
+	if _, ok := em.(channels.EditEventModel); ok {
+		t.Errorf("Event model implements channels.EditEventModel when the " +
+			"EventModel does not implement EditEventModel.")
+	}
+
+	em = newEventModel(struct {
+		EventModel
+		EditEventModel
+	}{})
+	if _, ok := em.(channels.EditEventModel); !ok {
+		t.Errorf("Event model does not implement channels.EditEventModel " +
+			"when the EventModel implements EditEventModel.")
+	}
+}
+
+// Tests that newEventModel returns a channels.PollEventModel only when the
+// EventModel also implements PollEventModel.
+func Test_newEventModel_PollEventModel(t *testing.T) {
+	em := newEventModel(struct {
+		EventModel
+		EditEventModel
+	}{})
+	if _, ok := em.(channels.PollEventModel); ok {
+		t.Errorf("Event model implements channels.PollEventModel when the " +
+			"EventModel does not implement PollEventModel.")
+	}
+
+	em = newEventModel(struct {
+		EventModel
+		PollEventModel
+	}{})
+	if _, ok := em.(channels.PollEventModel); !ok {
+		t.Errorf("Event model does not implement channels.PollEventModel " +
+			"when the EventModel implements PollEventModel.")
+	}
+	if _, ok := em.(channels.EditEventModel); ok {
+		t.Errorf("Event model implements channels.EditEventModel when the " +
+			"EventModel does not implement EditEventModel.")
+	}
+
+	em = newEventModel(struct {
+		EventModel
+		EditEventModel
+		PollEventModel
+	}{})
+	_, canEdit := em.(channels.EditEventModel)
+	_, canPoll := em.(channels.PollEventModel)
+	if !canEdit || !canPoll {
+		t.Errorf("Event model does not implement both "+
+			"channels.EditEventModel (%t) and channels.PollEventModel (%t) "+
+			"when the EventModel implements both.", canEdit, canPoll)
+	}
+}
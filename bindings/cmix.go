@@ -68,14 +68,50 @@ func NewSynchronizedCmix(ndfJSON, storageDir, remoteStoragePathPrefix string,
 	password []byte,
 	remote RemoteStore) error {
 
+	return NewSynchronizedCmixWithPolicy(ndfJSON, storageDir,
+		remoteStoragePathPrefix, password, remote, nil)
+}
+
+// NewSynchronizedCmixWithPolicy clones a Cmix from remote storage, only
+// synchronizing the keys selected by the sync policy. The same policy should
+// be passed to [LoadSynchronizedCmixWithPolicy].
+//
+// Parameters:
+//   - ndfJSON - the NDF file used to connect to the network.
+//   - storageDir - the local directory or path used for the encrypted key value
+//     store.
+//   - remoteStoragePathPrefix - the remote "directory" or path prefix used
+//     by the RemoteStore when reading/writing files.
+//   - password - the pssword used to decrypt the encrypted key value store.
+//   - remote - the RemoteStore implementation to use for multi-device
+//     synchronization.
+//   - syncPolicyJSON - A JSON marshalled [collective.SyncPolicy]. This may be
+//     empty, in which case every key is synchronized.
+//
+// Example syncPolicyJSON:
+//
+//	{
+//	  "excludedPrefixes": ["fileTransfer"],
+//	  "maxSize": 65536,
+//	  "localOnly": ["deviceState"]
+//	}
+func NewSynchronizedCmixWithPolicy(ndfJSON, storageDir,
+	remoteStoragePathPrefix string, password []byte, remote RemoteStore,
+	syncPolicyJSON []byte) error {
+
+	policy, err := parseSyncPolicy(syncPolicyJSON)
+	if err != nil {
+		return err
+	}
+
 	secret := copyAndClear(password)
 	wrappedRemote := newRemoteStoreFileSystemWrapper(remote)
 	jww.INFO.Printf("[BINDINGS] NewSynchronizedCmix, "+
 		"storageDir: %s, remoteStoragePathPrefix: %s",
 		storageDir, remoteStoragePathPrefix)
-	return xxdk.NewSynchronizedCmix(ndfJSON, storageDir,
+	return xxdk.NewSynchronizedCmixWithPolicy(ndfJSON, storageDir,
 		remoteStoragePathPrefix, secret,
-		wrappedRemote)
+		wrappedRemote, policy)
 }
 
 // LoadCmix will load an existing user storage from the storageDir using the
@@ -120,6 +156,22 @@ func LoadCmix(storageDir string, password []byte, cmixParamsJSON []byte) (*Cmix,
 // instances.
 func LoadSynchronizedCmix(storageDir, remoteStoragePathPrefix string, password []byte,
 	remote RemoteStore, cmixParamsJSON []byte) (*Cmix, error) {
+	return LoadSynchronizedCmixWithPolicy(storageDir, remoteStoragePathPrefix,
+		password, remote, nil, cmixParamsJSON)
+}
+
+// LoadSynchronizedCmixWithPolicy loads an existing user storage in the same
+// way as [LoadSynchronizedCmix], but only synchronizes the keys selected by
+// the sync policy. Keys the policy excludes are stored locally, but changes to
+// them are neither uploaded nor applied from other instances.
+//
+// Parameters:
+//   - syncPolicyJSON - A JSON marshalled [collective.SyncPolicy]. This may be
+//     empty, in which case every key under a synchronized prefix is
+//     synchronized. See [NewSynchronizedCmixWithPolicy] for an example.
+func LoadSynchronizedCmixWithPolicy(storageDir, remoteStoragePathPrefix string,
+	password []byte, remote RemoteStore, syncPolicyJSON,
+	cmixParamsJSON []byte) (*Cmix, error) {
 
 	secret := copyAndClear(password)
 
@@ -128,6 +180,11 @@ func LoadSynchronizedCmix(storageDir, remoteStoragePathPrefix string, password [
 		return nil, err
 	}
 
+	policy, err := parseSyncPolicy(syncPolicyJSON)
+	if err != nil {
+		return nil, err
+	}
+
 	synchedPrefixes := []string{
 		collective.StandardRemoteSyncPrefix,
 		"channels",
@@ -138,9 +195,9 @@ func LoadSynchronizedCmix(storageDir, remoteStoragePathPrefix string, password [
 		"storageDir: %s, remoteStoragePathPrefix: %s",
 		storageDir, remoteStoragePathPrefix)
 
-	net, err := xxdk.LoadSynchronizedCmix(storageDir,
+	net, err := xxdk.LoadSynchronizedCmixWithPolicy(storageDir,
 		remoteStoragePathPrefix, secret,
-		wrappedRemote, synchedPrefixes, params)
+		wrappedRemote, synchedPrefixes, policy, params)
 	if err != nil {
 		return nil, errors.Errorf("LoadSynchronizedCmix failed: %+v",
 			err)
@@ -12,6 +12,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
@@ -113,6 +114,12 @@ func NewDMClient(cmixID, notificationsID int, privateIdentity []byte,
 		return nil, err
 	}
 
+	// Add the disappearing message purge thread to API services tracking
+	err = user.api.AddService(m.StartProcesses)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add channel to singleton and return
 	return dmClients.add(m), nil
 }
@@ -175,6 +182,12 @@ func NewDMClientWithGoEventModel(cmixID, notificationsID int,
 		return nil, err
 	}
 
+	// Add the disappearing message purge thread to API services tracking
+	err = user.api.AddService(m.StartProcesses)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add channel to singleton and return
 	return dmClients.add(m), nil
 }
@@ -239,6 +252,12 @@ func NewDmManagerMobile(cmixID, notificationsID int, privateIdentity []byte,
 		return nil, err
 	}
 
+	// Add the disappearing message purge thread to API services tracking
+	err = user.api.AddService(m.StartProcesses)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add channel to singleton and return
 	return dmClients.add(m), nil
 }
@@ -432,6 +451,133 @@ func (dmc *DMClient) SetMobileNotificationsLevel(
 		partnerPubKey, dm.NotificationLevel(level))
 }
 
+// MarkRead marks all messages in the conversation with a timestamp at or
+// before the given timestamp as read. The read marker is synced to all the
+// user's devices. It only ever moves forward; marking an older timestamp does
+// nothing.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+//   - timestamp - The timestamp of the newest read message, in Unix nano.
+func (dmc *DMClient) MarkRead(partnerPubKey []byte, timestamp int64) error {
+	return dmc.api.MarkRead(partnerPubKey, time.Unix(0, timestamp))
+}
+
+// GetReadMarker returns the timestamp of the last read message in the
+// conversation.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+//
+// Returns:
+//   - int64 - The read marker, in Unix nano. It is zero if no messages in the
+//     conversation have been marked read.
+func (dmc *DMClient) GetReadMarker(partnerPubKey []byte) int64 {
+	lastRead, exists := dmc.api.GetReadMarker(partnerPubKey)
+	if !exists {
+		return 0
+	}
+	return lastRead.UnixNano()
+}
+
+// GetUnreadCount returns the number of messages from the partner received
+// after the read marker.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+//
+// Returns:
+//   - int64 - The number of unread messages.
+func (dmc *DMClient) GetUnreadCount(partnerPubKey []byte) (int64, error) {
+	count, err := dmc.api.GetUnreadCount(partnerPubKey)
+	return int64(count), err
+}
+
+// GetDisappearingTimer returns the duration after which messages in the
+// conversation with the partner are deleted.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+//
+// Returns:
+//   - int - The timer, in milliseconds. Returns 0 if disappearing messages are
+//     disabled.
+func (dmc *DMClient) GetDisappearingTimer(partnerPubKey []byte) int {
+	return int(dmc.api.GetDisappearingTimer(partnerPubKey).Milliseconds())
+}
+
+// EnablePresence opts in to or out of sharing presence. While disabled,
+// presence updates are neither sent nor received. Presence is disabled by
+// default.
+//
+// Parameters:
+//   - enabled - Set to true to share presence.
+func (dmc *DMClient) EnablePresence(enabled bool) error {
+	return dmc.api.EnablePresence(enabled)
+}
+
+// PresenceEnabled returns true if presence sharing is enabled.
+func (dmc *DMClient) PresenceEnabled() bool {
+	return dmc.api.PresenceEnabled()
+}
+
+// EnableMessageRequests turns the message request inbox on or off. While
+// enabled, messages from senders with no existing conversation are held until
+// the request is accepted or declined. Message requests are disabled by
+// default.
+//
+// Parameters:
+//   - enabled - Set to true to hold messages from unknown senders.
+func (dmc *DMClient) EnableMessageRequests(enabled bool) error {
+	return dmc.api.EnableMessageRequests(enabled)
+}
+
+// MessageRequestsEnabled returns true if the message request inbox is enabled.
+func (dmc *DMClient) MessageRequestsEnabled() bool {
+	return dmc.api.MessageRequestsEnabled()
+}
+
+// GetMessageRequests returns all pending message requests, oldest first.
+//
+// Returns:
+//   - []byte - JSON of a slice of [dm.MessageRequest].
+//
+// Example return:
+//
+//	[
+//	  {
+//	    "pubKey": "Q86WTJ5NOg3zTr+/I4ykZ21Fvo1+bcAvwUOyFP12IAo=",
+//	    "token": 3452127362,
+//	    "nickname": "Bob",
+//	    "timestamp": "2023-06-28T14:24:44.914452321Z",
+//	    "numMessages": 2
+//	  }
+//	]
+func (dmc *DMClient) GetMessageRequests() ([]byte, error) {
+	return json.Marshal(dmc.api.GetMessageRequests())
+}
+
+// AcceptMessageRequest accepts the pending message request from the partner.
+// The held messages are passed to the event model and all further messages
+// from the partner are received normally.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+func (dmc *DMClient) AcceptMessageRequest(partnerPubKey []byte) error {
+	return dmc.api.AcceptMessageRequest(partnerPubKey)
+}
+
+// DeclineMessageRequest drops the pending message request from the partner
+// and all of its held messages.
+//
+// Parameters:
+//   - partnerPubKey - The partner's Ed25519 public key.
+//   - block - Set to true to also block the partner.
+func (dmc *DMClient) DeclineMessageRequest(
+	partnerPubKey []byte, block bool) error {
+	return dmc.api.DeclineMessageRequest(partnerPubKey, block)
+}
+
 // GetDmNotificationReportsForMe checks the notification data against the filter
 // list to determine which notifications belong to the user. A list of
 // notification reports is returned detailing all notifications for the user.
@@ -664,6 +810,202 @@ func (dmc *DMClient) SendSilent(partnerPubKeyBytes []byte,
 	return constructDMSendReport(msgID, rnd.ID, ephID)
 }
 
+// CreateGroupConversation creates a group DM conversation with the given
+// members and returns its ID. This user is added to the members if not
+// included. Nothing is sent to the members until the first message.
+//
+// Messages in the conversation are passed to the [DMReceiver] with the
+// conversation ID in place of the partner's public key.
+//
+// Parameters:
+//   - membersJSON - JSON of a slice of [dm.GroupMember] containing the public
+//     key and DM token of each member.
+//
+// Example members JSON:
+//
+//	[
+//	  {
+//	    "pubKey": "Q86WTJ5NOg3zTr+/I4ykZ21Fvo1+bcAvwUOyFP12IAo=",
+//	    "token": 2954136932
+//	  },
+//	  {
+//	    "pubKey": "0oU+Ti5NOYOVvIcMJqHyp3YoyCd2hhwbOGQHk1Gvn8k=",
+//	    "token": 1732812366
+//	  }
+//	]
+//
+// Returns:
+//   - []byte - The conversation ID.
+func (dmc *DMClient) CreateGroupConversation(membersJSON []byte) ([]byte, error) {
+	var members []dm.GroupMember
+	if err := json.Unmarshal(membersJSON, &members); err != nil {
+		return nil, err
+	}
+
+	return dmc.api.CreateGroupConversation(members)
+}
+
+// GetGroupConversation returns the members of the group DM conversation.
+//
+// Parameters:
+//   - conversationID - The ID of the group DM conversation.
+//
+// Returns:
+//   - []byte - JSON of a slice of [dm.GroupMember] sorted by public key.
+func (dmc *DMClient) GetGroupConversation(conversationID []byte) ([]byte, error) {
+	members, exists := dmc.api.GetGroupConversation(conversationID)
+	if !exists {
+		return nil, dm.UnknownGroupConversationErr
+	}
+
+	return json.Marshal(members)
+}
+
+// SendGroupText is used to send a formatted message to every member of a group
+// DM conversation.
+//
+// Parameters:
+//   - conversationID - The ID of the group DM conversation.
+//   - message - The contents of the message. This is expected to be Unicode,
+//     and thus a string data type is expected.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. If left empty, then
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [DMSendReport].
+func (dmc *DMClient) SendGroupText(conversationID []byte, message string,
+	cmixParamsJSON []byte) ([]byte, error) {
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, ephID, err :=
+		dmc.api.SendGroupText(conversationID, message, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	return constructDMSendReport(msgID, rnd.ID, ephID)
+}
+
+// SendGroupReply is used to send a formatted reply to every member of a group
+// DM conversation.
+//
+// Parameters:
+//   - conversationID - The ID of the group DM conversation.
+//   - replyMessage - The contents of the reply.
+//   - replyToBytes - The bytes of the [message.ID] of the message to reply to.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. If left empty, then
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [DMSendReport].
+func (dmc *DMClient) SendGroupReply(conversationID []byte, replyMessage string,
+	replyToBytes []byte, cmixParamsJSON []byte) ([]byte, error) {
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	replyTo, err := message.UnmarshalID(replyToBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, ephID, err := dmc.api.SendGroupReply(
+		conversationID, replyMessage, replyTo, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	return constructDMSendReport(msgID, rnd.ID, ephID)
+}
+
+// SendGroupReaction is used to send a reaction to a message in a group DM
+// conversation. The reaction must be a single emoji with no other characters,
+// and will be rejected otherwise.
+//
+// Parameters:
+//   - conversationID - The ID of the group DM conversation.
+//   - reaction - The user's reaction. This should be a single emoji with no
+//     other characters.
+//   - reactToBytes - The bytes of the [message.ID] of the message to react to.
+//   - cmixParamsJSON - JSON of [xxdk.CMIXParams]. If left empty, then
+//     [GetDefaultCMixParams] will be used internally.
+//
+// Returns:
+//   - []byte - JSON of [DMSendReport].
+func (dmc *DMClient) SendGroupReaction(conversationID []byte, reaction string,
+	reactToBytes []byte, cmixParamsJSON []byte) ([]byte, error) {
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	reactTo, err := message.UnmarshalID(reactToBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID, rnd, ephID, err := dmc.api.SendGroupReaction(
+		conversationID, reaction, reactTo, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	return constructDMSendReport(msgID, rnd.ID, ephID)
+}
+
+// SendTyping sends an ephemeral signal to the partner that this user started
+// or stopped typing. It is received by the partner via
+// [DmCallbacks.EventUpdate] with event type [DmTypingUpdate] and is never
+// stored. Repeated signals with the same state are rate limited and nothing is
+// sent to blocked partners.
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key.
+//   - partnerToken - The token used to derive the reception ID for the partner.
+//   - typing - Set to true when the user starts typing and false when they
+//     stop.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+func (dmc *DMClient) SendTyping(partnerPubKeyBytes []byte, partnerToken int32,
+	typing bool, cmixParamsJSON []byte) error {
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return err
+	}
+
+	return dmc.api.SendTyping(ed25519.PublicKey(partnerPubKeyBytes),
+		uint32(partnerToken), typing, params.CMIX)
+}
+
+// SendPresence sends an ephemeral signal to the partner that this user came
+// online or went offline. It is received by the partner via
+// [DmCallbacks.EventUpdate] with event type [DmPresenceUpdate] and is never
+// stored. Returns [dm.PresenceDisabledErr] if presence sharing is disabled.
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key.
+//   - partnerToken - The token used to derive the reception ID for the partner.
+//   - online - Set to true when the user comes online and false when they go
+//     offline.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+func (dmc *DMClient) SendPresence(partnerPubKeyBytes []byte, partnerToken int32,
+	online bool, cmixParamsJSON []byte) error {
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return err
+	}
+
+	return dmc.api.SendPresence(ed25519.PublicKey(partnerPubKeyBytes),
+		uint32(partnerToken), online, params.CMIX)
+}
+
 // SendInvite is used to send to a DM partner an invitation to another
 // channel.
 //
@@ -759,6 +1101,43 @@ func (dmc *DMClient) DeleteMessage(partnerPubKeyBytes []byte, partnerToken int32
 	return constructDMSendReport(msgID, rnd.ID, ephID)
 }
 
+// SetDisappearingTimer sets the duration after which messages in the
+// conversation are deleted and sends it to the partner so that it is applied
+// on both sides.
+//
+// Parameters:
+//   - partnerPubKeyBytes - The bytes of the public key of the partner's ED25519
+//     signing key.
+//   - partnerToken - The token used to derive the reception ID for the partner.
+//   - timerMS - The duration, in milliseconds, after which messages are
+//     deleted. Set to 0 to disable disappearing messages.
+//   - cmixParamsJSON - A JSON marshalled [xxdk.CMIXParams]. This may be empty,
+//     and GetDefaultCMixParams will be used internally.
+//
+// Returns:
+//   - []byte - A JSON marshalled [DMSendReport].
+func (dmc *DMClient) SetDisappearingTimer(partnerPubKeyBytes []byte,
+	partnerToken int32, timerMS int, cmixParamsJSON []byte) ([]byte, error) {
+	partnerPubKey := ed25519.PublicKey(partnerPubKeyBytes)
+	timer := time.Duration(timerMS) * time.Millisecond
+
+	// Unmarshal cmix params
+	params, err := parseCMixParams(cmixParamsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send timer
+	msgID, rnd, ephID, err := dmc.api.SetDisappearingTimer(
+		partnerPubKey, uint32(partnerToken), timer, params.CMIX)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct send report
+	return constructDMSendReport(msgID, rnd.ID, ephID)
+}
+
 // Send is used to send a raw message. In general, it
 // should be wrapped in a function that defines the wire protocol.
 //
@@ -900,6 +1279,19 @@ const (
 
 	// DmMessageDeleted indicates the data is [DmMessageDeletedJSON].
 	DmMessageDeleted int64 = 4000
+
+	// DmReadMarkerUpdate indicates the data is [DmReadMarkerUpdateJSON].
+	DmReadMarkerUpdate int64 = 5000
+
+	// DmTypingUpdate indicates the data is [DmTypingUpdateJSON].
+	DmTypingUpdate int64 = 6000
+
+	// DmPresenceUpdate indicates the data is [DmPresenceUpdateJSON].
+	DmPresenceUpdate int64 = 7000
+
+	// DmMessageRequestUpdate indicates the data is
+	// [DmMessageRequestUpdateJSON].
+	DmMessageRequestUpdate int64 = 8000
 )
 
 type dmCallbacks struct {
@@ -936,6 +1328,40 @@ func (dmCBS *dmCallbacks) BlockedUser(user ed25519.PublicKey, blocked bool) {
 	})
 }
 
+func (dmCBS *dmCallbacks) ReadMarkerUpdate(
+	partnerPubKey ed25519.PublicKey, lastRead time.Time) {
+	dmCBS.eventUpdate(DmReadMarkerUpdate, DmReadMarkerUpdateJSON{
+		PubKey:   partnerPubKey,
+		LastRead: lastRead.UnixNano(),
+	})
+}
+
+func (dmCBS *dmCallbacks) TypingUpdate(
+	partnerPubKey ed25519.PublicKey, typing bool, timestamp time.Time) {
+	dmCBS.eventUpdate(DmTypingUpdate, DmTypingUpdateJSON{
+		PubKey:    partnerPubKey,
+		Typing:    typing,
+		Timestamp: timestamp.UnixNano(),
+	})
+}
+
+func (dmCBS *dmCallbacks) PresenceUpdate(
+	partnerPubKey ed25519.PublicKey, online bool, timestamp time.Time) {
+	dmCBS.eventUpdate(DmPresenceUpdate, DmPresenceUpdateJSON{
+		PubKey:    partnerPubKey,
+		Online:    online,
+		Timestamp: timestamp.UnixNano(),
+	})
+}
+
+func (dmCBS *dmCallbacks) MessageRequestUpdate(
+	partnerPubKey ed25519.PublicKey, pending bool) {
+	dmCBS.eventUpdate(DmMessageRequestUpdate, DmMessageRequestUpdateJSON{
+		PubKey:  partnerPubKey,
+		Pending: pending,
+	})
+}
+
 func (dmCBS *dmCallbacks) MessageReceived(uuid uint64, pubKey ed25519.PublicKey,
 	messageUpdate, conversationUpdate bool) {
 	dmCBS.eventUpdate(DmMessageReceived, DmMessageReceivedJSON{
@@ -1052,3 +1478,78 @@ type DmMessageReceivedJSON struct {
 type DmMessageDeletedJSON struct {
 	MessageID message.ID `json:"messageID"`
 }
+
+// DmReadMarkerUpdateJSON is returned when the read marker of a conversation is
+// loaded or advances on this or another of the user's devices.
+//
+// Fields:
+//   - PubKey - The DM partner's [ed25519.PublicKey].
+//   - LastRead - The timestamp of the newest read message, in Unix nano.
+//
+// Example JSON:
+//  {
+//    "pubKey": "Q86WTJ5NOg3zTr+/I4ykZ21Fvo1+bcAvwUOyFP12IAo=",
+//    "lastRead": 1687962284914452321
+//  }
+type DmReadMarkerUpdateJSON struct {
+	PubKey   ed25519.PublicKey `json:"pubKey"`
+	LastRead int64             `json:"lastRead"`
+}
+
+// DmTypingUpdateJSON is returned when the DM partner starts or stops typing.
+//
+// Fields:
+//   - PubKey - The DM partner's [ed25519.PublicKey].
+//   - Typing - True if the partner is typing.
+//   - Timestamp - The time the partner sent the update, in Unix nano.
+//
+// Example JSON:
+//  {
+//    "pubKey": "Q86WTJ5NOg3zTr+/I4ykZ21Fvo1+bcAvwUOyFP12IAo=",
+//    "typing": true,
+//    "timestamp": 1687962284914452321
+//  }
+type DmTypingUpdateJSON struct {
+	PubKey    ed25519.PublicKey `json:"pubKey"`
+	Typing    bool              `json:"typing"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// DmPresenceUpdateJSON is returned when the DM partner comes online or goes
+// offline. It is only returned while presence sharing is enabled.
+//
+// Fields:
+//   - PubKey - The DM partner's [ed25519.PublicKey].
+//   - Online - True if the partner is online.
+//   - Timestamp - The time the partner sent the update, in Unix nano.
+//
+// Example JSON:
+//  {
+//    "pubKey": "Q86WTJ5NOg3zTr+/I4ykZ21Fvo1+bcAvwUOyFP12IAo=",
+//    "online": false,
+//    "timestamp": 1687962284914452321
+//  }
+type DmPresenceUpdateJSON struct {
+	PubKey    ed25519.PublicKey `json:"pubKey"`
+	Online    bool              `json:"online"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// DmMessageRequestUpdateJSON is returned when a message from an unknown
+// partner is held in a message request and when the request is accepted or
+// declined.
+//
+// Fields:
+//   - PubKey - The partner's [ed25519.PublicKey].
+//   - Pending - True if the request is pending and false if it was accepted or
+//     declined.
+//
+// Example JSON:
+//  {
+//    "pubKey": "Q86WTJ5NOg3zTr+/I4ykZ21Fvo1+bcAvwUOyFP12IAo=",
+//    "pending": true
+//  }
+type DmMessageRequestUpdateJSON struct {
+	PubKey  ed25519.PublicKey `json:"pubKey"`
+	Pending bool              `json:"pending"`
+}
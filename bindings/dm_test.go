@@ -113,3 +113,20 @@ func newPubKey(rng io.Reader) ed25519.PublicKey {
 	pubKey, _, _ := ed25519.GenerateKey(rng)
 	return pubKey
 }
+
+// Produces example JSON of DmReadMarkerUpdateJSON to be used for documentation.
+func Test_DmReadMarkerUpdateJSON(t *testing.T) {
+	prng := rand.New(rand.NewSource(623677))
+
+	rmJSON := DmReadMarkerUpdateJSON{
+		PubKey:   newPubKey(prng),
+		LastRead: 1687962284914452321,
+	}
+
+	data, err := json.MarshalIndent(rmJSON, "//  ", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Printf("//  %s\n", data)
+}
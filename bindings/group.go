@@ -11,10 +11,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
 	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	gc "gitlab.com/elixxir/client/v4/groupChat"
 	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	gcStorage "gitlab.com/elixxir/client/v4/groupChat/storage"
+	"gitlab.com/elixxir/crypto/group"
 	"gitlab.com/elixxir/primitives/format"
 	"gitlab.com/xx_network/primitives/id"
 	"time"
@@ -61,6 +64,48 @@ func NewGroupChat(e2eID int,
 	return &GroupChat{m: wrapper}, nil
 }
 
+// NewGroupChatMobile creates a bindings-layer group chat manager that stores
+// all sent and received group messages in a SqlLite database for mobile use.
+//
+// Parameters:
+//   - e2eID - e2e object ID in the tracker.
+//   - requestFunc - a callback to handle group chat requests.
+//   - processor - the group chat message processor.
+//   - dbFilePath - absolute string path to the SqlLite database file.
+//   - cbs - a callback that is called when a message is stored in the
+//     database.
+func NewGroupChatMobile(e2eID int, requestFunc GroupRequest,
+	processor GroupChatProcessor, dbFilePath string,
+	cbs GroupChatMessageReceived) (*GroupChat, error) {
+
+	// Get user from singleton
+	user, err := e2eTrackerSingleton.get(e2eID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct a wrapper for the request callback
+	requestCb := func(g gs.Group) {
+		requestFunc.Callback(&Group{g: g})
+	}
+
+	model, err := gcStorage.NewEventModel(dbFilePath, &groupChatStorageCbs{cbs})
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct a group chat manager
+	gcInt, err := gc.NewManagerWithEventModel(user.api, requestCb,
+		&groupChatProcessor{bindingsCb: processor}, model)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct wrapper
+	wrapper := gc.NewWrapper(gcInt)
+	return &GroupChat{m: wrapper}, nil
+}
+
 // MakeGroup creates a new Group and sends a group request to all members in the
 // group.
 //
@@ -177,6 +222,206 @@ func (g *GroupChat) LeaveGroup(groupId []byte) error {
 	return g.m.LeaveGroup(grpId)
 }
 
+// AddMember adds a member to a group and rekeys it. Only group admins can add
+// members.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//     This can be pulled from a marshalled GroupReport.
+//   - memberId - the marshalled bytes of the new member's ID. The admin must
+//     have an authenticated channel with them.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupReport object, which can be
+//     passed into Cmix.WaitForRoundResult to see if the membership messages
+//     sends succeeded.
+func (g *GroupChat) AddMember(groupId, memberId []byte) ([]byte, error) {
+	return g.updateMembership(groupId, memberId, g.m.AddMember)
+}
+
+// RemoveMember removes a member from a group and rekeys it so that the removed
+// member cannot read any messages sent afterward. Only group admins can remove
+// members and only the creator can remove other admins.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//     This can be pulled from a marshalled GroupReport.
+//   - memberId - the marshalled bytes of the ID of the member to remove.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupReport object, which can be
+//     passed into Cmix.WaitForRoundResult to see if the membership messages
+//     sends succeeded.
+func (g *GroupChat) RemoveMember(groupId, memberId []byte) ([]byte, error) {
+	return g.updateMembership(groupId, memberId, g.m.RemoveMember)
+}
+
+// SetAdmin makes a member an admin of a group or removes their admin role.
+// Only the group creator can change the admins.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//   - memberId - the marshalled bytes of the member's ID.
+//   - admin - true to make the member an admin and false to remove the role.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupSendReport object.
+func (g *GroupChat) SetAdmin(
+	groupId, memberId []byte, admin bool) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+	memberID, err := id.Unmarshal(memberId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal member ID: %+v", err)
+	}
+
+	return makeGroupSendReport(g.m.SetAdmin(groupID, memberID, admin))
+}
+
+// RenameGroup changes the name of a group for all members. Only group admins
+// can rename the group.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//   - name - the new name of the group.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupSendReport object.
+func (g *GroupChat) RenameGroup(groupId, name []byte) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+
+	return makeGroupSendReport(g.m.RenameGroup(groupID, name))
+}
+
+// SendAdminNotice sends a notice signed by the admin to all members of a group.
+// Only group admins can send notices.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//   - notice - the text of the notice.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupSendReport object.
+func (g *GroupChat) SendAdminNotice(
+	groupId []byte, notice string) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+
+	return makeGroupSendReport(g.m.SendAdminNotice(groupID, notice))
+}
+
+// RegisterAdminCallbacks registers callbacks that are called when an admin
+// renames a group, changes its admins, or sends a notice.
+func (g *GroupChat) RegisterAdminCallbacks(cbs GroupChatAdminCallbacks) {
+	g.m.RegisterAdminCallbacks(&groupChatAdminCallbacks{cbs})
+}
+
+// DeclineInvite declines the invite to a group and notifies the group leader.
+//
+// Parameters:
+//   - serializedGroupData - the result of calling Group.Serialize() on
+//     any Group object returned over the bindings
+func (g *GroupChat) DeclineInvite(serializedGroupData []byte) error {
+	grp, err := DeserializeGroup(serializedGroupData)
+	if err != nil {
+		return err
+	}
+	return g.m.DeclineInvite(grp.g)
+}
+
+// RevokeInvite revokes the invite of a member who has not yet joined or
+// declined a group. The member is removed and the group is rekeyed. Only the
+// group leader can revoke invites.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//     This can be pulled from a marshalled GroupReport.
+//   - memberId - the marshalled bytes of the ID of the invited member.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupReport object, which can be
+//     passed into Cmix.WaitForRoundResult to see if the membership messages
+//     sends succeeded.
+func (g *GroupChat) RevokeInvite(groupId, memberId []byte) ([]byte, error) {
+	return g.updateMembership(groupId, memberId, g.m.RevokeInvite)
+}
+
+// GetInvites returns the status of every invite tracked for a group.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of a list of [groupStore.Invite].
+//
+// Example return:
+//
+//	[
+//	  {
+//	    "groupID": "AAAAAAAAAM0AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE",
+//	    "leaderID": "AAAAAAAAB8gAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAD",
+//	    "memberID": "AAAAAAAJlasAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAD",
+//	    "status": 1,
+//	    "updated": "2023-01-04T12:00:00Z"
+//	  }
+//	]
+func (g *GroupChat) GetInvites(groupId []byte) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+
+	return json.Marshal(g.m.GetInvites(groupID))
+}
+
+// GetPendingInvites returns all received invites that have not been joined,
+// declined, revoked, or expired.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of a list of [groupStore.Invite].
+func (g *GroupChat) GetPendingInvites() ([]byte, error) {
+	return json.Marshal(g.m.PendingInvites())
+}
+
+// updateMembership unmarshalls the IDs, calls the membership update function,
+// and returns the JSON marshalled GroupReport.
+func (g *GroupChat) updateMembership(groupId, memberId []byte,
+	update func(groupID, memberID *id.ID) (
+		[]id.Round, gc.RequestStatus, error)) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+
+	memberID, err := id.Unmarshal(memberId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal member ID: %+v", err)
+	}
+
+	rnds, status, err := update(groupID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GroupReport{
+		Id:         groupID.Bytes(),
+		RoundsList: makeRoundsList(rnds...),
+		Status:     int(status),
+	}
+	if len(rnds) > 0 {
+		report.RoundURL = getRoundURL(rnds[0])
+	}
+
+	return json.Marshal(report)
+}
+
 // Send is the bindings-level function for sending to a group.
 //
 // Parameters:
@@ -196,7 +441,110 @@ func (g *GroupChat) Send(groupId, message []byte, tag string) ([]byte, error) {
 	}
 
 	// Send group message
-	round, timestamp, msgID, err := g.m.Send(groupID, message, tag)
+	return makeGroupSendReport(g.m.Send(groupID, message, tag))
+}
+
+// SendText sends a typed text message to a group. Members receive it on the
+// callbacks registered with GroupChat.AddTypedService.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID. This can be pulled from
+//     marshalled GroupReport.
+//   - text - the text of the message.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupSendReport object.
+func (g *GroupChat) SendText(groupId []byte, text string) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+
+	return makeGroupSendReport(g.m.SendText(groupID, text))
+}
+
+// SendReply sends a typed text message to a group as a reply to another
+// message.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//   - text - the text of the reply.
+//   - replyTo - the message ID of the message being replied to. This can be
+//     pulled from a GroupSendReport or GroupChatMessage.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupSendReport object.
+func (g *GroupChat) SendReply(
+	groupId []byte, text string, replyTo []byte) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+	replyToID, err := unmarshalGroupMessageID(replyTo)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeGroupSendReport(g.m.SendReply(groupID, text, replyToID))
+}
+
+// SendReaction sends a typed reaction to another message to a group. The
+// reaction must be a single emoji.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//   - reaction - the emoji to react with.
+//   - reactTo - the message ID of the message being reacted to.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupSendReport object.
+func (g *GroupChat) SendReaction(
+	groupId []byte, reaction string, reactTo []byte) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+	reactToID, err := unmarshalGroupMessageID(reactTo)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeGroupSendReport(g.m.SendReaction(groupID, reaction, reactToID))
+}
+
+// SendDelete asks all members of a group to delete a message sent by the user.
+//
+// Parameters:
+//   - groupId - the byte data representing a group ID.
+//   - messageId - the message ID of the message to delete.
+//
+// Returns:
+//   - []byte - the JSON marshalled bytes of the GroupSendReport object.
+func (g *GroupChat) SendDelete(groupId, messageId []byte) ([]byte, error) {
+	groupID, err := id.Unmarshal(groupId)
+	if err != nil {
+		return nil, errors.Errorf("Failed to unmarshal group ID: %+v", err)
+	}
+	messageID, err := unmarshalGroupMessageID(messageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeGroupSendReport(g.m.SendDelete(groupID, messageID))
+}
+
+// AddTypedService registers callbacks for typed group messages sent with
+// SendText, SendReply, SendReaction, and SendDelete. It can only be called
+// once.
+func (g *GroupChat) AddTypedService(cbs GroupChatTypedCallbacks) error {
+	return g.m.AddService(gc.TypedServiceTag,
+		gc.NewTypedProcessor(&groupChatTypedCallbacks{cbs}))
+}
+
+// makeGroupSendReport returns the JSON marshalled GroupSendReport for the
+// results of a group message send.
+func makeGroupSendReport(round rounds.Round, timestamp time.Time,
+	msgID group.MessageID, err error) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
@@ -212,6 +560,17 @@ func (g *GroupChat) Send(groupId, message []byte, tag string) ([]byte, error) {
 	return json.Marshal(sendReport)
 }
 
+// unmarshalGroupMessageID unmarshalls the bytes into a group.MessageID.
+func unmarshalGroupMessageID(b []byte) (group.MessageID, error) {
+	var messageID group.MessageID
+	if len(b) != group.MessageIdLen {
+		return messageID, errors.Errorf("Invalid group message ID length "+
+			"%d; expected %d", len(b), group.MessageIdLen)
+	}
+	copy(messageID[:], b)
+	return messageID, nil
+}
+
 // GetGroups returns a list of group IDs that the user is a member of.
 //
 // Returns:
@@ -319,6 +678,25 @@ func (g *Group) GetMembership() ([]byte, error) {
 	return json.Marshal(g.g.Members)
 }
 
+// GetAdmins returns the list of admins of the group other than the creator,
+// who is always an admin and is the first member returned by GetMembership.
+//
+// Returns:
+//   - []byte - JSON marshalled list of [id.ID].
+//
+// Example JSON return:
+//
+//	[
+//	  "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAD"
+//	]
+func (g *Group) GetAdmins() ([]byte, error) {
+	admins := g.g.Admins
+	if admins == nil {
+		admins = []*id.ID{}
+	}
+	return json.Marshal(admins)
+}
+
 // Serialize serializes the Group.
 func (g *Group) Serialize() []byte {
 	return g.g.Serialize()
@@ -427,6 +805,132 @@ func (gcp *groupChatProcessor) String() string {
 	return gcp.bindingsCb.String()
 }
 
+// GroupChatTypedCallbacks is called for each typed group message received
+// after it is registered with GroupChat.AddTypedService. The decryptedMessage
+// field will be a JSON marshalled GroupChatMessage.
+type GroupChatTypedCallbacks interface {
+	ReceiveText(decryptedMessage []byte, text string, roundId int64)
+	ReceiveReply(decryptedMessage []byte, text string, replyTo []byte,
+		roundId int64)
+	ReceiveReaction(decryptedMessage []byte, reaction string, reactTo []byte,
+		roundId int64)
+	ReceiveDelete(decryptedMessage []byte, messageId []byte, roundId int64)
+}
+
+// groupChatTypedCallbacks wraps GroupChatTypedCallbacks to adhere to the
+// [groupChat.TypedCallbacks] interface.
+type groupChatTypedCallbacks struct {
+	bindingsCbs GroupChatTypedCallbacks
+}
+
+// marshalGroupChatMessage returns the JSON marshalled GroupChatMessage. Errors
+// are logged and nil is returned.
+func marshalGroupChatMessage(decryptedMsg gc.MessageReceive) []byte {
+	decryptedMessage, err := json.Marshal(convertMessageReceive(decryptedMsg))
+	if err != nil {
+		jww.ERROR.Printf("[GC] Failed to JSON marshal group message %s: %+v",
+			decryptedMsg.ID, err)
+	}
+	return decryptedMessage
+}
+
+// ReceiveText is called when a typed text message is received.
+func (gtc *groupChatTypedCallbacks) ReceiveText(
+	msg gc.MessageReceive, text string, round rounds.Round) {
+	gtc.bindingsCbs.ReceiveText(
+		marshalGroupChatMessage(msg), text, int64(round.ID))
+}
+
+// ReceiveReply is called when a typed reply is received.
+func (gtc *groupChatTypedCallbacks) ReceiveReply(msg gc.MessageReceive,
+	text string, replyTo group.MessageID, round rounds.Round) {
+	gtc.bindingsCbs.ReceiveReply(
+		marshalGroupChatMessage(msg), text, replyTo.Bytes(), int64(round.ID))
+}
+
+// ReceiveReaction is called when a typed reaction is received.
+func (gtc *groupChatTypedCallbacks) ReceiveReaction(msg gc.MessageReceive,
+	reaction string, reactTo group.MessageID, round rounds.Round) {
+	gtc.bindingsCbs.ReceiveReaction(marshalGroupChatMessage(msg), reaction,
+		reactTo.Bytes(), int64(round.ID))
+}
+
+// ReceiveDelete is called when a typed delete is received.
+func (gtc *groupChatTypedCallbacks) ReceiveDelete(msg gc.MessageReceive,
+	messageID group.MessageID, round rounds.Round) {
+	gtc.bindingsCbs.ReceiveDelete(
+		marshalGroupChatMessage(msg), messageID.Bytes(), int64(round.ID))
+}
+
+// GroupChatAdminCallbacks is called for each verified admin change or notice
+// received after it is registered with GroupChat.RegisterAdminCallbacks. The
+// decryptedMessage field will be a JSON marshalled GroupChatMessage.
+type GroupChatAdminCallbacks interface {
+	GroupRenamed(decryptedMessage []byte, name []byte, roundId int64)
+
+	// AdminsChanged is called with the JSON marshalled list of admin IDs,
+	// which does not include the creator.
+	AdminsChanged(decryptedMessage []byte, admins []byte, roundId int64)
+	ReceiveAdminNotice(decryptedMessage []byte, notice string, roundId int64)
+}
+
+// groupChatAdminCallbacks wraps GroupChatAdminCallbacks to adhere to the
+// [groupChat.AdminCallbacks] interface.
+type groupChatAdminCallbacks struct {
+	bindingsCbs GroupChatAdminCallbacks
+}
+
+// GroupRenamed is called when an admin renames the group.
+func (gac *groupChatAdminCallbacks) GroupRenamed(
+	msg gc.MessageReceive, name []byte, round rounds.Round) {
+	gac.bindingsCbs.GroupRenamed(
+		marshalGroupChatMessage(msg), name, int64(round.ID))
+}
+
+// AdminsChanged is called when the group creator changes the admins.
+func (gac *groupChatAdminCallbacks) AdminsChanged(
+	msg gc.MessageReceive, admins []*id.ID, round rounds.Round) {
+	if admins == nil {
+		admins = []*id.ID{}
+	}
+	adminsJSON, err := json.Marshal(admins)
+	if err != nil {
+		jww.ERROR.Printf("[GC] Failed to JSON marshal admins of group %s: "+
+			"%+v", msg.GroupID, err)
+	}
+	gac.bindingsCbs.AdminsChanged(
+		marshalGroupChatMessage(msg), adminsJSON, int64(round.ID))
+}
+
+// ReceiveAdminNotice is called when an admin sends a notice.
+func (gac *groupChatAdminCallbacks) ReceiveAdminNotice(
+	msg gc.MessageReceive, notice string, round rounds.Round) {
+	gac.bindingsCbs.ReceiveAdminNotice(
+		marshalGroupChatMessage(msg), notice, int64(round.ID))
+}
+
+// GroupChatMessageReceived is called when a group chat message is sent or
+// received and stored in the database by the manager created with
+// NewGroupChatMobile.
+type GroupChatMessageReceived interface {
+	// MessageReceived is called with the UUID of the stored message in the
+	// database and the marshalled bytes of the group ID.
+	MessageReceived(uuid int64, groupId []byte)
+}
+
+// groupChatStorageCbs wraps GroupChatMessageReceived to adhere to the
+// [storage.Callbacks] interface.
+type groupChatStorageCbs struct {
+	cbs GroupChatMessageReceived
+}
+
+// MessageReceived is called any time a message is sent or received.
+func (gcs *groupChatStorageCbs) MessageReceived(uuid uint64, groupID *id.ID) {
+	if gcs.cbs != nil {
+		gcs.cbs.MessageReceived(int64(uuid), groupID.Marshal())
+	}
+}
+
 /////////////////////////////////////////////////////////////////////////////////
 // Report Structures
 ////////////////////////////////////////////////////////////////////////////////
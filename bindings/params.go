@@ -12,6 +12,7 @@ package bindings
 import (
 	"encoding/json"
 	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/collective"
 	"gitlab.com/elixxir/client/v4/fileTransfer"
 	e2eFileTransfer "gitlab.com/elixxir/client/v4/fileTransfer/e2e"
 	"gitlab.com/elixxir/client/v4/single"
@@ -121,3 +122,14 @@ func parseE2EParams(data []byte) (xxdk.E2EParams, error) {
 	err := p.Unmarshal(data)
 	return *p, err
 }
+
+// parseSyncPolicy is a helper function which parses a JSON marshalled
+// [collective.SyncPolicy]. It returns nil, which synchronizes every key, if
+// the data is empty.
+func parseSyncPolicy(data []byte) (*collective.SyncPolicy, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	p := &collective.SyncPolicy{}
+	return p, json.Unmarshal(data, p)
+}
@@ -48,6 +48,15 @@ const (
 	// GroupCreationRequest - A group chat request message sent to all members in a group.
 	GroupCreationRequest = 40
 
+	// GroupMembershipUpdate is sent by the group leader to all remaining
+	// members when members are added to or removed from a group. It contains
+	// the new membership and key preimage.
+	GroupMembershipUpdate MessageType = 41
+
+	// GroupInviteResponse is sent by an invited member to the group leader when
+	// they join or decline a group.
+	GroupInviteResponse MessageType = 42
+
 	// NewFileTransfer is transmitted first on the initialization of a file
 	// transfer to inform the receiver about the incoming file.
 	NewFileTransfer MessageType = 50
@@ -80,6 +89,10 @@ func (mt MessageType) String() string {
 		return "E2eClose"
 	case GroupCreationRequest:
 		return "GroupCreationRequest"
+	case GroupMembershipUpdate:
+		return "GroupMembershipUpdate"
+	case GroupInviteResponse:
+		return "GroupInviteResponse"
 	case NewFileTransfer:
 		return "NewFileTransfer"
 	case EndFileTransfer:
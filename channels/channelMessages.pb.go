@@ -151,7 +151,7 @@ type UserMessage struct {
 	// Signature is the signature proving this message has been sent by the
 	// owner of this user's public key.
 	//
-	//	Signature = Sig(User_ECCPublicKey, Message)
+	//  Signature = Sig(User_ECCPublicKey, Message)
 	Signature []byte `protobuf:"bytes,3,opt,name=Signature,proto3" json:"Signature,omitempty"`
 	// ECCPublicKey is the user's EC Public key. This is provided by the
 	// network.
@@ -211,6 +211,343 @@ func (x *UserMessage) GetECCPublicKey() []byte {
 	return nil
 }
 
+// CMIXChannelPoll is the payload for a Poll MessageType. It asks the channel a
+// question with a fixed list of options that members can vote on.
+type CMIXChannelPoll struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version  uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Question string   `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
+	Options  []string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *CMIXChannelPoll) Reset() {
+	*x = CMIXChannelPoll{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channelMessages_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelPoll) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelPoll) ProtoMessage() {}
+
+func (x *CMIXChannelPoll) ProtoReflect() protoreflect.Message {
+	mi := &file_channelMessages_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelPoll.ProtoReflect.Descriptor instead.
+func (*CMIXChannelPoll) Descriptor() ([]byte, []int) {
+	return file_channelMessages_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CMIXChannelPoll) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelPoll) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *CMIXChannelPoll) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// CMIXChannelVote is the payload for a Vote MessageType. It casts a vote for an
+// option of a poll. Only the most recent vote from each user is counted.
+type CMIXChannelVote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version       uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PollMessageID []byte `protobuf:"bytes,2,opt,name=pollMessageID,proto3" json:"pollMessageID,omitempty"` // The [channel.MessageID] of the poll
+	Option        uint32 `protobuf:"varint,3,opt,name=option,proto3" json:"option,omitempty"`              // The index of the chosen option in the poll
+}
+
+func (x *CMIXChannelVote) Reset() {
+	*x = CMIXChannelVote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channelMessages_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelVote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelVote) ProtoMessage() {}
+
+func (x *CMIXChannelVote) ProtoReflect() protoreflect.Message {
+	mi := &file_channelMessages_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelVote.ProtoReflect.Descriptor instead.
+func (*CMIXChannelVote) Descriptor() ([]byte, []int) {
+	return file_channelMessages_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CMIXChannelVote) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelVote) GetPollMessageID() []byte {
+	if x != nil {
+		return x.PollMessageID
+	}
+	return nil
+}
+
+func (x *CMIXChannelVote) GetOption() uint32 {
+	if x != nil {
+		return x.Option
+	}
+	return 0
+}
+
+// CMIXChannelClosePoll is the payload for a ClosePoll MessageType. It sets the
+// time after which votes for a poll are no longer counted. Only the channel
+// admin can close a poll.
+type CMIXChannelClosePoll struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version       uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PollMessageID []byte `protobuf:"bytes,2,opt,name=pollMessageID,proto3" json:"pollMessageID,omitempty"` // The [channel.MessageID] of the poll
+	ClosingTime   int64  `protobuf:"varint,3,opt,name=closingTime,proto3" json:"closingTime,omitempty"`    // Unix nanoseconds after which votes are ignored
+}
+
+func (x *CMIXChannelClosePoll) Reset() {
+	*x = CMIXChannelClosePoll{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channelMessages_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelClosePoll) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelClosePoll) ProtoMessage() {}
+
+func (x *CMIXChannelClosePoll) ProtoReflect() protoreflect.Message {
+	mi := &file_channelMessages_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelClosePoll.ProtoReflect.Descriptor instead.
+func (*CMIXChannelClosePoll) Descriptor() ([]byte, []int) {
+	return file_channelMessages_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CMIXChannelClosePoll) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelClosePoll) GetPollMessageID() []byte {
+	if x != nil {
+		return x.PollMessageID
+	}
+	return nil
+}
+
+func (x *CMIXChannelClosePoll) GetClosingTime() int64 {
+	if x != nil {
+		return x.ClosingTime
+	}
+	return 0
+}
+
+// CMIXChannelModerator is the payload for a Moderator MessageType. It grants a
+// user permission to delete, pin, or mute on behalf of the channel admin until
+// the lease of the message ends. Only the channel admin can grant or revoke
+// moderator permissions.
+type CMIXChannelModerator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version     uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PubKey      []byte `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`            // The [ed25519.PublicKey] of the moderator
+	Permissions uint32 `protobuf:"varint,3,opt,name=permissions,proto3" json:"permissions,omitempty"` // Bitmask of the granted ModeratorPermission
+	UndoAction  bool   `protobuf:"varint,4,opt,name=undoAction,proto3" json:"undoAction,omitempty"`   // If true, the grant is revoked
+}
+
+func (x *CMIXChannelModerator) Reset() {
+	*x = CMIXChannelModerator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channelMessages_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelModerator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelModerator) ProtoMessage() {}
+
+func (x *CMIXChannelModerator) ProtoReflect() protoreflect.Message {
+	mi := &file_channelMessages_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelModerator.ProtoReflect.Descriptor instead.
+func (*CMIXChannelModerator) Descriptor() ([]byte, []int) {
+	return file_channelMessages_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CMIXChannelModerator) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelModerator) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *CMIXChannelModerator) GetPermissions() uint32 {
+	if x != nil {
+		return x.Permissions
+	}
+	return 0
+}
+
+func (x *CMIXChannelModerator) GetUndoAction() bool {
+	if x != nil {
+		return x.UndoAction
+	}
+	return false
+}
+
+// CMIXChannelSlowMode is the payload for a SlowMode MessageType. It limits how
+// often each user may post to the channel until the lease of the message ends.
+// Only the channel admin can set slow mode.
+type CMIXChannelSlowMode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version    uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Interval   int64  `protobuf:"varint,2,opt,name=interval,proto3" json:"interval,omitempty"`     // Minimum nanoseconds between posts from each user
+	UndoAction bool   `protobuf:"varint,3,opt,name=undoAction,proto3" json:"undoAction,omitempty"` // If true, slow mode is disabled
+}
+
+func (x *CMIXChannelSlowMode) Reset() {
+	*x = CMIXChannelSlowMode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channelMessages_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelSlowMode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelSlowMode) ProtoMessage() {}
+
+func (x *CMIXChannelSlowMode) ProtoReflect() protoreflect.Message {
+	mi := &file_channelMessages_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelSlowMode.ProtoReflect.Descriptor instead.
+func (*CMIXChannelSlowMode) Descriptor() ([]byte, []int) {
+	return file_channelMessages_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CMIXChannelSlowMode) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelSlowMode) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *CMIXChannelSlowMode) GetUndoAction() bool {
+	if x != nil {
+		return x.UndoAction
+	}
+	return false
+}
+
 var File_channelMessages_proto protoreflect.FileDescriptor
 
 var file_channelMessages_proto_rawDesc = []byte{
@@ -235,10 +572,46 @@ var file_channelMessages_proto_rawDesc = []byte{
 	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
 	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x45, 0x43, 0x43,
 	0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0c, 0x45, 0x43, 0x43, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x42, 0x24, 0x5a,
-	0x22, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x69, 0x78,
-	0x78, 0x69, 0x72, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x63, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0c, 0x45, 0x43, 0x43, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x22, 0x61, 0x0a,
+	0x0f, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x6c, 0x6c,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x22, 0x69, 0x0a, 0x0f, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x56,
+	0x6f, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a,
+	0x0d, 0x70, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x78, 0x0a, 0x14, 0x43,
+	0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x50,
+	0x6f, 0x6c, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a,
+	0x0d, 0x70, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x6c, 0x6f, 0x73, 0x69, 0x6e,
+	0x67, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x8a, 0x01, 0x0a, 0x14, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x4b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x12, 0x20, 0x0a, 0x0b, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x6b, 0x0a, 0x13, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x53, 0x6c, 0x6f, 0x77, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12,
+	0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c,
+	0x69, 0x78, 0x78, 0x69, 0x72, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -253,10 +626,15 @@ func file_channelMessages_proto_rawDescGZIP() []byte {
 	return file_channelMessages_proto_rawDescData
 }
 
-var file_channelMessages_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_channelMessages_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_channelMessages_proto_goTypes = []interface{}{
-	(*ChannelMessage)(nil), // 0: channels.ChannelMessage
-	(*UserMessage)(nil),    // 1: channels.UserMessage
+	(*ChannelMessage)(nil),       // 0: channels.ChannelMessage
+	(*UserMessage)(nil),          // 1: channels.UserMessage
+	(*CMIXChannelPoll)(nil),      // 2: channels.CMIXChannelPoll
+	(*CMIXChannelVote)(nil),      // 3: channels.CMIXChannelVote
+	(*CMIXChannelClosePoll)(nil), // 4: channels.CMIXChannelClosePoll
+	(*CMIXChannelModerator)(nil), // 5: channels.CMIXChannelModerator
+	(*CMIXChannelSlowMode)(nil),  // 6: channels.CMIXChannelSlowMode
 }
 var file_channelMessages_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -296,6 +674,66 @@ func file_channelMessages_proto_init() {
 				return nil
 			}
 		}
+		file_channelMessages_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelPoll); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channelMessages_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelVote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channelMessages_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelClosePoll); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channelMessages_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelModerator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channelMessages_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelSlowMode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -303,7 +741,7 @@ func file_channelMessages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_channelMessages_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
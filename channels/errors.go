@@ -37,4 +37,32 @@ var (
 	// registered per type.
 	MessageTypeAlreadyRegistered = errors.New(
 		"the given message type has already been registered")
+
+	// MessageFilterAlreadyRegistered is returned if a message filter has
+	// already been registered with the supplied name.
+	MessageFilterAlreadyRegistered = errors.New(
+		"a message filter with the given name has already been registered")
+
+	// ScheduledSendNotFoundErr is returned when attempting to cancel or
+	// reschedule a scheduled send that does not exist or was already sent.
+	ScheduledSendNotFoundErr = errors.New(
+		"the scheduled send cannot be found")
+
+	// SlowModeErr is returned when attempting to post to a channel in slow
+	// mode before the slow mode interval has passed since the user's last post.
+	SlowModeErr = errors.New(
+		"the channel is in slow mode and the user posted too recently")
+
+	// ReadMarkersUnsupportedErr is returned when getting the unread count of a
+	// channel when the EventModel does not implement ReadMarkerEventModel.
+	ReadMarkersUnsupportedErr = errors.New(
+		"the event model does not support read markers")
+
+	// EditsUnsupportedErr is returned when sending an edit when the EventModel
+	// does not implement EditEventModel.
+	EditsUnsupportedErr = errors.New("the event model does not support edits")
+
+	// PollsUnsupportedErr is returned when sending a poll, vote, or closed poll
+	// when the EventModel does not implement PollEventModel.
+	PollsUnsupportedErr = errors.New("the event model does not support polls")
 )
@@ -27,6 +27,7 @@ import (
 	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
 	"gitlab.com/elixxir/crypto/fastRNG"
 	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/primitives/states"
 	"gitlab.com/xx_network/primitives/id"
 	"gitlab.com/xx_network/primitives/netTime"
 )
@@ -165,6 +166,56 @@ type EventModel interface {
 	MuteUser(channelID *id.ID, pubKey ed25519.PublicKey, unmute bool)
 }
 
+// EditEventModel is an EventModel that records message edits. If the
+// EventModel passed to the Manager implements this interface, then every edit
+// made by the original sender of a text message is passed to it. Otherwise,
+// edits are dropped and Manager.SendEdit returns EditsUnsupportedErr.
+type EditEventModel interface {
+	EventModel
+
+	// EditMessage is called whenever the original sender of a message replaces
+	// its text. The previous text should be kept so that the UI can mark the
+	// message as edited and show earlier versions.
+	//
+	// Edits may arrive out of order; the text of the edit with the latest
+	// timestamp must be displayed.
+	//
+	// Returns the UUID of the edited message. Returns an error if the message
+	// cannot be edited. It must return NoMessageErr if the message does not
+	// exist.
+	EditMessage(messageID, editMessageID message.ID, text string,
+		timestamp time.Time) (uint64, error)
+}
+
+// PollEventModel is an EventModel that counts the votes of polls. If the
+// EventModel passed to the Manager implements this interface, then every vote
+// and closed poll is passed to it. Otherwise, they are dropped and
+// Manager.SendPoll, Manager.SendVote, and Manager.ClosePoll return
+// PollsUnsupportedErr. Polls themselves are always passed to
+// EventModel.ReceiveMessage.
+type PollEventModel interface {
+	EventModel
+
+	// ReceiveVote is called whenever a vote for a poll is received. Only the
+	// most recent vote from each user (by timestamp) is counted; earlier votes
+	// from the same user are replaced. Votes with a timestamp at or after the
+	// closing time of the poll are not counted.
+	//
+	// Returns the UUID of the poll. Returns an error if the vote cannot be
+	// saved. It must return NoMessageErr if the poll does not exist.
+	ReceiveVote(pollID, voteID message.ID, pubKey ed25519.PublicKey,
+		option uint32, timestamp time.Time) (uint64, error)
+
+	// ClosePoll is called whenever the channel admin closes a poll. Votes with
+	// a timestamp at or after the closing time are not counted, including
+	// those that were received before the poll was closed. The closing time
+	// replaces any previously set closing time.
+	//
+	// Returns the UUID of the poll. Returns an error if the poll cannot be
+	// closed. It must return NoMessageErr if the poll does not exist.
+	ClosePoll(pollID message.ID, closingTime time.Time) (uint64, error)
+}
+
 // NoMessageErr must be returned by EventModel methods (such as
 // EventModel.UpdateFromUUID, EventModel.UpdateFromMessageID, and
 // EventModel.GetMessage) when the message cannot be found.
@@ -200,6 +251,7 @@ type ModelMessage struct {
 	PubKey          ed25519.PublicKey `json:"pubKey"`
 	CodesetVersion  uint8             `json:"codesetVersion"`
 	DmToken         uint32            `json:"dmToken"`
+	Edited          bool              `json:"edited"`
 }
 
 // MessageTypeReceiveMessage defines handlers for messages of various message
@@ -231,6 +283,10 @@ type events struct {
 	commandStore *CommandStore
 	leases       *ActionLeaseList
 	mutedUsers   *mutedUserManager
+	moderators   *moderatorManager
+	slowMode     *slowModeManager
+	roster       *rosterManager
+	filters      *filterChain
 	as           *ActionSaver
 
 	// List of registered message processors
@@ -350,10 +406,16 @@ func initEvents(model EventModel, maxMessageLength int, kv versioned.KV,
 		AdminText:   {"adminTextMessage", e.receiveTextMessage, false, true, false},
 		Reaction:    {"reaction", e.receiveReaction, true, false, false},
 		Invitation:  {"invitation", e.receiveInvitation, true, false, false},
+		Poll:        {"poll", e.receivePoll, true, false, false},
 		Delete:      {"delete", e.receiveDelete, true, true, false},
 		Pinned:      {"pinned", e.receivePinned, false, true, false},
 		Mute:        {"mute", e.receiveMute, false, true, false},
 		AdminReplay: {"adminReplay", e.receiveAdminReplay, true, true, false},
+		Edit:        {"edit", e.receiveEdit, true, false, false},
+		Vote:        {"vote", e.receiveVote, true, false, false},
+		ClosePoll:   {"closePoll", e.receiveClosePoll, false, true, false},
+		Moderator:   {"moderator", e.receiveModerator, false, true, false},
+		SlowMode:    {"slowMode", e.receiveSlowMode, false, true, false},
 	}
 
 	// Initialise list of message leases
@@ -370,6 +432,24 @@ func initEvents(model EventModel, maxMessageLength int, kv versioned.KV,
 		jww.FATAL.Panicf("[CH] Failed to initialise muted user list: %+v", err)
 	}
 
+	// Initialise list of moderators
+	e.moderators, err = newOrLoadModeratorManager(kv)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to initialise moderator list: %+v", err)
+	}
+
+	// Initialise slow mode settings
+	e.slowMode, err = newOrLoadSlowModeManager(kv)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to initialise slow mode: %+v", err)
+	}
+
+	// Initialise roster of channel members
+	e.roster = newRosterManager(kv, e.mutedUsers.isMuted)
+
+	// Initialise chain of message filters
+	e.filters = newFilterChain()
+
 	// Initialise action saver
 	e.as = NewActionSaver(e.triggerActionEvent, kv)
 
@@ -407,6 +487,11 @@ func (e *events) RegisterReceiveHandler(
 // Message Triggers                                                           //
 ////////////////////////////////////////////////////////////////////////////////
 
+// checkEditFunc verifies that an edit message is from the original sender of
+// the message it edits.
+type checkEditFunc func(channelID *id.ID, messageID message.ID,
+	pubKey ed25519.PublicKey, content []byte) bool
+
 // triggerEventFunc is triggered on normal message reception.
 type triggerEventFunc func(channelID *id.ID, umi *userMessageInternal,
 	encryptedPayload []byte, timestamp time.Time,
@@ -426,6 +511,10 @@ func (e *events) triggerEvent(channelID *id.ID, umi *userMessageInternal,
 	um := umi.GetUserMessage()
 	cm := umi.GetChannelMessage()
 
+	// Add the sender to the channel roster
+	e.roster.observe(channelID, um.ECCPublicKey, cm.Nickname, cm.DMToken, 0,
+		timestamp)
+
 	// Check if the user is muted on this channel
 	isMuted := e.mutedUsers.isMuted(channelID, um.ECCPublicKey)
 
@@ -436,8 +525,19 @@ func (e *events) triggerEvent(channelID *id.ID, umi *userMessageInternal,
 		return 0, nil
 	}
 
+	// Moderators perform the actions they were granted as the admin. Their
+	// actions cannot be replayed, so the lease cannot outlive the message. The
+	// grant expiry is checked against the round timestamp because the message
+	// timestamp is chosen by the sender.
+	lease := time.Duration(cm.Lease)
+	isModerator := e.moderators.hasPermission(channelID, um.ECCPublicKey,
+		umi.messageType, round.Timestamps[states.QUEUED])
+	if isModerator && lease > maxModeratorActionLease {
+		lease = maxModeratorActionLease
+	}
+
 	// Get handler for message type
-	handler, err := e.getHandler(umi.messageType, true, false, isMuted)
+	handler, err := e.getHandler(umi.messageType, true, isModerator, isMuted)
 	if err != nil {
 		return 0, errors.Errorf("Received message %s from %x on channel %s in "+
 			"round %d that could not be handled: %s; Contents: %v",
@@ -445,12 +545,55 @@ func (e *events) triggerEvent(channelID *id.ID, umi *userMessageInternal,
 			cm.Payload)
 	}
 
+	// Run the message filters on messages received from other users. Messages
+	// sent by this user are triggered with a different status.
+	var filterResult FilterResult
+	if status == Delivered && !e.filters.isEmpty() {
+		filterResult = e.filters.run(makeFilterMessage(channelID,
+			umi.GetMessageID(), umi.GetMessageType(), cm.Nickname, cm.Payload,
+			um.ECCPublicKey, 0, timestamp))
+		if filterResult.Action == FilterDrop {
+			jww.INFO.Printf("[CH] Dropping filtered message %s of type %s "+
+				"from %x on channel %s", umi.GetMessageID(),
+				umi.GetMessageType(), um.ECCPublicKey, channelID)
+			return 0, nil
+		}
+	}
+
+	// Hide posts from users posting faster than the channel's slow mode
+	// allows. The limit is decided on the round timestamp so that every client
+	// makes the same decision. Moderators are exempt, and admins are never
+	// limited because admin messages are not triggered here. This is checked
+	// after the filters so that dropped messages are not recorded as posts.
+	var slowModeHidden bool
+	if status == Delivered && !e.moderators.isModerator(
+		channelID, um.ECCPublicKey, round.Timestamps[states.QUEUED]) {
+		slowModeHidden = !e.slowMode.allowPost(channelID, um.ECCPublicKey,
+			umi.GetMessageType(), round.Timestamps[states.QUEUED])
+		if slowModeHidden {
+			jww.INFO.Printf("[CH] Hiding message %s of type %s from %x on "+
+				"channel %s sent too soon after their last post in slow mode",
+				umi.GetMessageID(), umi.GetMessageType(), um.ECCPublicKey,
+				channelID)
+		}
+	}
+
 	// Call the listener. This is already in an instanced event; no new thread
 	// is needed.
 	uuid := handler.listener(channelID, umi.GetMessageID(), umi.GetMessageType(),
 		cm.Nickname, cm.Payload, encryptedPayload, um.ECCPublicKey, cm.DMToken,
-		0, timestamp, time.Unix(0, cm.LocalTimestamp), time.Duration(cm.Lease),
-		id.Round(cm.RoundID), round, status, false, false)
+		0, timestamp, time.Unix(0, cm.LocalTimestamp), lease,
+		id.Round(cm.RoundID), round, status, isModerator,
+		filterResult.Action == FilterHide || slowModeHidden)
+
+	// Pass the score from the message filters to the event model
+	if sem, ok := e.model.(ScoredEventModel); ok &&
+		uuid != 0 && filterResult.Score != 0 {
+		if err = sem.UpdateScore(uuid, filterResult.Score); err != nil {
+			jww.ERROR.Printf("[CH] Failed to update score of message %s on "+
+				"channel %s: %+v", umi.GetMessageID(), channelID, err)
+		}
+	}
 
 	// If there is an update function, then call it in a new thread
 	if updateFn != nil {
@@ -931,6 +1074,7 @@ func (e *events) receiveMute(channelID *id.ID, messageID message.ID,
 	}
 
 	e.model.MuteUser(channelID, mutedUser, undoAction)
+	e.roster.mutedUpdate(channelID, mutedUser)
 
 	return 0
 }
@@ -963,6 +1107,416 @@ func (e *events) receiveAdminReplay(channelID *id.ID, messageID message.ID,
 	return 0
 }
 
+// receiveEdit is the internal function that handles the reception of edited
+// messages. The original sender of the edited message is verified by
+// events.checkEdit in the userListener before the edit is triggered. Edits are
+// dropped if the EventModel does not implement EditEventModel.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveEdit(channelID *id.ID, messageID message.ID,
+	messageType MessageType, _ string, content, _ []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp, _ time.Time,
+	lease time.Duration, _ id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType, pubKey,
+		codeset, timestamp, lease, round, fromAdmin)
+
+	model, ok := e.model.(EditEventModel)
+	if !ok {
+		jww.WARN.Printf("[CH] Dropping %s: the event model does not support "+
+			"edits", msgLog)
+		return 0
+	}
+
+	editMsg := &CMIXChannelEdit{}
+	if err := proto.Unmarshal(content, editMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			editMsg, msgLog, err)
+		return 0
+	}
+
+	var editedMessageID message.ID
+	copy(editedMessageID[:], editMsg.MessageID)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendEditTag)
+	jww.INFO.Printf("[CH] [%s] Received message %s from %x to channel %s to "+
+		"edit message %s", tag, messageID, pubKey, channelID, editedMessageID)
+
+	uuid, err := model.EditMessage(
+		editedMessageID, messageID, editMsg.Text, timestamp)
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to edit message %s: %+v", tag, msgLog, err)
+		return 0
+	}
+
+	return uuid
+}
+
+// checkEdit returns true if the edit message content targets a text message
+// in the channel that was sent by the same public key as the edit. It is
+// called by the userListener so that only the original sender of a message can
+// edit it.
+//
+// This function adheres to the checkEditFunc type.
+func (e *events) checkEdit(channelID *id.ID, messageID message.ID,
+	pubKey ed25519.PublicKey, content []byte) bool {
+	editMsg := &CMIXChannelEdit{}
+	if err := proto.Unmarshal(content, editMsg); err != nil {
+		jww.ERROR.Printf("[CH] Failed to proto unmarshal %T from edit %s on "+
+			"channel %s: %+v", editMsg, messageID, channelID, err)
+		return false
+	}
+
+	var editedMessageID message.ID
+	copy(editedMessageID[:], editMsg.MessageID)
+
+	targetMsg, err := e.model.GetMessage(editedMessageID)
+	if err != nil {
+		jww.ERROR.Printf("[CH] Failed to find target message %s for edit %s "+
+			"on channel %s: %+v", editedMessageID, messageID, channelID, err)
+		return false
+	}
+
+	if targetMsg.ChannelID != nil && !targetMsg.ChannelID.Cmp(channelID) {
+		jww.ERROR.Printf("[CH] Edit %s on channel %s must target a message "+
+			"in the same channel", messageID, channelID)
+		return false
+	} else if !bytes.Equal(targetMsg.PubKey, pubKey) {
+		jww.ERROR.Printf("[CH] Edit %s on channel %s from %x must come from "+
+			"the original sender %x", messageID, channelID, pubKey,
+			targetMsg.PubKey)
+		return false
+	} else if targetMsg.Type != Text {
+		jww.ERROR.Printf("[CH] Edit %s on channel %s cannot target message "+
+			"of type %s", messageID, channelID, targetMsg.Type)
+		return false
+	}
+
+	return true
+}
+
+// receivePoll is the internal function that handles the reception of polls.
+// The poll is passed to the event model as a message with its text set to the
+// JSON of the CMIXChannelPoll.
+//
+// Polls without a question or with an invalid number of options are dropped.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receivePoll(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, _ []byte,
+	pubKey ed25519.PublicKey, dmToken uint32, codeset uint8, timestamp,
+	_ time.Time, lease time.Duration, _ id.Round, round rounds.Round,
+	status SentStatus, fromAdmin, hidden bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType, pubKey,
+		codeset, timestamp, lease, round, fromAdmin)
+
+	poll := &CMIXChannelPoll{}
+	if err := proto.Unmarshal(content, poll); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			poll, msgLog, err)
+		return 0
+	}
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendPollTag)
+	jww.INFO.Printf("[CH] [%s] Received poll %s from %x on %s",
+		tag, messageID, pubKey, channelID)
+
+	if err := validatePoll(poll.Question, poll.Options); err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Dropping invalid poll in %s: %+v", tag, msgLog, err)
+		return 0
+	}
+
+	var pollJson bytes.Buffer
+	enc := json.NewEncoder(&pollJson)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(poll); err != nil {
+		jww.ERROR.Printf("[CH] [%s] Failed to JSON marshal poll: %+v", tag, err)
+		return 0
+	}
+
+	return e.model.ReceiveMessage(channelID, messageID, nickname,
+		pollJson.String(), pubKey, dmToken, codeset, timestamp, lease, round,
+		Poll, status, hidden)
+}
+
+// receiveVote is the internal function that handles the reception of votes.
+// Votes are dropped if the poll is unknown, the option does not exist, or the
+// EventModel does not implement PollEventModel.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveVote(channelID *id.ID, messageID message.ID,
+	messageType MessageType, _ string, content, _ []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp, _ time.Time,
+	lease time.Duration, _ id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType, pubKey,
+		codeset, timestamp, lease, round, fromAdmin)
+
+	model, ok := e.model.(PollEventModel)
+	if !ok {
+		jww.WARN.Printf("[CH] Dropping %s: the event model does not support "+
+			"polls", msgLog)
+		return 0
+	}
+
+	vote := &CMIXChannelVote{}
+	if err := proto.Unmarshal(content, vote); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			vote, msgLog, err)
+		return 0
+	}
+
+	var pollID message.ID
+	copy(pollID[:], vote.PollMessageID)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendVoteTag)
+	jww.INFO.Printf("[CH] [%s] Received vote %s from %x to channel %s for "+
+		"option %d of poll %s", tag, messageID, pubKey, channelID, vote.Option,
+		pollID)
+
+	poll, err := getPoll(e.model, channelID, pollID)
+	if err != nil {
+		jww.ERROR.Printf("[CH] [%s] Failed to find poll %s for vote from %s: "+
+			"%+v", tag, pollID, msgLog, err)
+		return 0
+	} else if int(vote.Option) >= len(poll.Options) {
+		jww.ERROR.Printf("[CH] [%s] Vote for option %d of poll %s with %d "+
+			"options is out of range for %s",
+			tag, vote.Option, pollID, len(poll.Options), msgLog)
+		return 0
+	}
+
+	uuid, err := model.ReceiveVote(
+		pollID, messageID, pubKey, vote.Option, timestamp)
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to save vote %s: %+v", tag, msgLog, err)
+		return 0
+	}
+
+	return uuid
+}
+
+// receiveClosePoll is the internal function that handles the reception of
+// closed polls from the channel admin. Closed polls are dropped if the
+// EventModel does not implement PollEventModel.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveClosePoll(channelID *id.ID, messageID message.ID,
+	messageType MessageType, _ string, content, _ []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp, _ time.Time,
+	lease time.Duration, _ id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType, pubKey,
+		codeset, timestamp, lease, round, fromAdmin)
+
+	model, ok := e.model.(PollEventModel)
+	if !ok {
+		jww.WARN.Printf("[CH] Dropping %s: the event model does not support "+
+			"polls", msgLog)
+		return 0
+	}
+
+	closeMsg := &CMIXChannelClosePoll{}
+	if err := proto.Unmarshal(content, closeMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			closeMsg, msgLog, err)
+		return 0
+	}
+
+	var pollID message.ID
+	copy(pollID[:], closeMsg.PollMessageID)
+	closingTime := time.Unix(0, closeMsg.ClosingTime)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendClosePollTag)
+	jww.INFO.Printf("[CH] [%s] Received message %s from %x to channel %s to "+
+		"close poll %s at %s", tag, messageID, pubKey, channelID, pollID,
+		closingTime)
+
+	if _, err := getPoll(e.model, channelID, pollID); err != nil {
+		jww.ERROR.Printf("[CH] [%s] Failed to find poll %s to close for %s: "+
+			"%+v", tag, pollID, msgLog, err)
+		return 0
+	}
+
+	uuid, err := model.ClosePoll(pollID, closingTime)
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to close poll %s: %+v", tag, msgLog, err)
+		return 0
+	}
+
+	return uuid
+}
+
+// receiveModerator is the internal function that handles the reception of
+// moderator grants and revocations from the channel admin. A grant lasts until
+// its lease ends, at which point the lease system revokes it.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveModerator(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType,
+		pubKey, codeset, timestamp, lease, round, fromAdmin)
+
+	moderatorMsg := &CMIXChannelModerator{}
+	if err := proto.Unmarshal(content, moderatorMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			moderatorMsg, msgLog, err)
+		return 0
+	}
+
+	if len(moderatorMsg.PubKey) != ed25519.PublicKeySize {
+		jww.ERROR.Printf("[CH] Failed unmarshal public key of moderator in "+
+			"%s: length of %d bytes required, received %d bytes",
+			msgLog, ed25519.PublicKeySize, len(moderatorMsg.PubKey))
+		return 0
+	}
+
+	moderator := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	copy(moderator[:], moderatorMsg.PubKey)
+	permissions := ModeratorPermission(moderatorMsg.Permissions)
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendModeratorTag)
+	jww.INFO.Printf("[CH] [%s] Received message %s from %s to channel %s to "+
+		"%s moderator %x with permissions %s", tag, messageID, nickname,
+		channelID, moderatorVerb(moderatorMsg.UndoAction), moderator,
+		permissions)
+
+	// The undo payload does not include the permissions so that all grants to
+	// the same user share a single lease
+	undoAction := moderatorMsg.UndoAction
+	payload, err := proto.Marshal(&CMIXChannelModerator{
+		Version:    moderatorMsg.Version,
+		PubKey:     moderatorMsg.PubKey,
+		UndoAction: true,
+	})
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to proto marshal %T from payload in %s: %+v",
+			tag, moderatorMsg, msgLog, err)
+		return 0
+	}
+
+	if undoAction {
+		err = e.leases.RemoveMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf(
+				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+			return 0
+		}
+		e.moderators.revoke(channelID, moderator)
+	} else {
+		err = e.leases.AddMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf(
+				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+			return 0
+		}
+
+		var expiry time.Time
+		if lease != ValidForever {
+			expiry = originatingTimestamp.Add(lease)
+		}
+		e.moderators.grant(channelID, moderator, permissions, expiry)
+	}
+
+	return 0
+}
+
+// receiveSlowMode is the internal function that handles the reception of slow
+// mode settings from the channel admin. Slow mode lasts until its lease ends,
+// at which point the lease system disables it.
+//
+// This function adheres to the MessageTypeReceiveMessage type.
+func (e *events) receiveSlowMode(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content, encryptedPayload []byte,
+	pubKey ed25519.PublicKey, _ uint32, codeset uint8, timestamp,
+	originatingTimestamp time.Time, lease time.Duration,
+	originatingRound id.Round, round rounds.Round, _ SentStatus, fromAdmin,
+	_ bool) uint64 {
+	msgLog := sprintfReceiveMessage(channelID, messageID, messageType,
+		pubKey, codeset, timestamp, lease, round, fromAdmin)
+
+	slowModeMsg := &CMIXChannelSlowMode{}
+	if err := proto.Unmarshal(content, slowModeMsg); err != nil {
+		jww.ERROR.Printf(
+			"[CH] Failed to proto unmarshal %T from payload in %s: %+v",
+			slowModeMsg, msgLog, err)
+		return 0
+	}
+
+	interval := time.Duration(slowModeMsg.Interval)
+	if !slowModeMsg.UndoAction && interval <= 0 {
+		jww.ERROR.Printf("[CH] Invalid slow mode interval %s in %s",
+			interval, msgLog)
+		return 0
+	}
+
+	tag := makeChaDebugTag(channelID, pubKey, content, SendSlowModeTag)
+	jww.INFO.Printf("[CH] [%s] Received message %s from %s to channel %s to "+
+		"%s slow mode with interval %s", tag, messageID, nickname, channelID,
+		slowModeVerb(slowModeMsg.UndoAction), interval)
+
+	// The undo payload does not include the interval so that all slow mode
+	// settings in the channel share a single lease
+	undoAction := slowModeMsg.UndoAction
+	payload, err := proto.Marshal(&CMIXChannelSlowMode{
+		Version:    slowModeMsg.Version,
+		UndoAction: true,
+	})
+	if err != nil {
+		jww.ERROR.Printf(
+			"[CH] [%s] Failed to proto marshal %T from payload in %s: %+v",
+			tag, slowModeMsg, msgLog, err)
+		return 0
+	}
+
+	if undoAction {
+		err = e.leases.RemoveMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf(
+				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+			return 0
+		}
+		e.slowMode.disable(channelID)
+	} else {
+		err = e.leases.AddMessage(channelID, messageID, messageType, content,
+			payload, encryptedPayload, timestamp, originatingTimestamp, lease,
+			originatingRound, round, fromAdmin)
+		if err != nil {
+			jww.ERROR.Printf(
+				"[CH] [%s] Lease system rejected %s: %+v", tag, msgLog, err)
+			return 0
+		}
+
+		var expiry time.Time
+		if lease != ValidForever {
+			expiry = originatingTimestamp.Add(lease)
+		}
+		e.slowMode.enable(channelID, interval, expiry)
+	}
+
+	return 0
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Debugging and Logging Utilities                                            //
 ////////////////////////////////////////////////////////////////////////////////
@@ -996,3 +1550,21 @@ func muteVerb(b bool) string {
 	}
 	return "mute"
 }
+
+// moderatorVerb returns the correct verb for the moderator action to use for
+// logging and debugging.
+func moderatorVerb(b bool) string {
+	if b {
+		return "revoke"
+	}
+	return "grant"
+}
+
+// slowModeVerb returns the correct verb for the slow mode action to use for
+// logging and debugging.
+func slowModeVerb(b bool) string {
+	if b {
+		return "disable"
+	}
+	return "enable"
+}
@@ -98,9 +98,9 @@ func Test_initEvents(t *testing.T) {
 	}
 
 	// check that all the default callbacks are registered
-	if len(e.registered) != 8 {
+	if len(e.registered) != 14 {
 		t.Errorf("The correct number of default handlers are not "+
-			"registered; %d vs %d", len(e.registered), 7)
+			"registered; %d vs %d", len(e.registered), 14)
 		// If this fails, is means the default handlers have changed. edit the
 		// number here and add tests below. be suspicious if it goes down.
 	}
@@ -929,6 +929,393 @@ func Test_events_receiveMute(t *testing.T) {
 	}
 }
 
+// Unit test of events.checkEdit and events.receiveEdit.
+func Test_events_checkEdit_receiveEdit(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	// Craft the input for the event
+	chID, _ := id.NewRandomID(prng, id.User)
+	targetMessageID := message.DeriveChannelMessageID(chID, 420, []byte("blarg"))
+	textPayload := &CMIXChannelEdit{
+		Version:   0,
+		MessageID: targetMessageID[:],
+		Text:      "edited",
+	}
+	textMarshaled, err := proto.Marshal(textPayload)
+	if err != nil {
+		t.Fatalf("Failed to proto marshal %T: %+v", textPayload, err)
+	}
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), textMarshaled)
+	senderUsername := "Alice"
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte("original")
+	me.pubKey = pi.PubKey
+	me.eventReceive = eventReceive{chID, targetMessageID, message.ID{},
+		senderUsername, content, ts, lease, r, Delivered, false, false, Text, 0,
+		0}
+
+	// Edits from other users are rejected
+	if e.checkEdit(chID, msgID, other.PubKey, textMarshaled) {
+		t.Errorf("Edit from another user accepted.")
+	}
+
+	// Edits to messages in another channel are rejected
+	otherChID, _ := id.NewRandomID(prng, id.User)
+	if e.checkEdit(otherChID, msgID, pi.PubKey, textMarshaled) {
+		t.Errorf("Edit to message in another channel accepted.")
+	}
+
+	// Edits from the original sender are accepted
+	if !e.checkEdit(chID, msgID, pi.PubKey, textMarshaled) {
+		t.Errorf("Edit from original sender rejected.")
+	}
+
+	// Call the handler
+	e.receiveEdit(chID, msgID, Edit, senderUsername, textMarshaled, nil,
+		pi.PubKey, 0, pi.CodesetVersion, ts.Add(time.Minute), ts, lease, r.ID,
+		r, Delivered, false, false)
+
+	// Check the results on the model
+	expected := eventReceive{chID, targetMessageID, message.ID{},
+		senderUsername, []byte("edited"), ts.Add(time.Minute), lease, r,
+		Delivered, false, false, Text, 0, 0}
+	if !reflect.DeepEqual(expected, me.eventReceive) {
+		t.Errorf("Did not receive expected values."+
+			"\nexpected: %+v\nreceived: %+v", expected, me.eventReceive)
+	}
+}
+
+// Tests that events.receivePoll passes valid polls to the event model as JSON,
+// that events.receiveVote drops votes for options that do not exist, and that
+// events.receiveClosePoll passes on the closing time.
+func Test_events_receivePoll_Vote_ClosePoll(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marshal := func(m proto.Message) []byte {
+		data, err2 := proto.Marshal(m)
+		if err2 != nil {
+			t.Fatalf("Failed to proto marshal %T: %+v", m, err2)
+		}
+		return data
+	}
+
+	// Invalid polls are dropped
+	invalid := marshal(&CMIXChannelPoll{Question: "?", Options: []string{"a"}})
+	pollID := message.DeriveChannelMessageID(chID, uint64(r.ID), invalid)
+	e.receivePoll(chID, pollID, Poll, "Alice", invalid, nil, pi.PubKey, 0,
+		pi.CodesetVersion, ts, ts, lease, r.ID, r, Delivered, false, false)
+	if me.messageType == Poll {
+		t.Errorf("Invalid poll was passed to the event model.")
+	}
+
+	poll := &CMIXChannelPoll{Question: "Pizza?", Options: []string{"Yes", "No"}}
+	pollMarshaled := marshal(poll)
+	pollID = message.DeriveChannelMessageID(chID, uint64(r.ID), pollMarshaled)
+	e.receivePoll(chID, pollID, Poll, "Alice", pollMarshaled, nil, pi.PubKey,
+		0, pi.CodesetVersion, ts, ts, lease, r.ID, r, Delivered, false, false)
+	if me.messageType != Poll {
+		t.Fatalf("Poll not passed to the event model.")
+	}
+	received := &CMIXChannelPoll{}
+	if err = json.Unmarshal(me.content, received); err != nil {
+		t.Fatalf("Failed to JSON unmarshal poll: %+v", err)
+	} else if !proto.Equal(poll, received) {
+		t.Errorf("Unexpected poll.\nexpected: %+v\nreceived: %+v",
+			poll, received)
+	}
+
+	votes := []struct {
+		pollID message.ID
+		option uint32
+		valid  bool
+	}{
+		{pollID, 1, true},
+		{pollID, 2, false},
+		{pollID, 0, true},
+	}
+	for j, v := range votes {
+		voteMarshaled := marshal(&CMIXChannelVote{
+			PollMessageID: v.pollID.Marshal(), Option: v.option})
+		voteID := message.DeriveChannelMessageID(chID, uint64(j), voteMarshaled)
+		e.receiveVote(chID, voteID, Vote, "Bob", voteMarshaled, nil, pi.PubKey,
+			0, pi.CodesetVersion, ts, ts, lease, r.ID, r, Delivered, false,
+			false)
+
+		option, exists := me.votes[voteID]
+		if v.valid && (!exists || option != v.option) {
+			t.Errorf("Vote %d not received.", j)
+		} else if !v.valid && exists {
+			t.Errorf("Invalid vote %d received.", j)
+		}
+	}
+
+	closingTime := ts.Add(time.Hour).Round(0)
+	closeMarshaled := marshal(&CMIXChannelClosePoll{
+		PollMessageID: pollID.Marshal(), ClosingTime: closingTime.UnixNano()})
+	closeID := message.DeriveChannelMessageID(chID, uint64(r.ID), closeMarshaled)
+	e.receiveClosePoll(chID, closeID, ClosePoll, AdminUsername, closeMarshaled,
+		nil, pi.PubKey, 0, pi.CodesetVersion, ts, ts, lease, r.ID, r,
+		Delivered, true, false)
+	if !me.closingTime.Equal(closingTime) {
+		t.Errorf("Unexpected closing time.\nexpected: %s\nreceived: %s",
+			closingTime, me.closingTime)
+	}
+}
+
+// Tests that events.receiveModerator grants and revokes moderator permissions.
+func Test_events_receiveModerator(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(65))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	moderator, _, _ := ed25519.GenerateKey(prng)
+	r := rounds.Round{ID: 419,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+
+	receive := func(permissions ModeratorPermission, undoAction bool) {
+		// Each command must be in a newer round to pass the replay blocker
+		r.ID++
+		payload, err := proto.Marshal(&CMIXChannelModerator{
+			PubKey:      moderator,
+			Permissions: uint32(permissions),
+			UndoAction:  undoAction,
+		})
+		if err != nil {
+			t.Fatalf("Failed to proto marshal: %+v", err)
+		}
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), payload)
+		e.receiveModerator(chID, msgID, Moderator, AdminUsername, payload,
+			nil, AdminFakePubKey, 0, 0, ts, ts, lease, r.ID, r, Delivered,
+			true, false)
+	}
+
+	receive(PinPermission|DeletePermission, false)
+	for action, expected := range map[MessageType]bool{
+		Pinned: true, Delete: true, Mute: false, Text: false} {
+		if e.moderators.hasPermission(chID, moderator, action, ts) != expected {
+			t.Errorf("Unexpected permission for %s.\nexpected: %t",
+				action, expected)
+		}
+	}
+	if e.moderators.hasPermission(
+		chID, moderator, Pinned, ts.Add(lease)) {
+		t.Errorf("Moderator has permission after the grant expired.")
+	}
+
+	grants := e.moderators.getModerators(chID)
+	if len(grants) != 1 || !bytes.Equal(grants[0].PubKey, moderator) ||
+		!grants[0].Expiry.Equal(ts.Add(lease)) {
+		t.Errorf("Unexpected moderators: %+v", grants)
+	}
+
+	receive(0, true)
+	if e.moderators.hasPermission(chID, moderator, Pinned, ts) {
+		t.Errorf("Moderator has permission after being revoked.")
+	}
+}
+
+// Tests that events.triggerEvent only passes a user action to an admin-only
+// handler when the sender is a moderator with permission for the action.
+func Test_events_triggerEvent_Moderator(t *testing.T) {
+	e := initEvents(&MockEvent{}, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID := &id.ID{1}
+	umi, usrMsg, _ := builtTestUMI(t, Pinned)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	trigger := func() error {
+		_, err := e.triggerEvent(chID, umi, nil, netTime.Now(),
+			receptionID.EphemeralIdentity{}, r, Delivered)
+		return err
+	}
+
+	if err := trigger(); err == nil {
+		t.Errorf("No error for pin from user who is not a moderator.")
+	}
+
+	e.moderators.grant(chID, usrMsg.ECCPublicKey, MutePermission, time.Time{})
+	if err := trigger(); err == nil {
+		t.Errorf("No error for pin from moderator without permission.")
+	}
+
+	e.moderators.grant(chID, usrMsg.ECCPublicKey, PinPermission,
+		netTime.Now().Add(-time.Minute))
+	if err := trigger(); err == nil {
+		t.Errorf("No error for pin from moderator with expired grant.")
+	}
+
+	// The expiry is checked against the round timestamp, so backdating the
+	// message does not extend the grant
+	_, err := e.triggerEvent(chID, umi, nil, netTime.Now().Add(-time.Hour),
+		receptionID.EphemeralIdentity{}, r, Delivered)
+	if err == nil {
+		t.Errorf("No error for backdated pin from moderator with expired " +
+			"grant.")
+	}
+
+	e.moderators.grant(chID, usrMsg.ECCPublicKey, PinPermission, time.Time{})
+	if err := trigger(); err != nil {
+		t.Errorf("Failed to trigger pin from moderator: %+v", err)
+	}
+
+	e.mutedUsers.muteUser(chID, usrMsg.ECCPublicKey)
+	if err := trigger(); err == nil {
+		t.Errorf("No error for pin from muted moderator.")
+	}
+}
+
+// Tests that events.receiveSlowMode enables and disables slow mode.
+func Test_events_receiveSlowMode(t *testing.T) {
+	me, prng := &MockEvent{}, rand.New(rand.NewSource(89))
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID, _ := id.NewRandomID(prng, id.User)
+	r := rounds.Round{ID: 419,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now()
+	lease := 69 * time.Minute
+
+	receive := func(interval time.Duration, undoAction bool) {
+		// Each command must be in a newer round to pass the replay blocker
+		r.ID++
+		payload, err := proto.Marshal(&CMIXChannelSlowMode{
+			Interval:   int64(interval),
+			UndoAction: undoAction,
+		})
+		if err != nil {
+			t.Fatalf("Failed to proto marshal: %+v", err)
+		}
+		msgID := message.DeriveChannelMessageID(chID, uint64(r.ID), payload)
+		e.receiveSlowMode(chID, msgID, SlowMode, AdminUsername, payload,
+			nil, AdminFakePubKey, 0, 0, ts, ts, lease, r.ID, r, Delivered,
+			true, false)
+	}
+
+	receive(0, false)
+	if interval := e.slowMode.getInterval(chID); interval != 0 {
+		t.Errorf("Slow mode enabled with invalid interval %s.", interval)
+	}
+
+	receive(time.Minute, false)
+	if interval := e.slowMode.getInterval(chID); interval != time.Minute {
+		t.Errorf("Unexpected slow mode interval.\nexpected: %s\nreceived: %s",
+			time.Minute, interval)
+	}
+
+	receive(5*time.Minute, false)
+	if interval := e.slowMode.getInterval(chID); interval != 5*time.Minute {
+		t.Errorf("Slow mode interval not replaced."+
+			"\nexpected: %s\nreceived: %s", 5*time.Minute, interval)
+	}
+
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+	last := ts.Add(lease - time.Minute)
+	e.slowMode.recordPost(chID, pubKey, Text, last)
+	if e.slowMode.allowPost(chID, pubKey, Text, last.Add(time.Second)) {
+		t.Errorf("Post allowed before the interval elapsed.")
+	}
+	if !e.slowMode.allowPost(chID, pubKey, Text, ts.Add(lease)) {
+		t.Errorf("Post not allowed after slow mode expired.")
+	}
+
+	receive(0, true)
+	if interval := e.slowMode.getInterval(chID); interval != 0 {
+		t.Errorf("Slow mode not disabled; interval is %s.", interval)
+	}
+}
+
+// Tests that events.triggerEvent hides posts received sooner than the slow mode
+// interval after the sender's last post, using the round timestamp instead of
+// the sender's timestamp, and that moderators are exempt.
+func Test_events_triggerEvent_SlowMode(t *testing.T) {
+	e := initEvents(&MockEvent{}, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	var called, hidden bool
+	err := e.RegisterReceiveHandler(FileTransfer, NewReceiveMessageHandler(
+		"dummy", func(_ *id.ID, _ message.ID, _ MessageType, _ string, _,
+			_ []byte, _ ed25519.PublicKey, _ uint32, _ uint8, _, _ time.Time,
+			_ time.Duration, _ id.Round, _ rounds.Round, _ SentStatus,
+			_, h bool) uint64 {
+			called, hidden = true, h
+			return 5
+		}, true, false, false))
+	if err != nil {
+		t.Fatalf("Failed to register handler: %+v", err)
+	}
+
+	chID := &id.ID{1}
+	umi, usrMsg, _ := builtTestUMI(t, FileTransfer)
+	e.slowMode.enable(chID, time.Minute, time.Time{})
+
+	start := netTime.Now()
+	trigger := func(roundTS time.Time) {
+		called, hidden = false, false
+		_, err = e.triggerEvent(chID, umi, nil, start,
+			receptionID.EphemeralIdentity{}, rounds.Round{ID: 42,
+				Timestamps: map[states.Round]time.Time{states.QUEUED: roundTS}},
+			Delivered)
+		if err != nil {
+			t.Fatalf("Failed to trigger event: %+v", err)
+		}
+		if !called {
+			t.Fatalf("Message not passed to the handler.")
+		}
+	}
+
+	tests := []struct {
+		roundTS time.Time
+		hidden  bool
+	}{
+		{start, false},
+		{start.Add(30 * time.Second), true},
+		{start.Add(time.Minute), false},
+	}
+	for i, tt := range tests {
+		trigger(tt.roundTS)
+		if hidden != tt.hidden {
+			t.Errorf("Unexpected hidden status for post at %s (%d)."+
+				"\nexpected: %t\nreceived: %t", tt.roundTS, i, tt.hidden, hidden)
+		}
+	}
+
+	e.moderators.grant(chID, usrMsg.ECCPublicKey, PinPermission, time.Time{})
+	trigger(start.Add(time.Minute + time.Second))
+	if hidden {
+		t.Errorf("Post from moderator hidden in slow mode.")
+	}
+}
+
 // Unit test of events.receiveAdminReplay.
 func Test_events_receiveAdminReplay(t *testing.T) {
 	me, prng := &MockEvent{}, csprng.NewSystemRNG()
@@ -1030,7 +1417,10 @@ var _ EventModel = (*MockEvent)(nil)
 
 // MockEvents adheres to the EventModel interface and is used for testing.
 type MockEvent struct {
-	uuid uint64
+	uuid        uint64
+	pubKey      ed25519.PublicKey
+	votes       map[message.ID]uint32
+	closingTime time.Time
 	eventReceive
 }
 
@@ -1044,9 +1434,10 @@ func (*MockEvent) JoinChannel(*cryptoBroadcast.Channel) {}
 func (*MockEvent) LeaveChannel(*id.ID)                  {}
 func (m *MockEvent) ReceiveMessage(channelID *id.ID,
 	messageID message.ID, nickname, text string,
-	_ ed25519.PublicKey, dmToken uint32, codeset uint8, timestamp time.Time,
+	pubKey ed25519.PublicKey, dmToken uint32, codeset uint8, timestamp time.Time,
 	lease time.Duration, round rounds.Round, messageType MessageType,
 	status SentStatus, hidden bool) uint64 {
+	m.pubKey = pubKey
 	m.eventReceive = eventReceive{
 		channelID:   channelID,
 		messageID:   messageID,
@@ -1199,13 +1590,35 @@ func (m *MockEvent) GetMessage(message.ID) (ModelMessage, error) {
 		Content:         m.eventReceive.content,
 		Type:            m.messageType,
 		Round:           m.round.ID,
-		PubKey:          nil,
+		PubKey:          m.pubKey,
 		CodesetVersion:  m.codeset,
 	}, nil
 }
 
 func (m *MockEvent) MuteUser(*id.ID, ed25519.PublicKey, bool) {}
 
+func (m *MockEvent) EditMessage(_, _ message.ID, text string,
+	timestamp time.Time) (uint64, error) {
+	m.eventReceive.content = []byte(text)
+	m.eventReceive.timestamp = timestamp
+	return m.getUUID(), nil
+}
+
+func (m *MockEvent) ReceiveVote(_, voteID message.ID, _ ed25519.PublicKey,
+	option uint32, _ time.Time) (uint64, error) {
+	if m.votes == nil {
+		m.votes = make(map[message.ID]uint32)
+	}
+	m.votes[voteID] = option
+	return m.getUUID(), nil
+}
+
+func (m *MockEvent) ClosePoll(_ message.ID, closingTime time.Time) (
+	uint64, error) {
+	m.closingTime = closingTime
+	return m.getUUID(), nil
+}
+
 func (m *MockEvent) DeleteMessage(message.ID) error {
 	m.eventReceive = eventReceive{}
 	return nil
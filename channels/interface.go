@@ -187,6 +187,42 @@ type Manager interface {
 		pings []ed25519.PublicKey) (
 		message.ID, rounds.Round, ephemeral.Id, error)
 
+	// SendEdit is used to replace the text of a message previously sent by
+	// this user. Only the original sender of a message may edit it and only
+	// text messages can be edited.
+	//
+	// Clients will drop the edit if they do not recognize the target message
+	// or if it was not sent by the same user.
+	//
+	// Returns EditsUnsupportedErr if the EventModel does not implement
+	// EditEventModel.
+	SendEdit(channelID *id.ID, targetMessage message.ID, newText string,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendPoll is used to send to a channel a poll that members can vote on
+	// using SendVote. Each option must be unique and there must be between 2
+	// and 16 options.
+	//
+	// Returns PollsUnsupportedErr if the EventModel does not implement
+	// PollEventModel.
+	//
+	// See [Manager.SendGeneric] for details on payload size limitations.
+	SendPoll(channelID *id.ID, question string, options []string,
+		validUntil time.Duration, params cmix.CMIXParams) (
+		message.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendVote is used to vote for an option of a poll, where option is the
+	// index of the option in the poll. Only the most recent vote from each user
+	// is counted, so a vote can be changed by voting again.
+	//
+	// Clients will drop the vote if they do not recognize the poll or if the
+	// poll was closed before the vote was sent.
+	//
+	// Returns PollsUnsupportedErr if the EventModel does not implement
+	// PollEventModel.
+	SendVote(channelID *id.ID, pollID message.ID, option uint32,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
 	////////////////////////////////////////////////////////////////////////////
 	// Admin Sending                                                          //
 	////////////////////////////////////////////////////////////////////////////
@@ -213,10 +249,10 @@ type Manager interface {
 		message.ID, rounds.Round, ephemeral.Id, error)
 
 	// DeleteMessage deletes the targeted message from storage. Users may delete
-	// their own messages but only the channel admin can delete other user's
-	// messages. If the user is not an admin of the channel or if they are not
-	// the sender of the targetMessage, then the error NotAnAdminErr is
-	// returned.
+	// their own messages but only the channel admin and moderators with
+	// DeletePermission can delete other user's messages. If the user is not an
+	// admin or moderator of the channel or if they are not the sender of the
+	// targetMessage, then an error is returned.
 	//
 	// Clients will drop the deletion if they do not recognize the target
 	// message.
@@ -225,9 +261,10 @@ type Manager interface {
 		message.ID, rounds.Round, ephemeral.Id, error)
 
 	// PinMessage pins the target message to the top of a channel view for all
-	// users in the specified channel. Only the channel admin can pin user
-	// messages; if the user is not an admin of the channel, then the error
-	// NotAnAdminErr is returned.
+	// users in the specified channel. Only the channel admin and moderators
+	// with PinPermission can pin user messages; if the user is not an admin or
+	// moderator of the channel, then the error NotAnAdminErr is returned.
+	// Pins by moderators last at most MessageLife.
 	//
 	// If undoAction is true, then the targeted message is unpinned. validUntil
 	// is the time the message will be pinned for; set this to ValidForever to
@@ -240,9 +277,10 @@ type Manager interface {
 
 	// MuteUser is used to mute a user in a channel. Muting a user will cause
 	// all future messages from the user being dropped on reception. Muted users
-	// are also unable to send messages. Only the channel admin can mute a user;
-	// if the user is not an admin of the channel, then the error NotAnAdminErr
-	// is returned.
+	// are also unable to send messages. Only the channel admin and moderators
+	// with MutePermission can mute a user; if the user is not an admin or
+	// moderator of the channel, then the error NotAnAdminErr is returned. Mutes
+	// by moderators last at most MessageLife.
 	//
 	// If undoAction is true, then the targeted user will be unmuted. validUntil
 	// is the time the user will be muted for; set this to ValidForever to mute
@@ -251,6 +289,99 @@ type Manager interface {
 		validUntil time.Duration, params cmix.CMIXParams) (
 		message.ID, rounds.Round, ephemeral.Id, error)
 
+	// ClosePoll is used to close a poll so that votes sent at or after the
+	// closing time are no longer counted. Only the channel admin can close a
+	// poll; if the user is not an admin of the channel, then the error
+	// NotAnAdminErr is returned.
+	//
+	// Closing a poll again replaces the previous closing time, so a poll can be
+	// reopened by closing it at a time in the future.
+	//
+	// Returns PollsUnsupportedErr if the EventModel does not implement
+	// PollEventModel.
+	ClosePoll(channelID *id.ID, pollID message.ID, closingTime time.Time,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
+	// GrantModerator grants the user permission to perform the given actions
+	// in the channel on behalf of the admin. The grant expires after
+	// validUntil; set this to ValidForever to grant indefinitely. Granting
+	// permissions to an existing moderator replaces their previous grant.
+	// Only the channel admin can grant moderator permissions; if the user is
+	// not an admin of the channel, then the error NotAnAdminErr is returned.
+	GrantModerator(channelID *id.ID, moderator ed25519.PublicKey,
+		permissions ModeratorPermission, validUntil time.Duration,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
+	// RevokeModerator revokes all moderator permissions of the user in the
+	// channel. Only the channel admin can revoke moderator permissions; if the
+	// user is not an admin of the channel, then the error NotAnAdminErr is
+	// returned.
+	RevokeModerator(channelID *id.ID, moderator ed25519.PublicKey,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
+	// SetSlowMode puts the channel in slow mode, limiting each user to one
+	// post per interval. Posts sent faster are hidden by every client, based on
+	// the round timestamp. Moderators are not limited, and messages sent as the
+	// admin are never limited. Slow mode expires after validUntil; set this to
+	// ValidForever to never expire.
+	// Setting slow mode replaces any previous slow mode in the channel. Only
+	// the channel admin can set slow mode; if the user is not an admin of the
+	// channel, then the error NotAnAdminErr is returned.
+	SetSlowMode(channelID *id.ID, interval, validUntil time.Duration,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+
+	// DisableSlowMode turns off slow mode in the channel. Only the channel
+	// admin can disable slow mode; if the user is not an admin of the channel,
+	// then the error NotAnAdminErr is returned.
+	DisableSlowMode(channelID *id.ID, params cmix.CMIXParams) (
+		message.ID, rounds.Round, ephemeral.Id, error)
+
+	////////////////////////////////////////////////////////////////////////////
+	// Scheduled Sending                                                      //
+	////////////////////////////////////////////////////////////////////////////
+
+	// ScheduleSend schedules a message to be sent to the channel at sendTime
+	// using [Manager.SendGeneric]. Scheduled sends are saved to storage and
+	// survive restarts; sends that become due while the client is offline are
+	// sent once it restarts. If tracked is true, the status of the message is
+	// reported through the sendTracker once it is sent.
+	//
+	// Returns the ID of the scheduled send. Returns ChannelDoesNotExistsErr
+	// if the channel has not been joined.
+	ScheduleSend(channelID *id.ID, messageType MessageType, msg []byte,
+		validUntil time.Duration, tracked bool, sendTime time.Time,
+		params cmix.CMIXParams, pingsMap map[PingType][]ed25519.PublicKey) (
+		uint64, error)
+
+	// ScheduleAdminSend schedules a message to be sent to the channel as the
+	// channel admin at sendTime using [Manager.SendAdminGeneric]. It behaves
+	// the same as ScheduleSend, except it returns NotAnAdminErr if the user is
+	// not an admin of the channel.
+	ScheduleAdminSend(channelID *id.ID, messageType MessageType, msg []byte,
+		validUntil time.Duration, tracked bool, sendTime time.Time,
+		params cmix.CMIXParams) (uint64, error)
+
+	// GetScheduledSends returns the pending and failed scheduled sends to the
+	// channel sorted by send time. If channelID is nil, then the sends for all
+	// channels are returned.
+	GetScheduledSends(channelID *id.ID) []ScheduledSend
+
+	// CancelScheduledSend cancels the pending or failed scheduled send with
+	// the given ID. Returns ScheduledSendNotFoundErr if the send does not
+	// exist, is being sent, or was already sent.
+	CancelScheduledSend(sendID uint64) error
+
+	// RescheduleSend changes the send time of the pending or failed scheduled
+	// send with the given ID. A failed send is sent again at the new time.
+	// Returns ScheduledSendNotFoundErr if the send does not exist, is being
+	// sent, or was already sent.
+	RescheduleSend(sendID uint64, sendTime time.Time) error
+
+	// RegisterScheduledSendFailedCallback registers the callback that is
+	// called when a scheduled send fails. A failed send is kept, with Failed
+	// set, until it is cancelled or rescheduled.
+	RegisterScheduledSendFailedCallback(cb ScheduledSendFailedCallback)
+
 	////////////////////////////////////////////////////////////////////////////
 	// Other Channel Actions                                                  //
 	////////////////////////////////////////////////////////////////////////////
@@ -279,6 +410,19 @@ type Manager interface {
 	RegisterReceiveHandler(
 		messageType MessageType, handler *ReceiveMessageHandler) error
 
+	// RegisterMessageFilter adds a MessageFilter to the end of the filter
+	// chain run on every message received from another user before it is
+	// passed to the EventModel. Each filter can drop, hide, or score the
+	// message.
+	//
+	// Returns MessageFilterAlreadyRegistered if a filter with the same name is
+	// already registered.
+	RegisterMessageFilter(name string, filter MessageFilter) error
+
+	// RemoveMessageFilter removes the MessageFilter with the given name from
+	// the filter chain. Does nothing if no filter with the name is registered.
+	RemoveMessageFilter(name string)
+
 	// SetNickname sets the nickname in a channel after checking that the
 	// nickname is valid using [IsNicknameValid].
 	SetNickname(nickname string, channelID *id.ID) error
@@ -298,6 +442,37 @@ type Manager interface {
 	// an empty list is returned.
 	GetMutedUsers(channelID *id.ID) []ed25519.PublicKey
 
+	// GetModerators returns the moderators of the channel and the permissions
+	// granted to them. Expired grants are not included. If there are no
+	// moderators or if the channel does not exist, an empty list is returned.
+	GetModerators(channelID *id.ID) []ModeratorGrant
+
+	// GetSlowMode returns the minimum duration between posts from each user in
+	// the channel. Returns zero if the channel is not in slow mode.
+	GetSlowMode(channelID *id.ID) time.Duration
+
+	// GetMembers returns every user that has been seen sending messages in
+	// the channel, sorted from most to least recently seen. Changes to members
+	// are reported via [UiCallbacks.MemberUpdate]. Returns
+	// ChannelDoesNotExistsErr if the channel has not been joined.
+	GetMembers(channelID *id.ID) ([]Member, error)
+
+	// MarkRead marks all messages in the channel with a timestamp at or before
+	// the given timestamp as read. The read marker is synced to all the user's
+	// devices and only ever moves forward. Returns ChannelDoesNotExistsErr if
+	// the channel has not been joined.
+	MarkRead(channelID *id.ID, timestamp time.Time) error
+
+	// GetReadMarker returns the timestamp of the last read message in the
+	// channel. Returns false if no messages in the channel have been marked
+	// read.
+	GetReadMarker(channelID *id.ID) (time.Time, bool)
+
+	// GetUnreadCount returns the number of messages in the channel received
+	// after the read marker. Returns ReadMarkersUnsupportedErr if the
+	// EventModel does not implement ReadMarkerEventModel.
+	GetUnreadCount(channelID *id.ID) (uint64, error)
+
 	// GetNotificationLevel returns the notification level for the given channel.
 	GetNotificationLevel(channelID *id.ID) (NotificationLevel, error)
 
@@ -512,4 +687,13 @@ type UiCallbacks interface {
 
 	// MessageDeleted is called every time a message is deleted.
 	MessageDeleted(messageID message.ID)
+
+	// MemberUpdate is called when a new member is seen in a channel or when
+	// the nickname, DM token, codeset, or muted state of a member changes.
+	MemberUpdate(channelID *id.ID, member Member)
+
+	// ReadMarkerUpdate is called when the read marker of a channel is loaded
+	// or advances on this or another of the user's devices. All messages with
+	// a timestamp at or before lastRead have been read.
+	ReadMarkerUpdate(channelID *id.ID, lastRead time.Time)
 }
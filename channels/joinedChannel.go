@@ -192,6 +192,26 @@ func (m *manager) removeChannelUnsafe(channelID *id.ID) error {
 		return err
 	}
 
+	err = m.moderators.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+
+	err = m.slowMode.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+
+	err = m.roster.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+
+	err = m.readMarkers.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+
 	err = m.leases.deleteLeaseMessages(channelID)
 	if err != nil {
 		return err
@@ -201,6 +221,11 @@ func (m *manager) removeChannelUnsafe(channelID *id.ID) error {
 
 	m.events.leases.RemoveChannel(channelID)
 
+	err = m.scheduled.removeChannel(channelID)
+	if err != nil {
+		return err
+	}
+
 	delete(m.channels, *channelID)
 
 	_, err = m.remote.DeleteMapElement(joinedChannelsMap,
@@ -299,6 +324,7 @@ func (m *manager) registerListeners(broadcastChan broadcast.Channel,
 		chID:      channel.ReceptionID,
 		trigger:   m.events.triggerEvent,
 		checkSent: m.st.MessageReceive,
+		checkEdit: m.events.checkEdit,
 	}).Listen, nil)
 	if err != nil {
 		return nil, err
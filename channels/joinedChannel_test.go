@@ -598,6 +598,21 @@ func (m *mockEventModel) DeleteMessage(cryptoMessage.ID) error {
 	panic("implement me")
 }
 
+func (m *mockEventModel) EditMessage(cryptoMessage.ID, cryptoMessage.ID,
+	string, time.Time) (uint64, error) {
+	panic("implement me")
+}
+
+func (m *mockEventModel) ReceiveVote(cryptoMessage.ID, cryptoMessage.ID,
+	ed25519.PublicKey, uint32, time.Time) (uint64, error) {
+	panic("implement me")
+}
+
+func (m *mockEventModel) ClosePoll(
+	cryptoMessage.ID, time.Time) (uint64, error) {
+	panic("implement me")
+}
+
 func (m *mockEventModel) MuteUser(*id.ID, ed25519.PublicKey, bool) {
 	panic("implement me")
 }
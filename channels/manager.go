@@ -67,6 +67,13 @@ type manager struct {
 	// Send tracker
 	st *sendTracker
 
+	// Messages scheduled to be sent later
+	scheduled *scheduledSendList
+
+	// Read markers synced between devices
+	readMarkers        *readMarkerManager
+	readMarkerCallback func(channelID *id.ID, lastRead time.Time)
+
 	// Makes the function that is used to create broadcasts be a pointer so that
 	// it can be replaced in tests
 	broadcastMaker broadcast.NewBroadcastChannelFunc
@@ -169,42 +176,50 @@ func NewManager(identity cryptoChannel.PrivateIdentity, kv versioned.KV,
 		nm, uiCallbacks)
 	m.dmTokens = make(map[id.ID]uint32)
 
-	return m, addService(m.leases.StartProcesses)
+	return m, m.startProcesses(addService)
 }
 
 // LoadManager restores a channel Manager from disk stored at the given storage
-// tag.
+// tag. The returned Manager does not send scheduled messages; a warning is
+// logged if any are pending. Use LoadManagerWithScheduledSends to send them.
 func LoadManager(storageTag string, kv versioned.KV, net Client,
 	rng *fastRNG.StreamGenerator, model EventModel,
 	extensions []ExtensionBuilder, nm NotificationsManager,
 	uiCallbacks UiCallbacks) (Manager, error) {
-	jww.INFO.Printf("[CH] LoadManager for tag %s", storageTag)
-
-	// Prefix the local with the username so multiple can be run
-	local, err := kv.Prefix(storageTag)
+	m, err := loadManager(storageTag, kv, net, rng, model, extensions, nm,
+		uiCallbacks)
 	if err != nil {
 		return nil, err
 	}
 
-	remote, err := kv.Prefix(collective.StandardRemoteSyncPrefix)
-	if err != nil {
-		return nil, err
+	if pending := m.scheduled.pending(); pending > 0 {
+		jww.WARN.Printf("[CH] Loaded %d pending scheduled sends for tag %s "+
+			"that will not be sent. Load the manager with "+
+			"LoadManagerWithScheduledSends to send them.", pending, storageTag)
 	}
 
-	// Load the identity
-	identity, err := loadIdentity(remote)
+	return m, nil
+}
+
+// LoadManagerWithScheduledSends restores a channel Manager from disk stored at
+// the given storage tag, the same as LoadManager, and adds the thread that
+// sends scheduled messages to be controlled by addService. Scheduled sends are
+// only sent by a Manager loaded with this function or created with NewManager.
+func LoadManagerWithScheduledSends(storageTag string, kv versioned.KV,
+	net Client, rng *fastRNG.StreamGenerator, model EventModel,
+	extensions []ExtensionBuilder, addService AddServiceFn,
+	nm NotificationsManager, uiCallbacks UiCallbacks) (Manager, error) {
+	m, err := loadManager(storageTag, kv, net, rng, model, extensions, nm,
+		uiCallbacks)
 	if err != nil {
 		return nil, err
 	}
 
-	m := setupManager(identity, local, remote, net, rng, model, extensions, nm,
-		uiCallbacks)
-
-	return m, nil
+	return m, addService(m.scheduled.StartProcesses)
 }
 
 // LoadManagerBuilder restores a channel Manager from disk stored at the given storage
-// tag.
+// tag. Like LoadManager, it does not send scheduled messages.
 func LoadManagerBuilder(storageTag string, kv versioned.KV, net Client,
 	rng *fastRNG.StreamGenerator, modelBuilder EventModelBuilder,
 	extensions []ExtensionBuilder, nm NotificationsManager,
@@ -217,6 +232,50 @@ func LoadManagerBuilder(storageTag string, kv versioned.KV, net Client,
 	return LoadManager(storageTag, kv, net, rng, model, extensions, nm, uiCallbacks)
 }
 
+// LoadManagerBuilderWithScheduledSends restores a channel Manager from disk
+// stored at the given storage tag using an EventModelBuilder. It is the same as
+// LoadManagerWithScheduledSends.
+func LoadManagerBuilderWithScheduledSends(storageTag string, kv versioned.KV,
+	net Client, rng *fastRNG.StreamGenerator, modelBuilder EventModelBuilder,
+	extensions []ExtensionBuilder, addService AddServiceFn,
+	nm NotificationsManager, uiCallbacks UiCallbacks) (Manager, error) {
+	model, err := modelBuilder(storageTag)
+	if err != nil {
+		return nil, errors.Errorf("Failed to build event model: %+v", err)
+	}
+
+	return LoadManagerWithScheduledSends(storageTag, kv, net, rng, model,
+		extensions, addService, nm, uiCallbacks)
+}
+
+// loadManager restores the manager from disk stored at the given storage tag.
+func loadManager(storageTag string, kv versioned.KV, net Client,
+	rng *fastRNG.StreamGenerator, model EventModel,
+	extensions []ExtensionBuilder, nm NotificationsManager,
+	uiCallbacks UiCallbacks) (*manager, error) {
+	jww.INFO.Printf("[CH] LoadManager for tag %s", storageTag)
+
+	// Prefix the local with the username so multiple can be run
+	local, err := kv.Prefix(storageTag)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := kv.Prefix(collective.StandardRemoteSyncPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the identity
+	identity, err := loadIdentity(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return setupManager(identity, local, remote, net, rng, model, extensions,
+		nm, uiCallbacks), nil
+}
+
 func setupManager(identity cryptoChannel.PrivateIdentity, local, remote versioned.KV,
 	net Client, rng *fastRNG.StreamGenerator, model EventModel,
 	extensionBuilders []ExtensionBuilder, nm NotificationsManager,
@@ -241,14 +300,25 @@ func setupManager(identity cryptoChannel.PrivateIdentity, local, remote versione
 	}
 
 	m.events.leases.RegisterReplayFn(m.adminReplayHandler)
+	m.events.roster.RegisterCallback(uiCallbacks.MemberUpdate)
 
 	m.st = loadSendTracker(net, local, m.events.triggerEvent,
 		m.events.triggerAdminEvent, model.UpdateFromUUID, rng)
 
+	var err error
+	m.scheduled, err = newOrLoadScheduledSendList(local)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Failed to load scheduled sends: %+v", err)
+	}
+	m.scheduled.RegisterSendFn(m.sendScheduled)
+
 	m.loadChannels()
 
 	m.nicknameManager = loadOrNewNicknameManager(remote, uiCallbacks.NicknameUpdate)
 
+	m.readMarkerCallback = uiCallbacks.ReadMarkerUpdate
+	m.readMarkers = loadOrNewReadMarkerManager(remote, m.readMarkerUpdate)
+
 	// Activate all extensions
 	var extensions []ExtensionMessageHandler
 	for i := range extensionBuilders {
@@ -279,6 +349,15 @@ func setupManager(identity cryptoChannel.PrivateIdentity, local, remote versione
 	return m
 }
 
+// startProcesses adds the threads for the action lease list and scheduled
+// sends to be controlled by the client thread control.
+func (m *manager) startProcesses(addService AddServiceFn) error {
+	if err := addService(m.leases.StartProcesses); err != nil {
+		return err
+	}
+	return addService(m.scheduled.StartProcesses)
+}
+
 // adminReplayHandler registers a ReplayActionFunc with the lease system.
 func (m *manager) adminReplayHandler(channelID *id.ID, encryptedPayload []byte) {
 	messageID, r, _, err := m.replayAdminMessage(
@@ -523,6 +602,32 @@ func (m *manager) GetMutedUsers(channelID *id.ID) []ed25519.PublicKey {
 	return m.mutedUsers.getMutedUsers(channelID)
 }
 
+// GetModerators returns the moderators of the channel and the permissions
+// granted to them. Expired grants are not included. If there are no moderators
+// or if the channel does not exist, an empty list is returned.
+func (m *manager) GetModerators(channelID *id.ID) []ModeratorGrant {
+	jww.INFO.Printf("[CH] GetModerators in channel %s", channelID)
+	return m.moderators.getModerators(channelID)
+}
+
+// GetSlowMode returns the minimum duration between posts from each user in the
+// channel. Returns zero if the channel is not in slow mode.
+func (m *manager) GetSlowMode(channelID *id.ID) time.Duration {
+	jww.INFO.Printf("[CH] GetSlowMode in channel %s", channelID)
+	return m.slowMode.getInterval(channelID)
+}
+
+// GetMembers returns every user that has been seen sending messages in the
+// channel, sorted from most to least recently seen. Returns
+// ChannelDoesNotExistsErr if the channel has not been joined.
+func (m *manager) GetMembers(channelID *id.ID) ([]Member, error) {
+	jww.INFO.Printf("[CH] GetMembers in channel %s", channelID)
+	if _, err := m.getChannel(channelID); err != nil {
+		return nil, err
+	}
+	return m.roster.getMembers(channelID)
+}
+
 // dummyUICallback is an implementation of UI callbacks that does nothing
 // it is used for tests and when nothing is passed in for UI callbacks
 type dummyUICallback struct{}
@@ -559,3 +664,11 @@ func (duiCB *dummyUICallback) UserMuted(*id.ID, ed25519.PublicKey, bool) {
 func (duiCB *dummyUICallback) MessageDeleted(cryptoMessage.ID) {
 	jww.DEBUG.Printf("MessageDeleted unimplemented in %T", duiCB)
 }
+
+func (duiCB *dummyUICallback) MemberUpdate(*id.ID, Member) {
+	jww.DEBUG.Printf("MemberUpdate unimplemented in %T", duiCB)
+}
+
+func (duiCB *dummyUICallback) ReadMarkerUpdate(*id.ID, time.Time) {
+	jww.DEBUG.Printf("ReadMarkerUpdate unimplemented in %T", duiCB)
+}
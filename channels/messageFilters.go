@@ -0,0 +1,237 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	jww "github.com/spf13/jwalterweatherman"
+
+	cryptoChannel "gitlab.com/elixxir/crypto/channel"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// FilterAction is the action a MessageFilter takes on a received message.
+type FilterAction uint8
+
+const (
+	// FilterAccept passes the message on to the next filter in the chain.
+	FilterAccept FilterAction = iota
+
+	// FilterHide passes the message on to the EventModel as hidden. The
+	// remaining filters in the chain are still run so that they can add to the
+	// score of the message.
+	FilterHide
+
+	// FilterDrop drops the message. It is never passed to the EventModel and
+	// the remaining filters in the chain are not run.
+	FilterDrop
+)
+
+// String returns a human-readable version of the FilterAction. Used for
+// debugging and logging. This function adheres to the fmt.Stringer interface.
+func (fa FilterAction) String() string {
+	switch fa {
+	case FilterAccept:
+		return "accept"
+	case FilterHide:
+		return "hide"
+	case FilterDrop:
+		return "drop"
+	default:
+		return "INVALID FILTER ACTION: " + strconv.Itoa(int(fa))
+	}
+}
+
+// FilterMessage contains the details of a received message that are passed to
+// each MessageFilter.
+type FilterMessage struct {
+	ChannelID   *id.ID      `json:"channelID"`
+	MessageID   message.ID  `json:"messageID"`
+	MessageType MessageType `json:"messageType"`
+
+	// Nickname is the nickname the sender set on the message. It may be empty.
+	Nickname string `json:"nickname"`
+
+	// Codename is the codename generated from the sender's public key.
+	Codename string            `json:"codename"`
+	PubKey   ed25519.PublicKey `json:"pubKey"`
+
+	// Content is the payload of the message as passed to the message handler.
+	Content []byte `json:"content"`
+
+	// Text is the decoded text of Text messages. It is empty for all other
+	// message types.
+	Text string `json:"text"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// FilterResult is the result of a MessageFilter run on a received message.
+type FilterResult struct {
+	// Action is the action to take on the message.
+	Action FilterAction `json:"action"`
+
+	// Score is added to the score of the message. The total score from all
+	// filters is passed to the EventModel if it implements ScoredEventModel.
+	Score int32 `json:"score"`
+}
+
+// MessageFilter is called on every message received from another user in a
+// channel before it is passed to the EventModel. It can be used to drop, hide,
+// or score messages, such as with local word lists or spam detection.
+//
+// Filters are called in the order they were registered and must not block.
+type MessageFilter func(msg FilterMessage) FilterResult
+
+// ScoredEventModel is an EventModel that stores the score given to received
+// messages by the registered MessageFilter chain. If the EventModel passed to
+// the Manager implements this interface, then the total score of every stored
+// message with a non-zero score is passed to it.
+type ScoredEventModel interface {
+	EventModel
+
+	// UpdateScore is called after a received message is stored by the
+	// EventModel with the total score given to it by all filters.
+	UpdateScore(uuid uint64, score int32) error
+}
+
+// namedFilter is a MessageFilter in the filterChain.
+type namedFilter struct {
+	name   string
+	filter MessageFilter
+}
+
+// filterChain is the ordered list of MessageFilter run on received messages.
+type filterChain struct {
+	filters []namedFilter
+	mux     sync.RWMutex
+}
+
+// newFilterChain initialises an empty filterChain.
+func newFilterChain() *filterChain {
+	return &filterChain{filters: []namedFilter{}}
+}
+
+// add appends the filter to the end of the chain. Returns
+// MessageFilterAlreadyRegistered if a filter with the same name exists.
+func (fc *filterChain) add(name string, filter MessageFilter) error {
+	fc.mux.Lock()
+	defer fc.mux.Unlock()
+
+	for _, nf := range fc.filters {
+		if nf.name == name {
+			return MessageFilterAlreadyRegistered
+		}
+	}
+
+	fc.filters = append(fc.filters, namedFilter{name, filter})
+	return nil
+}
+
+// remove removes the filter with the given name from the chain. Does nothing
+// if no filter with the name exists.
+func (fc *filterChain) remove(name string) {
+	fc.mux.Lock()
+	defer fc.mux.Unlock()
+
+	for i, nf := range fc.filters {
+		if nf.name == name {
+			fc.filters = append(fc.filters[:i:i], fc.filters[i+1:]...)
+			return
+		}
+	}
+}
+
+// run calls each filter in the chain on the message and returns the combined
+// result. The action of the result is the strictest action returned by any
+// filter and the score is the sum of all scores. Returns immediately if a
+// filter drops the message.
+func (fc *filterChain) run(msg FilterMessage) FilterResult {
+	fc.mux.RLock()
+	filters := fc.filters
+	fc.mux.RUnlock()
+
+	result := FilterResult{Action: FilterAccept}
+	for _, nf := range filters {
+		fr := nf.filter(msg)
+		result.Score += fr.Score
+		if fr.Action > result.Action {
+			result.Action = fr.Action
+		}
+
+		if result.Action == FilterDrop {
+			jww.DEBUG.Printf("[CH] Filter %q dropped message %s on channel %s",
+				nf.name, msg.MessageID, msg.ChannelID)
+			break
+		}
+	}
+
+	return result
+}
+
+// isEmpty returns true if no filters are registered.
+func (fc *filterChain) isEmpty() bool {
+	fc.mux.RLock()
+	defer fc.mux.RUnlock()
+	return len(fc.filters) == 0
+}
+
+// makeFilterMessage builds the FilterMessage for the message. The codename is
+// left empty if it cannot be generated from the public key.
+func makeFilterMessage(channelID *id.ID, messageID message.ID,
+	messageType MessageType, nickname string, content []byte,
+	pubKey ed25519.PublicKey, codeset uint8, timestamp time.Time) FilterMessage {
+	msg := FilterMessage{
+		ChannelID:   channelID,
+		MessageID:   messageID,
+		MessageType: messageType,
+		Nickname:    nickname,
+		PubKey:      pubKey,
+		Content:     content,
+		Timestamp:   timestamp,
+	}
+
+	identity, err := cryptoChannel.ConstructIdentity(pubKey, codeset)
+	if err != nil {
+		jww.WARN.Printf("[CH] Failed to construct codename for message %s "+
+			"on channel %s to filter: %+v", messageID, channelID, err)
+	} else {
+		msg.Codename = identity.Codename
+	}
+
+	if messageType == Text {
+		txt := &CMIXChannelText{}
+		if err = proto.Unmarshal(content, txt); err == nil {
+			msg.Text = txt.Text
+		}
+	}
+
+	return msg
+}
+
+// RegisterMessageFilter adds a MessageFilter to the end of the filter chain
+// run on every message received from another user before it is passed to the
+// EventModel. Returns MessageFilterAlreadyRegistered if a filter with the same
+// name is already registered.
+func (e *events) RegisterMessageFilter(name string, filter MessageFilter) error {
+	jww.INFO.Printf("[CH] RegisterMessageFilter %q", name)
+	return e.filters.add(name, filter)
+}
+
+// RemoveMessageFilter removes the MessageFilter with the given name from the
+// filter chain. Does nothing if no filter with the name is registered.
+func (e *events) RemoveMessageFilter(name string) {
+	jww.INFO.Printf("[CH] RemoveMessageFilter %q", name)
+	e.filters.remove(name)
+}
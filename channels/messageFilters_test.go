@@ -0,0 +1,259 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/elixxir/primitives/states"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that filterChain.add returns MessageFilterAlreadyRegistered for a
+// duplicate name and that filterChain.remove removes the filter.
+func Test_filterChain_add_remove(t *testing.T) {
+	fc := newFilterChain()
+	accept := func(FilterMessage) FilterResult { return FilterResult{} }
+
+	if err := fc.add("a", accept); err != nil {
+		t.Fatalf("Failed to add filter: %+v", err)
+	}
+	if err := fc.add("b", accept); err != nil {
+		t.Fatalf("Failed to add filter: %+v", err)
+	}
+	if err := fc.add("a", accept); err != MessageFilterAlreadyRegistered {
+		t.Errorf("Unexpected error for duplicate filter."+
+			"\nexpected: %v\nreceived: %+v", MessageFilterAlreadyRegistered, err)
+	}
+
+	fc.remove("a")
+	fc.remove("c")
+	if len(fc.filters) != 1 || fc.filters[0].name != "b" {
+		t.Errorf("Unexpected filters after removal: %+v", fc.filters)
+	}
+
+	fc.remove("b")
+	if !fc.isEmpty() {
+		t.Errorf("Filter chain not empty after removing all filters.")
+	}
+}
+
+// Tests that filterChain.run calls the filters in order, sums their scores,
+// keeps the strictest action, and stops at the first dropping filter.
+func Test_filterChain_run(t *testing.T) {
+	fc := newFilterChain()
+	var called []string
+	makeFilter := func(name string, fr FilterResult) MessageFilter {
+		return func(FilterMessage) FilterResult {
+			called = append(called, name)
+			return fr
+		}
+	}
+
+	_ = fc.add("score", makeFilter("score", FilterResult{FilterAccept, 3}))
+	_ = fc.add("hide", makeFilter("hide", FilterResult{FilterHide, 4}))
+	_ = fc.add("accept", makeFilter("accept", FilterResult{FilterAccept, -2}))
+
+	result := fc.run(FilterMessage{})
+	expected := FilterResult{FilterHide, 5}
+	if result != expected {
+		t.Errorf("Unexpected result.\nexpected: %+v\nreceived: %+v",
+			expected, result)
+	}
+	if len(called) != 3 {
+		t.Errorf("Unexpected filters called: %v", called)
+	}
+
+	called = nil
+	_ = fc.add("drop", makeFilter("drop", FilterResult{FilterDrop, 1}))
+	_ = fc.add("never", makeFilter("never", FilterResult{FilterAccept, 100}))
+	result = fc.run(FilterMessage{})
+	if result.Action != FilterDrop {
+		t.Errorf("Unexpected action.\nexpected: %s\nreceived: %s",
+			FilterDrop, result.Action)
+	}
+	if len(called) != 4 || called[3] != "drop" {
+		t.Errorf("Filters after the dropping filter were called: %v", called)
+	}
+}
+
+// Tests that makeFilterMessage generates the codename of the sender and
+// decodes the text of Text messages.
+func Test_makeFilterMessage(t *testing.T) {
+	pubKey, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+	content, err := proto.Marshal(&CMIXChannelText{Text: "hello"})
+	if err != nil {
+		t.Fatalf("Failed to marshal text: %+v", err)
+	}
+
+	msg := makeFilterMessage(&id.ID{1}, message.ID{2}, Text, "nick", content,
+		pubKey, 0, netTime.Now())
+	if msg.Codename == "" {
+		t.Errorf("Codename not generated.")
+	}
+	if msg.Text != "hello" {
+		t.Errorf("Unexpected text.\nexpected: %q\nreceived: %q",
+			"hello", msg.Text)
+	}
+
+	msg = makeFilterMessage(&id.ID{1}, message.ID{2}, Reaction, "nick",
+		content, pubKey, 0, netTime.Now())
+	if msg.Text != "" {
+		t.Errorf("Text decoded for non-text message: %q", msg.Text)
+	}
+}
+
+// Tests that events.triggerEvent drops, hides, and scores received messages
+// using the registered filters and does not filter messages sent by this user.
+func Test_events_triggerEvent_MessageFilter(t *testing.T) {
+	me := &mockScoredEvent{scores: make(map[uint64]int32)}
+	e := initEvents(me, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	var called, hidden bool
+	mt := MessageType(42)
+	err := e.RegisterReceiveHandler(mt, NewReceiveMessageHandler("dummy",
+		func(_ *id.ID, _ message.ID, _ MessageType, _ string, _, _ []byte,
+			_ ed25519.PublicKey, _ uint32, _ uint8, _, _ time.Time,
+			_ time.Duration, _ id.Round, _ rounds.Round, _ SentStatus,
+			_, h bool) uint64 {
+			called, hidden = true, h
+			return 5
+		}, true, false, false))
+	if err != nil {
+		t.Fatalf("Failed to register handler: %+v", err)
+	}
+
+	action := FilterAccept
+	err = e.RegisterMessageFilter("test", func(msg FilterMessage) FilterResult {
+		return FilterResult{action, int32(len(msg.Nickname))}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register filter: %+v", err)
+	}
+
+	chID := &id.ID{1}
+	umi, _, _ := builtTestUMI(t, mt)
+	tests := []struct {
+		action         FilterAction
+		status         SentStatus
+		called, hidden bool
+	}{
+		{FilterAccept, Delivered, true, false},
+		{FilterHide, Delivered, true, true},
+		{FilterDrop, Delivered, false, false},
+		{FilterDrop, Unsent, true, false},
+	}
+
+	for i, tt := range tests {
+		action, called, hidden = tt.action, false, false
+		delete(me.scores, 5)
+		_, err = e.triggerEvent(chID, umi, nil, netTime.Now(),
+			receptionID.EphemeralIdentity{}, rounds.Round{ID: 42}, tt.status)
+		if err != nil {
+			t.Fatalf("Failed to trigger event (%d): %+v", i, err)
+		}
+
+		if called != tt.called || hidden != tt.hidden {
+			t.Errorf("Unexpected handling of message with filter action %s "+
+				"and status %s (%d).\nexpected: called=%t hidden=%t"+
+				"\nreceived: called=%t hidden=%t", tt.action, tt.status, i,
+				tt.called, tt.hidden, called, hidden)
+		}
+
+		expectedScore := int32(0)
+		if tt.called && tt.status == Delivered {
+			expectedScore = int32(len(umi.GetChannelMessage().Nickname))
+		}
+		if me.scores[5] != expectedScore {
+			t.Errorf("Unexpected score (%d).\nexpected: %d\nreceived: %d",
+				i, expectedScore, me.scores[5])
+		}
+	}
+}
+
+// Tests that events.triggerEvent does not record a post dropped by the filters
+// in slow mode, so that it does not cause the sender's next post to be hidden.
+func Test_events_triggerEvent_MessageFilter_SlowMode(t *testing.T) {
+	e := initEvents(&MockEvent{}, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	var called, hidden bool
+	err := e.RegisterReceiveHandler(FileTransfer, NewReceiveMessageHandler(
+		"dummy", func(_ *id.ID, _ message.ID, _ MessageType, _ string, _,
+			_ []byte, _ ed25519.PublicKey, _ uint32, _ uint8, _, _ time.Time,
+			_ time.Duration, _ id.Round, _ rounds.Round, _ SentStatus,
+			_, h bool) uint64 {
+			called, hidden = true, h
+			return 5
+		}, true, false, false))
+	if err != nil {
+		t.Fatalf("Failed to register handler: %+v", err)
+	}
+
+	action := FilterDrop
+	err = e.RegisterMessageFilter("test", func(FilterMessage) FilterResult {
+		return FilterResult{Action: action}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register filter: %+v", err)
+	}
+
+	chID := &id.ID{1}
+	umi, _, _ := builtTestUMI(t, FileTransfer)
+	e.slowMode.enable(chID, time.Minute, time.Time{})
+
+	start := netTime.Now()
+	trigger := func(roundTS time.Time) {
+		called, hidden = false, false
+		_, err = e.triggerEvent(chID, umi, nil, start,
+			receptionID.EphemeralIdentity{}, rounds.Round{ID: 42,
+				Timestamps: map[states.Round]time.Time{states.QUEUED: roundTS}},
+			Delivered)
+		if err != nil {
+			t.Fatalf("Failed to trigger event: %+v", err)
+		}
+	}
+
+	trigger(start)
+	if called {
+		t.Fatalf("Dropped message passed to the handler.")
+	}
+
+	action = FilterAccept
+	trigger(start.Add(30 * time.Second))
+	if !called || hidden {
+		t.Errorf("Post after a dropped post not delivered visibly."+
+			"\nexpected: called=%t hidden=%t\nreceived: called=%t hidden=%t",
+			true, false, called, hidden)
+	}
+}
+
+// mockScoredEvent adheres to the ScoredEventModel interface and is used for
+// testing.
+type mockScoredEvent struct {
+	MockEvent
+	scores map[uint64]int32
+}
+
+func (m *mockScoredEvent) UpdateScore(uuid uint64, score int32) error {
+	m.scores[uuid] = score
+	return nil
+}
@@ -38,6 +38,10 @@ const (
 	// Invitation denotes that the message is an invitation to another channel.
 	Invitation MessageType = 5
 
+	// Poll denotes that the message is a poll that members of the channel can
+	// vote on.
+	Poll MessageType = 6
+
 	////////////////////////////////////////////////////////////////////////////
 	// Message Actions                                                        //
 	////////////////////////////////////////////////////////////////////////////
@@ -56,6 +60,26 @@ const (
 	// AdminReplay denotes that the message contains an admin message.
 	AdminReplay MessageType = 104
 
+	// Edit denotes that the text of a message should be replaced. Only the
+	// original sender of a message may edit it.
+	Edit MessageType = 105
+
+	// Vote denotes that the message is a vote for an option of a poll. Only the
+	// most recent vote from each user is counted.
+	Vote MessageType = 106
+
+	// ClosePoll denotes that votes for a poll received after the given time are
+	// no longer counted. Only the channel admin can close a poll.
+	ClosePoll MessageType = 107
+
+	// Moderator denotes that a user is granted or revoked moderator
+	// permissions. Only the channel admin can grant moderator permissions.
+	Moderator MessageType = 108
+
+	// SlowMode denotes that users may only post to the channel once per the
+	// given interval. Only the channel admin can set slow mode.
+	SlowMode MessageType = 109
+
 	////////////////////////////////////////////////////////////////////////////
 	// Extensions                                                             //
 	////////////////////////////////////////////////////////////////////////////
@@ -79,6 +103,8 @@ func (mt MessageType) String() string {
 		return "Silent"
 	case Invitation:
 		return "Invitation"
+	case Poll:
+		return "Poll"
 	case Delete:
 		return "Delete"
 	case Pinned:
@@ -87,6 +113,16 @@ func (mt MessageType) String() string {
 		return "Mute"
 	case AdminReplay:
 		return "AdminReplay"
+	case Edit:
+		return "Edit"
+	case Vote:
+		return "Vote"
+	case ClosePoll:
+		return "ClosePoll"
+	case Moderator:
+		return "Moderator"
+	case SlowMode:
+		return "SlowMode"
 	case FileTransfer:
 		return "FileTransfer"
 	default:
@@ -17,10 +17,12 @@ import (
 func TestMessageType_String_Consistency(t *testing.T) {
 	expectedStrings := map[MessageType]string{
 		Text: "Text", AdminText: "AdminText", Reaction: "Reaction", Silent: "Silent", Invitation: "Invitation",
-		Delete: "Delete", Pinned: "Pinned", Mute: "Mute",
-		AdminReplay: "AdminReplay", FileTransfer: "FileTransfer",
-		Invitation + 1: fmt.Sprintf("Unknown messageType %d", Invitation+1),
-		Invitation + 2: fmt.Sprintf("Unknown messageType %d", Invitation+2),
+		Poll: "Poll", Delete: "Delete", Pinned: "Pinned", Mute: "Mute",
+		AdminReplay: "AdminReplay", Edit: "Edit", Vote: "Vote",
+		ClosePoll: "ClosePoll", Moderator: "Moderator", SlowMode: "SlowMode",
+		FileTransfer: "FileTransfer",
+		Poll + 1:     fmt.Sprintf("Unknown messageType %d", Poll+1),
+		Poll + 2:     fmt.Sprintf("Unknown messageType %d", Poll+2),
 	}
 
 	for mt, expected := range expectedStrings {
@@ -47,8 +49,9 @@ func TestMessageType_Bytes_Consistency(t *testing.T) {
 // Tests that a MessageType marshalled via MessageType.Marshal and unmarshalled
 // via UnmarshalMessageType matches the original.
 func TestMessageType_Marshal_UnmarshalMessageType(t *testing.T) {
-	tests := []MessageType{Text, AdminText, Reaction, Delete, Pinned, Mute,
-		AdminReplay, FileTransfer}
+	tests := []MessageType{Text, AdminText, Reaction, Poll, Delete, Pinned,
+		Mute, AdminReplay, Edit, Vote, ClosePoll, Moderator, SlowMode,
+		FileTransfer}
 
 	for _, mt := range tests {
 		data := mt.Marshal()
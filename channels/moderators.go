@@ -0,0 +1,303 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// ModeratorPermission is a bit flag describing an action that a moderator is
+// allowed to perform on behalf of the channel admin.
+type ModeratorPermission uint32
+
+const (
+	// DeletePermission allows a moderator to delete any user's message.
+	DeletePermission ModeratorPermission = 1 << iota
+
+	// PinPermission allows a moderator to pin and unpin messages.
+	PinPermission
+
+	// MutePermission allows a moderator to mute and unmute users.
+	MutePermission
+
+	// AllModeratorPermissions contains every moderator permission.
+	AllModeratorPermissions = DeletePermission | PinPermission | MutePermission
+)
+
+// maxModeratorActionLease is the longest lease a moderator action can have.
+// Moderator actions are sent as user messages, which cannot be replayed by the
+// lease system like admin messages, so they cannot outlive a single message.
+const maxModeratorActionLease = MessageLife - gracePeriod
+
+// String returns a human-readable list of the permissions, used for debugging
+// and logging. This function adheres to the [fmt.Stringer] interface.
+func (mp ModeratorPermission) String() string {
+	var names []string
+	if mp&DeletePermission != 0 {
+		names = append(names, "delete")
+	}
+	if mp&PinPermission != 0 {
+		names = append(names, "pin")
+	}
+	if mp&MutePermission != 0 {
+		names = append(names, "mute")
+	}
+	return "{" + strings.Join(names, ",") + "}"
+}
+
+// permissionForAction returns the ModeratorPermission required to perform the
+// action of the given MessageType. Returns false if moderators cannot perform
+// the action.
+func permissionForAction(action MessageType) (ModeratorPermission, bool) {
+	switch action {
+	case Delete:
+		return DeletePermission, true
+	case Pinned:
+		return PinPermission, true
+	case Mute:
+		return MutePermission, true
+	default:
+		return 0, false
+	}
+}
+
+// ModeratorGrant describes the permissions granted to a moderator by the
+// channel admin.
+type ModeratorGrant struct {
+	// PubKey is the [ed25519.PublicKey] of the moderator.
+	PubKey ed25519.PublicKey `json:"pubKey"`
+
+	// Permissions are the actions the moderator may perform.
+	Permissions ModeratorPermission `json:"permissions"`
+
+	// Expiry is when the grant ends. It is zero if the grant never expires.
+	Expiry time.Time `json:"expiry"`
+}
+
+// allows returns true if the grant includes the permission and has not
+// expired by the given time.
+func (mg *ModeratorGrant) allows(
+	permission ModeratorPermission, ts time.Time) bool {
+	return mg.Permissions&permission == permission &&
+		(mg.Expiry.IsZero() || ts.Before(mg.Expiry))
+}
+
+// Storage values.
+const (
+	moderatorsStoreVer = 0
+	moderatorsStoreKey = "channelModerators"
+)
+
+// Error messages.
+const (
+	// moderatorManager.save
+	storeModeratorsErr = "could not store channel moderators: %+v"
+)
+
+// moderatorManager manages the list of moderators in each channel. Grants are
+// received from the channel admin and are stored until they are revoked, they
+// expire, or the channel is left.
+type moderatorManager struct {
+	// List of grants in each channel keyed on the moderator's public key. The
+	// internal map keys on mutedUserKey (which is a string) because
+	// json.Marshal (which is used for storage) requires the key be a string.
+	list map[id.ID]map[mutedUserKey]*ModeratorGrant
+
+	mux sync.RWMutex
+	kv  versioned.KV
+}
+
+// newOrLoadModeratorManager loads an existing moderatorManager from storage, if
+// it exists. Otherwise, it initialises a new empty moderatorManager.
+func newOrLoadModeratorManager(kv versioned.KV) (*moderatorManager, error) {
+	mm := &moderatorManager{
+		list: make(map[id.ID]map[mutedUserKey]*ModeratorGrant),
+		kv:   kv,
+	}
+
+	obj, err := kv.Get(moderatorsStoreKey, moderatorsStoreVer)
+	if err != nil {
+		if kv.Exists(err) {
+			return nil, err
+		}
+		return mm, nil
+	}
+
+	var disk map[string]map[mutedUserKey]*ModeratorGrant
+	if err = json.Unmarshal(obj.Data, &disk); err != nil {
+		return nil, err
+	}
+	for chID, grants := range disk {
+		channelID, err2 := decodeModeratorChannelKey(chID)
+		if err2 != nil {
+			return nil, err2
+		}
+		mm.list[*channelID] = grants
+	}
+
+	return mm, nil
+}
+
+// grant adds or replaces the moderator grant for the user in the channel.
+func (mm *moderatorManager) grant(channelID *id.ID,
+	pubKey ed25519.PublicKey, permissions ModeratorPermission,
+	expiry time.Time) {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+
+	if _, exists := mm.list[*channelID]; !exists {
+		mm.list[*channelID] = make(map[mutedUserKey]*ModeratorGrant)
+	}
+
+	mm.list[*channelID][makeMutedUserKey(pubKey)] = &ModeratorGrant{
+		PubKey:      pubKey,
+		Permissions: permissions,
+		Expiry:      expiry,
+	}
+
+	if err := mm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save moderators: %+v", err)
+	}
+}
+
+// revoke removes the moderator grant for the user in the channel.
+func (mm *moderatorManager) revoke(
+	channelID *id.ID, pubKey ed25519.PublicKey) {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+
+	grants, exists := mm.list[*channelID]
+	if !exists {
+		return
+	}
+
+	delete(grants, makeMutedUserKey(pubKey))
+	if len(grants) == 0 {
+		delete(mm.list, *channelID)
+	}
+
+	if err := mm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save moderators: %+v", err)
+	}
+}
+
+// hasPermission returns true if the user is a moderator of the channel at the
+// given time and is allowed to perform the action of the given MessageType.
+func (mm *moderatorManager) hasPermission(channelID *id.ID,
+	pubKey ed25519.PublicKey, action MessageType, ts time.Time) bool {
+	permission, ok := permissionForAction(action)
+	if !ok {
+		return false
+	}
+
+	mm.mux.RLock()
+	defer mm.mux.RUnlock()
+
+	grant, exists := mm.list[*channelID][makeMutedUserKey(pubKey)]
+	return exists && grant.allows(permission, ts)
+}
+
+// isModerator returns true if the user is a moderator of the channel with any
+// permission at the given time.
+func (mm *moderatorManager) isModerator(
+	channelID *id.ID, pubKey ed25519.PublicKey, ts time.Time) bool {
+	mm.mux.RLock()
+	defer mm.mux.RUnlock()
+
+	grant, exists := mm.list[*channelID][makeMutedUserKey(pubKey)]
+	return exists && (grant.Expiry.IsZero() || ts.Before(grant.Expiry))
+}
+
+// getModerators returns the unexpired moderator grants for the channel sorted
+// by public key.
+func (mm *moderatorManager) getModerators(channelID *id.ID) []ModeratorGrant {
+	mm.mux.RLock()
+	defer mm.mux.RUnlock()
+
+	now := netTime.Now()
+	grants := make([]ModeratorGrant, 0, len(mm.list[*channelID]))
+	for _, grant := range mm.list[*channelID] {
+		if grant.Expiry.IsZero() || now.Before(grant.Expiry) {
+			grants = append(grants, *grant)
+		}
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		return string(grants[i].PubKey) < string(grants[j].PubKey)
+	})
+
+	return grants
+}
+
+// removeChannel deletes the moderators for the given channel. This should only
+// be called when leaving a channel.
+func (mm *moderatorManager) removeChannel(channelID *id.ID) error {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+
+	if _, exists := mm.list[*channelID]; !exists {
+		return nil
+	}
+
+	delete(mm.list, *channelID)
+	return mm.save()
+}
+
+// save stores all moderator grants to storage. Must be called under lock.
+func (mm *moderatorManager) save() error {
+	disk := make(map[string]map[mutedUserKey]*ModeratorGrant, len(mm.list))
+	for channelID, grants := range mm.list {
+		chID := channelID
+		disk[makeModeratorChannelKey(&chID)] = grants
+	}
+
+	data, err := json.Marshal(disk)
+	if err != nil {
+		return errors.Errorf(storeModeratorsErr, err)
+	}
+
+	obj := &versioned.Object{
+		Version:   moderatorsStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	if err = mm.kv.Set(moderatorsStoreKey, obj); err != nil {
+		return errors.Errorf(storeModeratorsErr, err)
+	}
+	return nil
+}
+
+// makeModeratorChannelKey encodes the channel ID for use as a key in the stored
+// moderator list.
+func makeModeratorChannelKey(channelID *id.ID) string {
+	return base64.StdEncoding.EncodeToString(channelID.Marshal())
+}
+
+// decodeModeratorChannelKey decodes a channel ID encoded with
+// makeModeratorChannelKey.
+func decodeModeratorChannelKey(key string) (*id.ID, error) {
+	data, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+	return id.Unmarshal(data)
+}
@@ -0,0 +1,119 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that a moderatorManager loaded via newOrLoadModeratorManager matches
+// the original.
+func Test_newOrLoadModeratorManager(t *testing.T) {
+	prng := rand.New(rand.NewSource(3523))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	mm, err := newOrLoadModeratorManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to make new moderatorManager: %+v", err)
+	}
+
+	expiry := netTime.Now().Add(time.Hour).Round(0)
+	for i := 0; i < 3; i++ {
+		channelID, _ := id.NewRandomID(prng, id.User)
+		for j := 0; j < 4; j++ {
+			pubKey, _, _ := ed25519.GenerateKey(prng)
+			mm.grant(channelID, pubKey, ModeratorPermission(j+1), expiry)
+		}
+	}
+
+	loaded, err := newOrLoadModeratorManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to load moderatorManager: %+v", err)
+	}
+
+	for channelID := range mm.list {
+		chID := channelID
+		expected := mm.getModerators(&chID)
+		received := loaded.getModerators(&chID)
+		if len(expected) != len(received) {
+			t.Fatalf("Unexpected number of moderators for channel %s."+
+				"\nexpected: %d\nreceived: %d",
+				channelID, len(expected), len(received))
+		}
+		for j := range expected {
+			if !bytes.Equal(expected[j].PubKey, received[j].PubKey) ||
+				expected[j].Permissions != received[j].Permissions ||
+				!expected[j].Expiry.Equal(received[j].Expiry) {
+				t.Errorf("Unexpected moderator %d for channel %s."+
+					"\nexpected: %+v\nreceived: %+v",
+					j, channelID, expected[j], received[j])
+			}
+		}
+	}
+}
+
+// Tests that moderatorManager.getModerators excludes expired grants and that
+// moderatorManager.revoke and moderatorManager.removeChannel remove grants.
+func Test_moderatorManager_getModerators(t *testing.T) {
+	prng := rand.New(rand.NewSource(3523))
+	mm, err := newOrLoadModeratorManager(versioned.NewKV(ekv.MakeMemstore()))
+	if err != nil {
+		t.Fatalf("Failed to make new moderatorManager: %+v", err)
+	}
+
+	channelID, _ := id.NewRandomID(prng, id.User)
+	active, _, _ := ed25519.GenerateKey(prng)
+	expired, _, _ := ed25519.GenerateKey(prng)
+	revoked, _, _ := ed25519.GenerateKey(prng)
+	mm.grant(channelID, active, AllModeratorPermissions, time.Time{})
+	mm.grant(channelID, expired, MutePermission,
+		netTime.Now().Add(-time.Second))
+	mm.grant(channelID, revoked, PinPermission, time.Time{})
+	mm.revoke(channelID, revoked)
+
+	expected := []ModeratorGrant{{active, AllModeratorPermissions, time.Time{}}}
+	received := mm.getModerators(channelID)
+	if !reflect.DeepEqual(expected, received) {
+		t.Errorf("Unexpected moderators.\nexpected: %+v\nreceived: %+v",
+			expected, received)
+	}
+
+	if err = mm.removeChannel(channelID); err != nil {
+		t.Fatalf("Failed to remove channel: %+v", err)
+	}
+	if received = mm.getModerators(channelID); len(received) != 0 {
+		t.Errorf("Moderators remain after removing channel: %+v", received)
+	}
+}
+
+// Consistency test of ModeratorPermission.String.
+func TestModeratorPermission_String(t *testing.T) {
+	tests := map[ModeratorPermission]string{
+		0:                                 "{}",
+		DeletePermission:                  "{delete}",
+		PinPermission | MutePermission:    "{pin,mute}",
+		AllModeratorPermissions:           "{delete,pin,mute}",
+		DeletePermission | MutePermission: "{delete,mute}",
+	}
+
+	for mp, expected := range tests {
+		if mp.String() != expected {
+			t.Errorf("Unexpected string for %d.\nexpected: %s\nreceived: %s",
+				uint32(mp), expected, mp)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Storage values.
+const (
+	readMarkerPrefix     = "readMarker"
+	readMarkerMapName    = "readMarkerMap"
+	readMarkerMapVersion = 0
+	readMarkerStoreVer   = 0
+)
+
+// ReadMarkerEventModel is an EventModel that stores the read marker of each
+// channel and counts the messages received after it. If the EventModel passed
+// to the Manager implements this interface, then every read marker set on any
+// of the user's devices is passed to it.
+type ReadMarkerEventModel interface {
+	EventModel
+
+	// MarkRead is called whenever the read marker of a channel advances. All
+	// messages in the channel with a timestamp at or before the marker are
+	// read.
+	MarkRead(channelID *id.ID, timestamp time.Time) error
+
+	// GetUnreadCount returns the number of messages in the channel received
+	// after the read marker.
+	GetUnreadCount(channelID *id.ID) (uint64, error)
+}
+
+// readMarkerManager tracks the timestamp of the last read message in each
+// channel. Markers are stored in the remote KV so that they are synced between
+// all the user's devices. A marker only ever moves forward in time.
+type readMarkerManager struct {
+	byChannel map[id.ID]time.Time
+	callback  func(channelID *id.ID, lastRead time.Time)
+	remote    versioned.KV
+	mux       sync.RWMutex
+}
+
+// loadOrNewReadMarkerManager loads the read markers from the remote KV and
+// listens for changes made on other devices. The callback is called for every
+// loaded marker and every time a marker advances.
+func loadOrNewReadMarkerManager(remote versioned.KV,
+	callback func(channelID *id.ID, lastRead time.Time)) *readMarkerManager {
+	kvRemote, err := remote.Prefix(readMarkerPrefix)
+	if err != nil {
+		jww.FATAL.Panicf("[CH] Read markers failed to prefix KV (remote)")
+	}
+
+	rmm := &readMarkerManager{
+		byChannel: make(map[id.ID]time.Time),
+		callback:  callback,
+		remote:    kvRemote,
+	}
+
+	err = rmm.remote.ListenOnRemoteMap(readMarkerMapName, readMarkerMapVersion,
+		rmm.mapUpdate, false)
+	if err != nil && rmm.remote.Exists(err) {
+		jww.FATAL.Panicf("[CH] Failed to load and listen to remote "+
+			"updates on readMarkerManager: %+v", err)
+	}
+
+	return rmm
+}
+
+// markRead advances the read marker of the channel to the timestamp. It does
+// nothing if the current marker is at or after the timestamp.
+func (rmm *readMarkerManager) markRead(
+	channelID *id.ID, timestamp time.Time) error {
+	rmm.mux.Lock()
+	defer rmm.mux.Unlock()
+
+	if lastRead, exists := rmm.byChannel[*channelID]; exists &&
+		!timestamp.After(lastRead) {
+		return nil
+	}
+
+	data, err := json.Marshal(timestamp)
+	if err != nil {
+		return err
+	}
+
+	err = rmm.remote.StoreMapElement(readMarkerMapName, marshalChID(channelID),
+		&versioned.Object{
+			Version:   readMarkerStoreVer,
+			Timestamp: netTime.Now(),
+			Data:      data,
+		}, readMarkerMapVersion)
+	if err != nil {
+		return err
+	}
+
+	rmm.byChannel[*channelID] = timestamp
+	go rmm.callback(channelID, timestamp)
+
+	return nil
+}
+
+// getReadMarker returns the read marker of the channel. Returns false if no
+// messages in the channel have been read.
+func (rmm *readMarkerManager) getReadMarker(
+	channelID *id.ID) (lastRead time.Time, exists bool) {
+	rmm.mux.RLock()
+	defer rmm.mux.RUnlock()
+
+	lastRead, exists = rmm.byChannel[*channelID]
+	return lastRead, exists
+}
+
+// removeChannel deletes the read marker of the channel. This should only be
+// called when leaving a channel.
+func (rmm *readMarkerManager) removeChannel(channelID *id.ID) error {
+	rmm.mux.Lock()
+	defer rmm.mux.Unlock()
+
+	if _, exists := rmm.byChannel[*channelID]; !exists {
+		return nil
+	}
+
+	_, err := rmm.remote.DeleteMapElement(
+		readMarkerMapName, marshalChID(channelID), readMarkerMapVersion)
+	if err != nil {
+		return err
+	}
+
+	delete(rmm.byChannel, *channelID)
+	return nil
+}
+
+// mapUpdate handles map updates, handles by versioned.KV's ListenOnRemoteMap
+// method. Markers from other devices are only applied if they are newer than
+// the local marker.
+func (rmm *readMarkerManager) mapUpdate(
+	edits map[string]versioned.ElementEdit) {
+	rmm.mux.Lock()
+	defer rmm.mux.Unlock()
+
+	for elementName, edit := range edits {
+		channelID, err := unmarshalChID(elementName)
+		if err != nil {
+			jww.WARN.Printf("[CH] Failed to unmarshal id in read marker "+
+				"update %s on operation %s, skipping: %+v", elementName,
+				edit.Operation, err)
+			continue
+		}
+
+		switch edit.Operation {
+		case versioned.Deleted:
+			delete(rmm.byChannel, *channelID)
+			continue
+		case versioned.Created, versioned.Updated, versioned.Loaded:
+		default:
+			jww.WARN.Printf("[CH] Failed to handle read marker update %s, "+
+				"bad operation: %s, skipping", elementName, edit.Operation)
+			continue
+		}
+
+		var timestamp time.Time
+		if err = json.Unmarshal(edit.NewElement.Data, &timestamp); err != nil {
+			jww.WARN.Printf("[CH] Failed to unmarshal data in read marker "+
+				"update %s, skipping: %+v", elementName, err)
+			continue
+		}
+
+		if lastRead, exists := rmm.byChannel[*channelID]; exists &&
+			!timestamp.After(lastRead) {
+			continue
+		}
+
+		rmm.byChannel[*channelID] = timestamp
+		go rmm.callback(channelID, timestamp)
+	}
+}
+
+// MarkRead marks all messages in the channel with a timestamp at or before the
+// given timestamp as read. The read marker is synced to all the user's devices.
+// It only ever moves forward; marking an older timestamp does nothing.
+//
+// Returns ChannelDoesNotExistsErr if the channel has not been joined.
+func (m *manager) MarkRead(channelID *id.ID, timestamp time.Time) error {
+	jww.INFO.Printf("[CH] MarkRead in channel %s at %s", channelID, timestamp)
+	if _, err := m.getChannel(channelID); err != nil {
+		return err
+	}
+	return m.readMarkers.markRead(channelID, timestamp)
+}
+
+// GetReadMarker returns the timestamp of the last read message in the channel.
+// Returns false if no messages in the channel have been marked read.
+func (m *manager) GetReadMarker(channelID *id.ID) (time.Time, bool) {
+	return m.readMarkers.getReadMarker(channelID)
+}
+
+// GetUnreadCount returns the number of messages in the channel received after
+// the read marker.
+//
+// Returns ReadMarkersUnsupportedErr if the EventModel does not implement
+// ReadMarkerEventModel.
+func (m *manager) GetUnreadCount(channelID *id.ID) (uint64, error) {
+	model, ok := m.events.model.(ReadMarkerEventModel)
+	if !ok {
+		return 0, ReadMarkersUnsupportedErr
+	}
+	return model.GetUnreadCount(channelID)
+}
+
+// readMarkerUpdate is called when a read marker is loaded or advances, either
+// locally or on another device. It passes the marker to the EventModel, if it
+// stores read markers, and to the UI.
+func (m *manager) readMarkerUpdate(channelID *id.ID, lastRead time.Time) {
+	if model, ok := m.events.model.(ReadMarkerEventModel); ok {
+		if err := model.MarkRead(channelID, lastRead); err != nil {
+			jww.ERROR.Printf("[CH] Failed to mark channel %s read at %s in "+
+				"event model: %+v", channelID, lastRead, err)
+		}
+	}
+	m.readMarkerCallback(channelID, lastRead)
+}
@@ -0,0 +1,299 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Storage values.
+const (
+	rosterStoreVer    = 0
+	rosterStorePrefix = "channelRoster/"
+)
+
+// Error messages.
+const (
+	// rosterManager.save
+	storeRosterErr = "could not store roster for channel %s: %+v"
+
+	// rosterManager.loadChannel
+	loadRosterErr = "could not load roster for channel %s"
+)
+
+// rosterLastSeenResolution is how far the last seen time of a member must
+// advance before it is saved to storage. Changes to the nickname, DM token, or
+// codeset are always saved. This prevents every received message from causing
+// a write to storage.
+const rosterLastSeenResolution = 5 * time.Minute
+
+// Member is a user that has been seen sending messages in a channel.
+type Member struct {
+	// PubKey is the [ed25519.PublicKey] of the user.
+	PubKey ed25519.PublicKey `json:"pubKey"`
+
+	// Nickname is the nickname in the newest message from the user.
+	Nickname string `json:"nickname"`
+
+	// CodesetVersion is the codeset version in the newest message from the
+	// user.
+	CodesetVersion uint8 `json:"codesetVersion"`
+
+	// DmToken is the DM token in the newest message from the user. It is zero
+	// if the user does not accept DMs.
+	DmToken uint32 `json:"dmToken"`
+
+	// FirstSeen is the timestamp of the oldest message from the user.
+	FirstSeen time.Time `json:"firstSeen"`
+
+	// LastSeen is the timestamp of the newest message from the user.
+	LastSeen time.Time `json:"lastSeen"`
+
+	// Muted is true if the user is muted in the channel.
+	Muted bool `json:"muted"`
+}
+
+// MemberUpdateCallback is called when a new member is seen in a channel or
+// when the nickname, DM token, codeset, or muted state of a member changes.
+type MemberUpdateCallback func(channelID *id.ID, member Member)
+
+// rosterEntry is a Member stored in the roster.
+type rosterEntry struct {
+	Member
+
+	// savedLastSeen is the last seen time stored to storage.
+	savedLastSeen time.Time
+}
+
+// rosterManager tracks every user seen sending messages in each channel. The
+// roster for a channel is loaded from storage the first time it is accessed.
+type rosterManager struct {
+	// List of members in each loaded channel. The internal map keys on
+	// mutedUserKey (which is a string) because json.Marshal (which is used for
+	// storage) requires the key be a string.
+	list map[id.ID]map[mutedUserKey]*rosterEntry
+
+	// isMuted returns true if the user is muted in the channel.
+	isMuted func(channelID *id.ID, userPubKey ed25519.PublicKey) bool
+
+	// callback is called when a member is added or changed.
+	callback MemberUpdateCallback
+
+	mux sync.Mutex
+	kv  versioned.KV
+}
+
+// newRosterManager initializes a new rosterManager. The roster for each channel
+// is loaded from storage when first accessed.
+func newRosterManager(kv versioned.KV,
+	isMuted func(*id.ID, ed25519.PublicKey) bool) *rosterManager {
+	return &rosterManager{
+		list:    make(map[id.ID]map[mutedUserKey]*rosterEntry),
+		isMuted: isMuted,
+		kv:      kv,
+	}
+}
+
+// RegisterCallback registers the callback that is called when a member is
+// added or changed.
+func (rm *rosterManager) RegisterCallback(cb MemberUpdateCallback) {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	rm.callback = cb
+}
+
+// observe records that a message from the user was received at the given
+// timestamp. The nickname, DM token, and codeset are only updated if the
+// message is newer than every other message seen from the user.
+func (rm *rosterManager) observe(channelID *id.ID, pubKey ed25519.PublicKey,
+	nickname string, dmToken uint32, codeset uint8, timestamp time.Time) {
+	rm.mux.Lock()
+
+	members, err := rm.loadChannel(channelID)
+	if err != nil {
+		rm.mux.Unlock()
+		jww.ERROR.Printf("[CH] Failed to add %x to roster: %+v", pubKey, err)
+		return
+	}
+
+	var changed bool
+	key := makeMutedUserKey(pubKey)
+	re, exists := members[key]
+	if !exists {
+		re = &rosterEntry{Member: Member{
+			PubKey:         pubKey,
+			Nickname:       nickname,
+			CodesetVersion: codeset,
+			DmToken:        dmToken,
+			FirstSeen:      timestamp,
+			LastSeen:       timestamp,
+		}}
+		members[key] = re
+		changed = true
+	} else {
+		if timestamp.Before(re.FirstSeen) {
+			re.FirstSeen = timestamp
+		}
+		if !timestamp.Before(re.LastSeen) {
+			re.LastSeen = timestamp
+			changed = re.Nickname != nickname || re.DmToken != dmToken ||
+				re.CodesetVersion != codeset
+			re.Nickname = nickname
+			re.DmToken = dmToken
+			re.CodesetVersion = codeset
+		}
+	}
+
+	if changed || re.LastSeen.Sub(re.savedLastSeen) >= rosterLastSeenResolution {
+		if err = rm.save(channelID); err != nil {
+			jww.FATAL.Panicf("[CH] Failed to save roster: %+v", err)
+		}
+		re.savedLastSeen = re.LastSeen
+	}
+
+	member, callback := re.Member, rm.callback
+	rm.mux.Unlock()
+
+	if changed && callback != nil {
+		member.Muted = rm.isMuted(channelID, member.PubKey)
+		callback(channelID, member)
+	}
+}
+
+// mutedUpdate calls the callback for the user if they are a member of the
+// channel. It is called when the user is muted or unmuted.
+func (rm *rosterManager) mutedUpdate(
+	channelID *id.ID, pubKey ed25519.PublicKey) {
+	rm.mux.Lock()
+	members, err := rm.loadChannel(channelID)
+	if err != nil {
+		rm.mux.Unlock()
+		jww.ERROR.Printf("[CH] Failed to load roster: %+v", err)
+		return
+	}
+
+	re, exists := members[makeMutedUserKey(pubKey)]
+	if !exists || rm.callback == nil {
+		rm.mux.Unlock()
+		return
+	}
+	member, callback := re.Member, rm.callback
+	rm.mux.Unlock()
+
+	member.Muted = rm.isMuted(channelID, member.PubKey)
+	callback(channelID, member)
+}
+
+// getMembers returns every member of the channel, sorted from most to least
+// recently seen.
+func (rm *rosterManager) getMembers(channelID *id.ID) ([]Member, error) {
+	rm.mux.Lock()
+	members, err := rm.loadChannel(channelID)
+	if err != nil {
+		rm.mux.Unlock()
+		return nil, err
+	}
+
+	list := make([]Member, 0, len(members))
+	for _, re := range members {
+		list = append(list, re.Member)
+	}
+	rm.mux.Unlock()
+
+	for i := range list {
+		list[i].Muted = rm.isMuted(channelID, list[i].PubKey)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].LastSeen.Equal(list[j].LastSeen) {
+			return list[i].LastSeen.After(list[j].LastSeen)
+		}
+		return string(list[i].PubKey) < string(list[j].PubKey)
+	})
+
+	return list, nil
+}
+
+// removeChannel deletes the roster for the given channel. This should only be
+// called when leaving a channel.
+func (rm *rosterManager) removeChannel(channelID *id.ID) error {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+
+	delete(rm.list, *channelID)
+	return rm.kv.Delete(makeRosterStoreKey(channelID), rosterStoreVer)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Storage Functions                                                          //
+////////////////////////////////////////////////////////////////////////////////
+
+// loadChannel returns the roster for the channel, loading it from storage if it
+// has not yet been loaded. Must be called under lock.
+func (rm *rosterManager) loadChannel(
+	channelID *id.ID) (map[mutedUserKey]*rosterEntry, error) {
+	if members, exists := rm.list[*channelID]; exists {
+		return members, nil
+	}
+
+	members := make(map[mutedUserKey]*rosterEntry)
+	obj, err := rm.kv.Get(makeRosterStoreKey(channelID), rosterStoreVer)
+	if err != nil {
+		if rm.kv.Exists(err) {
+			return nil, errors.Wrapf(err, loadRosterErr, channelID)
+		}
+	} else {
+		if err = json.Unmarshal(obj.Data, &members); err != nil {
+			return nil, errors.Wrapf(err, loadRosterErr, channelID)
+		}
+		for _, re := range members {
+			re.savedLastSeen = re.LastSeen
+		}
+	}
+
+	rm.list[*channelID] = members
+	return members, nil
+}
+
+// save stores the roster for the given channel to storage. Must be called under
+// lock.
+func (rm *rosterManager) save(channelID *id.ID) error {
+	data, err := json.Marshal(rm.list[*channelID])
+	if err != nil {
+		return errors.Errorf(storeRosterErr, channelID, err)
+	}
+
+	obj := &versioned.Object{
+		Version:   rosterStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	if err = rm.kv.Set(makeRosterStoreKey(channelID), obj); err != nil {
+		return errors.Errorf(storeRosterErr, channelID, err)
+	}
+	return nil
+}
+
+// makeRosterStoreKey generates the key used to save and load the roster for a
+// specific channel from storage.
+func makeRosterStoreKey(channelID *id.ID) string {
+	return rosterStorePrefix + base64.StdEncoding.EncodeToString(channelID[:])
+}
@@ -0,0 +1,180 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/rand"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/elixxir/primitives/states"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that rosterManager.observe adds new members, only updates the member
+// details from newer messages, and calls the callback only on changes.
+func Test_rosterManager_observe(t *testing.T) {
+	prng := rand.New(rand.NewSource(8426))
+	mum := newMutedUserManager(versioned.NewKV(ekv.MakeMemstore()))
+	rm := newRosterManager(versioned.NewKV(ekv.MakeMemstore()), mum.isMuted)
+	updates := make(chan Member, 10)
+	rm.RegisterCallback(func(_ *id.ID, member Member) { updates <- member })
+
+	channelID, _ := id.NewRandomID(prng, id.User)
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+	start := netTime.Now().Round(0)
+
+	tests := []struct {
+		nickname string
+		dmToken  uint32
+		ts       time.Time
+		update   bool
+		expected Member
+	}{
+		{"first", 1, start, true,
+			Member{pubKey, "first", 0, 1, start, start, false}},
+		{"first", 1, start.Add(time.Minute), false,
+			Member{pubKey, "first", 0, 1, start, start.Add(time.Minute), false}},
+		{"older", 2, start.Add(-time.Minute), false, Member{pubKey, "first", 0,
+			1, start.Add(-time.Minute), start.Add(time.Minute), false}},
+		{"newer", 3, start.Add(2 * time.Minute), true, Member{pubKey, "newer", 0,
+			3, start.Add(-time.Minute), start.Add(2 * time.Minute), false}},
+	}
+
+	for i, tt := range tests {
+		rm.observe(channelID, pubKey, tt.nickname, tt.dmToken, 0, tt.ts)
+
+		members, err := rm.getMembers(channelID)
+		if err != nil {
+			t.Fatalf("Failed to get members (%d): %+v", i, err)
+		} else if len(members) != 1 || !membersEqual(members[0], tt.expected) {
+			t.Errorf("Unexpected members (%d).\nexpected: %+v\nreceived: %+v",
+				i, tt.expected, members)
+		}
+
+		select {
+		case member := <-updates:
+			if !tt.update {
+				t.Errorf("Unexpected update (%d): %+v", i, member)
+			} else if !membersEqual(member, tt.expected) {
+				t.Errorf("Unexpected update (%d).\nexpected: %+v\nreceived: %+v",
+					i, tt.expected, member)
+			}
+		default:
+			if tt.update {
+				t.Errorf("No update received (%d).", i)
+			}
+		}
+	}
+
+	// Muting a member reports the change
+	mum.muteUser(channelID, pubKey)
+	rm.mutedUpdate(channelID, pubKey)
+	select {
+	case member := <-updates:
+		if !member.Muted {
+			t.Errorf("Update after muting does not show member as muted.")
+		}
+	default:
+		t.Errorf("No update received after muting.")
+	}
+}
+
+// Tests that rosterManager.getMembers returns the members loaded from storage
+// sorted from most to least recently seen and that rosterManager.removeChannel
+// deletes them.
+func Test_rosterManager_getMembers(t *testing.T) {
+	prng := rand.New(rand.NewSource(8426))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	mum := newMutedUserManager(kv)
+	rm := newRosterManager(kv, mum.isMuted)
+
+	channelID, _ := id.NewRandomID(prng, id.User)
+	start := netTime.Now().Round(0)
+	expected := make([]Member, 5)
+	for i := range expected {
+		pubKey, _, _ := ed25519.GenerateKey(prng)
+		ts := start.Add(time.Duration(i) * time.Hour)
+		rm.observe(channelID, pubKey, "nick", uint32(i), 0, ts)
+		expected[len(expected)-1-i] = Member{pubKey, "nick", 0, uint32(i), ts,
+			ts, i == 2}
+		if i == 2 {
+			mum.muteUser(channelID, pubKey)
+		}
+	}
+
+	loaded := newRosterManager(kv, mum.isMuted)
+	members, err := loaded.getMembers(channelID)
+	if err != nil {
+		t.Fatalf("Failed to get members: %+v", err)
+	} else if len(members) != len(expected) {
+		t.Fatalf("Unexpected number of members.\nexpected: %d\nreceived: %d",
+			len(expected), len(members))
+	}
+	for i := range expected {
+		if !membersEqual(expected[i], members[i]) {
+			t.Errorf("Unexpected member %d.\nexpected: %+v\nreceived: %+v",
+				i, expected[i], members[i])
+		}
+	}
+
+	if err = loaded.removeChannel(channelID); err != nil {
+		t.Fatalf("Failed to remove channel: %+v", err)
+	}
+	members, err = newRosterManager(kv, mum.isMuted).getMembers(channelID)
+	if err != nil {
+		t.Fatalf("Failed to get members: %+v", err)
+	} else if len(members) != 0 {
+		t.Errorf("Members remain after removing channel: %+v", members)
+	}
+}
+
+// Tests that events.triggerEvent adds the sender to the roster, even if the
+// message is rejected because the sender is muted.
+func Test_events_triggerEvent_Roster(t *testing.T) {
+	e := initEvents(&MockEvent{}, 512, versioned.NewKV(ekv.MakeMemstore()),
+		fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG))
+
+	chID := &id.ID{1}
+	umi, usrMsg, cm := builtTestUMI(t, Text)
+	e.mutedUsers.muteUser(chID, usrMsg.ECCPublicKey)
+	r := rounds.Round{ID: 420,
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
+	ts := netTime.Now().Round(0)
+
+	_, _ = e.triggerEvent(chID, umi, nil, ts, receptionID.EphemeralIdentity{},
+		r, Delivered)
+
+	members, err := e.roster.getMembers(chID)
+	if err != nil {
+		t.Fatalf("Failed to get members: %+v", err)
+	}
+	expected := Member{usrMsg.ECCPublicKey, cm.Nickname, 0, cm.DMToken, ts, ts,
+		true}
+	if len(members) != 1 || !membersEqual(expected, members[0]) {
+		t.Errorf("Unexpected members.\nexpected: %+v\nreceived: %+v",
+			expected, members)
+	}
+}
+
+// membersEqual returns true if the two members are equal.
+func membersEqual(a, b Member) bool {
+	return bytes.Equal(a.PubKey, b.PubKey) && a.Nickname == b.Nickname &&
+		a.CodesetVersion == b.CodesetVersion && a.DmToken == b.DmToken &&
+		a.FirstSeen.Equal(b.FirstSeen) && a.LastSeen.Equal(b.LastSeen) &&
+		a.Muted == b.Muted
+}
@@ -0,0 +1,649 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"container/list"
+	"crypto/ed25519"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+const (
+	// Thread stoppable name
+	scheduledSendThreadStoppable = "ScheduledSendThread"
+
+	// Storage key and version for the list of scheduled sends.
+	scheduledSendsStoreKey     = "scheduledSends"
+	scheduledSendsStoreVersion = 0
+)
+
+// Error messages.
+const (
+	// scheduledSendList.StartProcesses
+	noScheduledSendFuncErr = "scheduled send function not registered"
+
+	// scheduledSendList.save
+	storeScheduledSendsErr = "failed to store scheduled sends: %+v"
+)
+
+// ScheduledSend is a message that is held until its send time and then sent to
+// the channel via [Manager.SendGeneric] or [Manager.SendAdminGeneric].
+type ScheduledSend struct {
+	// ID uniquely identifies the scheduled send. It is used to cancel or
+	// reschedule the send.
+	ID uint64 `json:"id"`
+
+	// ChannelID is the ID of the channel the message is sent to.
+	ChannelID *id.ID `json:"channelID"`
+
+	// MessageType is the type of the message being sent.
+	MessageType MessageType `json:"messageType"`
+
+	// Payload is the message contents passed to the send function.
+	Payload []byte `json:"payload"`
+
+	// ValidUntil is the lease of the message, starting when it is sent.
+	ValidUntil time.Duration `json:"validUntil"`
+
+	// Tracked is true if the message is tracked in the sendTracker once sent.
+	Tracked bool `json:"tracked"`
+
+	// Admin is true if the message is sent as the channel admin.
+	Admin bool `json:"admin"`
+
+	// SendTime is when the message will be sent.
+	SendTime time.Time `json:"sendTime"`
+
+	// Params are the cMix parameters used to send the message.
+	Params cmix.CMIXParams `json:"params"`
+
+	// Pings are the users pinged by the message. Only used for non-admin
+	// messages.
+	Pings map[PingType][]ed25519.PublicKey `json:"pings,omitempty"`
+
+	// Failed is true if the send failed. Failed sends are kept until they are
+	// cancelled or rescheduled.
+	Failed bool `json:"failed,omitempty"`
+
+	// Error is the error returned by the failed send.
+	Error string `json:"error,omitempty"`
+}
+
+// ScheduledSendFailedCallback is called when a scheduled send fails. The send
+// is kept, marked as failed, until it is cancelled or rescheduled.
+type ScheduledSendFailedCallback func(ss ScheduledSend, err error)
+
+// scheduledSendFunc is called when a scheduled send is due. It returns an error
+// if the message could not be sent.
+type scheduledSendFunc func(ss *ScheduledSend) error
+
+// scheduledSendList keeps a list of messages that are sent at a later time.
+// The list is saved to storage on every change so that pending sends survive
+// restarts. Sends that become due while the client is offline are sent as soon
+// as the thread is started.
+//
+// A due send is only removed from storage once it has been sent. If the client
+// stops while the send is in progress, then it is sent again on restart.
+type scheduledSendList struct {
+	// List of scheduled sends sorted by send time, earliest to latest.
+	sends *list.List
+
+	// Scheduled sends in the list keyed on their ID.
+	byID map[uint64]*list.Element
+
+	// Due sends that are being sent or that failed, keyed on their ID. They
+	// are no longer in the list but remain in storage.
+	held map[uint64]*ScheduledSend
+
+	// The ID assigned to the next scheduled send.
+	nextID uint64
+
+	// sendFn is called for each scheduled send when it is due.
+	sendFn scheduledSendFunc
+
+	// failedCB is called when a scheduled send fails.
+	failedCB ScheduledSendFailedCallback
+
+	// wake is signaled when the list changes so that the thread can update its
+	// timer.
+	wake chan struct{}
+
+	kv  versioned.KV
+	mux sync.Mutex
+}
+
+// scheduledSendsDisk is the representation of a scheduledSendList in storage.
+type scheduledSendsDisk struct {
+	NextID uint64           `json:"nextID"`
+	Sends  []*ScheduledSend `json:"sends"`
+}
+
+// newOrLoadScheduledSendList loads an existing scheduledSendList from storage,
+// if it exists. Otherwise, it initialises a new empty scheduledSendList.
+func newOrLoadScheduledSendList(
+	kv versioned.KV) (*scheduledSendList, error) {
+	ssl := &scheduledSendList{
+		sends:  list.New(),
+		byID:   make(map[uint64]*list.Element),
+		held:   make(map[uint64]*ScheduledSend),
+		nextID: 1,
+		wake:   make(chan struct{}, 1),
+		kv:     kv,
+	}
+
+	obj, err := kv.Get(scheduledSendsStoreKey, scheduledSendsStoreVersion)
+	if err != nil {
+		if kv.Exists(err) {
+			return nil, err
+		}
+		return ssl, nil
+	}
+
+	var disk scheduledSendsDisk
+	if err = json.Unmarshal(obj.Data, &disk); err != nil {
+		return nil, err
+	}
+
+	ssl.nextID = disk.NextID
+	for _, ss := range disk.Sends {
+		if ss.Failed {
+			ssl.held[ss.ID] = ss
+		} else {
+			ssl.insert(ss)
+		}
+	}
+
+	return ssl, nil
+}
+
+// RegisterSendFn registers the function that is called to send a scheduled
+// message when it is due.
+func (ssl *scheduledSendList) RegisterSendFn(sendFn scheduledSendFunc) {
+	ssl.sendFn = sendFn
+}
+
+// RegisterFailedCallback registers the callback that is called when a
+// scheduled send fails.
+func (ssl *scheduledSendList) RegisterFailedCallback(
+	cb ScheduledSendFailedCallback) {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+	ssl.failedCB = cb
+}
+
+// StartProcesses starts the thread that sends scheduled messages when they are
+// due. This function adheres to the xxdk.Service type.
+//
+// Returns an error if no send function has been registered.
+func (ssl *scheduledSendList) StartProcesses() (stoppable.Stoppable, error) {
+	if ssl.sendFn == nil {
+		return nil, errors.New(noScheduledSendFuncErr)
+	}
+	stop := stoppable.NewSingle(scheduledSendThreadStoppable)
+
+	// Start the thread
+	go ssl.scheduledSendsThread(stop)
+
+	return stop, nil
+}
+
+// scheduledSendsThread sends every scheduled send once its send time is
+// reached.
+func (ssl *scheduledSendList) scheduledSendsThread(stop *stoppable.Single) {
+	jww.INFO.Printf(
+		"[CH] Starting scheduled send thread with stoppable %s", stop.Name())
+
+	// Start timer stopped until the first send time is known
+	timer := netTime.NewTimer(0)
+	timer.Stop()
+
+	for {
+		// Send all due messages in order on a separate thread so that slow
+		// sends do not delay the timer
+		due, next, exists := ssl.popDue(netTime.Now())
+		if len(due) > 0 {
+			go func(due []*ScheduledSend) {
+				for _, ss := range due {
+					ssl.done(ss.ID, ssl.sendFn(ss))
+				}
+			}(due)
+		}
+
+		if exists {
+			alarmTime := netTime.Until(next)
+			timer.Reset(alarmTime)
+			jww.DEBUG.Printf(
+				"[CH] Scheduled send alarm reset for %s", alarmTime)
+		}
+
+		select {
+		case <-stop.Quit():
+			jww.INFO.Printf("[CH] Stopping scheduled send thread: "+
+				"stoppable %s quit", stop.Name())
+			timer.Stop()
+			stop.ToStopped()
+			return
+		case <-ssl.wake:
+		case <-timer.C:
+		}
+
+		timer.Stop()
+	}
+}
+
+// popDue moves all sends that are due at the given time from the list to the
+// held sends and returns them in order. They stay in storage until done is
+// called. It also returns the send time of the next pending send, if one
+// exists.
+func (ssl *scheduledSendList) popDue(now time.Time) (
+	due []*ScheduledSend, next time.Time, exists bool) {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+
+	for e := ssl.sends.Front(); e != nil; e = ssl.sends.Front() {
+		ss := e.Value.(*ScheduledSend)
+		if ss.SendTime.After(now) {
+			next, exists = ss.SendTime, true
+			break
+		}
+		ssl.remove(e)
+		ssl.held[ss.ID] = ss
+		due = append(due, ss)
+	}
+
+	return due, next, exists
+}
+
+// done is called once the due send with the given ID has been sent. If the
+// send succeeded, then it is removed from storage. Otherwise, it is marked as
+// failed and the failure is reported to the registered callback. Sends that
+// were cancelled while being sent are ignored.
+func (ssl *scheduledSendList) done(sendID uint64, sendErr error) {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+
+	ss, exists := ssl.held[sendID]
+	if !exists {
+		return
+	}
+
+	if sendErr == nil {
+		delete(ssl.held, sendID)
+	} else {
+		ss.Failed, ss.Error = true, sendErr.Error()
+	}
+
+	if err := ssl.save(); err != nil {
+		jww.ERROR.Printf("[CH] Failed to update scheduled send %d in "+
+			"storage: %+v", sendID, err)
+	}
+
+	if sendErr != nil && ssl.failedCB != nil {
+		go ssl.failedCB(*ss, sendErr)
+	}
+}
+
+// add adds a new scheduled send to the list and saves it to storage. The ID of
+// the send is assigned and returned.
+func (ssl *scheduledSendList) add(ss *ScheduledSend) (uint64, error) {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+
+	ss.ID = ssl.nextID
+	ssl.nextID++
+	ssl.insert(ss)
+
+	if err := ssl.save(); err != nil {
+		ssl.remove(ssl.byID[ss.ID])
+		return 0, err
+	}
+
+	ssl.notify()
+	return ss.ID, nil
+}
+
+// get returns a copy of every pending and failed send, sorted by send time.
+// If channelID is not nil, then only sends to that channel are returned. Sends
+// that are being sent are not included.
+func (ssl *scheduledSendList) get(channelID *id.ID) []ScheduledSend {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+
+	sends := make([]ScheduledSend, 0, ssl.sends.Len())
+	for _, ss := range ssl.held {
+		if ss.Failed && (channelID == nil || ss.ChannelID.Cmp(channelID)) {
+			sends = append(sends, *ss)
+		}
+	}
+	sort.Slice(sends, func(i, j int) bool { return sends[i].ID < sends[j].ID })
+
+	for e := ssl.sends.Front(); e != nil; e = e.Next() {
+		ss := e.Value.(*ScheduledSend)
+		if channelID == nil || ss.ChannelID.Cmp(channelID) {
+			sends = append(sends, *ss)
+		}
+	}
+	sort.SliceStable(sends, func(i, j int) bool {
+		return sends[i].SendTime.Before(sends[j].SendTime)
+	})
+
+	return sends
+}
+
+// pending returns the number of sends waiting to be sent. Failed sends are not
+// counted.
+func (ssl *scheduledSendList) pending() int {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+	return ssl.sends.Len()
+}
+
+// cancel removes the pending or failed scheduled send with the given ID.
+// Returns ScheduledSendNotFoundErr if no send exists with the ID or if it is
+// being sent.
+func (ssl *scheduledSendList) cancel(sendID uint64) error {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+
+	if ss, exists := ssl.held[sendID]; exists && ss.Failed {
+		delete(ssl.held, sendID)
+		if err := ssl.save(); err != nil {
+			ssl.held[sendID] = ss
+			return err
+		}
+		return nil
+	}
+
+	e, exists := ssl.byID[sendID]
+	if !exists {
+		return ScheduledSendNotFoundErr
+	}
+	ssl.remove(e)
+
+	if err := ssl.save(); err != nil {
+		ssl.insert(e.Value.(*ScheduledSend))
+		return err
+	}
+
+	ssl.notify()
+	return nil
+}
+
+// reschedule changes the send time of the pending or failed scheduled send
+// with the given ID. A failed send is scheduled to be sent again. Returns
+// ScheduledSendNotFoundErr if no send exists with the ID or if it is being
+// sent.
+func (ssl *scheduledSendList) reschedule(
+	sendID uint64, sendTime time.Time) error {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+
+	if ss, exists := ssl.held[sendID]; exists && ss.Failed {
+		delete(ssl.held, sendID)
+		oldSendTime, oldErr := ss.SendTime, ss.Error
+		ss.SendTime, ss.Failed, ss.Error = sendTime, false, ""
+		ssl.insert(ss)
+
+		if err := ssl.save(); err != nil {
+			ssl.remove(ssl.byID[sendID])
+			ss.SendTime, ss.Failed, ss.Error = oldSendTime, true, oldErr
+			ssl.held[sendID] = ss
+			return err
+		}
+
+		ssl.notify()
+		return nil
+	}
+
+	e, exists := ssl.byID[sendID]
+	if !exists {
+		return ScheduledSendNotFoundErr
+	}
+	ss := e.Value.(*ScheduledSend)
+	oldSendTime := ss.SendTime
+
+	ssl.remove(e)
+	ss.SendTime = sendTime
+	ssl.insert(ss)
+
+	if err := ssl.save(); err != nil {
+		ssl.remove(ssl.byID[sendID])
+		ss.SendTime = oldSendTime
+		ssl.insert(ss)
+		return err
+	}
+
+	ssl.notify()
+	return nil
+}
+
+// removeChannel removes all scheduled sends to the given channel, including
+// failed sends and sends that are being sent.
+func (ssl *scheduledSendList) removeChannel(channelID *id.ID) error {
+	ssl.mux.Lock()
+	defer ssl.mux.Unlock()
+
+	var removed bool
+	for e := ssl.sends.Front(); e != nil; {
+		next := e.Next()
+		if e.Value.(*ScheduledSend).ChannelID.Cmp(channelID) {
+			ssl.remove(e)
+			removed = true
+		}
+		e = next
+	}
+	for sendID, ss := range ssl.held {
+		if ss.ChannelID.Cmp(channelID) {
+			delete(ssl.held, sendID)
+			removed = true
+		}
+	}
+
+	if !removed {
+		return nil
+	}
+
+	if err := ssl.save(); err != nil {
+		return err
+	}
+
+	ssl.notify()
+	return nil
+}
+
+// insert inserts the scheduled send into the list sorted by send time. Sends
+// with the same send time stay in the order they were inserted. Must be called
+// under lock.
+func (ssl *scheduledSendList) insert(ss *ScheduledSend) {
+	var e *list.Element
+	for mark := ssl.sends.Back(); mark != nil; mark = mark.Prev() {
+		if !mark.Value.(*ScheduledSend).SendTime.After(ss.SendTime) {
+			e = ssl.sends.InsertAfter(ss, mark)
+			break
+		}
+	}
+	if e == nil {
+		e = ssl.sends.PushFront(ss)
+	}
+	ssl.byID[ss.ID] = e
+}
+
+// remove removes the element from the list. Must be called under lock.
+func (ssl *scheduledSendList) remove(e *list.Element) {
+	ssl.sends.Remove(e)
+	delete(ssl.byID, e.Value.(*ScheduledSend).ID)
+}
+
+// notify wakes the thread so that it updates its timer. It does not block if a
+// wake is already pending.
+func (ssl *scheduledSendList) notify() {
+	select {
+	case ssl.wake <- struct{}{}:
+	default:
+	}
+}
+
+// save stores the list of scheduled sends and the held sends to storage. Must
+// be called under lock.
+func (ssl *scheduledSendList) save() error {
+	disk := scheduledSendsDisk{
+		NextID: ssl.nextID,
+		Sends:  make([]*ScheduledSend, 0, len(ssl.held)+ssl.sends.Len()),
+	}
+	for _, ss := range ssl.held {
+		disk.Sends = append(disk.Sends, ss)
+	}
+	for e := ssl.sends.Front(); e != nil; e = e.Next() {
+		disk.Sends = append(disk.Sends, e.Value.(*ScheduledSend))
+	}
+
+	data, err := json.Marshal(&disk)
+	if err != nil {
+		return errors.Errorf(storeScheduledSendsErr, err)
+	}
+
+	obj := &versioned.Object{
+		Version:   scheduledSendsStoreVersion,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	if err = ssl.kv.Set(scheduledSendsStoreKey, obj); err != nil {
+		return errors.Errorf(storeScheduledSendsErr, err)
+	}
+	return nil
+}
+
+// ScheduleSend schedules a message to be sent to the channel at the given
+// send time using [Manager.SendGeneric]. The send is saved to storage and
+// will be sent after a restart. If the send time is in the past, then the
+// message is sent immediately.
+//
+// Returns the ID of the scheduled send, which can be used to cancel or
+// reschedule it. Returns ChannelDoesNotExistsErr if the channel has not been
+// joined.
+func (m *manager) ScheduleSend(channelID *id.ID, messageType MessageType,
+	msg []byte, validUntil time.Duration, tracked bool, sendTime time.Time,
+	params cmix.CMIXParams, pingsMap map[PingType][]ed25519.PublicKey) (
+	uint64, error) {
+	jww.INFO.Printf("[CH] ScheduleSend %s to channel %s at %s",
+		messageType, channelID, sendTime)
+	if _, err := m.getChannel(channelID); err != nil {
+		return 0, err
+	}
+
+	return m.scheduled.add(&ScheduledSend{
+		ChannelID:   channelID.DeepCopy(),
+		MessageType: messageType,
+		Payload:     msg,
+		ValidUntil:  validUntil,
+		Tracked:     tracked,
+		SendTime:    sendTime,
+		Params:      params,
+		Pings:       pingsMap,
+	})
+}
+
+// ScheduleAdminSend schedules a message to be sent to the channel as the
+// channel admin at the given send time using [Manager.SendAdminGeneric]. The
+// send is saved to storage and will be sent after a restart. If the send time
+// is in the past, then the message is sent immediately.
+//
+// Returns the ID of the scheduled send, which can be used to cancel or
+// reschedule it. Returns ChannelDoesNotExistsErr if the channel has not been
+// joined and NotAnAdminErr if the user is not an admin of the channel.
+func (m *manager) ScheduleAdminSend(channelID *id.ID,
+	messageType MessageType, msg []byte, validUntil time.Duration,
+	tracked bool, sendTime time.Time, params cmix.CMIXParams) (uint64, error) {
+	jww.INFO.Printf("[CH] ScheduleAdminSend %s to channel %s at %s",
+		messageType, channelID, sendTime)
+	if _, err := m.getChannel(channelID); err != nil {
+		return 0, err
+	} else if !m.IsChannelAdmin(channelID) {
+		return 0, NotAnAdminErr
+	}
+
+	return m.scheduled.add(&ScheduledSend{
+		ChannelID:   channelID.DeepCopy(),
+		MessageType: messageType,
+		Payload:     msg,
+		ValidUntil:  validUntil,
+		Tracked:     tracked,
+		Admin:       true,
+		SendTime:    sendTime,
+		Params:      params,
+	})
+}
+
+// GetScheduledSends returns all pending and failed scheduled sends to the
+// channel, sorted by send time. If channelID is nil, then the sends for all
+// channels are returned.
+func (m *manager) GetScheduledSends(channelID *id.ID) []ScheduledSend {
+	return m.scheduled.get(channelID)
+}
+
+// CancelScheduledSend cancels the pending or failed scheduled send with the
+// given ID. Returns ScheduledSendNotFoundErr if the send does not exist, is
+// being sent, or has already been sent.
+func (m *manager) CancelScheduledSend(sendID uint64) error {
+	jww.INFO.Printf("[CH] CancelScheduledSend %d", sendID)
+	return m.scheduled.cancel(sendID)
+}
+
+// RescheduleSend changes the send time of the pending or failed scheduled send
+// with the given ID. A failed send is sent again at the new time. Returns
+// ScheduledSendNotFoundErr if the send does not exist, is being sent, or has
+// already been sent.
+func (m *manager) RescheduleSend(sendID uint64, sendTime time.Time) error {
+	jww.INFO.Printf("[CH] RescheduleSend %d to %s", sendID, sendTime)
+	return m.scheduled.reschedule(sendID, sendTime)
+}
+
+// RegisterScheduledSendFailedCallback registers the callback that is called
+// when a scheduled send fails. Only one callback can be registered; it replaces
+// any previously registered callback.
+func (m *manager) RegisterScheduledSendFailedCallback(
+	cb ScheduledSendFailedCallback) {
+	m.scheduled.RegisterFailedCallback(cb)
+}
+
+// sendScheduled sends a scheduled send once it is due. It is registered as the
+// scheduledSendFunc with the scheduledSendList.
+func (m *manager) sendScheduled(ss *ScheduledSend) error {
+	params := ss.Params.SetDebugTag(SendScheduledTag)
+
+	var err error
+	if ss.Admin {
+		_, _, _, err = m.SendAdminGeneric(ss.ChannelID, ss.MessageType,
+			ss.Payload, ss.ValidUntil, ss.Tracked, params)
+	} else {
+		_, _, _, err = m.SendGeneric(ss.ChannelID, ss.MessageType, ss.Payload,
+			ss.ValidUntil, ss.Tracked, params, ss.Pings)
+	}
+	if err != nil {
+		jww.ERROR.Printf("[CH] Failed to send scheduled send %d of %s to "+
+			"channel %s: %+v", ss.ID, ss.MessageType, ss.ChannelID, err)
+		return err
+	}
+
+	jww.INFO.Printf("[CH] Sent scheduled send %d of %s to channel %s",
+		ss.ID, ss.MessageType, ss.ChannelID)
+	return nil
+}
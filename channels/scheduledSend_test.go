@@ -0,0 +1,425 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	cryptoChannel "gitlab.com/elixxir/crypto/channel"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that newOrLoadScheduledSendList loads the scheduled sends saved to
+// storage in the order they will be sent.
+func Test_newOrLoadScheduledSendList(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	ssl, err := newOrLoadScheduledSendList(kv)
+	if err != nil {
+		t.Fatalf("Failed to make new scheduledSendList: %+v", err)
+	}
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	now := netTime.Now()
+	var expected []uint64
+	for _, delay := range []time.Duration{3, 1, 2} {
+		sendID, err := ssl.add(&ScheduledSend{
+			ChannelID:   channelID,
+			MessageType: Text,
+			Payload:     []byte(strconv.Itoa(int(delay))),
+			SendTime:    now.Add(delay * time.Hour),
+			Params:      cmix.GetDefaultCMIXParams(),
+		})
+		if err != nil {
+			t.Fatalf("Failed to add scheduled send: %+v", err)
+		}
+		expected = append(expected, sendID)
+	}
+	expected = []uint64{expected[1], expected[2], expected[0]}
+
+	loaded, err := newOrLoadScheduledSendList(kv)
+	if err != nil {
+		t.Fatalf("Failed to load scheduledSendList: %+v", err)
+	}
+
+	if loaded.nextID != ssl.nextID {
+		t.Errorf("Unexpected next ID.\nexpected: %d\nreceived: %d",
+			ssl.nextID, loaded.nextID)
+	}
+
+	sends := loaded.get(nil)
+	if len(sends) != len(expected) {
+		t.Fatalf("Unexpected number of sends.\nexpected: %d\nreceived: %d",
+			len(expected), len(sends))
+	}
+	for j, ss := range sends {
+		if ss.ID != expected[j] {
+			t.Errorf("Unexpected send %d.\nexpected: %d\nreceived: %d",
+				j, expected[j], ss.ID)
+		}
+		if !ss.ChannelID.Cmp(channelID) {
+			t.Errorf("Unexpected channel ID for send %d."+
+				"\nexpected: %s\nreceived: %s", j, channelID, ss.ChannelID)
+		}
+	}
+}
+
+// Tests that scheduledSendList.cancel and scheduledSendList.reschedule update
+// the list and that both return ScheduledSendNotFoundErr for an unknown send.
+func Test_scheduledSendList_cancel_reschedule(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	ssl, err := newOrLoadScheduledSendList(kv)
+	if err != nil {
+		t.Fatalf("Failed to make new scheduledSendList: %+v", err)
+	}
+
+	channelID1 := id.NewIdFromString("channel1", id.User, t)
+	channelID2 := id.NewIdFromString("channel2", id.User, t)
+	now := netTime.Now()
+	add := func(channelID *id.ID, delay time.Duration) uint64 {
+		sendID, err := ssl.add(&ScheduledSend{
+			ChannelID: channelID, SendTime: now.Add(delay)})
+		if err != nil {
+			t.Fatalf("Failed to add scheduled send: %+v", err)
+		}
+		return sendID
+	}
+	a := add(channelID1, time.Hour)
+	b := add(channelID2, 2*time.Hour)
+	c := add(channelID1, 3*time.Hour)
+
+	if err = ssl.reschedule(c, now); err != nil {
+		t.Fatalf("Failed to reschedule send: %+v", err)
+	}
+	if err = ssl.cancel(a); err != nil {
+		t.Fatalf("Failed to cancel send: %+v", err)
+	}
+
+	sends := ssl.get(nil)
+	if len(sends) != 2 || sends[0].ID != c || sends[1].ID != b {
+		t.Errorf("Unexpected sends after reschedule and cancel: %+v", sends)
+	}
+	if pending := ssl.pending(); pending != 2 {
+		t.Errorf("Unexpected number of pending sends."+
+			"\nexpected: %d\nreceived: %d", 2, pending)
+	}
+
+	sends = ssl.get(channelID2)
+	if len(sends) != 1 || sends[0].ID != b {
+		t.Errorf("Unexpected sends for channel %s: %+v", channelID2, sends)
+	}
+
+	if err = ssl.cancel(a); err != ScheduledSendNotFoundErr {
+		t.Errorf("Unexpected error when cancelling unknown send."+
+			"\nexpected: %v\nreceived: %+v", ScheduledSendNotFoundErr, err)
+	}
+	if err = ssl.reschedule(a, now); err != ScheduledSendNotFoundErr {
+		t.Errorf("Unexpected error when rescheduling unknown send."+
+			"\nexpected: %v\nreceived: %+v", ScheduledSendNotFoundErr, err)
+	}
+
+	if err = ssl.removeChannel(channelID1); err != nil {
+		t.Fatalf("Failed to remove channel: %+v", err)
+	}
+
+	// Changes must be persisted
+	loaded, err := newOrLoadScheduledSendList(kv)
+	if err != nil {
+		t.Fatalf("Failed to load scheduledSendList: %+v", err)
+	}
+	sends = loaded.get(nil)
+	if len(sends) != 1 || sends[0].ID != b {
+		t.Errorf("Unexpected sends after removing channel: %+v", sends)
+	}
+	if pending := loaded.pending(); pending != 1 {
+		t.Errorf("Unexpected number of pending sends after loading."+
+			"\nexpected: %d\nreceived: %d", 1, pending)
+	}
+}
+
+// Tests that scheduledSendList.scheduledSendsThread sends each message in order
+// once it is due, including sends that were due before the thread started, and
+// removes them from the list.
+func Test_scheduledSendList_scheduledSendsThread(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	ssl, err := newOrLoadScheduledSendList(kv)
+	if err != nil {
+		t.Fatalf("Failed to make new scheduledSendList: %+v", err)
+	}
+	sent := make(chan uint64, 10)
+	ssl.RegisterSendFn(func(ss *ScheduledSend) error {
+		sent <- ss.ID
+		return nil
+	})
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	now := netTime.Now()
+	var expected []uint64
+	for _, sendTime := range []time.Time{
+		now.Add(-time.Hour), now.Add(25 * time.Millisecond)} {
+		sendID, err := ssl.add(
+			&ScheduledSend{ChannelID: channelID, SendTime: sendTime})
+		if err != nil {
+			t.Fatalf("Failed to add scheduled send: %+v", err)
+		}
+		expected = append(expected, sendID)
+	}
+
+	stop, err := ssl.StartProcesses()
+	if err != nil {
+		t.Fatalf("Failed to start processes: %+v", err)
+	}
+
+	// Added after the thread starts
+	sendID, err := ssl.add(&ScheduledSend{
+		ChannelID: channelID, SendTime: now.Add(50 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("Failed to add scheduled send: %+v", err)
+	}
+	expected = append(expected, sendID)
+
+	for j, sendID := range expected {
+		select {
+		case received := <-sent:
+			if received != sendID {
+				t.Errorf("Unexpected send %d.\nexpected: %d\nreceived: %d",
+					j, sendID, received)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for send %d", j)
+		}
+	}
+
+	if err = stop.Close(); err != nil {
+		t.Errorf("Failed to close thread: %+v", err)
+	}
+	err = stoppable.WaitForStopped(stop, time.Second)
+	if err != nil {
+		t.Errorf("Thread did not stop: %+v", err)
+	}
+
+	// Sent messages are removed from storage once the send completes
+	for start := netTime.Now(); ; time.Sleep(time.Millisecond) {
+		loaded, err := newOrLoadScheduledSendList(kv)
+		if err != nil {
+			t.Fatalf("Failed to load scheduledSendList: %+v", err)
+		}
+		if sends := loaded.get(nil); len(sends) == 0 {
+			break
+		} else if netTime.Since(start) > time.Second {
+			t.Fatalf("Sent messages remain in storage: %+v", sends)
+		}
+	}
+}
+
+// Tests that a due send stays in storage until scheduledSendList.done is
+// called, and that a failed send is kept, marked as failed, reported to the
+// registered callback, and can be rescheduled or cancelled.
+func Test_scheduledSendList_done(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	ssl, err := newOrLoadScheduledSendList(kv)
+	if err != nil {
+		t.Fatalf("Failed to make new scheduledSendList: %+v", err)
+	}
+	failed := make(chan ScheduledSend, 1)
+	ssl.RegisterFailedCallback(func(ss ScheduledSend, err error) {
+		failed <- ss
+	})
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	now := netTime.Now()
+	a, _ := ssl.add(&ScheduledSend{ChannelID: channelID, SendTime: now})
+	b, _ := ssl.add(&ScheduledSend{ChannelID: channelID, SendTime: now})
+
+	due, _, _ := ssl.popDue(now)
+	if len(due) != 2 {
+		t.Fatalf("Unexpected number of due sends.\nexpected: %d\nreceived: %d",
+			2, len(due))
+	}
+	if err = ssl.cancel(a); err != ScheduledSendNotFoundErr {
+		t.Errorf("Unexpected error when cancelling send being sent."+
+			"\nexpected: %v\nreceived: %+v", ScheduledSendNotFoundErr, err)
+	}
+
+	// Sends being sent are resent on restart
+	loaded, err := newOrLoadScheduledSendList(kv)
+	if err != nil {
+		t.Fatalf("Failed to load scheduledSendList: %+v", err)
+	}
+	if sends := loaded.get(nil); len(sends) != 2 {
+		t.Errorf("Sends being sent were removed from storage: %+v", sends)
+	}
+
+	ssl.done(a, nil)
+	ssl.done(b, errors.New("send failed"))
+
+	select {
+	case ss := <-failed:
+		if ss.ID != b || !ss.Failed || ss.Error != "send failed" {
+			t.Errorf("Unexpected failed send: %+v", ss)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for failed callback")
+	}
+
+	loaded, err = newOrLoadScheduledSendList(kv)
+	if err != nil {
+		t.Fatalf("Failed to load scheduledSendList: %+v", err)
+	}
+	sends := loaded.get(nil)
+	if len(sends) != 1 || sends[0].ID != b || !sends[0].Failed {
+		t.Fatalf("Unexpected sends after done: %+v", sends)
+	}
+	if due, _, _ = loaded.popDue(now); len(due) != 0 {
+		t.Errorf("Failed send is due again: %+v", due)
+	}
+
+	if err = loaded.reschedule(b, now); err != nil {
+		t.Fatalf("Failed to reschedule failed send: %+v", err)
+	}
+	if due, _, _ = loaded.popDue(now); len(due) != 1 || due[0].Failed {
+		t.Errorf("Rescheduled send is not due: %+v", due)
+	}
+	loaded.done(b, errors.New("send failed"))
+	if err = loaded.cancel(b); err != nil {
+		t.Errorf("Failed to cancel failed send: %+v", err)
+	}
+	if sends = loaded.get(nil); len(sends) != 0 {
+		t.Errorf("Cancelled send remains: %+v", sends)
+	}
+}
+
+// Error path: Tests that scheduledSendList.StartProcesses returns an error when
+// no send function is registered.
+func Test_scheduledSendList_StartProcesses_NoSendFn(t *testing.T) {
+	ssl, err :=
+		newOrLoadScheduledSendList(versioned.NewKV(ekv.MakeMemstore()))
+	if err != nil {
+		t.Fatalf("Failed to make new scheduledSendList: %+v", err)
+	}
+
+	if _, err = ssl.StartProcesses(); err == nil {
+		t.Errorf("No error when starting without a send function.")
+	}
+}
+
+// Tests that manager.ScheduleAdminSend only schedules sends for joined
+// channels that the user is an admin of and that manager.sendScheduled sends
+// the scheduled message as the admin.
+func Test_manager_ScheduleAdminSend(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	prng := rand.New(rand.NewSource(64))
+	mem := ekv.MakeMemstore()
+	kv := versioned.NewKV(mem)
+	remote := collective.TestingKV(t, mem, collective.StandardPrefexs, nil)
+	remote, err := remote.Prefix(collective.StandardRemoteSyncPrefix)
+	require.NoError(t, err)
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	require.NoError(t, err)
+	scheduled, err := newOrLoadScheduledSendList(kv)
+	require.NoError(t, err)
+
+	m := &manager{
+		me:              pi,
+		channels:        make(map[id.ID]*joinedChannel),
+		local:           kv,
+		rng:             crng,
+		nicknameManager: &nicknameManager{byChannel: make(map[id.ID]string)},
+		st: loadSendTracker(&mockBroadcastClient{}, kv, func(*id.ID,
+			*userMessageInternal, []byte, time.Time,
+			receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+			uint64, error) {
+			return 0, nil
+		}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+			message.ID, receptionID.EphemeralIdentity, rounds.Round,
+			SentStatus) (uint64, error) {
+			return 0, nil
+		}, func(uint64, *message.ID, *time.Time, *rounds.Round, *bool, *bool,
+			*SentStatus) error {
+			return nil
+		}, crng),
+		adminKeysManager: newAdminKeysManager(remote, func(ch *id.ID, isAdmin bool) {}),
+		scheduled:        scheduled,
+	}
+
+	msg := []byte("hello world")
+	sendTime := netTime.Now().Add(-time.Minute)
+
+	ch, _, err := m.generateChannel("abc", "abc", cryptoBroadcast.Public, 1000)
+	require.NoError(t, err)
+	mbc := &mockBroadcastChannel{crypto: ch}
+
+	_, err = m.ScheduleAdminSend(ch.ReceptionID, Text, msg, time.Hour, false,
+		sendTime, cmix.GetDefaultCMIXParams())
+	if err != ChannelDoesNotExistsErr {
+		t.Errorf("Unexpected error for unjoined channel."+
+			"\nexpected: %v\nreceived: %+v", ChannelDoesNotExistsErr, err)
+	}
+	m.channels[*ch.ReceptionID] = &joinedChannel{broadcast: mbc}
+
+	notAdminID := id.NewIdFromString("notAdmin", id.User, t)
+	m.channels[*notAdminID] = &joinedChannel{}
+	_, err = m.ScheduleAdminSend(notAdminID, Text, msg, time.Hour, false,
+		sendTime, cmix.GetDefaultCMIXParams())
+	if err != NotAnAdminErr {
+		t.Errorf("Unexpected error for non-admin channel."+
+			"\nexpected: %v\nreceived: %+v", NotAnAdminErr, err)
+	}
+
+	sendID, err := m.ScheduleAdminSend(ch.ReceptionID, Text, msg, time.Hour,
+		false, sendTime, cmix.GetDefaultCMIXParams())
+	require.NoError(t, err)
+
+	sends := m.GetScheduledSends(ch.ReceptionID)
+	if len(sends) != 1 || sends[0].ID != sendID || !sends[0].Admin {
+		t.Fatalf("Unexpected scheduled sends: %+v", sends)
+	}
+
+	due, _, _ := m.scheduled.popDue(netTime.Now())
+	if len(due) != 1 {
+		t.Fatalf("Unexpected number of due sends.\nexpected: %d\nreceived: %d",
+			1, len(due))
+	}
+	if err = m.sendScheduled(due[0]); err != nil {
+		t.Fatalf("Failed to send scheduled send: %+v", err)
+	}
+	m.scheduled.done(due[0].ID, err)
+
+	chMgs := &ChannelMessage{}
+	if err = proto.Unmarshal(mbc.payload, chMgs); err != nil {
+		t.Fatalf("Could not proto unmarshal ChannelMessage: %+v", err)
+	}
+	if !bytes.Equal(chMgs.Payload, msg) {
+		t.Errorf("Incorrect message.\nexpected: %q\nreceived: %q",
+			msg, chMgs.Payload)
+	}
+
+	if err = m.CancelScheduledSend(sendID); err != ScheduledSendNotFoundErr {
+		t.Errorf("Unexpected error cancelling sent message."+
+			"\nexpected: %v\nreceived: %+v", ScheduledSendNotFoundErr, err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"crypto/ed25519"
 	"crypto/hmac"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"gitlab.com/elixxir/client/v4/emoji"
 	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
 	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/primitives/states"
 	"gitlab.com/xx_network/primitives/id"
 	"gitlab.com/xx_network/primitives/id/ephemeral"
 	"gitlab.com/xx_network/primitives/netTime"
@@ -38,6 +40,12 @@ const (
 	cmixChannelSilentVersion     = 0
 	cmixChannelDeleteVersion     = 0
 	cmixChannelPinVersion        = 0
+	cmixChannelEditVersion       = 0
+	cmixChannelPollVersion       = 0
+	cmixChannelVoteVersion       = 0
+	cmixChannelClosePollVersion  = 0
+	cmixChannelModeratorVersion  = 0
+	cmixChannelSlowModeVersion   = 0
 
 	// SendMessageTag is the base tag used when generating a debug tag for
 	// sending a message.
@@ -71,12 +79,45 @@ const (
 	// message.
 	SendMuteTag = "ChMute"
 
+	// SendEditTag is the base tag used when generating a debug tag for an edit
+	// message.
+	SendEditTag = "ChEdit"
+
+	// SendPollTag is the base tag used when generating a debug tag for sending
+	// a poll.
+	SendPollTag = "ChPoll"
+
+	// SendVoteTag is the base tag used when generating a debug tag for a vote
+	// message.
+	SendVoteTag = "ChVote"
+
+	// SendClosePollTag is the base tag used when generating a debug tag for a
+	// close poll message.
+	SendClosePollTag = "ChClosePoll"
+
+	// SendModeratorTag is the base tag used when generating a debug tag for a
+	// moderator grant or revocation message.
+	SendModeratorTag = "ChModerator"
+
+	// SendSlowModeTag is the base tag used when generating a debug tag for a
+	// slow mode message.
+	SendSlowModeTag = "ChSlowMode"
+
 	// SendAdminReplayTag is the base tag used when generating a debug tag for
 	// an admin replay message.
 	SendAdminReplayTag = "ChAdminReplay"
 
+	// SendScheduledTag is the base tag used when generating a debug tag for a
+	// scheduled send.
+	SendScheduledTag = "ChScheduled"
+
 	// The size of the nonce used in the message ID.
 	messageNonceSize = 4
+
+	// minPollOptions and maxPollOptions are the bounds on the number of options
+	// a poll can have.
+	minPollOptions = 2
+	maxPollOptions = 16
 )
 
 var emptyChannelID = &id.ID{}
@@ -130,6 +171,18 @@ func (m *manager) SendGeneric(channelID *id.ID, messageType MessageType,
 			errors.Errorf("user muted in channel %s", channelID)
 	}
 
+	// Reject the send if the channel is in slow mode and the user posted too
+	// recently. This is checked before sending so that no round is wasted on a
+	// message that every other client will hide. Moderators are exempt.
+	now := netTime.Now()
+	if !m.events.moderators.isModerator(channelID, m.me.PubKey, now) {
+		err := m.events.slowMode.checkPost(
+			channelID, m.me.PubKey, messageType, now)
+		if err != nil {
+			return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+		}
+	}
+
 	// Note: We log sends on exit, and append what happened to the message
 	// this cuts down on clutter in the log.
 	log := fmt.Sprintf(
@@ -260,6 +313,15 @@ func (m *manager) SendGeneric(channelID *id.ID, messageType MessageType,
 	log += fmt.Sprintf(
 		"Broadcast succeeded at %s on round %d, success!", timeNow(), r.ID)
 
+	// Record the post so that later sends respect the channel's slow mode.
+	// Other clients limit posts by their round timestamp.
+	roundTS, exists := r.Timestamps[states.QUEUED]
+	if !exists {
+		roundTS = netTime.Now()
+	}
+	m.events.slowMode.recordPost(
+		channelID, m.me.PubKey, messageType, roundTS)
+
 	if tracked {
 		err = m.st.send(uuid, messageID, r)
 		if err != nil {
@@ -493,6 +555,182 @@ func (m *manager) SendInvite(channelID *id.ID, msg string,
 		validUntil, true, params, pingMap)
 }
 
+// SendEdit is used to replace the text of a message previously sent by this
+// user. Only the original sender of a message may edit it and only text
+// messages can be edited.
+//
+// Clients will drop the edit if they do not recognize the target message or if
+// it was not sent by the same user.
+func (m *manager) SendEdit(channelID *id.ID, targetMessage message.ID,
+	newText string, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, targetMessage.Bytes(), SendEditTag)
+	jww.INFO.Printf("[CH] [%s] Edit message %s in channel %s",
+		tag, targetMessage, channelID)
+
+	if _, ok := m.events.model.(EditEventModel); !ok {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, EditsUnsupportedErr
+	}
+
+	msg, err := m.events.model.GetMessage(targetMessage)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"failed to find targeted message %s to edit: %+v",
+			targetMessage, err)
+	} else if !bytes.Equal(msg.PubKey, m.me.PubKey) {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{},
+			errors.New("can only edit message you are sender of")
+	} else if msg.Type != Text {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"can only edit messages of type %s, message is %s", Text, msg.Type)
+	}
+
+	edit := &CMIXChannelEdit{
+		Version:   cmixChannelEditVersion,
+		MessageID: targetMessage.Bytes(),
+		Text:      newText,
+	}
+
+	params = params.SetDebugTag(tag)
+
+	editMarshaled, err := proto.Marshal(edit)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return m.SendGeneric(
+		channelID, Edit, editMarshaled, ValidForever, false, params, nil)
+}
+
+// SendPoll is used to send to a channel a poll that members can vote on using
+// SendVote. Each option must be unique and there must be between 2 and 16
+// options.
+//
+// See [Manager.SendGeneric] for details on payload size limitations.
+func (m *manager) SendPoll(channelID *id.ID, question string, options []string,
+	validUntil time.Duration, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, []byte(question), SendPollTag)
+	jww.INFO.Printf("[CH] [%s] SendPoll on channel %s with %d options",
+		tag, channelID, len(options))
+
+	if _, ok := m.events.model.(PollEventModel); !ok {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, PollsUnsupportedErr
+	}
+
+	if err := validatePoll(question, options); err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	poll := &CMIXChannelPoll{
+		Version:  cmixChannelPollVersion,
+		Question: question,
+		Options:  options,
+	}
+
+	params = params.SetDebugTag(tag)
+
+	pollMarshaled, err := proto.Marshal(poll)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return m.SendGeneric(
+		channelID, Poll, pollMarshaled, validUntil, true, params, nil)
+}
+
+// SendVote is used to vote for an option of a poll, where option is the index
+// of the option in the poll. Only the most recent vote from each user is
+// counted, so a vote can be changed by voting again.
+//
+// Clients will drop the vote if they do not recognize the poll or if the poll
+// was closed before the vote was sent.
+func (m *manager) SendVote(channelID *id.ID, pollID message.ID, option uint32,
+	params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(channelID, m.me.PubKey, pollID.Bytes(), SendVoteTag)
+	jww.INFO.Printf("[CH] [%s] Vote for option %d of poll %s in channel %s",
+		tag, option, pollID, channelID)
+
+	if _, ok := m.events.model.(PollEventModel); !ok {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, PollsUnsupportedErr
+	}
+
+	poll, err := getPoll(m.events.model, channelID, pollID)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	} else if int(option) >= len(poll.Options) {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"option %d is out of range for poll with %d options",
+			option, len(poll.Options))
+	}
+
+	vote := &CMIXChannelVote{
+		Version:       cmixChannelVoteVersion,
+		PollMessageID: pollID.Bytes(),
+		Option:        option,
+	}
+
+	params = params.SetDebugTag(tag)
+
+	voteMarshaled, err := proto.Marshal(vote)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return m.SendGeneric(
+		channelID, Vote, voteMarshaled, ValidForever, false, params, nil)
+}
+
+// validatePoll returns an error if the poll has no question, has too few or
+// too many options, or has empty or duplicate options.
+func validatePoll(question string, options []string) error {
+	if question == "" {
+		return errors.New("poll must have a question")
+	} else if len(options) < minPollOptions || len(options) > maxPollOptions {
+		return errors.Errorf("poll must have between %d and %d options, "+
+			"has %d", minPollOptions, maxPollOptions, len(options))
+	}
+
+	seen := make(map[string]struct{}, len(options))
+	for i, option := range options {
+		if option == "" {
+			return errors.Errorf("poll option %d is empty", i)
+		} else if _, exists := seen[option]; exists {
+			return errors.Errorf("poll option %d (%q) is a duplicate", i, option)
+		}
+		seen[option] = struct{}{}
+	}
+
+	return nil
+}
+
+// getPoll returns the poll with the given [message.ID] from the event model.
+// Returns an error if the message does not exist, is not a poll, or is in a
+// different channel.
+func getPoll(model EventModel, channelID *id.ID, pollID message.ID) (
+	*CMIXChannelPoll, error) {
+	msg, err := model.GetMessage(pollID)
+	if err != nil {
+		return nil, errors.Errorf("failed to find poll %s: %+v", pollID, err)
+	} else if msg.Type != Poll {
+		return nil, errors.Errorf(
+			"message %s is of type %s, not a poll", pollID, msg.Type)
+	} else if msg.ChannelID != nil && !msg.ChannelID.Cmp(channelID) {
+		return nil, errors.Errorf(
+			"poll %s is in channel %s, not %s", pollID, msg.ChannelID, channelID)
+	}
+
+	poll := &CMIXChannelPoll{}
+	if err = json.Unmarshal(msg.Content, poll); err != nil {
+		return nil, errors.Errorf(
+			"failed to JSON unmarshal poll %s: %+v", pollID, err)
+	}
+
+	return poll, nil
+}
+
 // replayAdminMessage is used to rebroadcast an admin message asa a norma user.
 func (m *manager) replayAdminMessage(channelID *id.ID, encryptedPayload []byte,
 	params cmix.CMIXParams) (message.ID,
@@ -668,9 +906,10 @@ func (m *manager) SendAdminGeneric(channelID *id.ID, messageType MessageType,
 }
 
 // DeleteMessage deletes the targeted message from storage. Users may delete
-// their own messages but only the channel admin can delete other user's
-// messages. If the user is not an admin of the channel or if they are not the
-// sender of the targetMessage, then the error NotAnAdminErr is returned.
+// their own messages but only the channel admin and moderators with
+// DeletePermission can delete other user's messages. If the user is not an
+// admin or moderator of the channel or if they are not the sender of the
+// targetMessage, then an error is returned.
 //
 // Clients will drop the deletion if they do not recognize the target message.
 func (m *manager) DeleteMessage(channelID *id.ID,
@@ -684,7 +923,7 @@ func (m *manager) DeleteMessage(channelID *id.ID,
 	// Load private key from storage. If it does not exist, then check if the
 	// user is the sender of the message to delete.
 	isChannelAdmin := m.IsChannelAdmin(channelID)
-	if !isChannelAdmin {
+	if !isChannelAdmin && !m.isModerator(channelID, Delete) {
 		msg, err := m.events.model.GetMessage(targetMessage)
 		if err != nil {
 			return message.ID{}, rounds.Round{}, ephemeral.Id{},
@@ -696,7 +935,7 @@ func (m *manager) DeleteMessage(channelID *id.ID,
 		if !bytes.Equal(msg.PubKey, m.me.PubKey) {
 			return message.ID{}, rounds.Round{}, ephemeral.Id{},
 				errors.Errorf("can only delete message you are sender of " +
-					"or if you are the channel admin.")
+					"or if you are the channel admin or a moderator.")
 		}
 	}
 
@@ -722,7 +961,9 @@ func (m *manager) DeleteMessage(channelID *id.ID,
 }
 
 // PinMessage pins the target message to the top of a channel view for all
-// users in the specified channel. Only the channel admin can pin user messages.
+// users in the specified channel. Only the channel admin and moderators with
+// PinPermission can pin user messages. Moderators send the pin as a normal user
+// message, so its lease is limited to MessageLife.
 //
 // If undoAction is true, then the targeted message is unpinned.
 //
@@ -754,15 +995,21 @@ func (m *manager) PinMessage(channelID *id.ID,
 
 	params = params.SetDebugTag(tag)
 
+	if !m.IsChannelAdmin(channelID) && m.isModerator(channelID, Pinned) {
+		return m.SendGeneric(channelID, Pinned, pinnedMarshaled,
+			capModeratorLease(validUntil), false, params, nil)
+	}
+
 	return m.SendAdminGeneric(
 		channelID, Pinned, pinnedMarshaled, validUntil, false, params)
 }
 
 // MuteUser is used to mute a user in a channel. Muting a user will cause all
 // future messages from the user being dropped on reception. Muted users are
-// also unable to send messages. Only the channel admin can mute a user; if the
-// user is not an admin of the channel, then the error NotAnAdminErr is
-// returned.
+// also unable to send messages. Only the channel admin and moderators with
+// MutePermission can mute a user; if the user is not an admin or moderator of
+// the channel, then the error NotAnAdminErr is returned. Moderators send the
+// mute as a normal user message, so its lease is limited to MessageLife.
 //
 // If undoAction is true, then the targeted user will be unmuted.
 func (m *manager) MuteUser(channelID *id.ID, mutedUser ed25519.PublicKey,
@@ -791,10 +1038,178 @@ func (m *manager) MuteUser(channelID *id.ID, mutedUser ed25519.PublicKey,
 		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
 	}
 
+	if !m.IsChannelAdmin(channelID) && m.isModerator(channelID, Mute) {
+		return m.SendGeneric(channelID, Mute, mutedMarshaled,
+			capModeratorLease(validUntil), false, params, nil)
+	}
+
 	return m.SendAdminGeneric(
 		channelID, Mute, mutedMarshaled, validUntil, false, params)
 }
 
+// ClosePoll is used to close a poll so that votes sent at or after the closing
+// time are no longer counted. Only the channel admin can close a poll; if the
+// user is not an admin of the channel, then the error NotAnAdminErr is
+// returned.
+//
+// Closing a poll again replaces the previous closing time, so a poll can be
+// reopened by closing it at a time in the future.
+func (m *manager) ClosePoll(channelID *id.ID, pollID message.ID,
+	closingTime time.Time, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, pollID.Bytes(), SendClosePollTag)
+	jww.INFO.Printf("[CH] [%s] Close poll %s in channel %s at %s",
+		tag, pollID, channelID, closingTime)
+
+	if _, ok := m.events.model.(PollEventModel); !ok {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, PollsUnsupportedErr
+	}
+
+	closeMessage := &CMIXChannelClosePoll{
+		Version:       cmixChannelClosePollVersion,
+		PollMessageID: pollID.Bytes(),
+		ClosingTime:   closingTime.UnixNano(),
+	}
+
+	params = params.SetDebugTag(tag)
+
+	closeMarshaled, err := proto.Marshal(closeMessage)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return m.SendAdminGeneric(
+		channelID, ClosePoll, closeMarshaled, ValidForever, false, params)
+}
+
+// GrantModerator grants the user permission to perform the given actions in
+// the channel on behalf of the admin until validUntil has elapsed. Granting
+// permissions to an existing moderator replaces their previous grant. Only the
+// channel admin can grant moderator permissions; if the user is not an admin
+// of the channel, then the error NotAnAdminErr is returned.
+func (m *manager) GrantModerator(channelID *id.ID,
+	moderator ed25519.PublicKey, permissions ModeratorPermission,
+	validUntil time.Duration, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(channelID, m.me.PubKey, moderator, SendModeratorTag)
+	jww.INFO.Printf("[CH] [%s] Grant moderator %x permissions %s in channel "+
+		"%s for %s", tag, moderator, permissions, channelID, validUntil)
+
+	if len(moderator) != ed25519.PublicKeySize {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"moderator public key must be %d bytes, received %d bytes",
+			ed25519.PublicKeySize, len(moderator))
+	} else if permissions == 0 ||
+		permissions&^AllModeratorPermissions != 0 {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"invalid moderator permissions %b", uint32(permissions))
+	}
+
+	return m.sendModerator(channelID, &CMIXChannelModerator{
+		Version:     cmixChannelModeratorVersion,
+		PubKey:      moderator,
+		Permissions: uint32(permissions),
+	}, validUntil, params.SetDebugTag(tag))
+}
+
+// RevokeModerator revokes all moderator permissions of the user in the
+// channel. Only the channel admin can revoke moderator permissions; if the user
+// is not an admin of the channel, then the error NotAnAdminErr is returned.
+func (m *manager) RevokeModerator(channelID *id.ID,
+	moderator ed25519.PublicKey, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(channelID, m.me.PubKey, moderator, SendModeratorTag)
+	jww.INFO.Printf("[CH] [%s] Revoke moderator %x in channel %s",
+		tag, moderator, channelID)
+
+	return m.sendModerator(channelID, &CMIXChannelModerator{
+		Version:    cmixChannelModeratorVersion,
+		PubKey:     moderator,
+		UndoAction: true,
+	}, ValidForever, params.SetDebugTag(tag))
+}
+
+// sendModerator sends the moderator grant or revocation as the channel admin.
+func (m *manager) sendModerator(channelID *id.ID,
+	moderatorMsg *CMIXChannelModerator, validUntil time.Duration,
+	params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	moderatorMarshaled, err := proto.Marshal(moderatorMsg)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return m.SendAdminGeneric(
+		channelID, Moderator, moderatorMarshaled, validUntil, false, params)
+}
+
+// SetSlowMode puts the channel in slow mode, limiting each user to one post per
+// interval until validUntil has elapsed. Setting slow mode replaces any previous
+// slow mode in the channel. Only the channel admin can set slow mode; if the
+// user is not an admin of the channel, then the error NotAnAdminErr is
+// returned.
+func (m *manager) SetSlowMode(channelID *id.ID, interval,
+	validUntil time.Duration, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(
+		channelID, m.me.PubKey, []byte(interval.String()), SendSlowModeTag)
+	jww.INFO.Printf("[CH] [%s] Set slow mode with interval %s in channel %s "+
+		"for %s", tag, interval, channelID, validUntil)
+
+	if interval <= 0 {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
+			"slow mode interval must be positive, received %s", interval)
+	}
+
+	return m.sendSlowMode(channelID, &CMIXChannelSlowMode{
+		Version:  cmixChannelSlowModeVersion,
+		Interval: int64(interval),
+	}, validUntil, params.SetDebugTag(tag))
+}
+
+// DisableSlowMode turns off slow mode in the channel. Only the channel admin
+// can disable slow mode; if the user is not an admin of the channel, then the
+// error NotAnAdminErr is returned.
+func (m *manager) DisableSlowMode(channelID *id.ID, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeChaDebugTag(channelID, m.me.PubKey, nil, SendSlowModeTag)
+	jww.INFO.Printf("[CH] [%s] Disable slow mode in channel %s", tag, channelID)
+
+	return m.sendSlowMode(channelID, &CMIXChannelSlowMode{
+		Version:    cmixChannelSlowModeVersion,
+		UndoAction: true,
+	}, ValidForever, params.SetDebugTag(tag))
+}
+
+// sendSlowMode sends the slow mode setting as the channel admin.
+func (m *manager) sendSlowMode(channelID *id.ID,
+	slowModeMsg *CMIXChannelSlowMode, validUntil time.Duration,
+	params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	slowModeMarshaled, err := proto.Marshal(slowModeMsg)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return m.SendAdminGeneric(
+		channelID, SlowMode, slowModeMarshaled, validUntil, false, params)
+}
+
+// isModerator returns true if the user is currently a moderator of the channel
+// with permission to perform the action.
+func (m *manager) isModerator(channelID *id.ID, action MessageType) bool {
+	return m.events.moderators.hasPermission(
+		channelID, m.me.PubKey, action, netTime.Now())
+}
+
+// capModeratorLease limits the lease of a moderator action to the longest lease
+// that receivers accept.
+func capModeratorLease(validUntil time.Duration) time.Duration {
+	if validUntil > maxModeratorActionLease {
+		return maxModeratorActionLease
+	}
+	return validUntil
+}
+
 // makeChaDebugTag is a debug helper that creates non-unique msg identifier.
 //
 // This is set as the debug tag on messages and enables some level of tracing a
@@ -10,7 +10,9 @@ package channels
 import (
 	"bytes"
 	"crypto/ed25519"
+	"encoding/json"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 
@@ -662,6 +664,236 @@ func Test_manager_DeleteMessage(t *testing.T) {
 	}
 }
 
+func Test_manager_SendEdit(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	prng := rand.New(rand.NewSource(64))
+	mem := ekv.MakeMemstore()
+	kv := versioned.NewKV(mem)
+	remote := collective.TestingKV(t, mem, collective.StandardPrefexs, nil)
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatalf("GenerateIdentity error: %+v", err)
+	}
+
+	me := &MockEvent{}
+	m := &manager{
+		me:              pi,
+		channels:        make(map[id.ID]*joinedChannel),
+		local:           kv,
+		rng:             crng,
+		events:          initEvents(me, 512, kv, crng),
+		nicknameManager: &nicknameManager{byChannel: make(map[id.ID]string), remote: nil},
+		st: loadSendTracker(&mockBroadcastClient{}, kv, func(*id.ID,
+			*userMessageInternal, []byte, time.Time,
+			receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+			uint64, error) {
+			return 0, nil
+		}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+			message.ID, receptionID.EphemeralIdentity, rounds.Round,
+			SentStatus) (uint64, error) {
+			return 0, nil
+		}, func(uint64, *message.ID, *time.Time, *rounds.Round, *bool, *bool,
+			*SentStatus) error {
+			return nil
+		}, crng),
+		adminKeysManager: newAdminKeysManager(remote, func(ch *id.ID, isAdmin bool) {}),
+	}
+
+	rng := crng.GetStream()
+	defer rng.Close()
+	channelID, _ := id.NewRandomID(rng, id.User)
+	targetedMessageID := message.ID{56}
+	mbc := &mockBroadcastChannel{}
+	m.channels[*channelID] = &joinedChannel{broadcast: mbc}
+
+	// Editing a message sent by another user fails
+	otherPubKey, _, _ := ed25519.GenerateKey(prng)
+	me.ReceiveMessage(channelID, targetedMessageID, "nick", "old", otherPubKey,
+		0, 0, netTime.Now(), ValidForever, rounds.Round{}, Text, Delivered,
+		false)
+	_, _, _, err =
+		m.SendEdit(channelID, targetedMessageID, "new", cmix.CMIXParams{})
+	if err == nil {
+		t.Errorf("SendEdit did not fail for message from another user.")
+	}
+
+	me.ReceiveMessage(channelID, targetedMessageID, "nick", "old", pi.PubKey,
+		0, 0, netTime.Now(), ValidForever, rounds.Round{}, Text, Delivered,
+		false)
+	messageID, _, _, err :=
+		m.SendEdit(channelID, targetedMessageID, "new", cmix.CMIXParams{})
+	if err != nil {
+		t.Fatalf("SendEdit error: %+v", err)
+	}
+
+	// Decode the user message
+	umi, err := unmarshalUserMessageInternal(mbc.payload, channelID, Edit)
+	if err != nil {
+		t.Fatalf("Failed to decode the user message: %+v", err)
+	}
+
+	if !umi.GetMessageID().Equals(messageID) {
+		t.Errorf("Incorrect message ID.\nexpected: %s\nreceived: %s",
+			messageID, umi.messageID)
+	}
+
+	// Decode the edit message
+	editMsg := &CMIXChannelEdit{}
+	err = proto.Unmarshal(umi.GetChannelMessage().Payload, editMsg)
+	if err != nil {
+		t.Fatalf("Could not proto unmarshal CMIXChannelEdit: %+v", err)
+	}
+
+	if editMsg.Text != "new" {
+		t.Errorf("Incorrect text.\nexpected: %s\nreceived: %s",
+			"new", editMsg.Text)
+	}
+
+	if !bytes.Equal(editMsg.MessageID, targetedMessageID[:]) {
+		t.Errorf("Incorrect MessageID.\nexpected: %v\nreceived: %v",
+			targetedMessageID, editMsg.MessageID)
+	}
+}
+
+func Test_manager_SendPoll_SendVote(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	prng := rand.New(rand.NewSource(64))
+	mem := ekv.MakeMemstore()
+	kv := versioned.NewKV(mem)
+	remote := collective.TestingKV(t, mem, collective.StandardPrefexs, nil)
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatalf("GenerateIdentity error: %+v", err)
+	}
+
+	me := &MockEvent{}
+	m := &manager{
+		me:              pi,
+		channels:        make(map[id.ID]*joinedChannel),
+		local:           kv,
+		rng:             crng,
+		events:          initEvents(me, 512, kv, crng),
+		nicknameManager: &nicknameManager{byChannel: make(map[id.ID]string), remote: nil},
+		st: loadSendTracker(&mockBroadcastClient{}, kv, func(*id.ID,
+			*userMessageInternal, []byte, time.Time,
+			receptionID.EphemeralIdentity, rounds.Round, SentStatus) (
+			uint64, error) {
+			return 0, nil
+		}, func(*id.ID, *ChannelMessage, MessageType, []byte, time.Time,
+			message.ID, receptionID.EphemeralIdentity, rounds.Round,
+			SentStatus) (uint64, error) {
+			return 0, nil
+		}, func(uint64, *message.ID, *time.Time, *rounds.Round, *bool, *bool,
+			*SentStatus) error {
+			return nil
+		}, crng),
+		adminKeysManager: newAdminKeysManager(remote, func(ch *id.ID, isAdmin bool) {}),
+	}
+
+	rng := crng.GetStream()
+	defer rng.Close()
+	channelID, _ := id.NewRandomID(rng, id.User)
+	mbc := &mockBroadcastChannel{}
+	m.channels[*channelID] = &joinedChannel{broadcast: mbc}
+
+	invalid := [][]string{nil, {"a"}, {"a", ""}, {"a", "a"}, make([]string, 17)}
+	for j, options := range invalid {
+		_, _, _, err = m.SendPoll(
+			channelID, "question", options, time.Hour, cmix.CMIXParams{})
+		if err == nil {
+			t.Errorf("SendPoll did not fail for invalid options %d: %q",
+				j, options)
+		}
+	}
+
+	question, options := "Pizza?", []string{"Yes", "No"}
+	pollID, _, _, err :=
+		m.SendPoll(channelID, question, options, time.Hour, cmix.CMIXParams{})
+	if err != nil {
+		t.Fatalf("SendPoll error: %+v", err)
+	}
+
+	umi, err := unmarshalUserMessageInternal(mbc.payload, channelID, Poll)
+	if err != nil {
+		t.Fatalf("Failed to decode the user message: %+v", err)
+	} else if !umi.GetMessageID().Equals(pollID) {
+		t.Errorf("Incorrect message ID.\nexpected: %s\nreceived: %s",
+			pollID, umi.messageID)
+	}
+	poll := &CMIXChannelPoll{}
+	err = proto.Unmarshal(umi.GetChannelMessage().Payload, poll)
+	if err != nil {
+		t.Fatalf("Could not proto unmarshal CMIXChannelPoll: %+v", err)
+	} else if poll.Question != question ||
+		!reflect.DeepEqual(poll.Options, options) {
+		t.Errorf("Incorrect poll.\nexpected: %s %q\nreceived: %s %q",
+			question, options, poll.Question, poll.Options)
+	}
+
+	// Deliver the poll to the event model so that it can be voted on
+	pollJson, _ := json.Marshal(poll)
+	me.ReceiveMessage(channelID, pollID, "nick", string(pollJson), pi.PubKey,
+		0, 0, netTime.Now(), time.Hour, rounds.Round{}, Poll, Delivered, false)
+
+	_, _, _, err = m.SendVote(channelID, pollID, 2, cmix.CMIXParams{})
+	if err == nil {
+		t.Errorf("SendVote did not fail for out of range option.")
+	}
+
+	voteID, _, _, err := m.SendVote(channelID, pollID, 1, cmix.CMIXParams{})
+	if err != nil {
+		t.Fatalf("SendVote error: %+v", err)
+	}
+
+	umi, err = unmarshalUserMessageInternal(mbc.payload, channelID, Vote)
+	if err != nil {
+		t.Fatalf("Failed to decode the user message: %+v", err)
+	} else if !umi.GetMessageID().Equals(voteID) {
+		t.Errorf("Incorrect message ID.\nexpected: %s\nreceived: %s",
+			voteID, umi.messageID)
+	}
+	vote := &CMIXChannelVote{}
+	err = proto.Unmarshal(umi.GetChannelMessage().Payload, vote)
+	if err != nil {
+		t.Fatalf("Could not proto unmarshal CMIXChannelVote: %+v", err)
+	} else if !bytes.Equal(vote.PollMessageID, pollID.Marshal()) ||
+		vote.Option != 1 {
+		t.Errorf("Incorrect vote.\nexpected: %s 1\nreceived: %x %d",
+			pollID, vote.PollMessageID, vote.Option)
+	}
+}
+
+// Tests that manager.SendPoll, manager.SendVote, and manager.ClosePoll return
+// PollsUnsupportedErr when the EventModel does not implement PollEventModel.
+func Test_manager_SendPoll_Unsupported(t *testing.T) {
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	prng := rand.New(rand.NewSource(64))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	pi, err := cryptoChannel.GenerateIdentity(prng)
+	if err != nil {
+		t.Fatalf("GenerateIdentity error: %+v", err)
+	}
+
+	// Embedding the interface hides the PollEventModel methods of MockEvent
+	model := struct{ EventModel }{&MockEvent{}}
+	m := &manager{
+		me:     pi,
+		events: initEvents(model, 512, kv, crng),
+	}
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	params := cmix.GetDefaultCMIXParams()
+	_, _, _, err = m.SendPoll(
+		channelID, "Question?", []string{"A", "B"}, ValidForever, params)
+	require.ErrorIs(t, err, PollsUnsupportedErr)
+
+	_, _, _, err = m.SendVote(channelID, message.ID{1}, 0, params)
+	require.ErrorIs(t, err, PollsUnsupportedErr)
+
+	_, _, _, err = m.ClosePoll(channelID, message.ID{1}, netTime.Now(), params)
+	require.ErrorIs(t, err, PollsUnsupportedErr)
+}
+
 func Test_manager_PinMessage(t *testing.T) {
 	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
 	mem := ekv.MakeMemstore()
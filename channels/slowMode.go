@@ -0,0 +1,414 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Storage values.
+const (
+	slowModeStoreVer = 0
+	slowModeStoreKey = "channelSlowMode"
+
+	slowModePostsStoreVer       = 0
+	slowModePostsStoreKeyPrefix = "channelSlowModePosts/"
+)
+
+// maxSlowModePosters is the maximum number of users whose last post is tracked
+// in each channel. When exceeded, the users who posted least recently are
+// evicted.
+const maxSlowModePosters = 1024
+
+// Error messages.
+const (
+	// slowModeManager.save
+	storeSlowModeErr = "could not store channel slow mode: %+v"
+
+	// slowModeManager.savePosts
+	storeSlowModePostsErr = "could not store slow mode posts for channel %s: %+v"
+)
+
+// isSlowModePost returns true if messages of the given MessageType count as a
+// post under slow mode. Actions on existing messages, such as deletions and
+// edits, are not limited.
+func isSlowModePost(mt MessageType) bool {
+	switch mt {
+	case Text, Reaction, Silent, Invitation, Poll, FileTransfer:
+		return true
+	default:
+		return false
+	}
+}
+
+// slowModeSetting is the slow mode configuration of a channel.
+type slowModeSetting struct {
+	// Interval is the minimum duration between posts from each user.
+	Interval time.Duration `json:"interval"`
+
+	// Expiry is when slow mode ends. It is zero if slow mode never expires.
+	Expiry time.Time `json:"expiry"`
+}
+
+// active returns true if slow mode has not expired by the given time.
+func (sms slowModeSetting) active(ts time.Time) bool {
+	return sms.Expiry.IsZero() || ts.Before(sms.Expiry)
+}
+
+// slowModeManager tracks the slow mode setting made by the admin in each
+// channel and the time each user last posted to it. Settings are received from
+// the channel admin and are stored until slow mode is disabled, it expires, or
+// the channel is left.
+//
+// The time of the last post from each user is the round timestamp of the post,
+// so that every client makes the same decision regardless of the timestamp
+// chosen by the sender. It is stored for each channel under its own key so
+// that limits survive restarts. Only posts within the interval of the latest
+// post are kept, up to maxSlowModePosters per channel.
+type slowModeManager struct {
+	// The slow mode setting of each channel
+	settings map[id.ID]slowModeSetting
+
+	// The round timestamp of the last accepted post from each user in each
+	// channel. The internal map keys on mutedUserKey, which is derived from the
+	// user's public key.
+	lastPost map[id.ID]map[mutedUserKey]time.Time
+
+	mux sync.RWMutex
+	kv  versioned.KV
+}
+
+// newOrLoadSlowModeManager loads an existing slowModeManager from storage, if
+// it exists. Otherwise, it initialises a new empty slowModeManager.
+func newOrLoadSlowModeManager(kv versioned.KV) (*slowModeManager, error) {
+	smm := &slowModeManager{
+		settings: make(map[id.ID]slowModeSetting),
+		lastPost: make(map[id.ID]map[mutedUserKey]time.Time),
+		kv:       kv,
+	}
+
+	obj, err := kv.Get(slowModeStoreKey, slowModeStoreVer)
+	if err != nil {
+		if kv.Exists(err) {
+			return nil, err
+		}
+		return smm, nil
+	}
+
+	var disk map[string]slowModeSetting
+	if err = json.Unmarshal(obj.Data, &disk); err != nil {
+		return nil, err
+	}
+	for chID, setting := range disk {
+		channelID, err2 := unmarshalChID(chID)
+		if err2 != nil {
+			return nil, err2
+		}
+		smm.settings[*channelID] = setting
+
+		posts, err2 := smm.loadPosts(channelID)
+		if err2 != nil {
+			return nil, err2
+		}
+		if len(posts) > 0 {
+			smm.lastPost[*channelID] = posts
+		}
+	}
+
+	return smm, nil
+}
+
+// enable sets the slow mode interval of the channel until the expiry,
+// replacing any previous setting.
+func (smm *slowModeManager) enable(
+	channelID *id.ID, interval time.Duration, expiry time.Time) {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	smm.settings[*channelID] = slowModeSetting{
+		Interval: interval,
+		Expiry:   expiry,
+	}
+
+	if err := smm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save slow mode: %+v", err)
+	}
+}
+
+// disable turns off slow mode in the channel.
+func (smm *slowModeManager) disable(channelID *id.ID) {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	if _, exists := smm.settings[*channelID]; !exists {
+		return
+	}
+
+	delete(smm.settings, *channelID)
+	delete(smm.lastPost, *channelID)
+
+	if err := smm.save(); err != nil {
+		jww.FATAL.Panicf("[CH] Failed to save slow mode: %+v", err)
+	}
+	if err := smm.deletePosts(channelID); err != nil {
+		jww.ERROR.Printf("[CH] Failed to delete slow mode posts for channel "+
+			"%s: %+v", channelID, err)
+	}
+}
+
+// getInterval returns the slow mode interval of the channel. Returns zero if
+// slow mode is disabled or has expired.
+func (smm *slowModeManager) getInterval(channelID *id.ID) time.Duration {
+	smm.mux.RLock()
+	defer smm.mux.RUnlock()
+
+	setting, exists := smm.settings[*channelID]
+	if !exists || !setting.active(netTime.Now()) {
+		return 0
+	}
+	return setting.Interval
+}
+
+// allowPost returns true if the user may post a message of the given type in
+// the channel at the round timestamp. If the post is allowed, it is recorded as
+// the user's most recent post.
+func (smm *slowModeManager) allowPost(channelID *id.ID,
+	pubKey ed25519.PublicKey, mt MessageType, ts time.Time) bool {
+	if !isSlowModePost(mt) {
+		return true
+	}
+
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	if smm.waitUnsafe(channelID, pubKey, ts) > 0 {
+		return false
+	}
+
+	smm.recordPostUnsafe(channelID, pubKey, ts)
+	return true
+}
+
+// checkPost returns SlowModeErr if the user cannot yet post a message of the
+// given type in the channel at the timestamp.
+func (smm *slowModeManager) checkPost(channelID *id.ID,
+	pubKey ed25519.PublicKey, mt MessageType, ts time.Time) error {
+	if !isSlowModePost(mt) {
+		return nil
+	}
+
+	smm.mux.RLock()
+	defer smm.mux.RUnlock()
+
+	if wait := smm.waitUnsafe(channelID, pubKey, ts); wait > 0 {
+		return errors.WithMessagef(
+			SlowModeErr, "next post allowed in %s", wait)
+	}
+	return nil
+}
+
+// recordPost records the round timestamp as the user's most recent post in the
+// channel. This is used for messages sent by this user, which are not seen by
+// the userListener.
+func (smm *slowModeManager) recordPost(channelID *id.ID,
+	pubKey ed25519.PublicKey, mt MessageType, ts time.Time) {
+	if !isSlowModePost(mt) {
+		return
+	}
+
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+	smm.recordPostUnsafe(channelID, pubKey, ts)
+}
+
+// removeChannel deletes the slow mode setting for the given channel. This
+// should only be called when leaving a channel.
+func (smm *slowModeManager) removeChannel(channelID *id.ID) error {
+	smm.mux.Lock()
+	defer smm.mux.Unlock()
+
+	delete(smm.lastPost, *channelID)
+	if _, exists := smm.settings[*channelID]; !exists {
+		return nil
+	}
+
+	delete(smm.settings, *channelID)
+	if err := smm.save(); err != nil {
+		return err
+	}
+	return smm.deletePosts(channelID)
+}
+
+// waitUnsafe returns the duration the user must wait after ts before posting in
+// the channel. Returns zero if the user can post. Messages may arrive out of
+// order, so posts before the last post are also limited. Must be called under
+// lock.
+func (smm *slowModeManager) waitUnsafe(
+	channelID *id.ID, pubKey ed25519.PublicKey, ts time.Time) time.Duration {
+	setting, exists := smm.settings[*channelID]
+	if !exists || !setting.active(ts) {
+		return 0
+	}
+
+	last, exists := smm.lastPost[*channelID][makeMutedUserKey(pubKey)]
+	if !exists {
+		return 0
+	}
+
+	elapsed := ts.Sub(last)
+	if elapsed < 0 {
+		elapsed = -elapsed
+	}
+	if elapsed >= setting.Interval {
+		return 0
+	}
+	return setting.Interval - elapsed
+}
+
+// recordPostUnsafe records the user's post if slow mode is enabled in the
+// channel and the post is newer than their last. Posts that can no longer limit
+// a new post are evicted and the channel's posts are saved to storage. Must be
+// called under lock.
+func (smm *slowModeManager) recordPostUnsafe(
+	channelID *id.ID, pubKey ed25519.PublicKey, ts time.Time) {
+	setting, exists := smm.settings[*channelID]
+	if !exists {
+		return
+	}
+
+	posts, exists := smm.lastPost[*channelID]
+	if !exists {
+		posts = make(map[mutedUserKey]time.Time)
+		smm.lastPost[*channelID] = posts
+	}
+
+	key := makeMutedUserKey(pubKey)
+	if last, exists := posts[key]; exists && !ts.After(last) {
+		return
+	}
+	posts[key] = ts
+
+	evictSlowModePosts(posts, ts.Add(-setting.Interval), maxSlowModePosters)
+
+	if err := smm.savePosts(channelID); err != nil {
+		jww.ERROR.Printf("[CH] Failed to save slow mode posts: %+v", err)
+	}
+}
+
+// evictSlowModePosts removes every post made before the cutoff. If more than
+// the max number of posts remain, then the oldest posts are removed.
+func evictSlowModePosts(
+	posts map[mutedUserKey]time.Time, cutoff time.Time, max int) {
+	for key, last := range posts {
+		if last.Before(cutoff) {
+			delete(posts, key)
+		}
+	}
+
+	if len(posts) <= max {
+		return
+	}
+
+	keys := make([]mutedUserKey, 0, len(posts))
+	for key := range posts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return posts[keys[i]].Before(posts[keys[j]])
+	})
+	for _, key := range keys[:len(keys)-max] {
+		delete(posts, key)
+	}
+}
+
+// save stores the slow mode setting of every channel to storage. Must be
+// called under lock.
+func (smm *slowModeManager) save() error {
+	disk := make(map[string]slowModeSetting, len(smm.settings))
+	for channelID, setting := range smm.settings {
+		chID := channelID
+		disk[marshalChID(&chID)] = setting
+	}
+
+	data, err := json.Marshal(disk)
+	if err != nil {
+		return errors.Errorf(storeSlowModeErr, err)
+	}
+
+	obj := &versioned.Object{
+		Version:   slowModeStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	if err = smm.kv.Set(slowModeStoreKey, obj); err != nil {
+		return errors.Errorf(storeSlowModeErr, err)
+	}
+	return nil
+}
+
+// savePosts stores the last post of each user in the channel to storage under
+// its own key. Must be called under lock.
+func (smm *slowModeManager) savePosts(channelID *id.ID) error {
+	data, err := json.Marshal(smm.lastPost[*channelID])
+	if err != nil {
+		return errors.Errorf(storeSlowModePostsErr, channelID, err)
+	}
+
+	obj := &versioned.Object{
+		Version:   slowModePostsStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	err = smm.kv.Set(makeSlowModePostsKey(channelID), obj)
+	if err != nil {
+		return errors.Errorf(storeSlowModePostsErr, channelID, err)
+	}
+	return nil
+}
+
+// loadPosts loads the last post of each user in the channel from storage.
+// Returns nil if no posts are stored.
+func (smm *slowModeManager) loadPosts(
+	channelID *id.ID) (map[mutedUserKey]time.Time, error) {
+	obj, err := smm.kv.Get(
+		makeSlowModePostsKey(channelID), slowModePostsStoreVer)
+	if err != nil {
+		if smm.kv.Exists(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	var posts map[mutedUserKey]time.Time
+	return posts, json.Unmarshal(obj.Data, &posts)
+}
+
+// deletePosts deletes the posts for the channel from storage.
+func (smm *slowModeManager) deletePosts(channelID *id.ID) error {
+	return smm.kv.Delete(makeSlowModePostsKey(channelID), slowModePostsStoreVer)
+}
+
+// makeSlowModePostsKey creates a key for saving the last posts in the channel.
+func makeSlowModePostsKey(channelID *id.ID) string {
+	return slowModePostsStoreKeyPrefix +
+		base64.StdEncoding.EncodeToString(channelID.Marshal())
+}
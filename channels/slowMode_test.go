@@ -0,0 +1,237 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channels
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that a slowModeManager loaded via newOrLoadSlowModeManager matches the
+// original.
+func Test_newOrLoadSlowModeManager(t *testing.T) {
+	prng := rand.New(rand.NewSource(7432))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	smm, err := newOrLoadSlowModeManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to make new slowModeManager: %+v", err)
+	}
+
+	expiry := netTime.Now().Add(time.Hour).Round(0)
+	for i := 0; i < 5; i++ {
+		channelID, _ := id.NewRandomID(prng, id.User)
+		smm.enable(channelID, time.Duration(i+1)*time.Minute, expiry)
+	}
+
+	loaded, err := newOrLoadSlowModeManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to load slowModeManager: %+v", err)
+	}
+
+	if len(smm.settings) != len(loaded.settings) {
+		t.Fatalf("Unexpected number of channels."+
+			"\nexpected: %d\nreceived: %d",
+			len(smm.settings), len(loaded.settings))
+	}
+	for channelID, expected := range smm.settings {
+		received := loaded.settings[channelID]
+		if expected.Interval != received.Interval ||
+			!expected.Expiry.Equal(received.Expiry) {
+			t.Errorf("Loaded setting for channel %s does not match original."+
+				"\nexpected: %+v\nreceived: %+v",
+				&channelID, expected, received)
+		}
+	}
+}
+
+// Tests that slowModeManager.allowPost only allows one post per interval from
+// each user and only limits posts while slow mode is enabled.
+func Test_slowModeManager_allowPost(t *testing.T) {
+	prng := rand.New(rand.NewSource(7433))
+	smm, err := newOrLoadSlowModeManager(versioned.NewKV(ekv.MakeMemstore()))
+	if err != nil {
+		t.Fatalf("Failed to make new slowModeManager: %+v", err)
+	}
+
+	channelID, _ := id.NewRandomID(prng, id.User)
+	user1, _, _ := ed25519.GenerateKey(prng)
+	user2, _, _ := ed25519.GenerateKey(prng)
+	ts := netTime.Now()
+
+	if !smm.allowPost(channelID, user1, Text, ts) ||
+		!smm.allowPost(channelID, user1, Text, ts) {
+		t.Errorf("Post not allowed when slow mode is disabled.")
+	}
+
+	smm.enable(channelID, time.Minute, time.Time{})
+
+	tests := []struct {
+		pubKey   ed25519.PublicKey
+		mt       MessageType
+		ts       time.Time
+		expected bool
+	}{
+		{user1, Text, ts, true},
+		{user1, Text, ts.Add(30 * time.Second), false},
+		{user1, Reaction, ts.Add(30 * time.Second), false},
+		{user1, Delete, ts.Add(30 * time.Second), true},
+		{user1, Edit, ts.Add(30 * time.Second), true},
+		{user2, Text, ts.Add(30 * time.Second), true},
+		{user1, Text, ts.Add(-30 * time.Second), false},
+		{user1, Text, ts.Add(time.Minute), true},
+		{user1, Text, ts.Add(90 * time.Second), false},
+	}
+
+	for i, tt := range tests {
+		allowed := smm.allowPost(channelID, tt.pubKey, tt.mt, tt.ts)
+		if allowed != tt.expected {
+			t.Errorf("Unexpected result for post of type %s at %s (%d)."+
+				"\nexpected: %t\nreceived: %t",
+				tt.mt, tt.ts, i, tt.expected, allowed)
+		}
+	}
+
+	smm.disable(channelID)
+	if !smm.allowPost(channelID, user1, Text, ts.Add(90*time.Second)) {
+		t.Errorf("Post not allowed after slow mode was disabled.")
+	}
+}
+
+// Tests that slowModeManager.checkPost returns SlowModeErr for a post recorded
+// with slowModeManager.recordPost until the interval has elapsed or slow mode
+// has expired.
+func Test_slowModeManager_checkPost_recordPost(t *testing.T) {
+	prng := rand.New(rand.NewSource(7434))
+	smm, err := newOrLoadSlowModeManager(versioned.NewKV(ekv.MakeMemstore()))
+	if err != nil {
+		t.Fatalf("Failed to make new slowModeManager: %+v", err)
+	}
+
+	channelID, _ := id.NewRandomID(prng, id.User)
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+	ts := netTime.Now()
+	smm.enable(channelID, time.Minute, ts.Add(time.Hour))
+
+	smm.recordPost(channelID, pubKey, Text, ts)
+
+	err = smm.checkPost(channelID, pubKey, Text, ts.Add(time.Second))
+	if !errors.Is(err, SlowModeErr) {
+		t.Errorf("Unexpected error for post sent too soon."+
+			"\nexpected: %v\nreceived: %+v", SlowModeErr, err)
+	}
+
+	err = smm.checkPost(channelID, pubKey, Text, ts.Add(time.Minute))
+	if err != nil {
+		t.Errorf("Error for post after the interval: %+v", err)
+	}
+
+	smm.recordPost(channelID, pubKey, Text, ts.Add(time.Hour-time.Second))
+	err = smm.checkPost(channelID, pubKey, Text, ts.Add(time.Hour))
+	if err != nil {
+		t.Errorf("Error for post after slow mode expired: %+v", err)
+	}
+}
+
+// Tests that slowModeManager.removeChannel removes the setting for the channel
+// from memory and storage.
+func Test_slowModeManager_removeChannel(t *testing.T) {
+	prng := rand.New(rand.NewSource(7435))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	smm, err := newOrLoadSlowModeManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to make new slowModeManager: %+v", err)
+	}
+
+	channelID, _ := id.NewRandomID(prng, id.User)
+	smm.enable(channelID, time.Minute, time.Time{})
+
+	if err = smm.removeChannel(channelID); err != nil {
+		t.Fatalf("Failed to remove channel: %+v", err)
+	}
+
+	if interval := smm.getInterval(channelID); interval != 0 {
+		t.Errorf("Channel still in slow mode with interval %s.", interval)
+	}
+
+	loaded, err := newOrLoadSlowModeManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to load slowModeManager: %+v", err)
+	}
+	if interval := loaded.getInterval(channelID); interval != 0 {
+		t.Errorf("Loaded channel still in slow mode with interval %s.",
+			interval)
+	}
+}
+
+// Tests that the last post of each user is saved to storage so that a loaded
+// slowModeManager still limits the user.
+func Test_slowModeManager_allowPost_Storage(t *testing.T) {
+	prng := rand.New(rand.NewSource(7436))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	smm, err := newOrLoadSlowModeManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to make new slowModeManager: %+v", err)
+	}
+
+	channelID, _ := id.NewRandomID(prng, id.User)
+	pubKey, _, _ := ed25519.GenerateKey(prng)
+	ts := netTime.Now()
+	smm.enable(channelID, time.Minute, time.Time{})
+	if !smm.allowPost(channelID, pubKey, Text, ts) {
+		t.Fatalf("First post not allowed.")
+	}
+
+	loaded, err := newOrLoadSlowModeManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to load slowModeManager: %+v", err)
+	}
+	if loaded.allowPost(channelID, pubKey, Text, ts.Add(time.Second)) {
+		t.Errorf("Post allowed after loading before the interval elapsed.")
+	}
+
+	smm.disable(channelID)
+	smm.enable(channelID, time.Minute, time.Time{})
+	loaded, err = newOrLoadSlowModeManager(kv)
+	if err != nil {
+		t.Fatalf("Failed to load slowModeManager: %+v", err)
+	}
+	if !loaded.allowPost(channelID, pubKey, Text, ts.Add(time.Second)) {
+		t.Errorf("Post not allowed after slow mode was reset.")
+	}
+}
+
+// Tests that evictSlowModePosts removes posts before the cutoff and the oldest
+// posts over the max.
+func Test_evictSlowModePosts(t *testing.T) {
+	ts := netTime.Now()
+	posts := make(map[mutedUserKey]time.Time)
+	for i := 0; i < 10; i++ {
+		posts[mutedUserKey(strconv.Itoa(i))] = ts.Add(time.Duration(i) * time.Second)
+	}
+
+	evictSlowModePosts(posts, ts.Add(2*time.Second), 5)
+
+	expected := map[mutedUserKey]time.Time{}
+	for i := 5; i < 10; i++ {
+		expected[mutedUserKey(strconv.Itoa(i))] = ts.Add(time.Duration(i) * time.Second)
+	}
+	if !reflect.DeepEqual(expected, posts) {
+		t.Errorf("Unexpected posts after eviction.\nexpected: %v\nreceived: %v",
+			expected, posts)
+	}
+}
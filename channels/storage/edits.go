@@ -0,0 +1,152 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// MessageVersion is a single version of the text of an edited message.
+type MessageVersion struct {
+	// EditMessageID is the [message.ID] of the edit that set this text. For the
+	// original text, it is the ID of the message itself.
+	EditMessageID message.ID
+
+	// Text is the text of the message in this version.
+	Text string
+
+	// Timestamp is when this version was sent.
+	Timestamp time.Time
+}
+
+// EditMessage is called whenever the original sender of a message replaces its
+// text. Every version is saved as a MessageEdit, and the Message displays the
+// text of the version with the latest timestamp.
+//
+// Returns an error if the message cannot be edited. It returns
+// channels.NoMessageErr if the message does not exist.
+func (i *impl) EditMessage(messageID, editMessageID message.ID, text string,
+	timestamp time.Time) (uint64, error) {
+	parentErr := "failed to EditMessage"
+
+	currentMessage := &Message{}
+
+	// Build a transaction to prevent race conditions
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(
+			currentMessage, "message_id = ?", messageID.Marshal()).Error
+		if err != nil {
+			return err
+		}
+
+		// Keep the original text as the first version on the first edit
+		if !*currentMessage.Edited {
+			err = tx.Create(&MessageEdit{
+				MessageId:     currentMessage.MessageId,
+				EditMessageId: currentMessage.MessageId,
+				Text:          currentMessage.Text,
+				Timestamp:     currentMessage.Timestamp,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// Ignore edits that have already been received
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&MessageEdit{
+				MessageId:     currentMessage.MessageId,
+				EditMessageId: editMessageID.Marshal(),
+				Text:          []byte(text),
+				Timestamp:     timestamp,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		// Display the latest version since edits may arrive out of order
+		latest := &MessageEdit{}
+		err = tx.Where("message_id = ?", currentMessage.MessageId).
+			Order("timestamp DESC").Order("id DESC").Take(latest).Error
+		if err != nil {
+			return err
+		}
+
+		edited := true
+		return tx.Updates(&Message{
+			Id:     currentMessage.Id,
+			Text:   latest.Text,
+			Edited: &edited,
+		}).Error
+	})
+	cancel()
+
+	if err != nil {
+		if errors.Is(gorm.ErrRecordNotFound, err) {
+			return 0, errors.WithMessage(channels.NoMessageErr, parentErr)
+		}
+		return 0, errors.WithMessage(err, parentErr)
+	}
+	channelId := &id.ID{}
+	copy(channelId[:], currentMessage.ChannelId)
+
+	go i.cbs.MessageReceived(currentMessage.Id, channelId, true)
+	return uint64(currentMessage.Id), nil
+}
+
+// GetEditHistory returns every version of the text of the message with the
+// given [message.ID], ordered from oldest to newest. The first version is the
+// original text. An empty list is returned if the message was never edited.
+//
+// Returns an error if the history cannot be gotten. It returns
+// channels.NoMessageErr if the message does not exist.
+func (i *impl) GetEditHistory(messageID message.ID) ([]MessageVersion, error) {
+	parentErr := "failed to GetEditHistory"
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	var count int64
+	err := i.db.WithContext(ctx).Model(&Message{}).
+		Where("message_id = ?", messageID.Marshal()).Count(&count).Error
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	} else if count == 0 {
+		return nil, errors.WithMessage(channels.NoMessageErr, parentErr)
+	}
+
+	var edits []MessageEdit
+	err = i.db.WithContext(ctx).Where("message_id = ?", messageID.Marshal()).
+		Order("timestamp ASC").Order("id ASC").Find(&edits).Error
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	}
+
+	versions := make([]MessageVersion, len(edits))
+	for j, edit := range edits {
+		editMessageID, err := message.UnmarshalID(edit.EditMessageId)
+		if err != nil {
+			return nil, errors.WithMessage(err, parentErr)
+		}
+		versions[j] = MessageVersion{
+			EditMessageID: editMessageID,
+			Text:          string(edit.Text),
+			Timestamp:     edit.Timestamp,
+		}
+	}
+
+	return versions, nil
+}
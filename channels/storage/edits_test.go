@@ -0,0 +1,139 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.EditMessage displays the latest version of a message, even
+// when edits arrive out of order, and that impl.GetEditHistory returns every
+// version.
+func Test_impl_EditMessage(t *testing.T) {
+	model, err := newImpl(
+		"file:Test_impl_EditMessage?mode=memory&cache=shared", &dummyCbs{})
+	if err != nil {
+		t.Fatalf("Failed to create new impl: %+v", err)
+	}
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{ReceptionID: channelID})
+
+	pubKey := ed25519.PublicKey("pubKey")
+	start := time.Unix(1700000000, 0)
+	otherID := message.DeriveChannelMessageID(channelID, 0, []byte("other"))
+	msgID := message.DeriveChannelMessageID(channelID, 1, []byte("original"))
+	model.ReceiveMessage(channelID, otherID, "nick", "other", pubKey, 0, 0,
+		start, 0, rounds.Round{}, channels.Text, channels.Delivered, false)
+	uuid := model.ReceiveMessage(channelID, msgID, "nick", "original", pubKey,
+		0, 0, start, 0, rounds.Round{}, channels.Text, channels.Delivered, false)
+
+	history, err := model.GetEditHistory(msgID)
+	if err != nil {
+		t.Fatalf("Failed to get edit history: %+v", err)
+	} else if len(history) != 0 {
+		t.Errorf("Unedited message has history: %+v", history)
+	}
+
+	edit1 := message.DeriveChannelMessageID(channelID, 2, []byte("edit1"))
+	edit2 := message.DeriveChannelMessageID(channelID, 3, []byte("edit2"))
+
+	// Receive the second edit before the first
+	edits := []struct {
+		editID   message.ID
+		text     string
+		ts       time.Time
+		expected string
+	}{
+		{edit2, "second", start.Add(2 * time.Minute), "second"},
+		{edit1, "first", start.Add(time.Minute), "second"},
+		{edit1, "first", start.Add(time.Minute), "second"},
+	}
+	for j, edit := range edits {
+		editedUUID, err := model.EditMessage(msgID, edit.editID, edit.text,
+			edit.ts)
+		if err != nil {
+			t.Fatalf("Failed to edit message (%d): %+v", j, err)
+		} else if editedUUID != uuid {
+			t.Errorf("Unexpected UUID (%d).\nexpected: %d\nreceived: %d",
+				j, uuid, editedUUID)
+		}
+
+		msg, err := model.GetMessage(msgID)
+		if err != nil {
+			t.Fatalf("Failed to get message (%d): %+v", j, err)
+		}
+		if string(msg.Content) != edit.expected || !msg.Edited {
+			t.Errorf("Unexpected message after edit (%d)."+
+				"\nexpected: %q (edited)\nreceived: %q (edited: %t)",
+				j, edit.expected, msg.Content, msg.Edited)
+		}
+	}
+
+	history, err = model.GetEditHistory(msgID)
+	if err != nil {
+		t.Fatalf("Failed to get edit history: %+v", err)
+	}
+	expected := []MessageVersion{
+		{msgID, "original", start},
+		{edit1, "first", start.Add(time.Minute)},
+		{edit2, "second", start.Add(2 * time.Minute)},
+	}
+	if len(history) != len(expected) {
+		t.Fatalf("Unexpected history length.\nexpected: %d\nreceived: %d",
+			len(expected), len(history))
+	}
+	for j, version := range history {
+		if version.EditMessageID != expected[j].EditMessageID ||
+			version.Text != expected[j].Text ||
+			!version.Timestamp.Equal(expected[j].Timestamp) {
+			t.Errorf("Unexpected version %d.\nexpected: %+v\nreceived: %+v",
+				j, expected[j], version)
+		}
+	}
+
+	// The edited text should be searchable
+	results, err := model.SearchMessages(SearchParams{Text: "second"})
+	if err != nil {
+		t.Fatalf("Failed to search: %+v", err)
+	} else if len(results) != 1 || results[0].MessageID != msgID {
+		t.Errorf("Unexpected search results: %+v", results)
+	}
+
+	// The history is deleted with the message
+	if err = model.DeleteMessage(msgID); err != nil {
+		t.Fatalf("Failed to delete message: %+v", err)
+	}
+	var count int64
+	model.db.Model(&MessageEdit{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d edits remain after deleting message.", count)
+	}
+
+	_, err = model.EditMessage(msgID, edit1, "first", start)
+	if !errors.Is(err, channels.NoMessageErr) {
+		t.Errorf("Unexpected error for missing message."+
+			"\nexpected: %v\nreceived: %+v", channels.NoMessageErr, err)
+	}
+	_, err = model.GetEditHistory(msgID)
+	if !errors.Is(err, channels.NoMessageErr) {
+		t.Errorf("Unexpected error for missing message."+
+			"\nexpected: %v\nreceived: %+v", channels.NoMessageErr, err)
+	}
+}
@@ -75,6 +75,17 @@ func (i *impl) LeaveChannel(channelID *id.ID) {
 		return
 	}
 	jww.DEBUG.Printf("Successfully deleted channel: %s", channelID)
+
+	// Read markers are not deleted by the CASCADE
+	ctx, cancel = newContext()
+	err = i.db.WithContext(ctx).
+		Delete(&ReadMarker{ChannelId: channelID.Marshal()}).Error
+	cancel()
+	if err != nil {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Unable to delete ReadMarker: %+v", err))
+	}
+
 	go i.cbs.ChannelUpdate(channelID, true)
 }
 
@@ -248,9 +259,10 @@ func (i *impl) UpdateFromMessageID(messageID message.ID, timestamp *time.Time,
 func (i *impl) GetMessage(messageID message.ID) (channels.ModelMessage, error) {
 	parentErr := "failed to GetMessage"
 
-	result := &Message{MessageId: messageID.Bytes()}
+	result := &Message{}
 	ctx, cancel := newContext()
-	err := i.db.WithContext(ctx).Take(result).Error
+	err := i.db.WithContext(ctx).Take(
+		result, "message_id = ?", messageID.Marshal()).Error
 	cancel()
 	if err != nil {
 		if errors.Is(gorm.ErrRecordNotFound, err) {
@@ -259,39 +271,11 @@ func (i *impl) GetMessage(messageID message.ID) (channels.ModelMessage, error) {
 		return channels.ModelMessage{}, errors.WithMessage(err, parentErr)
 	}
 
-	var channelId *id.ID
-	if result.ChannelId != nil {
-		channelId, err = id.Unmarshal(result.ChannelId)
-		if err != nil {
-			return channels.ModelMessage{}, errors.WithMessage(err, parentErr)
-		}
-	}
-
-	var parentMsgId message.ID
-	if result.ParentMessageId != nil {
-		parentMsgId, err = message.UnmarshalID(result.ParentMessageId)
-		if err != nil {
-			return channels.ModelMessage{}, errors.WithMessage(err, parentErr)
-		}
+	modelMsg, err := buildModelMessage(result)
+	if err != nil {
+		return channels.ModelMessage{}, errors.WithMessage(err, parentErr)
 	}
-
-	return channels.ModelMessage{
-		UUID:            uint64(result.Id),
-		Nickname:        result.Nickname,
-		MessageID:       messageID,
-		ChannelID:       channelId,
-		ParentMessageID: parentMsgId,
-		Timestamp:       result.Timestamp,
-		Lease:           result.Lease,
-		Status:          channels.SentStatus(result.Status),
-		Hidden:          *result.Hidden,
-		Pinned:          *result.Pinned,
-		Content:         result.Text,
-		Type:            channels.MessageType(result.Type),
-		Round:           id.Round(result.Round),
-		PubKey:          result.Pubkey,
-		CodesetVersion:  result.CodesetVersion,
-	}, nil
+	return modelMsg, nil
 }
 
 // MuteUser is called whenever a user is muted or unmuted.
@@ -360,6 +344,7 @@ func buildMessage(channelID, messageID, parentID []byte, nickname,
 	timestamp time.Time, lease time.Duration, round id.Round,
 	mType channels.MessageType, pinned, hidden bool,
 	status channels.SentStatus) *Message {
+	edited := false
 	return &Message{
 		MessageId:       messageID,
 		Nickname:        nickname,
@@ -370,6 +355,7 @@ func buildMessage(channelID, messageID, parentID []byte, nickname,
 		Status:          uint8(status),
 		Hidden:          &hidden,
 		Pinned:          &pinned,
+		Edited:          &edited,
 		Text:            []byte(text),
 		Type:            uint16(mType),
 		Round:           int64(round),
@@ -379,3 +365,52 @@ func buildMessage(channelID, messageID, parentID []byte, nickname,
 		CodesetVersion: codeset,
 	}
 }
+
+// buildModelMessage is a private helper that converts a stored Message into a
+// [channels.ModelMessage].
+func buildModelMessage(msg *Message) (channels.ModelMessage, error) {
+	var err error
+	var channelId *id.ID
+	if msg.ChannelId != nil {
+		channelId, err = id.Unmarshal(msg.ChannelId)
+		if err != nil {
+			return channels.ModelMessage{}, err
+		}
+	}
+
+	var messageId message.ID
+	if msg.MessageId != nil {
+		messageId, err = message.UnmarshalID(msg.MessageId)
+		if err != nil {
+			return channels.ModelMessage{}, err
+		}
+	}
+
+	var parentMsgId message.ID
+	if msg.ParentMessageId != nil {
+		parentMsgId, err = message.UnmarshalID(msg.ParentMessageId)
+		if err != nil {
+			return channels.ModelMessage{}, err
+		}
+	}
+
+	return channels.ModelMessage{
+		UUID:            uint64(msg.Id),
+		Nickname:        msg.Nickname,
+		MessageID:       messageId,
+		ChannelID:       channelId,
+		ParentMessageID: parentMsgId,
+		Timestamp:       msg.Timestamp,
+		Lease:           msg.Lease,
+		Status:          channels.SentStatus(msg.Status),
+		Hidden:          *msg.Hidden,
+		Pinned:          *msg.Pinned,
+		Edited:          *msg.Edited,
+		Content:         msg.Text,
+		Type:            channels.MessageType(msg.Type),
+		Round:           id.Round(msg.Round),
+		PubKey:          msg.Pubkey,
+		CodesetVersion:  msg.CodesetVersion,
+		DmToken:         msg.DmToken,
+	}, nil
+}
@@ -39,10 +39,50 @@ type impl struct {
 	cbs UiCallbacks
 }
 
-// NewEventModel initializes the [channels.EventModel] interface with appropriate backend.
-func NewEventModel(dbFilePath string, uiCallbacks UiCallbacks) (channels.EventModel, error) {
+// EventModel is the [channels.EventModel] backed by this package's database.
+// It extends the interface with local queries over the stored messages.
+type EventModel interface {
+	channels.EventModel
+
+	// SearchMessages returns the stored messages that match the given
+	// parameters, newest first.
+	SearchMessages(params SearchParams) ([]channels.ModelMessage, error)
+
+	// GetThread returns the root message followed by all of its descendant
+	// replies, oldest first.
+	GetThread(rootID message.ID) ([]channels.ModelMessage, error)
+
+	// CountReplies returns the number of replies to each of the given
+	// messages.
+	CountReplies(messageIDs ...message.ID) (map[message.ID]int, error)
+
+	// GetActiveThreads returns the threads in the channel that have replies,
+	// ordered by most recent reply first.
+	GetActiveThreads(channelID *id.ID, limit, offset int) ([]Thread, error)
+
+	// GetEditHistory returns every version of the text of an edited message,
+	// oldest first.
+	GetEditHistory(messageID message.ID) ([]MessageVersion, error)
+
+	// GetPollResults returns the current tally of the votes for a poll.
+	GetPollResults(pollID message.ID) (PollResults, error)
+
+	// MarkRead advances the read marker of the channel to the timestamp.
+	MarkRead(channelID *id.ID, timestamp time.Time) error
+
+	// GetUnreadCount returns the number of messages in the channel received
+	// after the read marker.
+	GetUnreadCount(channelID *id.ID) (uint64, error)
+}
+
+// NewEventModel initializes the [EventModel] interface with appropriate
+// backend.
+func NewEventModel(dbFilePath string, uiCallbacks UiCallbacks) (EventModel, error) {
 	model, err := newImpl(dbFilePath, uiCallbacks)
-	return channels.EventModel(model), err
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
 }
 
 func newImpl(dbFilePath string, uiCallbacks UiCallbacks) (*impl, error) {
@@ -93,7 +133,14 @@ func newImpl(dbFilePath string, uiCallbacks UiCallbacks) (*impl, error) {
 
 	// Initialize the database schema
 	// WARNING: Order is important. Do not change without database testing
-	err = db.AutoMigrate(&Channel{}, &Message{}, File{})
+	err = db.AutoMigrate(&Channel{}, &Message{}, &MessageEdit{}, &PollVote{},
+		&PollClose{}, File{}, &ReadMarker{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the full-text search index over the Message table
+	err = initSearchIndex(db)
 	if err != nil {
 		return nil, err
 	}
@@ -34,11 +34,65 @@ type Message struct {
 	// Pointer to enforce zero-value reading in ORM.
 	Hidden *bool `gorm:"not null"`
 	Pinned *bool `gorm:"index;not null"`
+	Edited *bool `gorm:"not null;default:false"`
+
+	// Score is the total score given to the message by the channel message
+	// filters.
+	Score int32 `gorm:"not null;default:0"`
 
 	// User cryptographic Identity struct -- could be pulled out
 	Pubkey         []byte `gorm:"not null"`
 	DmToken        uint32 `gorm:"not null"`
 	CodesetVersion uint8  `gorm:"not null"`
+
+	Edits     []MessageEdit `gorm:"foreignKey:MessageId;references:MessageId;constraint:OnDelete:CASCADE"`
+	Votes     []PollVote    `gorm:"foreignKey:PollMessageId;references:MessageId;constraint:OnDelete:CASCADE"`
+	PollClose *PollClose    `gorm:"foreignKey:PollMessageId;references:MessageId;constraint:OnDelete:CASCADE"`
+}
+
+// MessageEdit defines the SQL representation of a single version of the text
+// of an edited Message. The first version of an edited Message is its original
+// text.
+//
+// A MessageEdit belongs to one Message.
+type MessageEdit struct {
+	Id            int64     `gorm:"primaryKey;autoIncrement:true"`
+	MessageId     []byte    `gorm:"index;not null"`
+	EditMessageId []byte    `gorm:"uniqueIndex;not null"`
+	Text          []byte    `gorm:"not null"`
+	Timestamp     time.Time `gorm:"not null"`
+}
+
+// PollVote defines the SQL representation of a single vote for a poll. Every
+// vote is kept so that the tally can be recomputed when a poll is closed.
+//
+// A PollVote belongs to one Message (the poll).
+type PollVote struct {
+	Id            int64     `gorm:"primaryKey;autoIncrement:true"`
+	PollMessageId []byte    `gorm:"index;not null"`
+	VoteMessageId []byte    `gorm:"uniqueIndex;not null"`
+	Pubkey        []byte    `gorm:"not null"`
+	Option        uint32    `gorm:"not null"`
+	Timestamp     time.Time `gorm:"not null"`
+}
+
+// PollClose defines the SQL representation of the closing time of a poll.
+//
+// A PollClose belongs to one Message (the poll).
+type PollClose struct {
+	Id            int64     `gorm:"primaryKey;autoIncrement:true"`
+	PollMessageId []byte    `gorm:"uniqueIndex;not null"`
+	ClosingTime   time.Time `gorm:"not null"`
+}
+
+// ReadMarker defines the SQL representation of the read marker of a Channel.
+// All messages in the channel with a timestamp at or before LastRead are read.
+//
+// A ReadMarker is not tied to a Channel because read markers synced from other
+// devices may arrive before the channel is joined.
+type ReadMarker struct {
+	ChannelId []byte    `gorm:"primaryKey;not null;autoIncrement:false"`
+	LastRead  time.Time `gorm:"not null"`
 }
 
 // Channel defines the SQL representation of a single Channel.
@@ -0,0 +1,173 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// PollResults is the tally of the votes for a poll.
+type PollResults struct {
+	// Votes is the number of users whose counted vote is for each option,
+	// keyed on the index of the option. Options without votes are omitted.
+	Votes map[uint32]int
+
+	// Total is the number of users with a counted vote.
+	Total int
+
+	// ClosingTime is the time after which votes are no longer counted. It is
+	// zero if the poll has not been closed.
+	ClosingTime time.Time
+}
+
+// ReceiveVote is called whenever a vote for a poll is received. Every vote is
+// saved and the tally is computed by GetPollResults, so votes may arrive in any
+// order.
+//
+// Returns an error if the vote cannot be saved. It returns
+// channels.NoMessageErr if the poll does not exist.
+func (i *impl) ReceiveVote(pollID, voteID message.ID, pubKey ed25519.PublicKey,
+	option uint32, timestamp time.Time) (uint64, error) {
+	parentErr := "failed to ReceiveVote"
+
+	poll := &Message{}
+
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(poll, "message_id = ?", pollID.Marshal()).Error
+		if err != nil {
+			return err
+		}
+
+		// Ignore votes that have already been received
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&PollVote{
+				PollMessageId: poll.MessageId,
+				VoteMessageId: voteID.Marshal(),
+				Pubkey:        pubKey,
+				Option:        option,
+				Timestamp:     timestamp,
+			}).Error
+	})
+	cancel()
+
+	return i.pollUpdated(poll, err, parentErr)
+}
+
+// ClosePoll is called whenever the channel admin closes a poll. The closing
+// time replaces any previously set closing time.
+//
+// Returns an error if the poll cannot be closed. It returns
+// channels.NoMessageErr if the poll does not exist.
+func (i *impl) ClosePoll(
+	pollID message.ID, closingTime time.Time) (uint64, error) {
+	parentErr := "failed to ClosePoll"
+
+	poll := &Message{}
+
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(poll, "message_id = ?", pollID.Marshal()).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "poll_message_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"closing_time"}),
+		}).Create(&PollClose{
+			PollMessageId: poll.MessageId,
+			ClosingTime:   closingTime,
+		}).Error
+	})
+	cancel()
+
+	return i.pollUpdated(poll, err, parentErr)
+}
+
+// pollUpdated handles the result of a change to the votes of a poll. On
+// success, the UI is notified that the poll message was updated.
+func (i *impl) pollUpdated(
+	poll *Message, err error, parentErr string) (uint64, error) {
+	if err != nil {
+		if errors.Is(gorm.ErrRecordNotFound, err) {
+			return 0, errors.WithMessage(channels.NoMessageErr, parentErr)
+		}
+		return 0, errors.WithMessage(err, parentErr)
+	}
+
+	channelId := &id.ID{}
+	copy(channelId[:], poll.ChannelId)
+
+	go i.cbs.MessageReceived(poll.Id, channelId, true)
+	return uint64(poll.Id), nil
+}
+
+// GetPollResults returns the tally of the votes for the poll with the given
+// [message.ID]. Only the most recent vote from each user sent before the poll
+// closed is counted.
+//
+// Returns an error if the results cannot be gotten. It returns
+// channels.NoMessageErr if the poll does not exist.
+func (i *impl) GetPollResults(pollID message.ID) (PollResults, error) {
+	parentErr := "failed to GetPollResults"
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	var count int64
+	err := i.db.WithContext(ctx).Model(&Message{}).
+		Where("message_id = ?", pollID.Marshal()).Count(&count).Error
+	if err != nil {
+		return PollResults{}, errors.WithMessage(err, parentErr)
+	} else if count == 0 {
+		return PollResults{},
+			errors.WithMessage(channels.NoMessageErr, parentErr)
+	}
+
+	results := PollResults{Votes: make(map[uint32]int)}
+
+	var closed []PollClose
+	err = i.db.WithContext(ctx).
+		Where("poll_message_id = ?", pollID.Marshal()).Find(&closed).Error
+	if err != nil {
+		return PollResults{}, errors.WithMessage(err, parentErr)
+	} else if len(closed) > 0 {
+		results.ClosingTime = closed[0].ClosingTime
+	}
+
+	tx := i.db.WithContext(ctx).Where("poll_message_id = ?", pollID.Marshal())
+	if !results.ClosingTime.IsZero() {
+		tx = tx.Where("timestamp < ?", results.ClosingTime)
+	}
+	var votes []PollVote
+	err = tx.Order("timestamp ASC").Order("id ASC").Find(&votes).Error
+	if err != nil {
+		return PollResults{}, errors.WithMessage(err, parentErr)
+	}
+
+	// Later votes replace earlier votes from the same user
+	latest := make(map[string]uint32, len(votes))
+	for _, vote := range votes {
+		latest[string(vote.Pubkey)] = vote.Option
+	}
+	for _, option := range latest {
+		results.Votes[option]++
+	}
+	results.Total = len(latest)
+
+	return results, nil
+}
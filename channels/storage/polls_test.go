@@ -0,0 +1,130 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.GetPollResults only counts the most recent vote from each
+// user and ignores votes at or after the closing time set by impl.ClosePoll.
+func Test_impl_PollResults(t *testing.T) {
+	model, err := newImpl(
+		"file:Test_impl_PollResults?mode=memory&cache=shared", &dummyCbs{})
+	if err != nil {
+		t.Fatalf("Failed to create new impl: %+v", err)
+	}
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{ReceptionID: channelID})
+
+	start := time.Unix(1700000000, 0)
+	pollID := message.DeriveChannelMessageID(channelID, 0, []byte("poll"))
+	uuid := model.ReceiveMessage(channelID, pollID, "nick",
+		`{"question":"?","options":["a","b","c"]}`, ed25519.PublicKey("pubKey"),
+		0, 0, start, 0, rounds.Round{}, channels.Poll, channels.Delivered,
+		false)
+
+	alice, bob, carol := ed25519.PublicKey("alice"), ed25519.PublicKey("bob"),
+		ed25519.PublicKey("carol")
+	votes := []struct {
+		pubKey ed25519.PublicKey
+		option uint32
+		offset time.Duration
+	}{
+		{alice, 0, 3 * time.Minute},
+		{alice, 1, 1 * time.Minute}, // Older than the vote above
+		{bob, 1, 2 * time.Minute},
+		{carol, 2, 4 * time.Minute},
+		{bob, 0, 6 * time.Minute}, // After the poll closes
+	}
+	for j, v := range votes {
+		voteID := message.DeriveChannelMessageID(
+			channelID, uint64(j+1), []byte("vote"))
+		for k := 0; k < 2; k++ {
+			pollUUID, err2 := model.ReceiveVote(
+				pollID, voteID, v.pubKey, v.option, start.Add(v.offset))
+			if err2 != nil {
+				t.Fatalf("Failed to receive vote %d: %+v", j, err2)
+			} else if pollUUID != uuid {
+				t.Errorf("Unexpected UUID for vote %d."+
+					"\nexpected: %d\nreceived: %d", j, uuid, pollUUID)
+			}
+		}
+	}
+
+	results, err := model.GetPollResults(pollID)
+	if err != nil {
+		t.Fatalf("Failed to get poll results: %+v", err)
+	}
+	expected := PollResults{Votes: map[uint32]int{0: 2, 2: 1}, Total: 3}
+	if !reflect.DeepEqual(expected, results) {
+		t.Errorf("Unexpected results for open poll."+
+			"\nexpected: %+v\nreceived: %+v", expected, results)
+	}
+
+	// Close the poll, then move the closing time earlier
+	for _, offset := range []time.Duration{5 * time.Minute, 4 * time.Minute} {
+		if _, err = model.ClosePoll(pollID, start.Add(offset)); err != nil {
+			t.Fatalf("Failed to close poll: %+v", err)
+		}
+	}
+
+	results, err = model.GetPollResults(pollID)
+	if err != nil {
+		t.Fatalf("Failed to get poll results: %+v", err)
+	}
+	expected = PollResults{Votes: map[uint32]int{0: 1, 1: 1}, Total: 2,
+		ClosingTime: start.Add(4 * time.Minute)}
+	if !reflect.DeepEqual(expected.Votes, results.Votes) ||
+		expected.Total != results.Total ||
+		!expected.ClosingTime.Equal(results.ClosingTime) {
+		t.Errorf("Unexpected results for closed poll."+
+			"\nexpected: %+v\nreceived: %+v", expected, results)
+	}
+
+	// Votes and the closing time are deleted with the poll
+	if err = model.DeleteMessage(pollID); err != nil {
+		t.Fatalf("Failed to delete poll: %+v", err)
+	}
+	var voteCount, closeCount int64
+	model.db.Model(&PollVote{}).Count(&voteCount)
+	model.db.Model(&PollClose{}).Count(&closeCount)
+	if voteCount != 0 || closeCount != 0 {
+		t.Errorf("%d votes and %d closing times remain after deleting poll.",
+			voteCount, closeCount)
+	}
+
+	_, err = model.GetPollResults(pollID)
+	if !errors.Is(err, channels.NoMessageErr) {
+		t.Errorf("Unexpected error for missing poll."+
+			"\nexpected: %v\nreceived: %+v", channels.NoMessageErr, err)
+	}
+	_, err = model.ReceiveVote(pollID, message.ID{}, alice, 0, start)
+	if !errors.Is(err, channels.NoMessageErr) {
+		t.Errorf("Unexpected error for missing poll."+
+			"\nexpected: %v\nreceived: %+v", channels.NoMessageErr, err)
+	}
+	_, err = model.ClosePoll(pollID, start)
+	if !errors.Is(err, channels.NoMessageErr) {
+		t.Errorf("Unexpected error for missing poll."+
+			"\nexpected: %v\nreceived: %+v", channels.NoMessageErr, err)
+	}
+}
@@ -0,0 +1,78 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// MarkRead is called whenever the read marker of a channel advances. All
+// messages in the channel with a timestamp at or before the marker are read.
+// The marker is never moved backwards.
+func (i *impl) MarkRead(channelID *id.ID, timestamp time.Time) error {
+	parentErr := "failed to MarkRead"
+
+	// Build a transaction to prevent race conditions
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		marker := &ReadMarker{}
+		err := tx.Limit(1).
+			Find(marker, "channel_id = ?", channelID.Marshal()).Error
+		if err != nil {
+			return err
+		} else if marker.ChannelId != nil && !timestamp.After(marker.LastRead) {
+			return nil
+		}
+
+		return tx.Save(&ReadMarker{
+			ChannelId: channelID.Marshal(),
+			LastRead:  timestamp,
+		}).Error
+	})
+	cancel()
+
+	if err != nil {
+		return errors.WithMessage(err, parentErr)
+	}
+	return nil
+}
+
+// GetUnreadCount returns the number of messages in the channel received after
+// the read marker. Hidden messages and reactions are not counted. If the
+// channel has no read marker, then every message is unread.
+func (i *impl) GetUnreadCount(channelID *id.ID) (uint64, error) {
+	parentErr := "failed to GetUnreadCount"
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	query := i.db.WithContext(ctx).Model(&Message{}).
+		Where("channel_id = ? AND hidden = ? AND type != ?",
+			channelID.Marshal(), false, uint16(channels.Reaction))
+
+	marker := &ReadMarker{}
+	err := i.db.WithContext(ctx).Limit(1).
+		Find(marker, "channel_id = ?", channelID.Marshal()).Error
+	if err != nil {
+		return 0, errors.WithMessage(err, parentErr)
+	} else if marker.ChannelId != nil {
+		query = query.Where("timestamp > ?", marker.LastRead)
+	}
+
+	var count int64
+	if err = query.Count(&count).Error; err != nil {
+		return 0, errors.WithMessage(err, parentErr)
+	}
+
+	return uint64(count), nil
+}
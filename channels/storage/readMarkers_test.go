@@ -0,0 +1,84 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl satisfies the channels.ReadMarkerEventModel interface.
+var _ channels.ReadMarkerEventModel = (*impl)(nil)
+
+// Tests that impl.GetUnreadCount counts the visible messages after the read
+// marker set by impl.MarkRead and that the marker never moves backwards.
+func Test_impl_MarkRead_GetUnreadCount(t *testing.T) {
+	model, err := newImpl(
+		"file:Test_impl_MarkRead?mode=memory&cache=shared", &dummyCbs{})
+	if err != nil {
+		t.Fatalf("Failed to create new impl: %+v", err)
+	}
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{ReceptionID: channelID})
+
+	start := time.Unix(1700000000, 0)
+	msgs := []struct {
+		mType  channels.MessageType
+		hidden bool
+	}{
+		{channels.Text, false},
+		{channels.Text, false},
+		{channels.Reaction, false}, // Reactions are not counted
+		{channels.Text, true},      // Hidden messages are not counted
+		{channels.Text, false},
+	}
+	for j, m := range msgs {
+		msgID := message.DeriveChannelMessageID(
+			channelID, uint64(j), []byte(strconv.Itoa(j)))
+		model.ReceiveMessage(channelID, msgID, "nick", "text",
+			ed25519.PublicKey("pubKey"), 0, 0,
+			start.Add(time.Duration(j)*time.Minute), 0, rounds.Round{},
+			m.mType, channels.Delivered, m.hidden)
+	}
+
+	tests := []struct {
+		markRead time.Time
+		expected uint64
+	}{
+		{time.Time{}, 3},
+		{start, 2},
+		{start.Add(4 * time.Minute), 0},
+		{start.Add(time.Minute), 0}, // Older marker is ignored
+	}
+	for j, tt := range tests {
+		if !tt.markRead.IsZero() {
+			if err = model.MarkRead(channelID, tt.markRead); err != nil {
+				t.Fatalf("Failed to mark read (%d): %+v", j, err)
+			}
+		}
+
+		count, err2 := model.GetUnreadCount(channelID)
+		if err2 != nil {
+			t.Fatalf("Failed to get unread count (%d): %+v", j, err2)
+		} else if count != tt.expected {
+			t.Errorf("Unexpected unread count (%d).\nexpected: %d\nreceived: %d",
+				j, tt.expected, count)
+		}
+	}
+}
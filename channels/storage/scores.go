@@ -0,0 +1,50 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// UpdateScore is called after a received message is stored with the total
+// score given to it by the channel message filters.
+//
+// Returns channels.NoMessageErr if the message does not exist.
+func (i *impl) UpdateScore(uuid uint64, score int32) error {
+	parentErr := "failed to UpdateScore"
+
+	currentMessage := &Message{Id: int64(uuid)}
+
+	// Build a transaction to prevent race conditions
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Take(currentMessage).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(currentMessage).Update("score", score).Error
+	})
+	cancel()
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.WithMessage(channels.NoMessageErr, parentErr)
+		}
+		return errors.WithMessage(err, parentErr)
+	}
+	channelID := &id.ID{}
+	copy(channelID[:], currentMessage.ChannelId)
+
+	go i.cbs.MessageReceived(currentMessage.Id, channelID, true)
+
+	return nil
+}
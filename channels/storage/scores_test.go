@@ -0,0 +1,61 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl satisfies the channels.ScoredEventModel interface.
+var _ channels.ScoredEventModel = (*impl)(nil)
+
+// Tests that impl.UpdateScore sets the score of the stored message.
+func Test_impl_UpdateScore(t *testing.T) {
+	model, err := newImpl(
+		"file:Test_impl_UpdateScore?mode=memory&cache=shared", &dummyCbs{})
+	if err != nil {
+		t.Fatalf("Failed to create new impl: %+v", err)
+	}
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{ReceptionID: channelID})
+
+	msgID := message.DeriveChannelMessageID(channelID, 0, []byte("text"))
+	uuid := model.ReceiveMessage(channelID, msgID, "nick", "text",
+		ed25519.PublicKey("pubKey"), 0, 0, time.Unix(1700000000, 0), 0,
+		rounds.Round{}, channels.Text, channels.Delivered, false)
+
+	if err = model.UpdateScore(uuid, 42); err != nil {
+		t.Fatalf("Failed to update score: %+v", err)
+	}
+
+	msg := &Message{Id: int64(uuid)}
+	if err = model.db.Take(msg).Error; err != nil {
+		t.Fatalf("Failed to get message: %+v", err)
+	}
+	if msg.Score != 42 {
+		t.Errorf("Unexpected score.\nexpected: %d\nreceived: %d", 42, msg.Score)
+	}
+
+	err = model.UpdateScore(uuid+1, 5)
+	if !errors.Is(err, channels.NoMessageErr) {
+		t.Errorf("Unexpected error for unknown message."+
+			"\nexpected: %v\nreceived: %+v", channels.NoMessageErr, err)
+	}
+}
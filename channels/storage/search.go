@@ -0,0 +1,201 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gorm.io/gorm"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+const (
+	// searchTableName is the name of the full-text search virtual table that
+	// indexes the text of every Message.
+	searchTableName = "message_search"
+
+	// defaultSearchLimit is the number of results returned by
+	// [impl.SearchMessages] when no limit is specified.
+	defaultSearchLimit = 50
+)
+
+// searchIndexTriggers keep the full-text search index in sync with the Message
+// table. Because they are triggers, rows removed by a cascading delete (e.g.,
+// on LeaveChannel) are also removed from the index.
+var searchIndexTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS message_search_insert
+	AFTER INSERT ON messages BEGIN
+		INSERT INTO message_search(rowid, text)
+		VALUES (new.id, CAST(new.text AS TEXT));
+	END;`,
+	`CREATE TRIGGER IF NOT EXISTS message_search_delete
+	AFTER DELETE ON messages BEGIN
+		DELETE FROM message_search WHERE rowid = old.id;
+	END;`,
+	`CREATE TRIGGER IF NOT EXISTS message_search_update
+	AFTER UPDATE OF text ON messages BEGIN
+		UPDATE message_search SET text = CAST(new.text AS TEXT)
+		WHERE rowid = new.id;
+	END;`,
+}
+
+// SearchParams contains the filters used to search for stored messages. All
+// fields are optional; zero values do not filter.
+type SearchParams struct {
+	// Text is the search string. Each whitespace-separated word must appear in
+	// the message text. Matching is case-insensitive.
+	Text string
+
+	// ChannelID restricts results to a single channel.
+	ChannelID *id.ID
+
+	// PubKey restricts results to messages sent by a single user.
+	PubKey ed25519.PublicKey
+
+	// Start and End restrict results to messages with a timestamp in the range
+	// [Start, End).
+	Start, End time.Time
+
+	// Types restricts results to messages of the given types.
+	Types []channels.MessageType
+
+	// IncludeHidden includes hidden messages (e.g., from muted users) in the
+	// results.
+	IncludeHidden bool
+
+	// Limit is the maximum number of results returned. Defaults to 50.
+	Limit int
+
+	// Offset is the number of results skipped, used for paging.
+	Offset int
+}
+
+// initSearchIndex creates the full-text search table and the triggers that
+// maintain it. FTS5 is used when the SQLite build supports it (i.e., when built
+// with the sqlite_fts5 tag); otherwise, it falls back to FTS4.
+//
+// If the table is created for the first time, all existing messages are added
+// to the index.
+func initSearchIndex(db *gorm.DB) error {
+	exists := db.Migrator().HasTable(searchTableName)
+	if !exists {
+		err := db.Exec("CREATE VIRTUAL TABLE " + searchTableName +
+			" USING fts5(text)").Error
+		if err != nil {
+			jww.WARN.Printf("FTS5 is unavailable, falling back to FTS4 "+
+				"for message search: %+v", err)
+			err = db.Exec("CREATE VIRTUAL TABLE " + searchTableName +
+				" USING fts4(text)").Error
+			if err != nil {
+				return errors.Errorf(
+					"failed to create message search table: %+v", err)
+			}
+		}
+	}
+
+	for _, trigger := range searchIndexTriggers {
+		if err := db.Exec(trigger).Error; err != nil {
+			return errors.Errorf(
+				"failed to create message search trigger: %+v", err)
+		}
+	}
+
+	if !exists {
+		err := db.Exec("INSERT INTO " + searchTableName + "(rowid, text) " +
+			"SELECT id, CAST(text AS TEXT) FROM messages").Error
+		if err != nil {
+			return errors.Errorf(
+				"failed to index existing messages: %+v", err)
+		}
+	}
+
+	return nil
+}
+
+// SearchMessages returns the stored messages that match the given parameters,
+// ordered from newest to oldest.
+func (i *impl) SearchMessages(params SearchParams) (
+	[]channels.ModelMessage, error) {
+	parentErr := "failed to SearchMessages"
+
+	ctx, cancel := newContext()
+	defer cancel()
+	tx := i.db.WithContext(ctx).Model(&Message{})
+
+	if query := buildMatchQuery(params.Text); query != "" {
+		tx = tx.Where("id IN (SELECT rowid FROM "+searchTableName+
+			" WHERE "+searchTableName+" MATCH ?)", query)
+	}
+	if params.ChannelID != nil {
+		tx = tx.Where("channel_id = ?", params.ChannelID.Marshal())
+	}
+	if params.PubKey != nil {
+		tx = tx.Where("pubkey = ?", []byte(params.PubKey))
+	}
+	if !params.Start.IsZero() {
+		tx = tx.Where("timestamp >= ?", params.Start)
+	}
+	if !params.End.IsZero() {
+		tx = tx.Where("timestamp < ?", params.End)
+	}
+	if len(params.Types) > 0 {
+		types := make([]uint16, len(params.Types))
+		for j, mt := range params.Types {
+			types[j] = uint16(mt)
+		}
+		tx = tx.Where("type IN ?", types)
+	}
+	if !params.IncludeHidden {
+		tx = tx.Where("hidden = ?", false)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	var results []Message
+	err := tx.Order("timestamp DESC").Order("id DESC").
+		Limit(limit).Offset(params.Offset).Find(&results).Error
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	}
+
+	return buildModelMessages(results)
+}
+
+// buildMatchQuery converts free text into a full-text search query where every
+// word must match. Each word is quoted so that characters that have meaning in
+// the query syntax are matched literally. Double quotes are dropped since the
+// tokenizer treats them as separators and FTS4 cannot escape them.
+func buildMatchQuery(text string) string {
+	words := strings.Fields(strings.ReplaceAll(text, `"`, " "))
+	for j, word := range words {
+		words[j] = `"` + word + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// buildModelMessages converts a list of stored Message into a list of
+// [channels.ModelMessage].
+func buildModelMessages(msgs []Message) ([]channels.ModelMessage, error) {
+	modelMsgs := make([]channels.ModelMessage, len(msgs))
+	for j := range msgs {
+		var err error
+		modelMsgs[j], err = buildModelMessage(&msgs[j])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return modelMsgs, nil
+}
@@ -0,0 +1,123 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.SearchMessages filters messages by text, channel, sender,
+// time, and type, and that deleted messages are removed from the index.
+func Test_impl_SearchMessages(t *testing.T) {
+	model, err := newImpl(
+		"file:Test_impl_SearchMessages?mode=memory&cache=shared", &dummyCbs{})
+	if err != nil {
+		t.Fatalf("Failed to create new impl: %+v", err)
+	}
+
+	chanA, chanB := id.NewIdFromString("chanA", id.User, t),
+		id.NewIdFromString("chanB", id.User, t)
+	for _, chanID := range []*id.ID{chanA, chanB} {
+		model.JoinChannel(&cryptoBroadcast.Channel{ReceptionID: chanID})
+	}
+
+	alice, bob := ed25519.PublicKey("alice"), ed25519.PublicKey("bob")
+	start := time.Unix(1700000000, 0)
+	msgs := []struct {
+		channelID *id.ID
+		text      string
+		pubKey    ed25519.PublicKey
+		mt        channels.MessageType
+	}{
+		{chanA, "hello world", alice, channels.Text},
+		{chanA, "Hello there", bob, channels.Text},
+		{chanB, "goodbye world", alice, channels.Text},
+		{chanB, "world", bob, channels.Reaction},
+		{chanA, `"quoted" world`, bob, channels.Text},
+	}
+	msgIDs := make([]message.ID, len(msgs))
+	for j, m := range msgs {
+		msgIDs[j] = message.DeriveChannelMessageID(
+			m.channelID, uint64(j), []byte(strconv.Itoa(j)))
+		model.ReceiveMessage(m.channelID, msgIDs[j], "nick", m.text, m.pubKey,
+			0, 0, start.Add(time.Duration(j)*time.Minute), 0, rounds.Round{},
+			m.mt, channels.Delivered, false)
+	}
+
+	tests := []struct {
+		params   SearchParams
+		expected []int
+	}{
+		{SearchParams{Text: "world"}, []int{4, 3, 2, 0}},
+		{SearchParams{Text: "hello"}, []int{1, 0}},
+		{SearchParams{Text: "hello world"}, []int{0}},
+		{SearchParams{Text: `"quoted"`}, []int{4}},
+		{SearchParams{Text: "world", ChannelID: chanB}, []int{3, 2}},
+		{SearchParams{Text: "world", PubKey: alice}, []int{2, 0}},
+		{SearchParams{Text: "world", Types: []channels.MessageType{
+			channels.Reaction}}, []int{3}},
+		{SearchParams{ChannelID: chanA, Start: start.Add(time.Minute),
+			End: start.Add(4 * time.Minute)}, []int{1}},
+		{SearchParams{Text: "world", Limit: 2}, []int{4, 3}},
+		{SearchParams{Text: "world", Limit: 2, Offset: 2}, []int{2, 0}},
+		{SearchParams{Text: "missing"}, []int{}},
+	}
+
+	for j, tt := range tests {
+		results, err := model.SearchMessages(tt.params)
+		if err != nil {
+			t.Errorf("Failed to search (%d): %+v", j, err)
+			continue
+		}
+		if len(results) != len(tt.expected) {
+			t.Errorf("Unexpected number of results (%d)."+
+				"\nexpected: %d\nreceived: %d",
+				j, len(tt.expected), len(results))
+			continue
+		}
+		for k, result := range results {
+			if result.MessageID != msgIDs[tt.expected[k]] {
+				t.Errorf("Unexpected result %d (%d).\nexpected: %s\nreceived: %s",
+					k, j, msgIDs[tt.expected[k]], result.MessageID)
+			}
+		}
+	}
+
+	// Delete a message and ensure it no longer appears in search results
+	if err = model.DeleteMessage(msgIDs[0]); err != nil {
+		t.Fatalf("Failed to delete message: %+v", err)
+	}
+	results, err := model.SearchMessages(SearchParams{Text: "hello"})
+	if err != nil {
+		t.Fatalf("Failed to search: %+v", err)
+	}
+	if len(results) != 1 || results[0].MessageID != msgIDs[1] {
+		t.Errorf("Unexpected results after delete: %+v", results)
+	}
+
+	// Leave a channel and ensure its messages no longer appear
+	model.LeaveChannel(chanB)
+	results, err = model.SearchMessages(SearchParams{Text: "world"})
+	if err != nil {
+		t.Fatalf("Failed to search: %+v", err)
+	}
+	if len(results) != 1 || results[0].MessageID != msgIDs[4] {
+		t.Errorf("Unexpected results after leave: %+v", results)
+	}
+}
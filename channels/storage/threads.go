@@ -0,0 +1,226 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// A reply is a message of one of the replyTypes that references a parent
+// message. Other messages, such as reactions, also use the parent field but are
+// not part of the thread. The queries below walk Message.ParentMessageId
+// recursively so that replies to replies belong to the same thread as their
+// root.
+const (
+	// threadQuery selects the root message and all of its descendant replies.
+	threadQuery = `WITH RECURSIVE thread(id, message_id) AS (
+		SELECT id, message_id FROM messages WHERE message_id = ?
+		UNION
+		SELECT m.id, m.message_id FROM messages m
+			JOIN thread t ON m.parent_message_id = t.message_id
+			WHERE m.type IN ?
+	)
+	SELECT * FROM messages WHERE id IN (SELECT id FROM thread)
+	ORDER BY timestamp ASC, id ASC`
+
+	// replyCountQuery counts the visible descendant replies for each of the
+	// given root messages.
+	replyCountQuery = `WITH RECURSIVE thread(root, message_id) AS (
+		SELECT message_id, message_id FROM messages WHERE message_id IN ?
+		UNION
+		SELECT t.root, m.message_id FROM messages m
+			JOIN thread t ON m.parent_message_id = t.message_id
+			WHERE m.type IN ? AND m.hidden = false
+	)
+	SELECT root, COUNT(*) - 1 AS reply_count FROM thread GROUP BY root`
+
+	// activeThreadsQuery lists the threads in a channel that have at least one
+	// visible reply, ordered by their most recent reply. SQLite returns the
+	// bare message_id column from the same row as MAX(timestamp).
+	activeThreadsQuery = `WITH RECURSIVE thread(root, message_id, timestamp, is_reply) AS (
+		SELECT message_id, message_id, timestamp, 0 FROM messages
+			WHERE channel_id = ? AND parent_message_id IS NULL
+			AND type != ? AND hidden = false
+		UNION
+		SELECT t.root, m.message_id, m.timestamp, 1 FROM messages m
+			JOIN thread t ON m.parent_message_id = t.message_id
+			WHERE m.type IN ? AND m.hidden = false
+	)
+	SELECT root, COUNT(*) AS reply_count,
+		message_id AS last_reply_id, MAX(timestamp) AS last_reply_time
+	FROM thread WHERE is_reply = 1 GROUP BY root
+	ORDER BY last_reply_time DESC LIMIT ? OFFSET ?`
+)
+
+// replyTypes are the message types that are replies when they reference a
+// parent message. Replies are always received as Text.
+var replyTypes = []uint16{uint16(channels.Text)}
+
+// Thread is a summary of a message thread in a channel.
+type Thread struct {
+	// Root is the message that started the thread.
+	Root channels.ModelMessage
+
+	// ReplyCount is the number of visible replies in the thread, including
+	// replies to replies.
+	ReplyCount int
+
+	// LastReply is the most recent reply in the thread.
+	LastReply channels.ModelMessage
+}
+
+// threadCount is the result of the reply count queries.
+type threadCount struct {
+	Root        []byte
+	ReplyCount  int
+	LastReplyId []byte
+}
+
+// GetThread returns the root message with the given [message.ID] followed by
+// all of its descendant replies, ordered from oldest to newest. Hidden replies
+// are included and marked as such.
+//
+// Returns an error if the thread cannot be gotten. It returns
+// channels.NoMessageErr if the root message does not exist.
+func (i *impl) GetThread(rootID message.ID) ([]channels.ModelMessage, error) {
+	parentErr := "failed to GetThread"
+
+	var results []Message
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Raw(threadQuery,
+		rootID.Marshal(), replyTypes).Scan(&results).Error
+	cancel()
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	} else if len(results) == 0 {
+		return nil, errors.WithMessage(channels.NoMessageErr, parentErr)
+	}
+
+	// The root is always first, even if a reply has an earlier timestamp due
+	// to clock skew
+	for j := range results {
+		if bytes.Equal(results[j].MessageId, rootID.Marshal()) {
+			root := results[j]
+			copy(results[1:j+1], results[:j])
+			results[0] = root
+			break
+		}
+	}
+
+	modelMsgs, err := buildModelMessages(results)
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	}
+	return modelMsgs, nil
+}
+
+// CountReplies returns the number of visible replies, including replies to
+// replies, for each of the given messages. Messages that do not exist are not
+// included in the returned map.
+func (i *impl) CountReplies(messageIDs ...message.ID) (
+	map[message.ID]int, error) {
+	parentErr := "failed to CountReplies"
+
+	counts := make(map[message.ID]int, len(messageIDs))
+	if len(messageIDs) == 0 {
+		return counts, nil
+	}
+
+	ids := make([][]byte, len(messageIDs))
+	for j, messageID := range messageIDs {
+		ids[j] = messageID.Marshal()
+	}
+
+	var results []threadCount
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Raw(replyCountQuery,
+		ids, replyTypes).Scan(&results).Error
+	cancel()
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	}
+
+	for _, result := range results {
+		messageID, err := message.UnmarshalID(result.Root)
+		if err != nil {
+			return nil, errors.WithMessage(err, parentErr)
+		}
+		counts[messageID] = result.ReplyCount
+	}
+
+	return counts, nil
+}
+
+// GetActiveThreads returns the threads in the channel with at least one
+// visible reply, ordered by most recent reply first. Limit defaults to 50 if it
+// is not positive; offset is used for paging.
+func (i *impl) GetActiveThreads(channelID *id.ID, limit, offset int) (
+	[]Thread, error) {
+	parentErr := "failed to GetActiveThreads"
+
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	var counts []threadCount
+	err := i.db.WithContext(ctx).Raw(activeThreadsQuery, channelID.Marshal(),
+		uint16(channels.Reaction), replyTypes, limit, offset).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	} else if len(counts) == 0 {
+		return []Thread{}, nil
+	}
+
+	// Look up the root and last reply of every thread at once
+	ids := make([][]byte, 0, 2*len(counts))
+	for _, count := range counts {
+		ids = append(ids, count.Root, count.LastReplyId)
+	}
+	var msgs []Message
+	err = i.db.WithContext(ctx).Where("message_id IN ?", ids).Find(&msgs).Error
+	if err != nil {
+		return nil, errors.WithMessage(err, parentErr)
+	}
+	modelMsgs := make(map[string]channels.ModelMessage, len(msgs))
+	for j := range msgs {
+		modelMsg, err := buildModelMessage(&msgs[j])
+		if err != nil {
+			return nil, errors.WithMessage(err, parentErr)
+		}
+		modelMsgs[string(msgs[j].MessageId)] = modelMsg
+	}
+
+	threads := make([]Thread, 0, len(counts))
+	for _, count := range counts {
+		// Skip threads whose messages were deleted since they were counted
+		root, exists := modelMsgs[string(count.Root)]
+		if !exists {
+			continue
+		}
+		lastReply, exists := modelMsgs[string(count.LastReplyId)]
+		if !exists {
+			continue
+		}
+		threads = append(threads, Thread{
+			Root:       root,
+			ReplyCount: count.ReplyCount,
+			LastReply:  lastReply,
+		})
+	}
+
+	return threads, nil
+}
@@ -0,0 +1,144 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	cryptoBroadcast "gitlab.com/elixxir/crypto/broadcast"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.GetThread, impl.CountReplies, and impl.GetActiveThreads
+// walk reply chains and ignore reactions and other non-reply children.
+func Test_impl_Threads(t *testing.T) {
+	model, err := newImpl(
+		"file:Test_impl_Threads?mode=memory&cache=shared", &dummyCbs{})
+	if err != nil {
+		t.Fatalf("Failed to create new impl: %+v", err)
+	}
+
+	channelID := id.NewIdFromString("channel", id.User, t)
+	model.JoinChannel(&cryptoBroadcast.Channel{ReceptionID: channelID})
+
+	pubKey := ed25519.PublicKey("pubKey")
+	start := time.Unix(1700000000, 0)
+	newID := func(j int) message.ID {
+		return message.DeriveChannelMessageID(
+			channelID, uint64(j), []byte(strconv.Itoa(j)))
+	}
+	receive := func(j int, parent *message.ID, mt channels.MessageType,
+		hidden bool) message.ID {
+		msgID := newID(j)
+		ts := start.Add(time.Duration(j) * time.Minute)
+		if parent == nil {
+			model.ReceiveMessage(channelID, msgID, "nick", "text", pubKey, 0,
+				0, ts, 0, rounds.Round{}, mt, channels.Delivered, hidden)
+		} else {
+			model.ReceiveReply(channelID, msgID, *parent, "nick", "text",
+				pubKey, 0, 0, ts, 0, rounds.Round{}, mt, channels.Delivered,
+				hidden)
+		}
+		return msgID
+	}
+
+	// rootA <- replyA1 <- replyA2
+	//       <- reaction
+	//       <- hidden reply
+	//       <- pin
+	// rootB <- replyB1
+	// rootC
+	rootA := receive(0, nil, channels.Text, false)
+	rootB := receive(1, nil, channels.Text, false)
+	rootC := receive(2, nil, channels.Text, false)
+	replyA1 := receive(3, &rootA, channels.Text, false)
+	receive(4, &rootA, channels.Reaction, false)
+	replyB1 := receive(5, &rootB, channels.Text, false)
+	replyA2 := receive(6, &replyA1, channels.Text, false)
+	hidden := receive(7, &rootA, channels.Text, true)
+	receive(8, &rootA, channels.Pinned, false)
+
+	thread, err := model.GetThread(rootA)
+	if err != nil {
+		t.Fatalf("Failed to get thread: %+v", err)
+	}
+	expected := []message.ID{rootA, replyA1, replyA2, hidden}
+	if len(thread) != len(expected) {
+		t.Fatalf("Unexpected thread length.\nexpected: %d\nreceived: %d",
+			len(expected), len(thread))
+	}
+	for j, msg := range thread {
+		if msg.MessageID != expected[j] {
+			t.Errorf("Unexpected message %d in thread."+
+				"\nexpected: %s\nreceived: %s", j, expected[j], msg.MessageID)
+		}
+	}
+
+	_, err = model.GetThread(newID(100))
+	if !errors.Is(err, channels.NoMessageErr) {
+		t.Errorf("Unexpected error for missing root."+
+			"\nexpected: %v\nreceived: %+v", channels.NoMessageErr, err)
+	}
+
+	counts, err := model.CountReplies(rootA, rootB, rootC, replyA1, newID(100))
+	if err != nil {
+		t.Fatalf("Failed to count replies: %+v", err)
+	}
+	expectedCounts := map[message.ID]int{
+		rootA: 2, rootB: 1, rootC: 0, replyA1: 1}
+	if len(counts) != len(expectedCounts) {
+		t.Errorf("Unexpected counts.\nexpected: %v\nreceived: %v",
+			expectedCounts, counts)
+	}
+	for msgID, count := range expectedCounts {
+		if counts[msgID] != count {
+			t.Errorf("Unexpected count for %s.\nexpected: %d\nreceived: %d",
+				msgID, count, counts[msgID])
+		}
+	}
+
+	threads, err := model.GetActiveThreads(channelID, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to get active threads: %+v", err)
+	}
+	expectedThreads := []struct {
+		root, lastReply message.ID
+		count           int
+	}{{rootA, replyA2, 2}, {rootB, replyB1, 1}}
+	if len(threads) != len(expectedThreads) {
+		t.Fatalf("Unexpected number of threads.\nexpected: %d\nreceived: %d",
+			len(expectedThreads), len(threads))
+	}
+	for j, et := range expectedThreads {
+		if threads[j].Root.MessageID != et.root ||
+			threads[j].LastReply.MessageID != et.lastReply ||
+			threads[j].ReplyCount != et.count {
+			t.Errorf("Unexpected thread %d.\nexpected: %+v\nreceived: "+
+				"root %s, last reply %s, count %d", j, et,
+				threads[j].Root.MessageID, threads[j].LastReply.MessageID,
+				threads[j].ReplyCount)
+		}
+	}
+
+	threads, err = model.GetActiveThreads(channelID, 1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get active threads: %+v", err)
+	}
+	if len(threads) != 1 || threads[0].Root.MessageID != rootB {
+		t.Errorf("Unexpected paged threads: %+v", threads)
+	}
+}
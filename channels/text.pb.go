@@ -468,6 +468,71 @@ func (x *CMIXChannelInvitation) GetPassword() string {
 	return ""
 }
 
+// CMIXChannelEdit is the payload for an Edit MessageType. It replaces the text
+// of a message previously sent by the same user.
+type CMIXChannelEdit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version   uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	MessageID []byte `protobuf:"bytes,2,opt,name=messageID,proto3" json:"messageID,omitempty"` // The [channel.MessageID] of the message to edit
+	Text      string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`           // The new text of the message
+}
+
+func (x *CMIXChannelEdit) Reset() {
+	*x = CMIXChannelEdit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_text_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CMIXChannelEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CMIXChannelEdit) ProtoMessage() {}
+
+func (x *CMIXChannelEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_text_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CMIXChannelEdit.ProtoReflect.Descriptor instead.
+func (*CMIXChannelEdit) Descriptor() ([]byte, []int) {
+	return file_text_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CMIXChannelEdit) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CMIXChannelEdit) GetMessageID() []byte {
+	if x != nil {
+		return x.MessageID
+	}
+	return nil
+}
+
+func (x *CMIXChannelEdit) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
 var File_text_proto protoreflect.FileDescriptor
 
 var file_text_proto_rawDesc = []byte{
@@ -516,10 +581,15 @@ var file_text_proto_rawDesc = []byte{
 	0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x76,
 	0x69, 0x74, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
 	0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
-	0x6f, 0x72, 0x64, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x65, 0x6c, 0x69, 0x78, 0x78, 0x69, 0x72, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x2f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x6f, 0x72, 0x64, 0x22, 0x5d, 0x0a, 0x0f, 0x43, 0x4d, 0x49, 0x58, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x45, 0x64, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x65, 0x6c, 0x69, 0x78, 0x78, 0x69, 0x72, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2f,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -534,7 +604,7 @@ func file_text_proto_rawDescGZIP() []byte {
 	return file_text_proto_rawDescData
 }
 
-var file_text_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_text_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_text_proto_goTypes = []interface{}{
 	(*CMIXChannelText)(nil),          // 0: channels.CMIXChannelText
 	(*CMIXChannelReaction)(nil),      // 1: channels.CMIXChannelReaction
@@ -543,6 +613,7 @@ var file_text_proto_goTypes = []interface{}{
 	(*CMIXChannelPinned)(nil),        // 4: channels.CMIXChannelPinned
 	(*CMIXChannelMute)(nil),          // 5: channels.CMIXChannelMute
 	(*CMIXChannelInvitation)(nil),    // 6: channels.CMIXChannelInvitation
+	(*CMIXChannelEdit)(nil),          // 7: channels.CMIXChannelEdit
 }
 var file_text_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -642,6 +713,18 @@ func file_text_proto_init() {
 				return nil
 			}
 		}
+		file_text_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CMIXChannelEdit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -649,7 +732,7 @@ func file_text_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_text_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -26,6 +26,7 @@ type userListener struct {
 	chID      *id.ID
 	trigger   triggerEventFunc
 	checkSent messageReceiveFunc
+	checkEdit checkEditFunc
 }
 
 // Listen is called when a message is received for the user listener.
@@ -67,6 +68,15 @@ func (ul *userListener) Listen(payload, encryptedPayload []byte, tags []string,
 		return
 	}
 
+	// Check that edits are signed by the original sender of the edited message
+	if mt == Edit &&
+		!ul.checkEdit(ul.chID, msgID, um.ECCPublicKey, cm.Payload) {
+		jww.WARN.Printf("[CH] Edit %s on channel %s from %x was not sent by "+
+			"the original sender of the edited message",
+			msgID, ul.chID, um.ECCPublicKey)
+		return
+	}
+
 	// Replace the timestamp on the message if it is outside the allowable range
 	ts := message.VetTimestamp(
 		time.Unix(0, cm.LocalTimestamp), round.Timestamps[states.QUEUED], msgID)
@@ -364,3 +364,76 @@ func Test_userListener_Listen_BadSizedBroadcast(t *testing.T) {
 		t.Fatalf("Data returned after invalid listen")
 	}
 }
+
+// Tests that an edit is rejected when it is not from the original sender of the
+// edited message.
+func Test_userListener_Listen_EditNotFromSender(t *testing.T) {
+	// Build inputs
+	chID := &id.ID{}
+	chID[0] = 1
+
+	r := rounds.Round{ID: 420, Timestamps: make(map[states.Round]time.Time)}
+	r.Timestamps[states.QUEUED] = netTime.Now()
+
+	rng := rand.New(rand.NewSource(42))
+	pub, priv, err := ed25519.GenerateKey(rng)
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 keypair, cant run test")
+	}
+
+	cm := &ChannelMessage{
+		Lease:   int64(time.Hour),
+		RoundID: uint64(r.ID),
+		Payload: []byte("blarg"),
+	}
+
+	cmSerial, err := proto.Marshal(cm)
+	if err != nil {
+		t.Fatalf("Failed to marshal proto: %+v", err)
+	}
+
+	sig := ed25519.Sign(priv, cmSerial)
+	ns := &mockNameService{validChMsg: true}
+
+	um := &UserMessage{
+		Message:      cmSerial,
+		Signature:    sig,
+		ECCPublicKey: pub,
+	}
+
+	umSerial, err := proto.Marshal(um)
+	if err != nil {
+		t.Fatalf("Failed to marshal proto: %+v", err)
+	}
+
+	// Build the listener
+	dummy := &triggerEventDummy{}
+
+	var checked bool
+	al := userListener{
+		chID:    chID,
+		name:    ns,
+		trigger: dummy.triggerEvent,
+		checkSent: func(message.ID, rounds.Round) bool {
+			return false
+		},
+		checkEdit: func(channelID *id.ID, _ message.ID,
+			pubKey ed25519.PublicKey, content []byte) bool {
+			checked = channelID.Cmp(chID) && pubKey.Equal(pub) &&
+				bytes.Equal(content, cm.Payload)
+			return false
+		},
+	}
+
+	// Call the listener
+	al.Listen(umSerial, nil, nil, Edit.Marshal(),
+		receptionID.EphemeralIdentity{}, r)
+
+	// Check the results
+	if !checked {
+		t.Errorf("Edit not checked with expected values")
+	}
+	if dummy.gotData {
+		t.Fatalf("Data returned after invalid listen")
+	}
+}
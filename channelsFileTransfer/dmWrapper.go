@@ -0,0 +1,166 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channelsFileTransfer
+
+import (
+	"crypto/ed25519"
+	"time"
+
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	ftCrypto "gitlab.com/elixxir/crypto/fileTransfer"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+)
+
+// DMSender interface matches a subset of the dm.Sender methods used by the
+// DMWrapper for easier testing.
+type DMSender interface {
+	Send(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		messageType dm.MessageType, plaintext []byte,
+		params cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error)
+}
+
+// DMWrapper facilitates the sending and receiving of files over direct
+// messages. Files are uploaded and downloaded the same way as with Wrapper, but
+// the file info is sent to a DM partner as a dm.FileTransferType message
+// instead of to a channel.
+type DMWrapper struct {
+	w  *Wrapper
+	dm DMSender
+}
+
+// NewDMWrapper generates a new file transfer wrapper for the DM client and
+// file event model. It allows for sending and receiving of files over direct
+// messages. Any in-progress uploads and downloads are resumed.
+func NewDMWrapper(user FtE2e, params Params, dmClient DMSender,
+	ev FileEventModel) (*DMWrapper, error) {
+
+	// Create new file manager and get list of in-progress sends and receives
+	fm, inProgressSends, inProgressReceives, err := newManager(user, params)
+	if err != nil {
+		return nil, err
+	}
+	jww.INFO.Printf("[FT] Starting DM file transfer manager; found %d "+
+		"in-progress uploads and %d in-progress downloads",
+		len(inProgressSends), len(inProgressReceives))
+
+	w := &Wrapper{m: fm, ev: ev}
+	err = w.loadInProgress(inProgressSends, inProgressReceives)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DMWrapper{w: w, dm: dmClient}, nil
+}
+
+// StartProcesses starts the sending threads. Adheres to the xxdk.Service type.
+func (dw *DMWrapper) StartProcesses() (stoppable.Stoppable, error) {
+	return dw.w.StartProcesses()
+}
+
+// MaxFileNameLen returns the max number of bytes allowed for a file name.
+func (dw *DMWrapper) MaxFileNameLen() int {
+	return dw.w.MaxFileNameLen()
+}
+
+// MaxFileTypeLen returns the max number of bytes allowed for a file type.
+func (dw *DMWrapper) MaxFileTypeLen() int {
+	return dw.w.MaxFileTypeLen()
+}
+
+// MaxFileSize returns the max number of bytes allowed for a file.
+func (dw *DMWrapper) MaxFileSize() int {
+	return dw.w.MaxFileSize()
+}
+
+// MaxPreviewSize returns the max number of bytes allowed for a file preview.
+func (dw *DMWrapper) MaxPreviewSize() int {
+	return dw.w.MaxPreviewSize()
+}
+
+/* === Sending ============================================================== */
+
+// Upload starts uploading the file to a new ID that can be sent to a DM
+// partner when complete. To get progress information about the upload a
+// SentProgressCallback must be registered. Refer to FileTransfer.Upload for
+// more details.
+func (dw *DMWrapper) Upload(fileData []byte, retry float32,
+	progressCB SentProgressCallback, period time.Duration) (ftCrypto.ID, error) {
+	return dw.w.Upload(fileData, retry, progressCB, period)
+}
+
+// Send sends the specified file info to the DM partner. Once a file is
+// uploaded via Upload, its file link (found in the event model) can be sent to
+// any partner.
+//
+// Parameters:
+//   - partnerPubKey - The Ed25519 public key of the partner.
+//   - partnerToken - The DM token of the partner.
+//   - fileLink - JSON of FileLink stored in the event model.
+//   - fileName - Human-readable file name. Max length defined by
+//     MaxFileNameLen.
+//   - fileType - Shorthand that identifies the type of file. Max length
+//     defined by MaxFileTypeLen.
+//   - preview - A preview of the file data (e.g. a thumbnail). Max size
+//     defined by MaxPreviewSize.
+//   - params - The cmix.CMIXParams to send this.
+func (dw *DMWrapper) Send(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, fileLink []byte, fileName, fileType string,
+	preview []byte, params cmix.CMIXParams) (
+	message.ID, rounds.Round, ephemeral.Id, error) {
+
+	fileInfo, err := dw.w.makeFileInfo(fileLink, fileName, fileType, preview)
+	if err != nil {
+		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return dw.dm.Send(
+		partnerPubKey, partnerToken, dm.FileTransferType, fileInfo, params)
+}
+
+// RegisterSentProgressCallback registers the callback to the given file ID.
+func (dw *DMWrapper) RegisterSentProgressCallback(fileID ftCrypto.ID,
+	progressCB SentProgressCallback, period time.Duration) error {
+	return dw.w.RegisterSentProgressCallback(fileID, progressCB, period)
+}
+
+// RetryUpload retries uploading a failed file upload. Returns an error if the
+// transfer has not run out of retries.
+func (dw *DMWrapper) RetryUpload(fileID ftCrypto.ID,
+	progressCB SentProgressCallback, period time.Duration) error {
+	return dw.w.RetryUpload(fileID, progressCB, period)
+}
+
+// CloseSend deletes a file from the internal storage once a transfer has
+// completed or reached the retry limit. If neither of those condition are
+// met, an error is returned.
+func (dw *DMWrapper) CloseSend(fileID ftCrypto.ID) error {
+	return dw.w.CloseSend(fileID)
+}
+
+/* === Receiving ============================================================ */
+
+// Download beings the download of the file described in the marshalled
+// FileInfo received in a dm.FileTransferType message. The progress of the
+// download is reported on the progress callback.
+func (dw *DMWrapper) Download(fileInfo []byte,
+	progressCB ReceivedProgressCallback, period time.Duration) (
+	ftCrypto.ID, error) {
+	return dw.w.Download(fileInfo, progressCB, period)
+}
+
+// RegisterReceivedProgressCallback registers the callback to the given file ID.
+func (dw *DMWrapper) RegisterReceivedProgressCallback(fileID ftCrypto.ID,
+	progressCB ReceivedProgressCallback, period time.Duration) error {
+	return dw.w.RegisterReceivedProgressCallback(fileID, progressCB, period)
+}
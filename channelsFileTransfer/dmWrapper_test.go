@@ -0,0 +1,175 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package channelsFileTransfer
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+)
+
+// Smoke test of uploading a file, sending it over a DM, and downloading it.
+func Test_DMWrapper_Smoke(t *testing.T) {
+	timeout := 15 * time.Second
+	cMixHandler := newMockCmixHandler()
+	rngGen := fastRNG.NewStreamGenerator(1000, 10, csprng.NewSystemRNG)
+	params := DefaultParams()
+	params.ResendWait = 15 * time.Millisecond
+	params.MaxThroughput = 0
+
+	sentCh := make(chan mockDMSend, 10)
+	dmSender := &mockDMSender{sentCh}
+
+	// Set up the first client
+	myID1 := id.NewIdFromString("myID1", id.User, t)
+	storage1 := newMockStorage()
+	cMix1 := newMockCmix(myID1, cMixHandler, storage1)
+	user1 := newMockE2e(myID1, cMix1, storage1, rngGen)
+	evFileCh1 := make(chan ModelFile, 100)
+	ev1 := newMockEventModel(func(msg ModelFile) { evFileCh1 <- msg },
+		func(channels.ModelMessage) {}, t)
+
+	dw1, err := NewDMWrapper(user1, params, dmSender, ev1)
+	require.NoError(t, err)
+
+	// Set up the second client
+	myID2 := id.NewIdFromString("myID2", id.User, t)
+	storage2 := newMockStorage()
+	cMix2 := newMockCmix(myID2, cMixHandler, storage2)
+	user2 := newMockE2e(myID2, cMix2, storage2, rngGen)
+	evFileCh2 := make(chan ModelFile, 100)
+	ev2 := newMockEventModel(func(msg ModelFile) { evFileCh2 <- msg },
+		func(channels.ModelMessage) {}, t)
+
+	dw2, err := NewDMWrapper(user2, params, dmSender, ev2)
+	require.NoError(t, err)
+
+	stop1, err := dw1.StartProcesses()
+	require.NoError(t, err)
+	stop2, err := dw2.StartProcesses()
+	require.NoError(t, err)
+
+	// Upload file
+	fileName, fileType := "myFile", "txt"
+	fileData := []byte(loremIpsum)
+	preview := []byte("Lorem ipsum dolor sit amet")
+	fid, err := dw1.Upload(fileData, 2.0, nil, 0)
+	require.NoError(t, err)
+
+	var fileLink []byte
+	for fileLink == nil {
+		select {
+		case f := <-evFileCh1:
+			if f.Status == Complete {
+				fileLink = f.Link
+			}
+		case <-time.After(timeout):
+			t.Fatalf("Timed out after %s waiting for file to upload.", timeout)
+		}
+	}
+
+	// Send the file to the partner
+	partnerPubKey, _, err := ed25519.GenerateKey(csprng.NewSystemRNG())
+	require.NoError(t, err)
+	_, _, _, err = dw1.Send(partnerPubKey, 42, fileLink, fileName, fileType,
+		preview, cmix.GetDefaultCMIXParams())
+	require.NoError(t, err)
+
+	var sent mockDMSend
+	select {
+	case sent = <-sentCh:
+	case <-time.After(timeout):
+		t.Fatalf("Timed out after %s waiting for DM send.", timeout)
+	}
+	require.Equal(t, partnerPubKey, sent.partnerPubKey)
+	require.Equal(t, uint32(42), sent.partnerToken)
+	require.Equal(t, dm.FileTransferType, sent.messageType)
+
+	var fl FileLink
+	require.NoError(t, json.Unmarshal(fileLink, &fl))
+	expectedContent, err := json.Marshal(
+		FileInfo{fileName, fileType, preview, fl})
+	require.NoError(t, err)
+	require.Equal(t, expectedContent, sent.plaintext)
+
+	// Download the file on the second client
+	_, err = dw2.Download(sent.plaintext, nil, 0)
+	require.NoError(t, err)
+
+	for {
+		select {
+		case f := <-evFileCh2:
+			if f.Status != Complete {
+				continue
+			}
+			require.Equal(t, fid, f.ID)
+			require.Equal(t, fileData, f.Data)
+			require.NoError(t, stop1.Close())
+			require.NoError(t, stop2.Close())
+			return
+		case <-time.After(timeout):
+			t.Fatalf("Timed out after %s waiting for file to download.", timeout)
+		}
+	}
+}
+
+// Tests that DMWrapper.Send returns an error for an invalid file link and does
+// not send anything.
+func TestDMWrapper_Send_InvalidLink(t *testing.T) {
+	sentCh := make(chan mockDMSend, 10)
+	myID := id.NewIdFromString("myID", id.User, t)
+	s := newMockStorage()
+	user := newMockE2e(myID, newMockCmix(myID, newMockCmixHandler(), s), s,
+		fastRNG.NewStreamGenerator(1000, 10, csprng.NewSystemRNG))
+	ev := newMockEventModel(func(ModelFile) {},
+		func(channels.ModelMessage) {}, t)
+
+	dw, err := NewDMWrapper(user, DefaultParams(), &mockDMSender{sentCh}, ev)
+	require.NoError(t, err)
+
+	_, _, _, err = dw.Send(make(ed25519.PublicKey, ed25519.PublicKeySize), 42,
+		[]byte("invalid"), "name", "txt", nil, cmix.GetDefaultCMIXParams())
+	require.Error(t, err)
+	require.Empty(t, sentCh)
+}
+
+// Tests that dm.Client adheres to the DMSender interface.
+var _ DMSender = dm.Client(nil)
+
+// mockDMSend contains the parameters of a call to mockDMSender.Send.
+type mockDMSend struct {
+	partnerPubKey ed25519.PublicKey
+	partnerToken  uint32
+	messageType   dm.MessageType
+	plaintext     []byte
+}
+
+// mockDMSender adheres to the DMSender interface.
+type mockDMSender struct {
+	sent chan mockDMSend
+}
+
+func (m *mockDMSender) Send(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, messageType dm.MessageType, plaintext []byte,
+	_ cmix.CMIXParams) (message.ID, rounds.Round, ephemeral.Id, error) {
+	m.sent <- mockDMSend{partnerPubKey, partnerToken, messageType, plaintext}
+	return message.ID{}, rounds.Round{}, ephemeral.Id{}, nil
+}
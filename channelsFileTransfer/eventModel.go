@@ -18,6 +18,14 @@ import (
 // EventModel is an interface that allows the user to get channels messages and
 // file transfers.
 type EventModel interface {
+	FileEventModel
+	channels.EventModel
+}
+
+// FileEventModel is an interface that allows the user to get file transfers.
+// It contains the file storage methods of EventModel so that files can be
+// tracked for transfers that do not happen over channels (e.g., DMWrapper).
+type FileEventModel interface {
 	// ReceiveFile is called when a file upload or download beings.
 	//
 	// fileLink and fileData are nillable and may be updated based upon the UUID
@@ -53,8 +61,6 @@ type EventModel interface {
 	// Returns fatal errors. It must return channels.NoMessageErr if the file
 	// does not exist.
 	DeleteFile(fileID ftCrypto.ID) error
-
-	channels.EventModel
 }
 
 // ModelFile contains a file and all of its information.
@@ -540,6 +540,15 @@ func (m *mockEventModel) GetMessage(cryptoMessage.ID) (channels.ModelMessage, er
 }
 func (m *mockEventModel) DeleteMessage(cryptoMessage.ID) error     { panic("implement me") }
 func (m *mockEventModel) MuteUser(*id.ID, ed25519.PublicKey, bool) { panic("implement me") }
+func (m *mockEventModel) EditMessage(cryptoMessage.ID, cryptoMessage.ID, string, time.Time) (uint64, error) {
+	panic("implement me")
+}
+func (m *mockEventModel) ReceiveVote(cryptoMessage.ID, cryptoMessage.ID, ed25519.PublicKey, uint32, time.Time) (uint64, error) {
+	panic("implement me")
+}
+func (m *mockEventModel) ClosePoll(cryptoMessage.ID, time.Time) (uint64, error) {
+	panic("implement me")
+}
 
 ////////////////////////////////////////////////////////////////////////////////
 // Mock Channels Manager                                                      //
@@ -628,12 +637,54 @@ func (m *mockChannelsManager) PinMessage(*id.ID, cryptoMessage.ID, bool, time.Du
 func (m *mockChannelsManager) MuteUser(*id.ID, ed25519.PublicKey, bool, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
 	panic("implement me")
 }
+func (m *mockChannelsManager) SendEdit(*id.ID, cryptoMessage.ID, string, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) SendPoll(*id.ID, string, []string, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) SendVote(*id.ID, cryptoMessage.ID, uint32, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) ClosePoll(*id.ID, cryptoMessage.ID, time.Time, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) GrantModerator(*id.ID, ed25519.PublicKey, channels.ModeratorPermission, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) RevokeModerator(*id.ID, ed25519.PublicKey, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) SetSlowMode(*id.ID, time.Duration, time.Duration, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) DisableSlowMode(*id.ID, cmix.CMIXParams) (cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) ScheduleSend(*id.ID, channels.MessageType, []byte, time.Duration, bool, time.Time, cmix.CMIXParams, map[channels.PingType][]ed25519.PublicKey) (uint64, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) ScheduleAdminSend(*id.ID, channels.MessageType, []byte, time.Duration, bool, time.Time, cmix.CMIXParams) (uint64, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) GetScheduledSends(*id.ID) []channels.ScheduledSend {
+	panic("implement me")
+}
+func (m *mockChannelsManager) RegisterScheduledSendFailedCallback(channels.ScheduledSendFailedCallback) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) CancelScheduledSend(uint64) error             { panic("implement me") }
+func (m *mockChannelsManager) RescheduleSend(uint64, time.Time) error       { panic("implement me") }
 func (m *mockChannelsManager) GetIdentity() cryptoChannel.Identity          { panic("implement me") }
 func (m *mockChannelsManager) ExportPrivateIdentity(string) ([]byte, error) { panic("implement me") }
 func (m *mockChannelsManager) GetStorageTag() string                        { panic("implement me") }
 func (m *mockChannelsManager) RegisterReceiveHandler(channels.MessageType, *channels.ReceiveMessageHandler) error {
 	panic("implement me")
 }
+func (m *mockChannelsManager) RegisterMessageFilter(string, channels.MessageFilter) error {
+	panic("implement me")
+}
+func (m *mockChannelsManager) RemoveMessageFilter(string)       { panic("implement me") }
 func (m *mockChannelsManager) SetNickname(string, *id.ID) error { panic("implement me") }
 func (m *mockChannelsManager) DeleteNickname(*id.ID) error      { panic("implement me") }
 
@@ -643,6 +694,22 @@ func (m *mockChannelsManager) GetNickname(*id.ID) (nickname string, exists bool)
 
 func (m *mockChannelsManager) Muted(*id.ID) bool                        { panic("implement me") }
 func (m *mockChannelsManager) GetMutedUsers(*id.ID) []ed25519.PublicKey { panic("implement me") }
+func (m *mockChannelsManager) GetModerators(*id.ID) []channels.ModeratorGrant {
+	panic("implement me")
+}
+func (m *mockChannelsManager) GetSlowMode(*id.ID) time.Duration {
+	panic("implement me")
+}
+func (m *mockChannelsManager) GetMembers(*id.ID) ([]channels.Member, error) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) MarkRead(*id.ID, time.Time) error { panic("implement me") }
+func (m *mockChannelsManager) GetReadMarker(*id.ID) (time.Time, bool) {
+	panic("implement me")
+}
+func (m *mockChannelsManager) GetUnreadCount(*id.ID) (uint64, error) {
+	panic("implement me")
+}
 func (m *mockChannelsManager) GetNotificationLevel(*id.ID) (channels.NotificationLevel, error) {
 	panic("implement me")
 }
@@ -31,10 +31,11 @@ import (
 // Wrapper facilitates the sending and receiving file over channels using the
 // event model. It adheres to the FileTransfer interface.
 type Wrapper struct {
-	m  *manager
-	ch channels.Manager
-	ev EventModel
-	me cryptoChannel.PrivateIdentity
+	m    *manager
+	ch   channels.Manager
+	ev   FileEventModel
+	chEv channels.EventModel
+	me   cryptoChannel.PrivateIdentity
 }
 
 // NewWrapper generated a new file transfer wrapper for the channel manager and
@@ -64,66 +65,75 @@ func NewWrapper(
 
 		w.ch = m
 		w.ev = ev
+		w.chEv = ev
 		w.me = me
 
-		// TODO: Currently, each file is looked up in the event model its own
-		//  GetFile call. In the future, if there are performance issues loading
-		//  in-progress files from the event model on startup, then a new event
-		//  model call GetFiles should be added to get all the files at once.
-
-		uploads := make(map[ftCrypto.ID]ModelFile, len(inProgressSends))
-		downloads := make(map[ftCrypto.ID]ModelFile, len(inProgressReceives))
-		var staleUploads, staleDownloads []ftCrypto.ID
-
-		// Lookup file data each in-progress uploads
-		for i, fid := range inProgressSends {
-			file, err2 := ev.GetFile(fid)
-			if err2 != nil {
-				jww.ERROR.Printf("[FT] Failed to get in-progress file upload "+
-					"%s from event model; dropping upload %d/%d: %+v",
-					fid, i+1, len(inProgressSends), err)
-				staleUploads = append(staleUploads, fid)
-			} else {
-				uploads[fid] = file
-			}
-		}
-
-		// Load the uploads into file transfer manager
-		err = w.m.loadInProgressUploads(
-			uploads, staleUploads, w.uploadErrorTracker, w.uploadCompleteCB)
+		err = w.loadInProgress(inProgressSends, inProgressReceives)
 		if err != nil {
 			return nil, err
 		}
 
-		// Lookup file data each in-progress downloads
-		for i, fid := range inProgressReceives {
-			// Skip any downloads that are already handled in the uploads list
-			if _, exists := uploads[fid]; exists {
-				continue
-			}
+		return []channels.ExtensionMessageHandler{&w}, nil
+	}
 
-			file, err2 := ev.GetFile(fid)
-			if err2 != nil {
-				jww.ERROR.Printf("[FT] Failed to get in-progress file "+
-					"download %s from event model; dropping download %d/%d: %+v",
-					fid, i+1, len(inProgressReceives), err)
-				staleDownloads = append(staleDownloads, fid)
-			} else {
-				downloads[fid] = file
-			}
-		}
+	return &w, eb, nil
+}
 
-		// Load the downloads into file transfer manager
-		err = w.m.loadInProgressDownloads(
-			downloads, staleDownloads, w.downloadCompleteCB)
+// loadInProgress looks up each in-progress upload and download in the event
+// model and loads them into the file transfer manager so that they resume from
+// where they left off.
+func (w *Wrapper) loadInProgress(
+	inProgressSends, inProgressReceives []ftCrypto.ID) error {
+	// TODO: Currently, each file is looked up in the event model its own
+	//  GetFile call. In the future, if there are performance issues loading
+	//  in-progress files from the event model on startup, then a new event
+	//  model call GetFiles should be added to get all the files at once.
+
+	uploads := make(map[ftCrypto.ID]ModelFile, len(inProgressSends))
+	downloads := make(map[ftCrypto.ID]ModelFile, len(inProgressReceives))
+	var staleUploads, staleDownloads []ftCrypto.ID
+
+	// Lookup file data each in-progress uploads
+	for i, fid := range inProgressSends {
+		file, err := w.ev.GetFile(fid)
 		if err != nil {
-			return nil, err
+			jww.ERROR.Printf("[FT] Failed to get in-progress file upload "+
+				"%s from event model; dropping upload %d/%d: %+v",
+				fid, i+1, len(inProgressSends), err)
+			staleUploads = append(staleUploads, fid)
+		} else {
+			uploads[fid] = file
 		}
+	}
 
-		return []channels.ExtensionMessageHandler{&w}, nil
+	// Load the uploads into file transfer manager
+	err := w.m.loadInProgressUploads(
+		uploads, staleUploads, w.uploadErrorTracker, w.uploadCompleteCB)
+	if err != nil {
+		return err
 	}
 
-	return &w, eb, nil
+	// Lookup file data each in-progress downloads
+	for i, fid := range inProgressReceives {
+		// Skip any downloads that are already handled in the uploads list
+		if _, exists := uploads[fid]; exists {
+			continue
+		}
+
+		file, err2 := w.ev.GetFile(fid)
+		if err2 != nil {
+			jww.ERROR.Printf("[FT] Failed to get in-progress file "+
+				"download %s from event model; dropping download %d/%d: %+v",
+				fid, i+1, len(inProgressReceives), err2)
+			staleDownloads = append(staleDownloads, fid)
+		} else {
+			downloads[fid] = file
+		}
+	}
+
+	// Load the downloads into file transfer manager
+	return w.m.loadInProgressDownloads(
+		downloads, staleDownloads, w.downloadCompleteCB)
 }
 
 // StartProcesses starts the sending threads. Adheres to the xxdk.Service type.
@@ -313,19 +323,33 @@ func (w *Wrapper) Send(channelID *id.ID, fileLink []byte, fileName,
 	params xxdk.CMIXParams, pings []ed25519.PublicKey) (
 	message.ID, rounds.Round, ephemeral.Id, error) {
 
-	if err := w.m.verifyFileInfo(fileName, fileType, preview); err != nil {
+	fileInfo, err := w.makeFileInfo(fileLink, fileName, fileType, preview)
+	if err != nil {
 		return message.ID{}, rounds.Round{}, ephemeral.Id{}, err
 	}
 
+	pingMap := map[channels.PingType][]ed25519.PublicKey{
+		channels.MentionPing: pings}
+
+	return w.ch.SendGeneric(channelID, channels.FileTransfer,
+		fileInfo, validUntil, true, params.CMIX, pingMap)
+}
+
+// makeFileInfo verifies the file details and file link and returns the JSON of
+// the FileInfo to send to the recipients.
+func (w *Wrapper) makeFileInfo(fileLink []byte, fileName, fileType string,
+	preview []byte) ([]byte, error) {
+	if err := w.m.verifyFileInfo(fileName, fileType, preview); err != nil {
+		return nil, err
+	}
+
 	var fl FileLink
 	if err := json.Unmarshal(fileLink, &fl); err != nil {
-		return message.ID{}, rounds.Round{}, ephemeral.Id{},
-			errors.Wrap(err, "error JSON unmarshalling file link")
+		return nil, errors.Wrap(err, "error JSON unmarshalling file link")
 	}
 
 	if fl.Expired() {
-		return message.ID{}, rounds.Round{}, ephemeral.Id{}, errors.Errorf(
-			"file link expired; send occured %d ago",
+		return nil, errors.Errorf("file link expired; send occured %d ago",
 			netTime.Since(fl.SentTimestamp))
 	}
 
@@ -338,15 +362,10 @@ func (w *Wrapper) Send(channelID *id.ID, fileLink []byte, fileName,
 
 	fileInfo, err := json.Marshal(fi)
 	if err != nil {
-		return message.ID{}, rounds.Round{}, ephemeral.Id{},
-			errors.Wrap(err, "error JSON marshalling file info")
+		return nil, errors.Wrap(err, "error JSON marshalling file info")
 	}
 
-	pingMap := map[channels.PingType][]ed25519.PublicKey{
-		channels.MentionPing: pings}
-
-	return w.ch.SendGeneric(channelID, channels.FileTransfer,
-		fileInfo, validUntil, true, params.CMIX, pingMap)
+	return fileInfo, nil
 }
 
 // RegisterSentProgressCallback registers the callback to the given file
@@ -634,7 +653,7 @@ func (w *Wrapper) Handle(channelID *id.ID, messageID message.ID,
 	jww.INFO.Printf("[CH] Received file transfer %s from %x on %s",
 		fi.FileID, pubKey, channelID)
 
-	return w.ev.ReceiveMessage(channelID, messageID, nickname, string(content),
+	return w.chEv.ReceiveMessage(channelID, messageID, nickname, string(content),
 		pubKey, dmToken, codeset, timestamp, lease, round, messageType,
 		channels.Delivered, hidden)
 }
@@ -457,6 +457,23 @@ func (m *eventModel) MuteUser(*id.ID, ed25519.PublicKey, bool) {
 	jww.WARN.Printf("MuteUser is unimplemented in the CLI event model!")
 }
 
+func (m *eventModel) EditMessage(
+	message.ID, message.ID, string, time.Time) (uint64, error) {
+	jww.WARN.Printf("EditMessage is unimplemented in the CLI event model!")
+	return 0, nil
+}
+
+func (m *eventModel) ReceiveVote(message.ID, message.ID, ed25519.PublicKey,
+	uint32, time.Time) (uint64, error) {
+	jww.WARN.Printf("ReceiveVote is unimplemented in the CLI event model!")
+	return 0, nil
+}
+
+func (m *eventModel) ClosePoll(message.ID, time.Time) (uint64, error) {
+	jww.WARN.Printf("ClosePoll is unimplemented in the CLI event model!")
+	return 0, nil
+}
+
 type channelCbs struct{}
 
 func (c *channelCbs) AdminKeysUpdate(*id.ID, bool) {}
@@ -473,6 +490,8 @@ func (c *channelCbs) ChannelUpdate(*id.ID, bool)                {}
 func (c *channelCbs) MessageReceived(int64, *id.ID, bool)       {}
 func (c *channelCbs) UserMuted(*id.ID, ed25519.PublicKey, bool) {}
 func (c *channelCbs) MessageDeleted(message.ID)                 {}
+func (c *channelCbs) MemberUpdate(*id.ID, channels.Member)      {}
+func (c *channelCbs) ReadMarkerUpdate(*id.ID, time.Time)        {}
 
 func init() {
 	channelsCmd.Flags().String(channelsNameFlag, "ChannelName",
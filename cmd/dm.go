@@ -116,6 +116,10 @@ var dmCmd = &cobra.Command{
 		if err != nil {
 			jww.FATAL.Panicf("%+v", err)
 		}
+		err = user.AddService(dmClient.StartProcesses)
+		if err != nil {
+			jww.FATAL.Panicf("%+v", err)
+		}
 
 		err = user.StartNetworkFollower(5 * time.Second)
 		if err != nil {
@@ -0,0 +1,61 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"sync"
+
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// hybridClock is a hybrid logical clock (HLC) used to timestamp every
+// [Mutate] written by this device.
+//
+// The clock follows the wall clock when it is ahead of every timestamp this
+// device has produced or observed. Otherwise, it advances by a single
+// nanosecond, which acts as the logical counter of the HLC folded into the low
+// order bits of the Unix nano timestamp. This keeps timestamps in the same
+// format as patch files written before the clock existed, while guaranteeing
+// that:
+//   - timestamps produced by the device are strictly increasing, even if its
+//     wall clock goes backwards; and
+//   - a mutation made after observing a mutation from another device is always
+//     ordered after it, even if this device's wall clock is behind.
+type hybridClock struct {
+	// last is the largest timestamp, in Unix nanoseconds, that the clock has
+	// produced or observed.
+	last int64
+	mux  sync.Mutex
+}
+
+// Now returns the next timestamp, in Unix nanoseconds, for a local mutation.
+func (hc *hybridClock) Now() int64 {
+	hc.mux.Lock()
+	defer hc.mux.Unlock()
+
+	wall := netTime.Now().UTC().UnixNano()
+	if wall > hc.last {
+		hc.last = wall
+	} else {
+		hc.last++
+	}
+
+	return hc.last
+}
+
+// Observe advances the clock to the timestamp of a mutation received from
+// another device, or loaded from storage, so that every later local mutation
+// is ordered after it.
+func (hc *hybridClock) Observe(ts int64) {
+	hc.mux.Lock()
+	defer hc.mux.Unlock()
+
+	if ts > hc.last {
+		hc.last = ts
+	}
+}
@@ -0,0 +1,43 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that hybridClock.Now returns strictly increasing timestamps.
+func TestHybridClock_Now(t *testing.T) {
+	hc := &hybridClock{}
+
+	last := hc.Now()
+	for i := 0; i < 1000; i++ {
+		ts := hc.Now()
+		require.Greater(t, ts, last)
+		last = ts
+	}
+}
+
+// Tests that after hybridClock.Observe is called with a timestamp ahead of the
+// wall clock, hybridClock.Now returns timestamps after it.
+func TestHybridClock_Observe(t *testing.T) {
+	hc := &hybridClock{}
+
+	remote := netTime.Now().Add(time.Hour).UnixNano()
+	hc.Observe(remote)
+	require.Equal(t, remote+1, hc.Now())
+	require.Equal(t, remote+2, hc.Now())
+
+	// Observing an older timestamp does not move the clock backwards
+	hc.Observe(remote - int64(time.Minute))
+	require.Equal(t, remote+3, hc.Now())
+}
@@ -86,6 +86,10 @@ type collector struct {
 
 	encrypt encryptor
 
+	// devices decides which devices' changes are collected and distributes
+	// the shared keys. It is nil if devices are not managed.
+	devices *deviceManager
+
 	//tracks connection state
 	connected *uint32
 	*notifier
@@ -223,6 +227,10 @@ func (c *collector) collect() error {
 		return err
 	}
 
+	if c.devices != nil {
+		devices = c.devices.update(devices)
+	}
+
 	jww.DEBUG.Printf("[%s] initDevices: %v", collectorLogHeader,
 		devices)
 	newUpdates, err := c.collectAllChanges(devices)
@@ -305,12 +313,35 @@ func (c *collector) collectAllChanges(devices []InstanceID) (
 	return newUpdates, nil
 }
 
-// collectChanges will collate all changes across all devices.
+// collectChanges will collate all changes across all devices. Only the
+// segments of the device's transaction log written since the last read are
+// downloaded. For devices that do not write segments, the full patch is
+// downloaded only if it was modified since the last read. Returns when the
+// snapshot, segment, or full patch that was read was written.
 func (c *collector) collectChanges(deviceID InstanceID) (*Patch,
 	time.Time, error) {
 	kid := c.encrypt.KeyID(deviceID)
 
-	// Get the last time the device log was written on the remote
+	index, err := c.readIndex(deviceID, kid)
+	if err == nil {
+		patch, lastRead, err := c.collectSegments(
+			deviceID, kid, index, c.lastUpdateRead[deviceID])
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		jww.DEBUG.Printf("[%s] collected changes from %s: %d",
+			collectorLogHeader, deviceID, len(patch.keys))
+
+		return patch, lastRead, nil
+	}
+
+	jww.DEBUG.Printf("[%s] no index found for %s, reading full patch: %+v",
+		collectorLogHeader, deviceID, err)
+
+	// Devices that do not write segments are tracked by when their full patch
+	// was last written to the remote so that it is only read again once it
+	// changes
 	logPath := getTxLogPath(c.syncPath, kid, deviceID)
 	lastRemoteUpdate, err := c.remote.GetLastModified(logPath)
 	if err != nil {
@@ -318,34 +349,17 @@ func (c *collector) collectChanges(deviceID InstanceID) (*Patch,
 			errors.Wrapf(err, "GetLastModified(%s) ", logPath)
 	}
 
-	// determine the update timestamp we saw from the device
-	lastTrackedUpdate := c.lastUpdateRead[deviceID]
-
-	if !lastRemoteUpdate.After(lastTrackedUpdate) {
-		// FIXME: we warn here, because the
-		// very first thing that is done on
-		// start is to write a txLog, which
-		// means this condition is almost
-		// always true on the first run, and
-		// there may be updates to
-		// collect. Must speak w/ ben on
-		// preferences for how to addres.
-		jww.WARN.Printf("last remote after tracked: "+
-			"%s != %s", lastRemoteUpdate,
-			lastTrackedUpdate)
+	lastRead := c.lastUpdateRead[deviceID]
+	if tracked, exists := c.devicePatchTracker[deviceID]; exists &&
+		!lastRemoteUpdate.After(lastRead) {
+		jww.DEBUG.Printf("[%s] no changes from %s since %s",
+			collectorLogHeader, deviceID, lastRead)
+		return tracked, lastRead, nil
 	}
 
-	patchFile, err := c.remote.Read(logPath)
+	patch, err := c.readPatch(deviceID, logPath)
 	if err != nil {
-		return nil, time.Time{},
-			errors.Wrapf(err, "path: %s", logPath)
-	}
-
-	_, patch, err := handleIncomingFile(deviceID,
-		patchFile, c.encrypt)
-	if err != nil {
-		return nil, time.Time{},
-			errors.Wrapf(err, "path: %s", logPath)
+		return nil, time.Time{}, err
 	}
 
 	jww.DEBUG.Printf("[%s] collected changes from %s: %d",
@@ -368,9 +382,22 @@ func (c *collector) applyChanges() error {
 	devices, patches, ignoreBefore := prepareDiff(c.devicePatchTracker,
 		c.lastMutationRead)
 
+	// advance the clock past every mutation seen so that local writes made
+	// after applying these changes are ordered after them on all devices
+	for _, patch := range patches {
+		c.txLog.clock.Observe(patch.lastTimestamp())
+	}
+
 	//execute the diff
 	updates, lastSeen := localPatch.Diff(patches, ignoreBefore)
 
+	// drop the updates this device does not synchronize
+	for key, m := range updates {
+		if !c.txLog.policy.synchronizes(key, m.Value) {
+			delete(updates, key)
+		}
+	}
+
 	jww.INFO.Printf("[%s] Applying updates: %d",
 		collectorLogHeader, len(updates))
 
@@ -204,7 +204,7 @@ func TestCollector_ApplyChanges(t *testing.T) {
 
 	// These are generated from a previous run, they're always the same due
 	// to the entropy source
-	expectedVals := []int{30, 11, 12, 23, 24, 15}
+	expectedVals := []int{20, 11, 12, 23, 24, 15}
 	for i := 0; i < 6; i++ {
 		key := fmt.Sprintf("Key%d", i)
 		val, err := remoteKv.remote.GetBytes(key)
@@ -12,6 +12,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/pkg/errors"
 	"gitlab.com/elixxir/crypto/fastRNG"
@@ -29,16 +30,56 @@ type encryptor interface {
 type deviceCrypto struct {
 	secret []byte
 	rngGen *fastRNG.StreamGenerator
+
+	// key is the shared key generated when a device was last revoked. Data is
+	// encrypted with the secret until a device is revoked.
+	key []byte
+
+	// previous are the keys that were used before the current key, oldest
+	// first. They are used to decrypt data written before a rotation.
+	previous [][]byte
+	mux      sync.RWMutex
 }
 
 func (dc *deviceCrypto) Encrypt(data []byte) []byte {
 	stream := dc.rngGen.GetStream()
 	defer stream.Close()
+
+	dc.mux.RLock()
+	defer dc.mux.RUnlock()
+	if dc.key != nil {
+		return encrypt(data, dc.key, stream)
+	}
 	return encrypt(data, dc.secret, stream)
 }
 
+// Decrypt decrypts the data with the current key, falling back to each
+// previous key, newest first, and then the secret.
 func (dc *deviceCrypto) Decrypt(data []byte) ([]byte, error) {
-	return decrypt(data, dc.secret)
+	dc.mux.RLock()
+	defer dc.mux.RUnlock()
+	if dc.key == nil {
+		return decrypt(data, dc.secret)
+	}
+
+	plaintext, err := decrypt(data, dc.key)
+	for i := len(dc.previous) - 1; err != nil && i >= 0; i-- {
+		plaintext, err = decrypt(data, dc.previous[i])
+	}
+	if err != nil {
+		plaintext, err = decrypt(data, dc.secret)
+	}
+	return plaintext, err
+}
+
+// setKeys sets the key used to encrypt data and the previous keys used to
+// decrypt older data. The secret is still used to build key IDs so that the
+// paths of the transaction logs do not change.
+func (dc *deviceCrypto) setKeys(key []byte, previous [][]byte) {
+	dc.mux.Lock()
+	defer dc.mux.Unlock()
+	dc.key = key
+	dc.previous = previous
 }
 
 func (dc *deviceCrypto) KeyID(deviceID InstanceID) string {
@@ -0,0 +1,911 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/ekv"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Each device synchronizing an account publishes a record containing its
+// public key next to its transaction log. Until a device is revoked, every
+// device encrypts its transaction log with the device secret.
+//
+// Revoking a device generates a new random key, which is sealed to the public
+// key of every remaining member and written to the key file of the revoking
+// device. Members pick up the new key when collecting and re-upload their
+// transaction logs encrypted with it, so the revoked device cannot read any
+// mutations made after it was revoked. Changes from revoked devices are no
+// longer collected.
+//
+// Devices that join after a device was revoked only know the device secret.
+// They are pending, and their changes are not collected, until a member
+// approves them, which shares the current key with them.
+//
+// Revocation does not change the device secret. A revoked device that still
+// knows it can join again with a new instance ID, but it remains pending
+// unless it is approved.
+
+// Device management constants.
+const (
+	// deviceRecordPathFmt is the path of a device's record, relative to the
+	// sync path: [deviceid]/[keyid]/device.xx
+	deviceRecordPathFmt = "%s/%s/device.xx"
+
+	// deviceKeysPathFmt is the path of the key file a device writes when it
+	// revokes or approves a device, relative to the sync path:
+	// [deviceid]/[keyid]/keys.xx
+	deviceKeysPathFmt = "%s/%s/keys.xx"
+
+	toDiskDeviceKeysKeyName    = "DeviceKeys_"
+	toDiskDeviceKeyPairKeyName = "DeviceKeyPair_"
+	toDiskPublicKeysKeyName    = "DevicePublicKeys_"
+
+	// deviceNamesMapName is the synchronized map that device names are stored
+	// in, keyed on the instance ID.
+	deviceNamesMapName = "collectiveDeviceNames"
+
+	// deviceKeySize is the size of the shared keys and the public and private
+	// keys of a device.
+	deviceKeySize = 32
+
+	// sealNonceSize is the size of the nonce prepended to a sealed key file
+	// entry.
+	sealNonceSize = 24
+)
+
+// Error messages.
+const (
+	// versionedKV.devices
+	noDeviceManagerErr = "device management requires a synchronized KV"
+
+	// newDeviceManager
+	loadKeyPairErr      = "failed to load device key pair: %+v"
+	generateKeyPairErr  = "failed to generate device key pair: %+v"
+	saveKeyPairErr      = "failed to save device key pair: %+v"
+	unmarshalKeysErr    = "failed to unmarshal device keys: %+v"
+	unmarshalPinnedErr  = "failed to unmarshal device public keys: %+v"
+	saveDeviceKeysErr   = "failed to save device keys: %+v"
+	saveDevicePinnedErr = "failed to save device public keys: %+v"
+
+	// deviceManager.revoke
+	revokeSelfErr    = "cannot revoke this device"
+	deviceRevokedErr = "device %s is revoked"
+	generateKeyErr   = "failed to generate shared key: %+v"
+
+	// deviceManager.getPublicKey
+	missingRecordErr = "device %s has not published a record"
+	decodeRecordErr  = "failed to decode record of device %s: %+v"
+	recordKeySizeErr = "record of device %s has a public key of %d bytes"
+
+	// deviceManager.applyKeyUpdate
+	decodeKeysFileErr = "failed to decode key file of device %s: %+v"
+	openKeysErr       = "failed to open keys sealed by device %s"
+)
+
+// DeviceStatus describes whether a device's changes are synchronized.
+type DeviceStatus uint8
+
+const (
+	// DeviceActive is a device whose changes are synchronized.
+	DeviceActive DeviceStatus = iota
+
+	// DevicePending is a device that joined after a device was revoked and has
+	// not been approved.
+	DevicePending
+
+	// DeviceRevoked is a device that was revoked.
+	DeviceRevoked
+)
+
+// String returns a human-readable name of the DeviceStatus.
+func (s DeviceStatus) String() string {
+	switch s {
+	case DeviceActive:
+		return "active"
+	case DevicePending:
+		return "pending"
+	case DeviceRevoked:
+		return "revoked"
+	default:
+		return fmt.Sprintf("INVALID STATUS: %d", s)
+	}
+}
+
+// DeviceInfo describes a device synchronizing the account.
+type DeviceInfo struct {
+	ID InstanceID
+
+	// Name is the name given to the device with
+	// [DeviceManager.SetDeviceName]. It is empty if the device has not been
+	// named.
+	Name string
+
+	// LastWrite is when the device last wrote its transaction log to the
+	// remote. It is zero if it is unknown.
+	LastWrite time.Time
+
+	Status DeviceStatus
+
+	// Local is true for this device.
+	Local bool
+}
+
+// DeviceManager manages the devices synchronizing an account. It is
+// implemented by the KV returned by [SynchronizedKV] and
+// [CloneFromRemoteStorage].
+type DeviceManager interface {
+	// ListDevices returns every device that has written to the remote, sorted
+	// by ID.
+	ListDevices() ([]DeviceInfo, error)
+
+	// SetDeviceName sets the human-readable name of the device. Names are
+	// synchronized to all devices.
+	SetDeviceName(deviceID InstanceID, name string) error
+
+	// RevokeDevice stops synchronizing changes from the device and rotates the
+	// shared key so that it cannot read changes made after it is revoked.
+	RevokeDevice(deviceID InstanceID) error
+
+	// ApproveDevice shares the current key with a pending device so that it
+	// can synchronize.
+	ApproveDevice(deviceID InstanceID) error
+}
+
+// deviceRecord is published by every device next to its transaction log.
+type deviceRecord struct {
+	PublicKey []byte `json:"publicKey"`
+}
+
+// keyFile is written by a device that revokes or approves a device. It
+// contains the deviceKeys sealed to each member.
+type keyFile struct {
+	Epoch uint32                `json:"epoch"`
+	Keys  map[InstanceID][]byte `json:"keys"`
+}
+
+// deviceKeys are the shared keys and the membership of the account.
+type deviceKeys struct {
+	// Epoch is incremented every time the key is rotated. It is zero until a
+	// device is revoked.
+	Epoch uint32 `json:"epoch"`
+
+	// Sender generated the current key. It breaks ties between keys generated
+	// concurrently for the same epoch.
+	Sender InstanceID `json:"sender"`
+
+	// Key is the current shared key and Previous are every key used before
+	// it.
+	Key      []byte   `json:"key"`
+	Previous [][]byte `json:"previous"`
+
+	// Members have been given the current key. Every device is a member while
+	// the epoch is zero.
+	Members []InstanceID `json:"members"`
+	Revoked []InstanceID `json:"revoked"`
+}
+
+// isMember returns true if the device has been given the current key.
+func (dk *deviceKeys) isMember(deviceID InstanceID) bool {
+	return dk.Epoch == 0 || containsDevice(dk.Members, deviceID)
+}
+
+// isRevoked returns true if the device has been revoked.
+func (dk *deviceKeys) isRevoked(deviceID InstanceID) bool {
+	return containsDevice(dk.Revoked, deviceID)
+}
+
+// revoke marks the device as revoked and removes it from the members.
+func (dk *deviceKeys) revoke(deviceID InstanceID) {
+	if !dk.isRevoked(deviceID) {
+		dk.Revoked = append(dk.Revoked, deviceID)
+	}
+
+	members := dk.Members[:0]
+	for _, member := range dk.Members {
+		if member != deviceID {
+			members = append(members, member)
+		}
+	}
+	dk.Members = members
+}
+
+// merge merges the deviceKeys received from another device. The other key is
+// adopted if it is for a later epoch or if it was generated concurrently by a
+// device with a greater ID. Returns true if the current key changed, and true
+// if the resulting key was given to a revoked device, in which case it must be
+// rotated again.
+func (dk *deviceKeys) merge(other *deviceKeys) (adopted, rotate bool) {
+	for _, deviceID := range other.Revoked {
+		dk.revoke(deviceID)
+	}
+
+	sameKey := bytes.Equal(dk.Key, other.Key)
+	if other.Epoch > dk.Epoch || (other.Epoch == dk.Epoch && !sameKey &&
+		other.Sender.Cmp(dk.Sender) == 1) {
+		dk.Previous = addKeys(dk.Previous, dk.Key)
+		dk.Previous = addKeys(dk.Previous, other.Previous...)
+		dk.Epoch, dk.Sender, dk.Key = other.Epoch, other.Sender, other.Key
+		dk.Members = nil
+		adopted, sameKey = true, true
+	}
+
+	if !sameKey {
+		return false, false
+	}
+
+	for _, deviceID := range other.Members {
+		if dk.isRevoked(deviceID) {
+			rotate = true
+		} else if !containsDevice(dk.Members, deviceID) {
+			dk.Members = append(dk.Members, deviceID)
+		}
+	}
+
+	return adopted, rotate
+}
+
+// clone returns a deep copy of the deviceKeys.
+func (dk *deviceKeys) clone() *deviceKeys {
+	return &deviceKeys{
+		Epoch:    dk.Epoch,
+		Sender:   dk.Sender,
+		Key:      dk.Key,
+		Previous: append([][]byte{}, dk.Previous...),
+		Members:  append([]InstanceID{}, dk.Members...),
+		Revoked:  append([]InstanceID{}, dk.Revoked...),
+	}
+}
+
+// deviceManager publishes this device's record, distributes and receives
+// shared keys, and decides which devices' changes are collected.
+type deviceManager struct {
+	myID     InstanceID
+	syncPath string
+
+	remote RemoteStore
+	kv     ekv.KeyValue
+	crypt  *deviceCrypto
+	rngGen *fastRNG.StreamGenerator
+
+	// this device's key pair, used to seal and open the shared keys
+	publicKey, privateKey *[deviceKeySize]byte
+
+	keys *deviceKeys
+
+	// pinned are the public keys of other devices, saved the first time they
+	// are read so that they cannot be replaced on the remote
+	pinned map[InstanceID][]byte
+
+	// published is set once this device's record has been written
+	published bool
+
+	// onRekey is called when the shared key changes, so that the transaction
+	// log is re-uploaded encrypted with the new key
+	onRekey func()
+
+	mux sync.Mutex
+}
+
+// newDeviceManager loads the device key pair, the shared keys, and the pinned
+// public keys from local storage, generating the key pair if this is a new
+// device. The loaded keys are set on the deviceCrypto.
+func newDeviceManager(myID InstanceID, syncPath string, remote RemoteStore,
+	crypt *deviceCrypto, kv ekv.KeyValue) (*deviceManager, error) {
+	dm := &deviceManager{
+		myID:     myID,
+		syncPath: syncPath,
+		remote:   remote,
+		kv:       kv,
+		crypt:    crypt,
+		rngGen:   crypt.rngGen,
+		keys:     &deviceKeys{},
+		pinned:   make(map[InstanceID][]byte),
+	}
+
+	if err := dm.loadKeyPair(); err != nil {
+		return nil, err
+	}
+
+	data, err := kv.GetBytes(makeDeviceKeysKey(syncPath))
+	if err == nil {
+		if err = json.Unmarshal(data, dm.keys); err != nil {
+			return nil, errors.Errorf(unmarshalKeysErr, err)
+		}
+	} else if ekv.Exists(err) {
+		return nil, err
+	}
+	crypt.setKeys(dm.keys.Key, dm.keys.Previous)
+
+	data, err = kv.GetBytes(makePublicKeysKey(syncPath))
+	if err == nil {
+		if err = json.Unmarshal(data, &dm.pinned); err != nil {
+			return nil, errors.Errorf(unmarshalPinnedErr, err)
+		}
+	} else if ekv.Exists(err) {
+		return nil, err
+	}
+
+	return dm, nil
+}
+
+// loadKeyPair loads this device's key pair from local storage or generates
+// and saves a new one.
+func (dm *deviceManager) loadKeyPair() error {
+	storageKey := makeDeviceKeyPairKey(dm.syncPath)
+	data, err := dm.kv.GetBytes(storageKey)
+	if err == nil && len(data) == 2*deviceKeySize {
+		dm.publicKey, dm.privateKey =
+			new([deviceKeySize]byte), new([deviceKeySize]byte)
+		copy(dm.publicKey[:], data[:deviceKeySize])
+		copy(dm.privateKey[:], data[deviceKeySize:])
+		return nil
+	} else if err != nil && ekv.Exists(err) {
+		return errors.Errorf(loadKeyPairErr, err)
+	}
+
+	stream := dm.rngGen.GetStream()
+	defer stream.Close()
+	dm.publicKey, dm.privateKey, err = box.GenerateKey(stream)
+	if err != nil {
+		return errors.Errorf(generateKeyPairErr, err)
+	}
+
+	data = append(dm.publicKey[:], dm.privateKey[:]...)
+	if err = dm.kv.SetBytes(storageKey, data); err != nil {
+		return errors.Errorf(saveKeyPairErr, err)
+	}
+
+	return nil
+}
+
+// update publishes this device's record if it has not been yet and applies the
+// keys shared by the other devices. Returns the devices whose changes are
+// collected, which excludes revoked and pending devices. Errors are logged so
+// that one device cannot stop collection from the others.
+func (dm *deviceManager) update(devices []InstanceID) []InstanceID {
+	dm.mux.Lock()
+	defer dm.mux.Unlock()
+
+	if !dm.published {
+		if err := dm.publishRecord(); err != nil {
+			jww.WARN.Printf("[%s] Failed to publish device record: %+v",
+				collectorLogHeader, err)
+		} else {
+			dm.published = true
+		}
+	}
+
+	devices = uniqueDevices(devices)
+	rotate := false
+	for _, deviceID := range devices {
+		if deviceID == dm.myID || dm.keys.isRevoked(deviceID) {
+			continue
+		}
+
+		// Pin the public key the first time the device is seen
+		if _, exists := dm.pinned[deviceID]; !exists {
+			if _, err := dm.getPublicKey(deviceID); err != nil {
+				jww.DEBUG.Printf("[%s] No record for device %s: %+v",
+					collectorLogHeader, deviceID, err)
+			}
+		}
+
+		r, err := dm.applyKeyUpdate(deviceID)
+		if err != nil {
+			jww.WARN.Printf("[%s] Failed to apply keys from device %s: %+v",
+				collectorLogHeader, deviceID, err)
+		}
+		rotate = rotate || r
+	}
+
+	if rotate {
+		jww.INFO.Printf("[%s] The current key was shared with a revoked "+
+			"device, rotating it", collectorLogHeader)
+		if err := dm.rotate(devices); err != nil {
+			jww.ERROR.Printf("[%s] Failed to rotate the shared key: %+v",
+				collectorLogHeader, err)
+		}
+	}
+
+	collect := make([]InstanceID, 0, len(devices))
+	for _, deviceID := range devices {
+		if deviceID == dm.myID ||
+			(!dm.keys.isRevoked(deviceID) && dm.keys.isMember(deviceID)) {
+			collect = append(collect, deviceID)
+		}
+	}
+
+	return collect
+}
+
+// applyKeyUpdate reads the key file of the device and merges the keys sealed
+// to this device, if any. Returns true if the key must be rotated.
+func (dm *deviceManager) applyKeyUpdate(deviceID InstanceID) (bool, error) {
+	data, err := dm.remote.Read(dm.keysPath(deviceID))
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	_, body, err := decodeFile(data)
+	if err != nil {
+		return false, errors.Errorf(decodeKeysFileErr, deviceID, err)
+	}
+	kf := &keyFile{}
+	if err = json.Unmarshal(body, kf); err != nil {
+		return false, errors.Errorf(decodeKeysFileErr, deviceID, err)
+	}
+
+	sealed, exists := kf.Keys[dm.myID]
+	if !exists || kf.Epoch < dm.keys.Epoch {
+		return false, nil
+	}
+
+	senderKey, err := dm.getPublicKey(deviceID)
+	if err != nil {
+		return false, err
+	}
+	payload, ok := openSealed(sealed, senderKey, dm.privateKey)
+	if !ok {
+		return false, errors.Errorf(openKeysErr, deviceID)
+	}
+	received := &deviceKeys{}
+	if err = json.Unmarshal(payload, received); err != nil {
+		return false, errors.Errorf(unmarshalKeysErr, err)
+	}
+
+	keys := dm.keys.clone()
+	adopted, rotate := keys.merge(received)
+	if err = dm.setKeys(keys); err != nil {
+		return false, err
+	}
+	if adopted {
+		jww.INFO.Printf("[%s] Received key for epoch %d from device %s",
+			collectorLogHeader, keys.Epoch, deviceID)
+		dm.rekeyed()
+	}
+
+	return rotate, nil
+}
+
+// revoke revokes the device and rotates the shared key.
+func (dm *deviceManager) revoke(deviceID InstanceID) error {
+	if deviceID == dm.myID {
+		return errors.New(revokeSelfErr)
+	}
+
+	devices, err := getDevices(dm.remote, dm.syncPath)
+	if err != nil {
+		return err
+	}
+
+	dm.mux.Lock()
+	defer dm.mux.Unlock()
+	return dm.rotate(devices, deviceID)
+}
+
+// approve shares the current key with the device.
+func (dm *deviceManager) approve(deviceID InstanceID) error {
+	dm.mux.Lock()
+	defer dm.mux.Unlock()
+
+	if dm.keys.isRevoked(deviceID) {
+		return errors.Errorf(deviceRevokedErr, deviceID)
+	} else if dm.keys.isMember(deviceID) {
+		return nil
+	}
+
+	if _, err := dm.getPublicKey(deviceID); err != nil {
+		return err
+	}
+
+	keys := dm.keys.clone()
+	keys.Members = append(keys.Members, deviceID)
+	if err := dm.publishKeys(keys); err != nil {
+		return err
+	}
+
+	return dm.setKeys(keys)
+}
+
+// rotate generates a new shared key and shares it with every member that is
+// not revoked. The devices are revoked first. Members that have not published
+// a record cannot be given the key and are left pending.
+func (dm *deviceManager) rotate(devices []InstanceID,
+	revoked ...InstanceID) error {
+	keys := dm.keys.clone()
+	for _, deviceID := range revoked {
+		keys.revoke(deviceID)
+	}
+
+	key := make([]byte, deviceKeySize)
+	stream := dm.rngGen.GetStream()
+	_, err := io.ReadFull(stream, key)
+	stream.Close()
+	if err != nil {
+		return errors.Errorf(generateKeyErr, err)
+	}
+
+	keys.Previous = addKeys(keys.Previous, keys.Key)
+	keys.Epoch++
+	keys.Sender = dm.myID
+	keys.Key = key
+	keys.Members = []InstanceID{dm.myID}
+	for _, deviceID := range uniqueDevices(devices) {
+		if deviceID == dm.myID || keys.isRevoked(deviceID) ||
+			!dm.keys.isMember(deviceID) {
+			continue
+		}
+		if _, err = dm.getPublicKey(deviceID); err != nil {
+			jww.WARN.Printf("[%s] Cannot share key with device %s, it will "+
+				"be pending until approved: %+v",
+				collectorLogHeader, deviceID, err)
+			continue
+		}
+		keys.Members = append(keys.Members, deviceID)
+	}
+
+	if err = dm.publishKeys(keys); err != nil {
+		return err
+	}
+	if err = dm.setKeys(keys); err != nil {
+		return err
+	}
+
+	jww.INFO.Printf("[%s] Rotated shared key to epoch %d for %d devices",
+		collectorLogHeader, keys.Epoch, len(keys.Members))
+	dm.rekeyed()
+	return nil
+}
+
+// publishKeys seals the keys to every member and writes them to this device's
+// key file.
+func (dm *deviceManager) publishKeys(keys *deviceKeys) error {
+	payload, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	stream := dm.rngGen.GetStream()
+	defer stream.Close()
+
+	kf := &keyFile{
+		Epoch: keys.Epoch,
+		Keys:  make(map[InstanceID][]byte, len(keys.Members)),
+	}
+	for _, deviceID := range keys.Members {
+		if deviceID == dm.myID {
+			continue
+		}
+		recipientKey, err := dm.getPublicKey(deviceID)
+		if err != nil {
+			return err
+		}
+		kf.Keys[deviceID] = seal(payload, recipientKey, dm.privateKey, stream)
+	}
+
+	data, err := json.Marshal(kf)
+	if err != nil {
+		return err
+	}
+
+	return dm.remote.Write(
+		dm.keysPath(dm.myID), buildFile(newHeader(dm.myID), data))
+}
+
+// publishRecord writes this device's record to the remote.
+func (dm *deviceManager) publishRecord() error {
+	data, err := json.Marshal(&deviceRecord{PublicKey: dm.publicKey[:]})
+	if err != nil {
+		return err
+	}
+
+	return dm.remote.Write(getDeviceRecordPath(
+		dm.syncPath, dm.crypt.KeyID(dm.myID), dm.myID),
+		buildFile(newHeader(dm.myID), data))
+}
+
+// getPublicKey returns the public key of the device. The key is read from the
+// device's record and pinned the first time it is requested.
+func (dm *deviceManager) getPublicKey(deviceID InstanceID) (
+	*[deviceKeySize]byte, error) {
+	if deviceID == dm.myID {
+		return dm.publicKey, nil
+	}
+
+	key, exists := dm.pinned[deviceID]
+	if !exists {
+		data, err := dm.remote.Read(getDeviceRecordPath(
+			dm.syncPath, dm.crypt.KeyID(deviceID), deviceID))
+		if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+			return nil, errors.Errorf(missingRecordErr, deviceID)
+		} else if err != nil {
+			return nil, err
+		}
+
+		_, body, err := decodeFile(data)
+		if err != nil {
+			return nil, errors.Errorf(decodeRecordErr, deviceID, err)
+		}
+		record := &deviceRecord{}
+		if err = json.Unmarshal(body, record); err != nil {
+			return nil, errors.Errorf(decodeRecordErr, deviceID, err)
+		}
+		if len(record.PublicKey) != deviceKeySize {
+			return nil, errors.Errorf(
+				recordKeySizeErr, deviceID, len(record.PublicKey))
+		}
+
+		key = record.PublicKey
+		dm.pinned[deviceID] = key
+		if err = dm.savePinned(); err != nil {
+			jww.WARN.Printf("[%s] %+v", collectorLogHeader, err)
+		}
+	}
+
+	publicKey := new([deviceKeySize]byte)
+	copy(publicKey[:], key)
+	return publicKey, nil
+}
+
+// list returns the status and last write time of every device that has
+// written to the remote, sorted by ID.
+func (dm *deviceManager) list() ([]DeviceInfo, error) {
+	devices, err := getDevices(dm.remote, dm.syncPath)
+	if err != nil {
+		return nil, err
+	}
+	devices = uniqueDevices(devices)
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Cmp(devices[j]) == -1
+	})
+
+	dm.mux.Lock()
+	keys := dm.keys.clone()
+	dm.mux.Unlock()
+
+	infos := make([]DeviceInfo, len(devices))
+	for i, deviceID := range devices {
+		infos[i] = DeviceInfo{
+			ID:        deviceID,
+			LastWrite: dm.lastWrite(deviceID),
+			Status:    DeviceActive,
+			Local:     deviceID == dm.myID,
+		}
+		if keys.isRevoked(deviceID) {
+			infos[i].Status = DeviceRevoked
+		} else if !keys.isMember(deviceID) {
+			infos[i].Status = DevicePending
+		}
+	}
+
+	return infos, nil
+}
+
+// lastWrite returns when the device last wrote its index or snapshot. Returns
+// zero if neither can be found.
+func (dm *deviceManager) lastWrite(deviceID InstanceID) time.Time {
+	keyID := dm.crypt.KeyID(deviceID)
+	var last time.Time
+	for _, path := range []string{
+		getTxLogIndexPath(dm.syncPath, keyID, deviceID),
+		getTxLogPath(dm.syncPath, keyID, deviceID),
+	} {
+		if t, err := dm.remote.GetLastModified(path); err == nil &&
+			t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+// setKeys sets and saves the shared keys.
+func (dm *deviceManager) setKeys(keys *deviceKeys) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	if err = dm.kv.SetBytes(makeDeviceKeysKey(dm.syncPath), data); err != nil {
+		return errors.Errorf(saveDeviceKeysErr, err)
+	}
+
+	dm.keys = keys
+	dm.crypt.setKeys(keys.Key, keys.Previous)
+	return nil
+}
+
+// savePinned saves the pinned public keys.
+func (dm *deviceManager) savePinned() error {
+	data, err := json.Marshal(dm.pinned)
+	if err != nil {
+		return err
+	}
+	if err = dm.kv.SetBytes(makePublicKeysKey(dm.syncPath), data); err != nil {
+		return errors.Errorf(saveDevicePinnedErr, err)
+	}
+	return nil
+}
+
+// rekeyed calls onRekey, if it is set.
+func (dm *deviceManager) rekeyed() {
+	if dm.onRekey != nil {
+		dm.onRekey()
+	}
+}
+
+// keysPath returns the path of the device's key file.
+func (dm *deviceManager) keysPath(deviceID InstanceID) string {
+	return getDeviceKeysPath(
+		dm.syncPath, dm.crypt.KeyID(deviceID), deviceID)
+}
+
+// seal encrypts and authenticates the data for the recipient. The nonce is
+// prepended to the result.
+func seal(data []byte, recipientKey, privateKey *[deviceKeySize]byte,
+	rng io.Reader) []byte {
+	var nonce [sealNonceSize]byte
+	if _, err := io.ReadFull(rng, nonce[:]); err != nil {
+		jww.FATAL.Panicf("Could not generate nonce: %+v", err)
+	}
+	return box.Seal(nonce[:], data, &nonce, recipientKey, privateKey)
+}
+
+// openSealed opens data sealed by the sender with seal. Returns false if it
+// cannot be authenticated.
+func openSealed(sealed []byte, senderKey, privateKey *[deviceKeySize]byte) (
+	[]byte, bool) {
+	if len(sealed) < sealNonceSize {
+		return nil, false
+	}
+	var nonce [sealNonceSize]byte
+	copy(nonce[:], sealed)
+	return box.Open(nil, sealed[sealNonceSize:], &nonce, senderKey, privateKey)
+}
+
+// addKeys appends each key that is not nil and not already in the list.
+func addKeys(keys [][]byte, add ...[]byte) [][]byte {
+	for _, key := range add {
+		if key == nil {
+			continue
+		}
+		exists := false
+		for _, k := range keys {
+			if bytes.Equal(k, key) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// containsDevice returns true if the device is in the list.
+func containsDevice(devices []InstanceID, deviceID InstanceID) bool {
+	for _, d := range devices {
+		if d == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueDevices returns the devices with duplicates removed.
+func uniqueDevices(devices []InstanceID) []InstanceID {
+	unique := make([]InstanceID, 0, len(devices))
+	for _, deviceID := range devices {
+		if !containsDevice(unique, deviceID) {
+			unique = append(unique, deviceID)
+		}
+	}
+	return unique
+}
+
+func makeDeviceKeysKey(path string) string {
+	return toDiskDeviceKeysKeyName + path
+}
+
+func makeDeviceKeyPairKey(path string) string {
+	return toDiskDeviceKeyPairKeyName + path
+}
+
+func makePublicKeysKey(path string) string {
+	return toDiskPublicKeysKeyName + path
+}
+
+func getDeviceRecordPath(syncPath, keyID string, deviceID InstanceID) string {
+	return filepath.Join(syncPath,
+		fmt.Sprintf(deviceRecordPathFmt, deviceID, keyID))
+}
+
+func getDeviceKeysPath(syncPath, keyID string, deviceID InstanceID) string {
+	return filepath.Join(syncPath,
+		fmt.Sprintf(deviceKeysPathFmt, deviceID, keyID))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DeviceManager implementation on the versionedKV
+///////////////////////////////////////////////////////////////////////////////
+
+// ListDevices implements [DeviceManager.ListDevices].
+func (r *versionedKV) ListDevices() ([]DeviceInfo, error) {
+	dm, err := r.devices()
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := dm.list()
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := r.remote.GetMap(deviceNamesMapName)
+	if err != nil {
+		jww.WARN.Printf("[%s] Failed to load device names: %+v",
+			collectorLogHeader, err)
+		return infos, nil
+	}
+	for i := range infos {
+		infos[i].Name = string(names[infos[i].ID.String()])
+	}
+
+	return infos, nil
+}
+
+// SetDeviceName implements [DeviceManager.SetDeviceName].
+func (r *versionedKV) SetDeviceName(deviceID InstanceID, name string) error {
+	if _, err := r.devices(); err != nil {
+		return err
+	}
+	return r.remote.StoreMapElement(
+		deviceNamesMapName, deviceID.String(), []byte(name))
+}
+
+// RevokeDevice implements [DeviceManager.RevokeDevice].
+func (r *versionedKV) RevokeDevice(deviceID InstanceID) error {
+	dm, err := r.devices()
+	if err != nil {
+		return err
+	}
+	return dm.revoke(deviceID)
+}
+
+// ApproveDevice implements [DeviceManager.ApproveDevice].
+func (r *versionedKV) ApproveDevice(deviceID InstanceID) error {
+	dm, err := r.devices()
+	if err != nil {
+		return err
+	}
+	return dm.approve(deviceID)
+}
+
+// devices returns the deviceManager of the KV. Returns an error if the KV is
+// not synchronized.
+func (r *versionedKV) devices() (*deviceManager, error) {
+	if r.remote.col == nil || r.remote.col.devices == nil {
+		return nil, errors.New(noDeviceManagerErr)
+	}
+	return r.remote.col.devices, nil
+}
@@ -0,0 +1,193 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+)
+
+// Tests that after a device is revoked, the other devices receive the new key
+// and no longer collect from the revoked device, and that the revoked device
+// cannot decrypt data encrypted with the new key.
+func TestDeviceManager_revoke(t *testing.T) {
+	remote := NewMockRemote()
+	syncPath := "devices"
+	a := newTestDeviceManager(t, remote, syncPath)
+	b := newTestDeviceManager(t, remote, syncPath)
+	c := newTestDeviceManager(t, remote, syncPath)
+	all := []InstanceID{a.myID, b.myID, c.myID}
+	for _, dm := range []*deviceManager{a, b, c} {
+		require.ElementsMatch(t, all, dm.update(all))
+	}
+
+	before := a.crypt.Encrypt([]byte("before"))
+
+	rekeyed := 0
+	b.onRekey = func() { rekeyed++ }
+	require.Error(t, a.revoke(a.myID))
+	require.NoError(t, a.revoke(c.myID))
+	require.Equal(t, uint32(1), a.keys.Epoch)
+
+	require.ElementsMatch(t, []InstanceID{a.myID, b.myID}, b.update(all))
+	require.Equal(t, 1, rekeyed)
+	require.Equal(t, a.keys.Key, b.keys.Key)
+	require.ElementsMatch(t, []InstanceID{a.myID, b.myID}, a.update(all))
+
+	// The revoked device is not given the key
+	c.update(all)
+	require.Nil(t, c.keys.Key)
+
+	after := a.crypt.Encrypt([]byte("after"))
+	decrypted, err := b.crypt.Decrypt(after)
+	require.NoError(t, err)
+	require.Equal(t, []byte("after"), decrypted)
+	_, err = c.crypt.Decrypt(after)
+	require.Error(t, err)
+
+	// Data written before the rotation can still be read
+	decrypted, err = b.crypt.Decrypt(before)
+	require.NoError(t, err)
+	require.Equal(t, []byte("before"), decrypted)
+
+	// The keys are loaded from disk
+	loaded, err := newDeviceManager(
+		b.myID, syncPath, remote, &deviceCrypto{rngGen: b.rngGen}, b.kv)
+	require.NoError(t, err)
+	require.Equal(t, b.keys, loaded.keys)
+	require.Equal(t, b.publicKey, loaded.publicKey)
+	require.Equal(t, b.pinned, loaded.pinned)
+	require.Equal(t, b.keys.Key, loaded.crypt.key)
+}
+
+// Tests that a device that joins after a device was revoked is pending until
+// it is approved.
+func TestDeviceManager_approve(t *testing.T) {
+	remote := NewMockRemote()
+	syncPath := "devices"
+	a := newTestDeviceManager(t, remote, syncPath)
+	b := newTestDeviceManager(t, remote, syncPath)
+	all := []InstanceID{a.myID, b.myID}
+	a.update(all)
+	b.update(all)
+	require.NoError(t, a.revoke(b.myID))
+
+	c := newTestDeviceManager(t, remote, syncPath)
+	all = append(all, c.myID)
+	require.ElementsMatch(t, all, c.update(all))
+	require.Equal(t, []InstanceID{a.myID}, a.update(all))
+
+	infos, err := a.list()
+	require.NoError(t, err)
+	statuses := make(map[InstanceID]DeviceStatus, len(infos))
+	for _, info := range infos {
+		statuses[info.ID] = info.Status
+		require.Equal(t, info.ID == a.myID, info.Local)
+	}
+	require.Equal(t, map[InstanceID]DeviceStatus{
+		a.myID: DeviceActive,
+		b.myID: DeviceRevoked,
+		c.myID: DevicePending,
+	}, statuses)
+
+	require.Error(t, a.approve(b.myID))
+	require.NoError(t, a.approve(c.myID))
+	require.ElementsMatch(t, []InstanceID{a.myID, c.myID}, a.update(all))
+	require.ElementsMatch(t, []InstanceID{a.myID, c.myID}, c.update(all))
+
+	decrypted, err := c.crypt.Decrypt(a.crypt.Encrypt([]byte("data")))
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), decrypted)
+}
+
+// Tests that two devices that concurrently revoke different devices converge
+// on a key that neither revoked device has.
+func TestDeviceManager_revoke_Concurrent(t *testing.T) {
+	remote := NewMockRemote()
+	syncPath := "devices"
+	dms := make([]*deviceManager, 4)
+	all := make([]InstanceID, len(dms))
+	for i := range dms {
+		dms[i] = newTestDeviceManager(t, remote, syncPath)
+		all[i] = dms[i].myID
+	}
+	for _, dm := range dms {
+		dm.update(all)
+	}
+
+	require.NoError(t, dms[0].revoke(dms[2].myID))
+	require.NoError(t, dms[1].revoke(dms[3].myID))
+
+	for i := 0; i < 3; i++ {
+		dms[0].update(all)
+		dms[1].update(all)
+	}
+
+	require.Equal(t, dms[0].keys.Key, dms[1].keys.Key)
+	for _, dm := range dms[:2] {
+		require.ElementsMatch(t, all[:2], dm.update(all))
+		require.ElementsMatch(t, all[2:], dm.keys.Revoked)
+	}
+
+	data := dms[0].crypt.Encrypt([]byte("data"))
+	for _, dm := range dms[2:] {
+		dm.update(all)
+		_, err := dm.crypt.Decrypt(data)
+		require.Error(t, err)
+	}
+}
+
+// Tests that versionedKV.ListDevices returns the names set with
+// versionedKV.SetDeviceName, and that a local KV does not manage devices.
+func TestVersionedKV_ListDevices(t *testing.T) {
+	rngGen := fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG)
+	kv, err := SynchronizedKV("devices", []byte("deviceSecret"),
+		NewMockRemote(), ekv.MakeMemstore(), nil, rngGen)
+	require.NoError(t, err)
+
+	myID := kv.remote.col.myID
+	kv.remote.col.devices.update([]InstanceID{myID})
+	require.NoError(t, kv.SetDeviceName(myID, "laptop"))
+
+	infos, err := kv.ListDevices()
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	require.Equal(t, myID, infos[0].ID)
+	require.Equal(t, "laptop", infos[0].Name)
+	require.Equal(t, DeviceActive, infos[0].Status)
+	require.True(t, infos[0].Local)
+
+	local, err := LocalKV([]byte("deviceSecret"), ekv.MakeMemstore(), rngGen)
+	require.NoError(t, err)
+	_, err = local.(DeviceManager).ListDevices()
+	require.Error(t, err)
+}
+
+// newTestDeviceManager creates a deviceManager for a new device.
+func newTestDeviceManager(
+	t *testing.T, remote RemoteStore, syncPath string) *deviceManager {
+	rngGen := fastRNG.NewStreamGenerator(1, 1, csprng.NewSystemRNG)
+	kv := ekv.MakeMemstore()
+
+	stream := rngGen.GetStream()
+	defer stream.Close()
+	myID, err := InitInstanceID(kv, stream)
+	require.NoError(t, err)
+
+	crypt := &deviceCrypto{
+		secret: []byte("deviceSecret"),
+		rngGen: rngGen,
+	}
+	dm, err := newDeviceManager(myID, syncPath, remote, crypt, kv)
+	require.NoError(t, err)
+	return dm
+}
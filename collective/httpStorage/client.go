@@ -0,0 +1,248 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package httpStorage contains implementations of [collective.RemoteStore] for
+// storage servers accessed over HTTP: S3-compatible object stores and WebDAV
+// servers.
+package httpStorage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Error messages.
+const (
+	newRequestErr = "failed to create %s request for %s: %+v"
+	authorizeErr  = "failed to authorize %s request for %s: %+v"
+	requestErr    = "%s request for %s failed after %d attempts: %+v"
+	readBodyErr   = "failed to read response to %s request for %s: %+v"
+	statusCodeErr = "%s request for %s failed with status %s: %s"
+	notFoundErr   = "%s request for %s"
+	lastModHdrErr = "failed to parse Last-Modified header %q for %s: %+v"
+)
+
+// maxErrBodySize is the maximum number of bytes of an unsuccessful response
+// body included in the returned error.
+const maxErrBodySize = 512
+
+// RetryParams configures how requests that fail because of network errors or
+// server errors are retried. Client errors, such as a missing file or invalid
+// credentials, are not retried.
+type RetryParams struct {
+	// Timeout is the time limit of a single attempt, after which it fails and
+	// is retried. It is ignored if an HTTP client is provided, in which case
+	// that client's timeout applies.
+	Timeout time.Duration
+
+	// MaxRetries is the number of times a failed request is retried.
+	MaxRetries int
+
+	// InitialDelay is the delay before the first retry. The delay doubles for
+	// every following retry.
+	InitialDelay time.Duration
+
+	// MaxDelay is the maximum delay between retries.
+	MaxDelay time.Duration
+}
+
+// GetDefaultRetryParams returns the default RetryParams.
+func GetDefaultRetryParams() RetryParams {
+	return RetryParams{
+		Timeout:      30 * time.Second,
+		MaxRetries:   3,
+		InitialDelay: 250 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+	}
+}
+
+// authorizer adds credentials to a request before it is sent. It is called on
+// every attempt so that signatures that include the time remain valid.
+type authorizer func(req *http.Request, body []byte) error
+
+// client sends requests to the storage server, retrying on failure, and tracks
+// the time of the last successful write.
+type client struct {
+	http      *http.Client
+	retry     RetryParams
+	authorize authorizer
+
+	lastWrite time.Time
+	mux       sync.RWMutex
+}
+
+// newClient creates a new client. If httpClient is nil, a client with the
+// timeout in the RetryParams is used, so that a stalled server cannot block a
+// request indefinitely.
+func newClient(
+	httpClient *http.Client, retry RetryParams, auth authorizer) *client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: retry.Timeout}
+	}
+	return &client{
+		http:      httpClient,
+		retry:     retry,
+		authorize: auth,
+	}
+}
+
+// do sends the request and returns the response and its body. Requests are
+// retried on network errors, 429 Too Many Requests, and 5xx responses.
+//
+// Returns an error for any response other than 2xx. A 404 Not Found is returned
+// as an error wrapping [os.ErrNotExist].
+func (c *client) do(method, url string, header http.Header, body []byte) (
+	*http.Response, []byte, error) {
+	delay := c.retry.InitialDelay
+	var err error
+	for attempt := 0; ; attempt++ {
+		var resp *http.Response
+		var respBody []byte
+		var retry bool
+		resp, respBody, retry, err = c.send(method, url, header, body)
+		if err == nil {
+			return checkStatus(method, url, resp, respBody)
+		} else if !retry {
+			return nil, nil, err
+		}
+
+		if attempt >= c.retry.MaxRetries {
+			return nil, nil, errors.Errorf(
+				requestErr, method, url, attempt+1, err)
+		}
+
+		jww.DEBUG.Printf("[COL] %s request for %s failed, retrying in %s: "+
+			"%+v", method, url, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > c.retry.MaxDelay {
+			delay = c.retry.MaxDelay
+		}
+	}
+}
+
+// send makes a single attempt at the request. Returns an error if the attempt
+// failed and true if it should be retried. Errors building or authorizing the
+// request are not retried because they cannot succeed on another attempt.
+func (c *client) send(method, url string, header http.Header, body []byte) (
+	*http.Response, []byte, bool, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, false,
+			errors.Errorf(newRequestErr, method, url, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	if c.authorize != nil {
+		if err = c.authorize(req, body); err != nil {
+			return nil, nil, false,
+				errors.Errorf(authorizeErr, method, url, err)
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, true, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, true, errors.Errorf(readBodyErr, method, url, err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= http.StatusInternalServerError {
+		return nil, nil, true, newStatusError(method, url, resp, respBody)
+	}
+
+	return resp, respBody, false, nil
+}
+
+// recordWrite saves the time of a successful write, taken from the Date header
+// of the response so that it is on the server's clock.
+func (c *client) recordWrite(resp *http.Response) {
+	written, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		written = netTime.Now()
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if written.After(c.lastWrite) {
+		c.lastWrite = written
+	}
+}
+
+// getLastWrite returns the time of the last successful write.
+func (c *client) getLastWrite() time.Time {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.lastWrite
+}
+
+// checkStatus returns an error if the response does not have a 2xx status.
+func checkStatus(method, url string, resp *http.Response, body []byte) (
+	*http.Response, []byte, error) {
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil, errors.Wrapf(os.ErrNotExist, notFoundErr, method, url)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, nil, newStatusError(method, url, resp, body)
+	}
+	return resp, body, nil
+}
+
+// statusError describes an unsuccessful response.
+type statusError struct {
+	code int
+	msg  string
+}
+
+// newStatusError returns an error describing the unsuccessful response.
+func newStatusError(
+	method, url string, resp *http.Response, body []byte) error {
+	if len(body) > maxErrBodySize {
+		body = body[:maxErrBodySize]
+	}
+	return &statusError{
+		code: resp.StatusCode,
+		msg:  fmt.Sprintf(statusCodeErr, method, url, resp.Status, body),
+	}
+}
+
+// Error returns the error message.
+func (se *statusError) Error() string {
+	return se.msg
+}
+
+// hasStatus returns true if the error is for a response with the status code.
+func hasStatus(err error, code int) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.code == code
+}
+
+// parseLastModified parses the Last-Modified header of the response.
+func parseLastModified(url string, resp *http.Response) (time.Time, error) {
+	lastMod := resp.Header.Get("Last-Modified")
+	t, err := http.ParseTime(lastMod)
+	if err != nil {
+		return time.Time{}, errors.Errorf(lastModHdrErr, lastMod, url, err)
+	}
+	return t, nil
+}
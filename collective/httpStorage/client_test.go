@@ -0,0 +1,64 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package httpStorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that client.do times out and retries requests to a server that does
+// not respond.
+func TestClient_do_Timeout(t *testing.T) {
+	stop := make(chan struct{})
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			<-stop
+		}))
+	defer srv.Close()
+	defer close(stop)
+
+	retry := GetDefaultRetryParams()
+	retry.Timeout = 10 * time.Millisecond
+	retry.InitialDelay = time.Millisecond
+	c := newClient(nil, retry, nil)
+
+	_, _, err := c.do(http.MethodGet, srv.URL, nil, nil)
+	require.Error(t, err)
+	require.Equal(t, int32(retry.MaxRetries+1), atomic.LoadInt32(&requests))
+}
+
+// Tests that client.do does not retry a request that fails to be authorized.
+func TestClient_do_AuthorizeError(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+		}))
+	defer srv.Close()
+
+	var attempts int
+	c := newClient(nil, GetDefaultRetryParams(),
+		func(*http.Request, []byte) error {
+			attempts++
+			return errors.New("invalid credentials")
+		})
+
+	_, _, err := c.do(http.MethodGet, srv.URL, nil, nil)
+	require.Error(t, err)
+	require.Equal(t, 1, attempts)
+	require.Zero(t, atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,422 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package httpStorage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Environment variables read by S3CredentialsFromEnv. They are the same ones
+// used by the AWS CLI and SDKs.
+const (
+	s3AccessKeyIdEnv     = "AWS_ACCESS_KEY_ID"
+	s3SecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+	s3SessionTokenEnv    = "AWS_SESSION_TOKEN"
+)
+
+// AWS signature version 4 constants.
+const (
+	sigV4Algorithm   = "AWS4-HMAC-SHA256"
+	sigV4Service     = "s3"
+	sigV4Terminator  = "aws4_request"
+	sigV4DateFormat  = "20060102"
+	sigV4TimeFormat  = "20060102T150405Z"
+	amzDateHeader    = "X-Amz-Date"
+	amzContentHeader = "X-Amz-Content-Sha256"
+	amzTokenHeader   = "X-Amz-Security-Token"
+)
+
+// Error messages.
+const (
+	// NewS3Store
+	s3MissingParamErr = "S3 %s is required"
+	s3EndpointErr     = "invalid S3 endpoint %q: %+v"
+
+	// S3CredentialsFromEnv
+	s3MissingEnvErr = "environment variable %s is not set"
+
+	// s3Store.ReadDir
+	s3ListUnmarshalErr = "failed to unmarshal object list for %s: %+v"
+)
+
+// S3Credentials are the credentials used to sign requests to an S3-compatible
+// object store.
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken is only required for temporary credentials.
+	SessionToken string
+}
+
+// S3CredentialsFromEnv loads the S3 credentials from the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY, and optional AWS_SESSION_TOKEN environment variables.
+func S3CredentialsFromEnv() (S3Credentials, error) {
+	creds := S3Credentials{
+		AccessKeyID:     os.Getenv(s3AccessKeyIdEnv),
+		SecretAccessKey: os.Getenv(s3SecretAccessKeyEnv),
+		SessionToken:    os.Getenv(s3SessionTokenEnv),
+	}
+
+	if creds.AccessKeyID == "" {
+		return S3Credentials{}, errors.Errorf(s3MissingEnvErr, s3AccessKeyIdEnv)
+	} else if creds.SecretAccessKey == "" {
+		return S3Credentials{},
+			errors.Errorf(s3MissingEnvErr, s3SecretAccessKeyEnv)
+	}
+
+	return creds, nil
+}
+
+// S3Params are the parameters used to connect to an S3-compatible object
+// store.
+type S3Params struct {
+	// Endpoint is the URL of the object store (e.g.,
+	// "https://s3.us-east-1.amazonaws.com" or "http://localhost:9000").
+	Endpoint string
+
+	// Region is the region the bucket is in. Stores that do not have regions,
+	// such as MinIO, generally accept "us-east-1".
+	Region string
+
+	// Bucket is the name of the bucket.
+	Bucket string
+
+	// Prefix is prepended to the key of every object. It allows multiple
+	// accounts to share a bucket.
+	Prefix string
+
+	// PathStyle addresses the bucket as part of the path
+	// (endpoint/bucket/key) instead of the host (bucket.endpoint/key). Most
+	// self-hosted stores, including MinIO, require path style.
+	PathStyle bool
+
+	// Credentials are used to sign every request.
+	Credentials S3Credentials
+
+	// Retry configures how failed requests are retried.
+	Retry RetryParams
+
+	// HTTPClient is the client used to make requests. If nil, a client with
+	// the Retry timeout is used.
+	HTTPClient *http.Client
+}
+
+// GetDefaultS3Params returns S3Params with the default retry parameters. The
+// endpoint, region, bucket and credentials must still be set.
+func GetDefaultS3Params() S3Params {
+	return S3Params{
+		Retry: GetDefaultRetryParams(),
+	}
+}
+
+// s3Store implements [collective.RemoteStore] for an S3-compatible object
+// store. Paths are used as object keys. Because object stores have no
+// directories, ReadDir returns the common prefixes of the keys under the path.
+type s3Store struct {
+	endpoint *url.URL
+	params   S3Params
+	*client
+}
+
+// NewS3Store returns a [collective.RemoteStore] that stores files as objects in
+// a bucket of an S3-compatible object store.
+func NewS3Store(params S3Params) (collective.RemoteStore, error) {
+	return newS3Store(params)
+}
+
+// newS3Store creates a new s3Store.
+func newS3Store(params S3Params) (*s3Store, error) {
+	switch {
+	case params.Endpoint == "":
+		return nil, errors.Errorf(s3MissingParamErr, "endpoint")
+	case params.Region == "":
+		return nil, errors.Errorf(s3MissingParamErr, "region")
+	case params.Bucket == "":
+		return nil, errors.Errorf(s3MissingParamErr, "bucket")
+	case params.Credentials.AccessKeyID == "" ||
+		params.Credentials.SecretAccessKey == "":
+		return nil, errors.Errorf(s3MissingParamErr, "credentials")
+	}
+
+	endpoint, err := url.Parse(params.Endpoint)
+	if err != nil {
+		return nil, errors.Errorf(s3EndpointErr, params.Endpoint, err)
+	} else if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, errors.Errorf(s3EndpointErr, params.Endpoint,
+			"scheme and host are required")
+	}
+
+	s := &s3Store{
+		endpoint: endpoint,
+		params:   params,
+	}
+	s.client = newClient(params.HTTPClient, params.Retry, s.sign)
+	return s, nil
+}
+
+// Read returns the contents of the object at the path. Returns an error
+// wrapping [os.ErrNotExist] if the object does not exist.
+func (s *s3Store) Read(path string) ([]byte, error) {
+	_, body, err := s.do(http.MethodGet, s.objectURL(path), nil, nil)
+	return body, err
+}
+
+// Write uploads the data as the object at the path, replacing any existing
+// object.
+func (s *s3Store) Write(path string, data []byte) error {
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	resp, _, err := s.do(http.MethodPut, s.objectURL(path), header, data)
+	if err != nil {
+		return err
+	}
+
+	s.recordWrite(resp)
+	return nil
+}
+
+// GetLastModified returns when the object at the path was last modified.
+func (s *s3Store) GetLastModified(path string) (time.Time, error) {
+	u := s.objectURL(path)
+	resp, _, err := s.do(http.MethodHead, u, nil, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parseLastModified(u, resp)
+}
+
+// GetLastWrite returns the time of the last successful Write, as reported by
+// the object store.
+func (s *s3Store) GetLastWrite() (time.Time, error) {
+	return s.getLastWrite(), nil
+}
+
+// ReadDir returns the names of the "directories" directly under the path,
+// sorted by name. These are the common prefixes of the object keys under the
+// path, up to the next "/".
+func (s *s3Store) ReadDir(path string) ([]string, error) {
+	prefix := s.key(path)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	var dirs []string
+	var token string
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"prefix":    {prefix},
+			"delimiter": {"/"},
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		u := s.bucketURL()
+		u.RawQuery = query.Encode()
+		_, body, err := s.do(http.MethodGet, u.String(), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list s3ListBucketResult
+		if err = xml.Unmarshal(body, &list); err != nil {
+			return nil, errors.Errorf(s3ListUnmarshalErr, path, err)
+		}
+
+		for _, cp := range list.CommonPrefixes {
+			dir := strings.TrimSuffix(
+				strings.TrimPrefix(cp.Prefix, prefix), "/")
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			break
+		}
+		token = list.NextContinuationToken
+	}
+
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// s3ListBucketResult is the response to a ListObjectsV2 request. Only the
+// fields used by ReadDir are included.
+type s3ListBucketResult struct {
+	CommonPrefixes []struct {
+		Prefix string
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// key returns the object key for the path.
+func (s *s3Store) key(p string) string {
+	p = cleanPath(p)
+	prefix := strings.Trim(s.params.Prefix, "/")
+	if prefix == "" {
+		return p
+	} else if p == "" {
+		return prefix
+	}
+	return prefix + "/" + p
+}
+
+// bucketURL returns the URL of the bucket.
+func (s *s3Store) bucketURL() *url.URL {
+	u := *s.endpoint
+	if s.params.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.params.Bucket + "/"
+	} else {
+		u.Host = s.params.Bucket + "." + u.Host
+		u.Path = "/"
+	}
+	return &u
+}
+
+// objectURL returns the URL of the object at the path.
+func (s *s3Store) objectURL(path string) string {
+	u := s.bucketURL()
+	u.Path += s.key(path)
+	return u.String()
+}
+
+// sign adds an AWS signature version 4 Authorization header to the request.
+func (s *s3Store) sign(req *http.Request, body []byte) error {
+	signSigV4(req, body, s.params.Credentials, s.params.Region, netTime.Now())
+	return nil
+}
+
+// signSigV4 signs the request using AWS signature version 4. All headers set on
+// the request are signed.
+//
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func signSigV4(req *http.Request, body []byte, creds S3Credentials,
+	region string, now time.Time) {
+	now = now.UTC()
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set(amzDateHeader, now.Format(sigV4TimeFormat))
+	req.Header.Set(amzContentHeader, hex.EncodeToString(payloadHash[:]))
+	if creds.SessionToken != "" {
+		req.Header.Set(amzTokenHeader, creds.SessionToken)
+	}
+
+	// Build the canonical headers, which always include the host
+	headers := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(
+			strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	date := now.Format(sigV4DateFormat)
+	scope := strings.Join(
+		[]string{date, region, sigV4Service, sigV4Terminator}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{sigV4Algorithm,
+		now.Format(sigV4TimeFormat), scope,
+		hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, sigV4Service)
+	key = hmacSHA256(key, sigV4Terminator)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", sigV4Algorithm+
+		" Credential="+creds.AccessKeyID+"/"+scope+
+		",SignedHeaders="+signedHeaders+",Signature="+signature)
+}
+
+// canonicalURI returns the URI-encoded path of the URL.
+func canonicalURI(u *url.URL) string {
+	p := u.EscapedPath()
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			unescaped = segment
+		}
+		segments[i] = uriEncode(unescaped)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery returns the query of the URL with the parameters sorted and
+// URI-encoded.
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	params := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			params = append(params, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// uriEncode encodes every byte except the unreserved characters as required by
+// signature version 4.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') ||
+			('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' ||
+			c == '~' {
+			b.WriteByte(c)
+		} else {
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,63 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package httpStorage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/client/v4/collective"
+)
+
+// testRemoteStore tests that the store implements the behaviour of
+// collective.RemoteStore that the collective package relies on.
+func testRemoteStore(t *testing.T, store collective.RemoteStore) {
+	_, err := store.Read("sync/missing/state.xx")
+	require.True(t, errors.Is(err, os.ErrNotExist),
+		"Unexpected error for missing file: %+v", err)
+
+	start := time.Now().Add(-time.Minute)
+	files := map[string][]byte{
+		"sync/device1/key1/state.xx": []byte("state1"),
+		"sync/device1/key1/index.xx": []byte("index1"),
+		"sync/device2/key2/state.xx": []byte("state2"),
+	}
+	for path, data := range files {
+		require.NoError(t, store.Write(path, data))
+	}
+
+	// Files can be overwritten
+	files["sync/device1/key1/state.xx"] = []byte("new state1")
+	require.NoError(t, store.Write(
+		"sync/device1/key1/state.xx", files["sync/device1/key1/state.xx"]))
+
+	for path, expected := range files {
+		data, err := store.Read(path)
+		require.NoError(t, err, path)
+		require.Equal(t, expected, data, path)
+
+		lastMod, err := store.GetLastModified(path)
+		require.NoError(t, err, path)
+		require.True(t, lastMod.After(start), "%s: %s", path, lastMod)
+	}
+
+	lastWrite, err := store.GetLastWrite()
+	require.NoError(t, err)
+	require.True(t, lastWrite.After(start), lastWrite)
+
+	devices, err := store.ReadDir("sync")
+	require.NoError(t, err)
+	require.Equal(t, []string{"device1", "device2"}, devices)
+
+	keys, err := store.ReadDir("sync/device1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"key1"}, keys)
+}
@@ -0,0 +1,280 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package httpStorage
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/collective"
+)
+
+// WebDAV methods and headers that are not defined in net/http.
+const (
+	methodPropfind = "PROPFIND"
+	methodMkcol    = "MKCOL"
+	depthHeader    = "Depth"
+)
+
+// propfindResourceType is the body of the PROPFIND request made by ReadDir.
+const propfindResourceType = `<?xml version="1.0" encoding="utf-8"?>` +
+	`<D:propfind xmlns:D="DAV:"><D:prop><D:resourcetype/></D:prop></D:propfind>`
+
+// Error messages.
+const (
+	// NewWebDAVStore
+	webdavMissingUrlErr = "WebDAV URL is required"
+	webdavUrlErr        = "invalid WebDAV URL %q: %+v"
+
+	// webdavStore.ReadDir
+	webdavPropfindUnmarshalErr = "failed to unmarshal PROPFIND response for %s: %+v"
+)
+
+// WebDAVParams are the parameters used to connect to a WebDAV server.
+type WebDAVParams struct {
+	// URL is the URL of the collection that all files are stored under (e.g.,
+	// "https://example.com/remote.php/dav/files/user/xxdk"). The collection
+	// must already exist.
+	URL string
+
+	// Username and Password are used for HTTP basic authentication if the
+	// username is set.
+	Username string
+	Password string
+
+	// BearerToken is sent in the Authorization header if set. It is used
+	// instead of basic authentication.
+	BearerToken string
+
+	// Retry configures how failed requests are retried.
+	Retry RetryParams
+
+	// HTTPClient is the client used to make requests. If nil, a client with
+	// the Retry timeout is used.
+	HTTPClient *http.Client
+}
+
+// GetDefaultWebDAVParams returns WebDAVParams with the default retry
+// parameters. The URL and credentials must still be set.
+func GetDefaultWebDAVParams() WebDAVParams {
+	return WebDAVParams{
+		Retry: GetDefaultRetryParams(),
+	}
+}
+
+// webdavStore implements [collective.RemoteStore] for a WebDAV server. Paths
+// are relative to the base collection, and collections are created as needed
+// when writing.
+type webdavStore struct {
+	base   *url.URL
+	params WebDAVParams
+	*client
+}
+
+// NewWebDAVStore returns a [collective.RemoteStore] that stores files on a
+// WebDAV server.
+func NewWebDAVStore(params WebDAVParams) (collective.RemoteStore, error) {
+	return newWebDAVStore(params)
+}
+
+// newWebDAVStore creates a new webdavStore.
+func newWebDAVStore(params WebDAVParams) (*webdavStore, error) {
+	if params.URL == "" {
+		return nil, errors.New(webdavMissingUrlErr)
+	}
+
+	base, err := url.Parse(params.URL)
+	if err != nil {
+		return nil, errors.Errorf(webdavUrlErr, params.URL, err)
+	} else if base.Scheme == "" || base.Host == "" {
+		return nil, errors.Errorf(webdavUrlErr, params.URL,
+			"scheme and host are required")
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/")
+
+	w := &webdavStore{
+		base:   base,
+		params: params,
+	}
+	w.client = newClient(params.HTTPClient, params.Retry, w.authorize)
+	return w, nil
+}
+
+// Read returns the contents of the file at the path. Returns an error wrapping
+// [os.ErrNotExist] if the file does not exist.
+func (w *webdavStore) Read(path string) ([]byte, error) {
+	_, body, err := w.do(http.MethodGet, w.fileURL(path), nil, nil)
+	return body, err
+}
+
+// Write writes the data to the file at the path, replacing any existing file.
+// If the parent collections do not exist, they are created.
+func (w *webdavStore) Write(path string, data []byte) error {
+	u := w.fileURL(path)
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	resp, _, err := w.do(http.MethodPut, u, header, data)
+	if hasStatus(err, http.StatusConflict) || errors.Is(err, os.ErrNotExist) {
+		// Servers return 409 Conflict, or 404 Not Found for some, when a
+		// parent collection is missing
+		if err = w.makeParents(path); err != nil {
+			return err
+		}
+		resp, _, err = w.do(http.MethodPut, u, header, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.recordWrite(resp)
+	return nil
+}
+
+// GetLastModified returns when the file at the path was last modified.
+func (w *webdavStore) GetLastModified(path string) (time.Time, error) {
+	u := w.fileURL(path)
+	resp, _, err := w.do(http.MethodHead, u, nil, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parseLastModified(u, resp)
+}
+
+// GetLastWrite returns the time of the last successful Write, as reported by
+// the WebDAV server.
+func (w *webdavStore) GetLastWrite() (time.Time, error) {
+	return w.getLastWrite(), nil
+}
+
+// ReadDir returns the names of the collections directly under the path,
+// sorted by name. Files are not included.
+func (w *webdavStore) ReadDir(dirPath string) ([]string, error) {
+	u := w.collectionURL(dirPath)
+	header := http.Header{
+		depthHeader:    {"1"},
+		"Content-Type": {"application/xml; charset=utf-8"},
+	}
+	_, body, err := w.do(methodPropfind, u.String(), header,
+		[]byte(propfindResourceType))
+	if err != nil {
+		return nil, err
+	}
+
+	var ms webdavMultistatus
+	if err = xml.Unmarshal(body, &ms); err != nil {
+		return nil, errors.Errorf(webdavPropfindUnmarshalErr, dirPath, err)
+	}
+
+	dirs := make([]string, 0, len(ms.Responses))
+	for _, r := range ms.Responses {
+		if !r.isCollection() {
+			continue
+		}
+
+		hrefURL, err := url.Parse(r.Href)
+		if err != nil {
+			continue
+		}
+		hrefPath := strings.TrimSuffix(hrefURL.Path, "/")
+
+		// Skip the collection itself
+		if hrefPath == strings.TrimSuffix(u.Path, "/") {
+			continue
+		}
+		dirs = append(dirs, path.Base(hrefPath))
+	}
+
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// webdavMultistatus is the response to a PROPFIND request. Only the fields
+// used by ReadDir are included.
+type webdavMultistatus struct {
+	Responses []webdavResponse `xml:"DAV: response"`
+}
+
+type webdavResponse struct {
+	Href      string `xml:"DAV: href"`
+	Propstats []struct {
+		Status string `xml:"DAV: status"`
+		Prop   struct {
+			ResourceType struct {
+				Collection *struct{} `xml:"DAV: collection"`
+			} `xml:"DAV: resourcetype"`
+		} `xml:"DAV: prop"`
+	} `xml:"DAV: propstat"`
+}
+
+// isCollection returns true if the response is for a collection.
+func (r webdavResponse) isCollection() bool {
+	for _, ps := range r.Propstats {
+		if ps.Prop.ResourceType.Collection != nil &&
+			strings.Contains(ps.Status, " 200 ") {
+			return true
+		}
+	}
+	return false
+}
+
+// makeParents creates every parent collection of the path that does not exist.
+func (w *webdavStore) makeParents(p string) error {
+	segments := strings.Split(cleanPath(p), "/")
+	for i := 1; i < len(segments); i++ {
+		u := w.collectionURL(strings.Join(segments[:i], "/"))
+		_, _, err := w.do(methodMkcol, u.String(), nil, nil)
+
+		// The server returns 405 Method Not Allowed if it already exists
+		if err != nil && !hasStatus(err, http.StatusMethodNotAllowed) {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileURL returns the URL of the file at the path.
+func (w *webdavStore) fileURL(p string) string {
+	u := *w.base
+	u.Path += "/" + cleanPath(p)
+	return u.String()
+}
+
+// collectionURL returns the URL of the collection at the path. Collection URLs
+// end in a slash.
+func (w *webdavStore) collectionURL(p string) *url.URL {
+	u := *w.base
+	if p = cleanPath(p); p != "" {
+		u.Path += "/" + p
+	}
+	u.Path += "/"
+	return &u
+}
+
+// authorize adds the credentials to the request.
+func (w *webdavStore) authorize(req *http.Request, _ []byte) error {
+	if w.params.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+w.params.BearerToken)
+	} else if w.params.Username != "" {
+		req.SetBasicAuth(w.params.Username, w.params.Password)
+	}
+	return nil
+}
+
+// cleanPath returns the path with forward slashes and without leading or
+// trailing slashes.
+func cleanPath(p string) string {
+	return strings.Trim(path.Clean("/"+filepath.ToSlash(p)), "/")
+}
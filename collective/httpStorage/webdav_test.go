@@ -0,0 +1,115 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package httpStorage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+)
+
+// newTestWebDAVServer starts an in-process WebDAV server that requires basic
+// authentication. The first failures requests fail with 503 Service
+// Unavailable. Returns the server and the count of requests it received.
+func newTestWebDAVServer(
+	t *testing.T, failures int32) (*httptest.Server, *int32) {
+	fs := webdav.NewMemFS()
+	require.NoError(t, fs.Mkdir(context.Background(), "/xxdk", 0700))
+	handler := &webdav.Handler{
+		FileSystem: fs,
+		LockSystem: webdav.NewMemLS(),
+	}
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) <= failures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if user, pass, ok := r.BasicAuth(); !ok ||
+				user != "user" || pass != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+// Tests that webdavStore adheres to the collective.RemoteStore behaviour.
+func TestWebDAVStore(t *testing.T) {
+	srv, _ := newTestWebDAVServer(t, 0)
+
+	params := GetDefaultWebDAVParams()
+	params.URL = srv.URL + "/xxdk/"
+	params.Username, params.Password = "user", "password"
+	store, err := NewWebDAVStore(params)
+	require.NoError(t, err)
+
+	testRemoteStore(t, store)
+}
+
+// Tests that webdavStore retries requests that fail with a server error.
+func TestWebDAVStore_Retry(t *testing.T) {
+	srv, requests := newTestWebDAVServer(t, 2)
+
+	params := GetDefaultWebDAVParams()
+	params.URL = srv.URL + "/xxdk"
+	params.Username, params.Password = "user", "password"
+	params.Retry.InitialDelay = time.Millisecond
+	store, err := NewWebDAVStore(params)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Write("file", []byte("data")))
+	require.Equal(t, int32(3), atomic.LoadInt32(requests))
+
+	// Fails once the retries are used up
+	srv, requests = newTestWebDAVServer(t, 100)
+	params.URL = srv.URL + "/xxdk"
+	store, err = NewWebDAVStore(params)
+	require.NoError(t, err)
+
+	require.Error(t, store.Write("file", []byte("data")))
+	require.Equal(t, int32(params.Retry.MaxRetries+1),
+		atomic.LoadInt32(requests))
+}
+
+// Tests that webdavStore does not retry requests that are rejected because of
+// invalid credentials.
+func TestWebDAVStore_Unauthorized(t *testing.T) {
+	srv, requests := newTestWebDAVServer(t, 0)
+
+	params := GetDefaultWebDAVParams()
+	params.URL = srv.URL + "/xxdk"
+	params.Username, params.Password = "user", "wrong"
+	store, err := NewWebDAVStore(params)
+	require.NoError(t, err)
+
+	err = store.Write("file", []byte("data"))
+	require.True(t, hasStatus(err, http.StatusUnauthorized), err)
+	require.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+// Error path: Tests that NewWebDAVStore returns an error for an invalid URL.
+func TestNewWebDAVStore_InvalidURL(t *testing.T) {
+	for _, u := range []string{"", "/xxdk", "://example.com"} {
+		params := GetDefaultWebDAVParams()
+		params.URL = u
+		_, err := NewWebDAVStore(params)
+		require.Error(t, err, u)
+	}
+}
@@ -284,6 +284,11 @@ func (r *internalKV) MapTransactionFromRemote(mapName string,
 
 			//handle the operation
 			if value.Deletion {
+				// a tombstone for an element that was never added
+				// locally is a no-op
+				if !exists && !mapSet.Has(elementName) {
+					continue
+				}
 				mapSet.Delete(elementName)
 				file.Delete()
 				element.Operation = versioned.Deleted
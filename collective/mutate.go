@@ -12,10 +12,15 @@ import (
 	"time"
 )
 
+// legacyTimestampLimit is the largest Mutate.Timestamp that is treated as a
+// Unix timestamp in seconds, the resolution written by older clients. Every
+// timestamp in nanoseconds after 1970-01-01 00:16:40 UTC is larger than it.
+const legacyTimestampLimit = 1e12
+
 // NewMutate is the constructor of a Mutate object.
 func NewMutate(ts time.Time, value []byte, deletion bool) Mutate {
 	return Mutate{
-		Timestamp: ts.UTC().Unix(),
+		Timestamp: ts.UTC().UnixNano(),
 		Value:     value,
 		Deletion:  deletion,
 	}
@@ -25,6 +30,11 @@ func NewMutate(ts time.Time, value []byte, deletion bool) Mutate {
 // for account synchronization. It inherits the private mutate object.
 // This prevents recursive calls by json.Marshal on header.MarshalJSON. Any
 // changes to the header object fields should be done in header.
+//
+// Timestamp is a hybrid logical clock timestamp in Unix nanoseconds (see
+// hybridClock). Mutations are ordered by it, with ties between devices broken
+// by device supremacy. Older clients wrote it in Unix seconds; such timestamps
+// are converted when read (see Mutate.normalizeTimestamp).
 type Mutate struct {
 	Timestamp int64
 	Value     []byte
@@ -37,6 +47,22 @@ func (m *Mutate) GetTimestamp() time.Time {
 	return time.Unix(0, m.Timestamp)
 }
 
+// normalizeTimestamp converts a timestamp in seconds written by older clients
+// to nanoseconds so that it can be ordered against newer mutations. It must be
+// called on every mutation read from storage or the remote.
+func (m *Mutate) normalizeTimestamp() {
+	if m.Timestamp > 0 && m.Timestamp < legacyTimestampLimit {
+		m.Timestamp *= int64(time.Second)
+	}
+}
+
+// supersedes returns true if the mutation is ordered after the other mutation.
+// Ties are resolved in favour of m, so it must be called on the mutation from
+// the supreme device.
+func (m *Mutate) supersedes(other *Mutate) bool {
+	return m.Timestamp >= other.Timestamp
+}
+
 func (m *Mutate) String() string {
 	return fmt.Sprintf("ts: %d, deleted? %v, data: %v...",
 		m.Timestamp, m.Deletion, m.Value)
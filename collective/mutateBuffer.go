@@ -50,6 +50,10 @@ func loadBuffer(kv ekv.KeyValue) (*mutateBuffer, map[int]map[string]Mutate) {
 			continue
 		}
 
+		for key, m := range mutates {
+			m.normalizeTimestamp()
+			mutates[key] = m
+		}
 		transactions[i] = mutates
 
 		if firstBucketStart == bufferSize {
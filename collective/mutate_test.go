@@ -26,7 +26,7 @@ func TestNewTransaction(t *testing.T) {
 	// Construct expected Mutate object
 	_, val := "key", []byte("value")
 	expectedTx := Mutate{
-		Timestamp: testTime.UTC().Unix(),
+		Timestamp: testTime.UTC().UnixNano(),
 		Value:     val,
 	}
 
@@ -48,7 +48,7 @@ func TestTransaction_MarshalJSON(t *testing.T) {
 	marshalledData, err := json.Marshal(tx)
 	require.NoError(t, err)
 
-	expectedTransactionJson := `{"Timestamp":1356127721,"Value":"dmFsdWU=","Deletion":false}`
+	expectedTransactionJson := `{"Timestamp":1356127721000000000,"Value":"dmFsdWU=","Deletion":false}`
 
 	// Check that marshaled data matches expected value
 	require.Equal(t, expectedTransactionJson, string(marshalledData))
@@ -82,7 +82,7 @@ func TestTransaction_UnmarshalJSON(t *testing.T) {
 	newTxData, err := json.Marshal(newTx)
 	require.NoError(t, err)
 
-	expectedTransactionJson := `{"Timestamp":1356127721,"Value":"dmFsdWU=","Deletion":false}`
+	expectedTransactionJson := `{"Timestamp":1356127721000000000,"Value":"dmFsdWU=","Deletion":false}`
 
 	// Ensure that newTx's marshalled data matches the expected JSON
 	// output (if no data has been lost, this should be the case)
@@ -111,7 +111,7 @@ func TestTransaction_UnmarshalJSON_ZeroTime(t *testing.T) {
 	newTx := NewMutate(time.Unix(0, 0), make([]byte, 0), false)
 	require.NoError(t, json.Unmarshal(oldTxData, &newTx))
 
-	require.Equal(t, newTx.Timestamp, testTime.Unix())
+	require.Equal(t, newTx.Timestamp, testTime.UnixNano())
 }
 
 // Smoke test of Mutate.serialize.
@@ -129,7 +129,7 @@ func TestTransaction_Serialize(t *testing.T) {
 	txSerial, err := json.Marshal(tx)
 	require.NoError(t, err)
 
-	expectedSerializedTransaction := "eyJUaW1lc3RhbXAiOjEzNTYxMjc3MjEsIlZhbHVlIjoiZG1Gc2RXVT0iLCJEZWxldGlvbiI6ZmFsc2V9"
+	expectedSerializedTransaction := "eyJUaW1lc3RhbXAiOjEzNTYxMjc3MjEwMDAwMDAwMDAsIlZhbHVlIjoiZG1Gc2RXVT0iLCJEZWxldGlvbiI6ZmFsc2V9"
 
 	// Ensure serialization is consistent
 	require.Equal(t, expectedSerializedTransaction,
@@ -44,6 +44,9 @@ func (p *Patch) Serialize() ([]byte, error) {
 func (p *Patch) Deserialize(b []byte) error {
 	err := json.Unmarshal(b, &p.keys)
 	for k, v := range p.keys {
+		if v != nil {
+			v.normalizeTimestamp()
+		}
 		d, _ := json.Marshal(v)
 		jww.DEBUG.Printf("Deserializing %s: %s->%s",
 			p.myID, k, d)
@@ -51,6 +54,25 @@ func (p *Patch) Deserialize(b []byte) error {
 	return err
 }
 
+// clone returns a copy of the patch. The mutations are shared.
+func (p *Patch) clone() *Patch {
+	c := newPatch(p.myID)
+	for key, m := range p.keys {
+		c.keys[key] = m
+	}
+	return c
+}
+
+// merge adds the mutations from the other patch that supersede the mutations
+// for the same keys in this patch.
+func (p *Patch) merge(other *Patch) {
+	for key, m := range other.keys {
+		if cur, exists := p.keys[key]; !exists || m.supersedes(cur) {
+			p.keys[key] = m
+		}
+	}
+}
+
 func (p *Patch) get(key string) (*Mutate, bool) {
 	m, exists := p.keys[key]
 	return m, exists
@@ -64,12 +86,18 @@ func (p *Patch) get(key string) (*Mutate, bool) {
 // as having larger device IDs. This supremacy will determine which mutation
 // is applied in the event they have the same timestamp
 // O(2*numPatches*numMutations)
-// Diff does not check the _____ for updates because they should already be
-// applied
+// Diff does not return keys where the winning mutation is from this patch
+// because they are already applied to the local KV.
+//
+// Because every key resolves to the mutation with the largest hybrid logical
+// clock timestamp across all patches, the result does not depend on the order
+// in which patches were received. For map elements, this makes the map a
+// last-writer-wins element set: deletions are kept in the patch as tombstones,
+// so element adds and removes commute across devices.
 func (p *Patch) Diff(patches []*Patch, lastSeen []time.Time) (
 	map[string]*Mutate, []time.Time) {
 	mutatedKeys, newLastSeen := p.findKeysWithUpdates(patches, lastSeen)
-	return buildMerge(patches, mutatedKeys), newLastSeen
+	return buildMerge(p.myID, patches, mutatedKeys), newLastSeen
 }
 
 func (p *Patch) findKeysWithUpdates(remotePatches []*Patch, lastSeen []time.Time) (map[string]struct{}, []time.Time) {
@@ -100,13 +128,28 @@ func (p *Patch) findKeysWithUpdates(remotePatches []*Patch, lastSeen []time.Time
 	return keys, newLastSeen
 }
 
-func buildMerge(patches []*Patch, mutatedKeys map[string]struct{}) map[string]*Mutate {
+// lastTimestamp returns the largest timestamp of all mutations in the patch.
+func (p *Patch) lastTimestamp() int64 {
+	var last int64
+	for _, m := range p.keys {
+		if m.Timestamp > last {
+			last = m.Timestamp
+		}
+	}
+	return last
+}
+
+// buildMerge returns the winning mutation for each mutated key. Keys won by
+// the local patch, identified by localID, are omitted.
+func buildMerge(localID InstanceID, patches []*Patch,
+	mutatedKeys map[string]struct{}) map[string]*Mutate {
 	output := make(map[string]*Mutate, len(mutatedKeys))
 
 	for key := range mutatedKeys {
 		defending := &Mutate{
 			Timestamp: 0,
 		}
+		var defendingID InstanceID
 
 		for _, patch := range patches {
 			if contender, exists := patch.get(key); exists {
@@ -114,13 +157,16 @@ func buildMerge(patches []*Patch, mutatedKeys map[string]struct{}) map[string]*M
 				// implements supremely, if they are the same the one
 				// which has the higher device ID, which will be
 				// closer to the end of the list, will be skipped
-				if !(defending.Timestamp > contender.Timestamp) {
+				if contender.supersedes(defending) {
 					defending = contender
+					defendingID = patch.myID
 				}
 			}
 		}
-		output[key] = defending
 
+		if defendingID != localID {
+			output[key] = defending
+		}
 	}
 	return output
 }
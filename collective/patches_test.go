@@ -0,0 +1,117 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+)
+
+// Tests that a mutation made on a device with a clock behind another device
+// wins over the mutation it observed when the clock observes it first.
+func TestPatch_Diff_ClockSkew(t *testing.T) {
+	ahead, behind := InstanceID{1}, InstanceID{2}
+	aheadClock, behindClock := &hybridClock{}, &hybridClock{}
+	aheadClock.Observe(time.Now().Add(time.Hour).UnixNano())
+
+	aheadPatch, behindPatch := newPatch(ahead), newPatch(behind)
+	aheadPatch.AddUnsafe("key",
+		Mutate{Timestamp: aheadClock.Now(), Value: []byte("first")})
+
+	// The device that is behind observes the first write and then overwrites
+	// it
+	behindClock.Observe(aheadPatch.lastTimestamp())
+	behindPatch.AddUnsafe("key",
+		Mutate{Timestamp: behindClock.Now(), Value: []byte("second")})
+
+	patches := []*Patch{aheadPatch, behindPatch}
+	lastSeen := make([]time.Time, len(patches))
+	updates, _ := aheadPatch.Diff(patches, lastSeen)
+	require.Equal(t, []byte("second"), updates["key"].Value)
+
+	// The device that wrote the value does not apply its own mutation again
+	updates, _ = behindPatch.Diff(patches, lastSeen)
+	require.Empty(t, updates)
+}
+
+// Tests that concurrent map element adds and removes on different devices
+// converge to the same state on every device, regardless of which patches each
+// device received first.
+func TestPatch_Diff_MapElementsCommute(t *testing.T) {
+	const mapName = "map"
+	elem1 := versioned.MakeElementKey(mapName, "elem1")
+	elem2 := versioned.MakeElementKey(mapName, "elem2")
+
+	patchA, patchB, patchC :=
+		newPatch(InstanceID{1}), newPatch(InstanceID{2}), newPatch(InstanceID{3})
+	patchA.AddUnsafe(elem1, Mutate{Timestamp: 10, Value: []byte("a1")})
+	patchA.AddUnsafe(elem2, Mutate{Timestamp: 20, Deletion: true})
+	patchB.AddUnsafe(elem1, Mutate{Timestamp: 30, Deletion: true})
+	patchB.AddUnsafe(elem2, Mutate{Timestamp: 15, Value: []byte("b2")})
+	patchC.AddUnsafe(elem2, Mutate{Timestamp: 20, Value: []byte("c2")})
+	patches := []*Patch{patchA, patchB, patchC}
+
+	// Each device first merges with one other device, and then with all of
+	// them, applying the updates to its local state after each merge
+	for i, local := range patches {
+		state := make(map[string]*Mutate)
+		for key, m := range local.keys {
+			state[key] = m
+		}
+
+		other := patches[(i+1)%len(patches)]
+		partial := []*Patch{local, other}
+		if local.myID.Cmp(other.myID) == 1 {
+			partial = []*Patch{other, local}
+		}
+		for _, received := range [][]*Patch{partial, patches} {
+			lastSeen := make([]time.Time, len(received))
+			updates, _ := local.Diff(received, lastSeen)
+			for key, m := range updates {
+				state[key] = m
+			}
+		}
+
+		require.True(t, state[elem1].Deletion, "device %d", i)
+		require.False(t, state[elem2].Deletion, "device %d", i)
+		require.Equal(t, []byte("c2"), state[elem2].Value, "device %d", i)
+	}
+}
+
+// Tests that Patch.Deserialize converts timestamps in seconds written by older
+// clients to nanoseconds so that they are ordered correctly against newer
+// mutations.
+func TestPatch_Deserialize_LegacyTimestamps(t *testing.T) {
+	legacy := time.Unix(1700000000, 0)
+	legacyPatch := []byte(`{"key":{"Timestamp":1700000000,` +
+		`"Value":"b2xk","Deletion":false}}`)
+
+	p := newPatch(InstanceID{1})
+	require.NoError(t, p.Deserialize(legacyPatch))
+	require.Equal(t, legacy.UnixNano(), p.keys["key"].Timestamp)
+	require.Equal(t, []byte("old"), p.keys["key"].Value)
+
+	// A mutation made an hour before the legacy one is ordered before it
+	older := NewMutate(legacy.Add(-time.Hour), []byte("older"), false)
+	require.True(t, p.keys["key"].supersedes(&older))
+	require.False(t, older.supersedes(p.keys["key"]))
+
+	// A mutation made a second after the legacy one supersedes it
+	newer := NewMutate(legacy.Add(time.Second), []byte("newer"), false)
+	require.True(t, newer.supersedes(p.keys["key"]))
+
+	// Timestamps already in nanoseconds are unchanged
+	serial, err := p.Serialize()
+	require.NoError(t, err)
+	loaded := newPatch(InstanceID{1})
+	require.NoError(t, loaded.Deserialize(serial))
+	require.Equal(t, p.keys, loaded.keys)
+}
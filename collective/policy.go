@@ -0,0 +1,152 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"strconv"
+	"strings"
+
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+)
+
+// SyncPolicy selects which keys written under the synchronized prefixes are
+// synchronized with the remote. Keys it excludes are still stored locally, but
+// changes to them are not uploaded and changes made by other devices are not
+// applied. This allows a device, such as a secondary device with limited
+// storage, to synchronize only part of the account.
+//
+// Prefixes are matched against the [versioned.KV] prefixes of a key, at any
+// depth, in the same way as the synchronized prefixes. Map elements are
+// matched using the prefixes and name of their map. Names are matched without
+// prefixes or versions.
+//
+// A nil SyncPolicy synchronizes every key.
+type SyncPolicy struct {
+	// Prefixes limits synchronization to keys under at least one of these
+	// prefixes. If empty, keys under any prefix are synchronized.
+	Prefixes []string `json:"prefixes,omitempty"`
+
+	// ExcludedPrefixes are prefixes whose keys are never synchronized. It
+	// takes precedence over Prefixes.
+	ExcludedPrefixes []string `json:"excludedPrefixes,omitempty"`
+
+	// Maps limits synchronization of map elements to the maps with these
+	// names. If empty, elements of every map are synchronized.
+	Maps []string `json:"maps,omitempty"`
+
+	// ExcludedMaps are the names of maps whose elements are never
+	// synchronized. It takes precedence over Maps.
+	ExcludedMaps []string `json:"excludedMaps,omitempty"`
+
+	// MaxSize is the maximum size, in bytes, of a synchronized value. Larger
+	// values are only stored locally. There is no limit if it is zero.
+	MaxSize int `json:"maxSize,omitempty"`
+
+	// MaxSizes overrides MaxSize for keys under each prefix. If a key is
+	// under several of them, the smallest limit applies.
+	MaxSizes map[string]int `json:"maxSizes,omitempty"`
+
+	// LocalOnly are the names of keys and maps that are only stored locally.
+	// Keys with these names are written directly to the local store, even
+	// under a synchronized prefix.
+	LocalOnly []string `json:"localOnly,omitempty"`
+}
+
+// synchronizes returns true if a change setting the key to the value, or
+// deleting it if the value is nil, is synchronized with the remote.
+func (sp *SyncPolicy) synchronizes(key string, value []byte) bool {
+	if sp == nil {
+		return true
+	}
+
+	if isMapElement, mapName, _ := versioned.DetectMapElement(key); isMapElement {
+		_, name := splitKey(mapName)
+		if containsString(sp.ExcludedMaps, name) ||
+			(len(sp.Maps) > 0 && !containsString(sp.Maps, name)) {
+			return false
+		}
+		key = mapName
+	}
+
+	prefixes, name := splitKey(key)
+	if containsString(sp.LocalOnly, name) ||
+		containsAny(sp.ExcludedPrefixes, prefixes) ||
+		(len(sp.Prefixes) > 0 && !containsAny(sp.Prefixes, prefixes)) {
+		return false
+	}
+
+	maxSize := sp.MaxSize
+	for _, prefix := range prefixes {
+		if size, exists := sp.MaxSizes[prefix]; exists &&
+			(maxSize == 0 || size < maxSize) {
+			maxSize = size
+		}
+	}
+	if maxSize > 0 && len(value) > maxSize {
+		jww.DEBUG.Printf("[COL] Value of %s is %d bytes, which is over the "+
+			"%d byte limit", key, len(value), maxSize)
+		return false
+	}
+
+	return true
+}
+
+// isLocalOnly returns true if the key is only stored locally.
+func (sp *SyncPolicy) isLocalOnly(key string) bool {
+	if sp == nil {
+		return false
+	}
+	_, name := splitKey(key)
+	return containsString(sp.LocalOnly, name)
+}
+
+// setPolicy sets the SyncPolicy of the remoteWriter and removes the mutations
+// it does not synchronize from the Patch, so that they are not uploaded with
+// the next snapshot. It must be called before the Runner is started.
+func (rw *remoteWriter) setPolicy(policy *SyncPolicy) {
+	rw.policy = policy
+	for key, m := range rw.state.keys {
+		if !policy.synchronizes(key, m.Value) {
+			delete(rw.state.keys, key)
+		}
+	}
+}
+
+// splitKey splits a full key into its prefixes and its name, without the
+// version suffix added by [versioned.KV].
+func splitKey(key string) (prefixes []string, name string) {
+	parts := strings.Split(key, versioned.PrefixSeparator)
+	name = parts[len(parts)-1]
+	if i := strings.LastIndex(name, "_"); i != -1 {
+		if _, err := strconv.ParseUint(name[i+1:], 10, 64); err == nil {
+			name = name[:i]
+		}
+	}
+	return parts[:len(parts)-1], name
+}
+
+// containsString returns true if the string is in the list.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny returns true if any of the strings are in the list.
+func containsAny(list []string, strs []string) bool {
+	for _, s := range strs {
+		if containsString(list, s) {
+			return true
+		}
+	}
+	return false
+}
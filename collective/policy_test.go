@@ -0,0 +1,183 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/ekv"
+)
+
+// Tests that SyncPolicy.synchronizes applies each rule of the policy.
+func TestSyncPolicy_synchronizes(t *testing.T) {
+	sep := versioned.PrefixSeparator
+	channelsMap := "sync" + sep + "channels" + sep + "channelList_0"
+	policy := &SyncPolicy{
+		Prefixes:         []string{"identities", "channels"},
+		ExcludedPrefixes: []string{"messages"},
+		Maps:             []string{"channelList", "members"},
+		ExcludedMaps:     []string{"members"},
+		MaxSize:          16,
+		MaxSizes:         map[string]int{"channels": 8},
+		LocalOnly:        []string{"deviceState"},
+	}
+	small, medium, large := []byte("small"), bytes.Repeat([]byte("m"), 12),
+		bytes.Repeat([]byte("l"), 32)
+
+	tests := []struct {
+		key      string
+		value    []byte
+		expected bool
+	}{
+		{"sync" + sep + "identities" + sep + "key_0", small, true},
+		{"sync" + sep + "identities" + sep + "key_0", nil, true},
+		{"sync" + sep + "identities" + sep + "key_0", medium, true},
+		{"sync" + sep + "identities" + sep + "key_0", large, false},
+		{"sync" + sep + "identities" + sep + "deviceState_0", small, false},
+		{"sync" + sep + "identities" + sep + "messages" + sep + "key_0",
+			small, false},
+		{"sync" + sep + "backup" + sep + "key_0", small, false},
+		{"sync" + sep + "channels" + sep + "key_0", small, true},
+		{"sync" + sep + "channels" + sep + "key_0", medium, false},
+		{versioned.MakeElementKey(channelsMap, "element"), small, true},
+		{versioned.MakeElementKey(channelsMap, "element"), medium, false},
+		{versioned.MakeElementKey("sync"+sep+"channels"+sep+"members_0",
+			"element"), small, false},
+		{versioned.MakeElementKey("sync"+sep+"channels"+sep+"other_0",
+			"element"), small, false},
+	}
+
+	for i, tt := range tests {
+		require.Equal(t, tt.expected, policy.synchronizes(tt.key, tt.value),
+			"%d: %s", i, tt.key)
+	}
+
+	var nilPolicy *SyncPolicy
+	for _, tt := range tests {
+		require.True(t, nilPolicy.synchronizes(tt.key, tt.value), tt.key)
+	}
+}
+
+// Tests that remoteWriter.setPolicy removes the mutations the policy does not
+// synchronize from the Patch.
+func TestRemoteWriter_setPolicy(t *testing.T) {
+	txLog := makeTransactionLog(ekv.MakeMemstore(), "policy",
+		NewMockRemote(), NewCountingReader(), t)
+	txLog.state.AddUnsafe("small", NewMutate(time.Now(), []byte("a"), false))
+	txLog.state.AddUnsafe("large",
+		NewMutate(time.Now(), bytes.Repeat([]byte("a"), 32), false))
+
+	txLog.setPolicy(&SyncPolicy{MaxSize: 16})
+	require.Contains(t, txLog.state.keys, "small")
+	require.NotContains(t, txLog.state.keys, "large")
+}
+
+// Tests that the changes a SyncPolicy excludes are stored locally but are not
+// added to the transaction log, and that local-only keys are written directly
+// to the local store.
+func TestVersionedKV_SyncPolicy(t *testing.T) {
+	rkv, txLog := testingKV(t, ekv.MakeMemstore(), []string{"sync"},
+		NewMockRemote(), NewCountingReader())
+	txLog.setPolicy(&SyncPolicy{
+		ExcludedPrefixes: []string{"bulky"},
+		LocalOnly:        []string{"localOnly"},
+	})
+
+	stop, err := rkv.StartProcesses()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, stop.Close()) }()
+
+	syncKV, err := rkv.Prefix("sync")
+	require.NoError(t, err)
+	bulkyKV, err := syncKV.Prefix("bulky")
+	require.NoError(t, err)
+
+	obj := &versioned.Object{
+		Version:   0,
+		Timestamp: time.Now(),
+		Data:      []byte("data"),
+	}
+	require.NoError(t, syncKV.Set("synced", obj))
+	require.NoError(t, syncKV.Set("localOnly", obj))
+	require.NoError(t, bulkyKV.Set("excluded", obj))
+
+	// Read blocks until every pending write has been processed
+	patch, unlock := txLog.Read()
+	keys := make([]string, 0, len(patch.keys))
+	for key := range patch.keys {
+		keys = append(keys, key)
+	}
+	unlock()
+	require.Equal(t, []string{syncKV.GetFullKey("synced", 0)}, keys)
+
+	for kv, key := range map[versioned.KV]string{
+		syncKV: "synced", bulkyKV: "excluded"} {
+		loaded, err := kv.Get(key, 0)
+		require.NoError(t, err, key)
+		require.Equal(t, obj.Data, loaded.Data)
+	}
+	loaded, err := rkv.remote.GetBytes(syncKV.GetFullKey("localOnly", 0))
+	require.NoError(t, err)
+	require.Equal(t, obj.Marshal(), loaded)
+}
+
+// Tests that collector.applyChanges does not apply changes from other devices
+// that the SyncPolicy excludes.
+func TestCollector_applyChanges_SyncPolicy(t *testing.T) {
+	remoteStore := NewMockRemote()
+	syncPath := "policy"
+	sep := versioned.PrefixSeparator
+
+	other := makeTransactionLog(ekv.MakeMemstore(), syncPath, remoteStore,
+		rand.New(rand.NewSource(42)), t)
+	values := map[string][]byte{
+		"included":                     []byte("value"),
+		"large":                        bytes.Repeat([]byte("a"), 32),
+		"bulky" + sep + "excluded_0":   []byte("value"),
+		"other" + sep + "localOnly_0":  []byte("value"),
+		"other" + sep + "remoteOnly_0": []byte("value"),
+	}
+	for key, value := range values {
+		other.state.AddUnsafe(key,
+			NewMutate(time.Now(), value, false))
+	}
+	require.NoError(t, other.writePatch(other.path, other.state))
+
+	kv := ekv.MakeMemstore()
+	txLog := makeTransactionLog(kv, syncPath, remoteStore,
+		NewCountingReader(), t)
+	txLog.setPolicy(&SyncPolicy{
+		ExcludedPrefixes: []string{"bulky"},
+		MaxSize:          16,
+		LocalOnly:        []string{"localOnly"},
+	})
+	remoteKv := newVersionedKV(txLog, kv, nil)
+	col := newCollector(txLog.header.DeviceID, syncPath, remoteStore,
+		remoteKv.remote, txLog.encrypt, txLog)
+
+	_, err := col.collectAllChanges(
+		[]InstanceID{other.header.DeviceID, txLog.header.DeviceID})
+	require.NoError(t, err)
+	require.NoError(t, col.applyChanges())
+
+	for _, key := range []string{"included", "other" + sep + "remoteOnly_0"} {
+		val, err := remoteKv.remote.GetBytes(key)
+		require.NoError(t, err, key)
+		require.Equal(t, values[key], val, key)
+	}
+	for _, key := range []string{"large", "bulky" + sep + "excluded_0",
+		"other" + sep + "localOnly_0"} {
+		_, err = remoteKv.remote.GetBytes(key)
+		require.Error(t, err, key)
+	}
+}
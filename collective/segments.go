@@ -0,0 +1,286 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// Each device stores its transaction log on the remote as a compacted snapshot
+// of its full Patch, at the same path old clients write the full Patch to, and
+// a list of incremental segments that each hold the mutations made since the
+// previous upload. An index file lists the segments written after the latest
+// snapshot so that readers only download the segments they have not yet seen.
+//
+// Every compactionThreshold segments, and each time the writer starts, the
+// full Patch is written as a new snapshot and the segments are discarded.
+// Segment paths are reused after a compaction, so the number of files on the
+// remote stays bounded.
+//
+// Clients that do not support segments read only the snapshot, so they see
+// changes from clients that do at each compaction.
+
+// Segment constants.
+const (
+	// txLogIndexPathFmt is the path of the index file, relative to the sync
+	// path: [deviceid]/[keyid]/index.xx
+	txLogIndexPathFmt = "%s/%s/index.xx"
+
+	// txLogSegmentPathFmt is the path of a segment, relative to the sync path:
+	// [deviceid]/[keyid]/segment_[number].xx
+	txLogSegmentPathFmt = "%s/%s/segment_%d.xx"
+
+	toDiskIndexKeyName = "TransactionLogIndex_"
+
+	// defaultCompactionThreshold is the number of segments written before they
+	// are compacted into a new snapshot.
+	defaultCompactionThreshold = 32
+)
+
+// Error messages.
+const (
+	emptyIndexErr     = "index file is empty"
+	decodeIndexErr    = "failed to decode the index file: %+v"
+	decryptIndexErr   = "failed to decrypt the index: %+v"
+	unmarshalIndexErr = "failed to unmarshal the index: %+v"
+	readSegmentErr    = "failed to read segment %d of %s: %+v"
+)
+
+// patchIndex lists the snapshot and segments of a device's transaction log.
+// Times are timestamps from the writer's hybridClock, which are strictly
+// increasing for a device, in Unix nanoseconds.
+type patchIndex struct {
+	// Snapshot is when the snapshot was written. The snapshot contains every
+	// mutation made before this time.
+	Snapshot int64 `json:"snapshot"`
+
+	// Segments are when each segment written after the snapshot was written,
+	// in order. The segment at index i is stored in the segment_i file.
+	Segments []int64 `json:"segments"`
+}
+
+// lastWritten returns when the most recent snapshot or segment was written.
+func (pi *patchIndex) lastWritten() int64 {
+	if len(pi.Segments) > 0 {
+		return pi.Segments[len(pi.Segments)-1]
+	}
+	return pi.Snapshot
+}
+
+// upload writes the mutations added since the last upload to the remote as a
+// new segment. If compact is set or the compaction threshold has been reached,
+// the full Patch is written as a new snapshot instead.
+func (rw *remoteWriter) upload(compact bool) error {
+	if compact || len(rw.index.Segments) >= rw.compactionThreshold {
+		return rw.writeSnapshot()
+	}
+
+	if len(rw.pending.keys) == 0 {
+		return nil
+	}
+
+	written := rw.clock.Now()
+	segment := len(rw.index.Segments)
+	err := rw.writePatch(getTxLogSegmentPath(
+		rw.syncPath, rw.keyID, rw.header.DeviceID, segment), rw.pending)
+	if err != nil {
+		return err
+	}
+
+	segments := make([]int64, len(rw.index.Segments), segment+1)
+	copy(segments, rw.index.Segments)
+	index := &patchIndex{
+		Snapshot: rw.index.Snapshot,
+		Segments: append(segments, written),
+	}
+	if err = rw.writeIndex(index); err != nil {
+		return err
+	}
+
+	jww.DEBUG.Printf("Wrote segment %d of patch %s: %d",
+		segment, rw.header.DeviceID, len(rw.pending.keys))
+	rw.pending = newPatch(rw.header.DeviceID)
+	return nil
+}
+
+// writeSnapshot writes the full Patch to the remote and resets the index so
+// that it has no segments.
+func (rw *remoteWriter) writeSnapshot() error {
+	written := rw.clock.Now()
+	if err := rw.writePatch(rw.path, rw.state); err != nil {
+		return err
+	}
+
+	if err := rw.writeIndex(&patchIndex{Snapshot: written}); err != nil {
+		return err
+	}
+
+	jww.DEBUG.Printf("Wrote snapshot of patch %s: %d",
+		rw.header.DeviceID, len(rw.state.keys))
+	rw.pending = newPatch(rw.header.DeviceID)
+	return nil
+}
+
+// writePatch encrypts the patch and writes it to the remote at the path.
+func (rw *remoteWriter) writePatch(path string, p *Patch) error {
+	serial, err := p.Serialize()
+	if err != nil {
+		return err
+	}
+
+	return rw.io.Write(path, buildFile(rw.header, rw.encrypt.Encrypt(serial)))
+}
+
+// writeIndex encrypts the index and writes it to the remote, and then saves
+// it to local storage. The index is only written after the files it lists so
+// that readers never see a segment that does not exist yet.
+func (rw *remoteWriter) writeIndex(index *patchIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	err = rw.io.Write(
+		getTxLogIndexPath(rw.syncPath, rw.keyID, rw.header.DeviceID),
+		buildFile(rw.header, rw.encrypt.Encrypt(data)))
+	if err != nil {
+		return err
+	}
+
+	rw.index = index
+	if err = rw.kv.SetBytes(rw.localIndexKey, data); err != nil {
+		jww.WARN.Printf("Failed to save transaction log index to disk: "+
+			"%+v", err)
+	}
+
+	return nil
+}
+
+// loadIndex loads the index of the last upload from local storage. Returns an
+// empty index if none is found.
+func (rw *remoteWriter) loadIndex() *patchIndex {
+	index := &patchIndex{}
+	data, err := rw.kv.GetBytes(rw.localIndexKey)
+	if err != nil {
+		return index
+	}
+
+	if err = json.Unmarshal(data, index); err != nil {
+		jww.WARN.Printf("Failed to load transaction log index from disk: "+
+			"%+v", err)
+		return &patchIndex{}
+	}
+
+	return index
+}
+
+// readIndex reads and decrypts the index file of the device from the remote.
+// Returns an error if the device does not have one, which is the case for
+// clients that only write the full Patch.
+func (c *collector) readIndex(deviceID InstanceID, keyID string) (
+	*patchIndex, error) {
+	data, err := c.remote.Read(getTxLogIndexPath(c.syncPath, keyID, deviceID))
+	if err != nil {
+		return nil, err
+	} else if len(data) == 0 {
+		return nil, errors.New(emptyIndexErr)
+	}
+
+	_, encrypted, err := decodeFile(data)
+	if err != nil {
+		return nil, errors.Errorf(decodeIndexErr, err)
+	}
+
+	decrypted, err := c.encrypt.Decrypt(encrypted)
+	if err != nil {
+		return nil, errors.Errorf(decryptIndexErr, err)
+	}
+
+	index := &patchIndex{}
+	if err = json.Unmarshal(decrypted, index); err != nil {
+		return nil, errors.Errorf(unmarshalIndexErr, err)
+	}
+
+	return index, nil
+}
+
+// collectSegments returns the device's Patch updated with the segments written
+// since lastRead. The snapshot is only downloaded if segments that have not
+// been read were compacted into it. Returns when the last segment read was
+// written.
+func (c *collector) collectSegments(deviceID InstanceID, keyID string,
+	index *patchIndex, lastRead time.Time) (*Patch, time.Time, error) {
+	last := lastRead.UnixNano()
+
+	var patch *Patch
+	if last < index.Snapshot {
+		var err error
+		patch, err = c.readPatch(deviceID,
+			getTxLogPath(c.syncPath, keyID, deviceID))
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		last = index.Snapshot
+	} else {
+		patch = c.devicePatchTracker[deviceID].clone()
+	}
+
+	for i, written := range index.Segments {
+		if written <= last {
+			continue
+		}
+
+		segPath := getTxLogSegmentPath(c.syncPath, keyID, deviceID, i)
+		segment, err := c.readPatch(deviceID, segPath)
+		if err != nil {
+			return nil, time.Time{}, errors.Errorf(
+				readSegmentErr, i, deviceID, err)
+		}
+		patch.merge(segment)
+		last = written
+	}
+
+	return patch, time.Unix(0, last), nil
+}
+
+// readPatch reads and decrypts the patch file at the path.
+func (c *collector) readPatch(deviceID InstanceID, path string) (
+	*Patch, error) {
+	patchFile, err := c.remote.Read(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "path: %s", path)
+	}
+
+	_, patch, err := handleIncomingFile(deviceID, patchFile, c.encrypt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "path: %s", path)
+	}
+
+	return patch, nil
+}
+
+func makeLocalIndexKey(path string) string {
+	return toDiskIndexKeyName + path
+}
+
+func getTxLogIndexPath(syncPath, keyID string, deviceID InstanceID) string {
+	return filepath.Join(syncPath,
+		fmt.Sprintf(txLogIndexPathFmt, deviceID, keyID))
+}
+
+func getTxLogSegmentPath(
+	syncPath, keyID string, deviceID InstanceID, segment int) string {
+	return filepath.Join(syncPath,
+		fmt.Sprintf(txLogSegmentPathFmt, deviceID, keyID, segment))
+}
@@ -0,0 +1,166 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package collective
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/elixxir/ekv"
+)
+
+// Tests that remoteWriter.upload writes a snapshot when compacting, a segment
+// containing only the pending mutations otherwise, and a new snapshot once the
+// compaction threshold is reached.
+func TestRemoteWriter_upload(t *testing.T) {
+	remoteStore := NewMockRemote()
+	syncPath := "collector/"
+	txLog := makeTransactionLog(ekv.MakeMemstore(), syncPath, remoteStore,
+		NewCountingReader(), t)
+	txLog.compactionThreshold = 2
+	deviceID := txLog.header.DeviceID
+
+	addMutations(txLog, 0, 5)
+	require.NoError(t, txLog.upload(true))
+	require.Empty(t, txLog.index.Segments)
+	require.Empty(t, txLog.pending.keys)
+
+	addMutations(txLog, 5, 7)
+	require.NoError(t, txLog.upload(false))
+	require.Len(t, txLog.index.Segments, 1)
+	require.Greater(t, txLog.index.Segments[0], txLog.index.Snapshot)
+
+	segPath := getTxLogSegmentPath(syncPath, txLog.keyID, deviceID, 0)
+	_, segment, err := handleIncomingFile(
+		deviceID, remoteStore.data[segPath], txLog.encrypt)
+	require.NoError(t, err)
+	require.Len(t, segment.keys, 2)
+
+	// Nothing is written when there are no pending mutations
+	require.NoError(t, txLog.upload(false))
+	require.Len(t, txLog.index.Segments, 1)
+
+	addMutations(txLog, 7, 8)
+	require.NoError(t, txLog.upload(false))
+	require.Len(t, txLog.index.Segments, 2)
+
+	addMutations(txLog, 8, 9)
+	require.NoError(t, txLog.upload(false))
+	require.Empty(t, txLog.index.Segments)
+
+	_, snapshot, err := handleIncomingFile(
+		deviceID, remoteStore.data[txLog.path], txLog.encrypt)
+	require.NoError(t, err)
+	require.Len(t, snapshot.keys, 9)
+
+	// The index is loaded from disk
+	require.Equal(t, txLog.index, txLog.loadIndex())
+}
+
+// Tests that collector.collectChanges reads the snapshot and all segments on
+// the first read, and only the new segments after that.
+func TestCollector_collectChanges_Segments(t *testing.T) {
+	remoteStore := NewMockRemote()
+	syncPath := "collector/"
+	txLog := makeTransactionLog(ekv.MakeMemstore(), syncPath, remoteStore,
+		NewCountingReader(), t)
+	deviceID := txLog.header.DeviceID
+
+	addMutations(txLog, 0, 5)
+	require.NoError(t, txLog.upload(true))
+	addMutations(txLog, 5, 7)
+	require.NoError(t, txLog.upload(false))
+
+	c := &collector{
+		syncPath:           syncPath,
+		remote:             remoteStore,
+		encrypt:            txLog.encrypt,
+		lastUpdateRead:     map[InstanceID]time.Time{deviceID: time.Unix(0, 0)},
+		devicePatchTracker: map[InstanceID]*Patch{deviceID: newPatch(deviceID)},
+	}
+
+	patch, lastRead, err := c.collectChanges(deviceID)
+	require.NoError(t, err)
+	require.Equal(t, txLog.state.keys, patch.keys)
+	require.Equal(t, txLog.index.lastWritten(), lastRead.UnixNano())
+	c.devicePatchTracker[deviceID] = patch
+	c.lastUpdateRead[deviceID] = lastRead
+
+	// Corrupt the snapshot and first segment so that reading them fails
+	remoteStore.data[txLog.path] = []byte("corrupt")
+	remoteStore.data[getTxLogSegmentPath(
+		syncPath, txLog.keyID, deviceID, 0)] = []byte("corrupt")
+
+	addMutations(txLog, 7, 10)
+	require.NoError(t, txLog.upload(false))
+
+	patch, lastRead, err = c.collectChanges(deviceID)
+	require.NoError(t, err)
+	require.Equal(t, txLog.state.keys, patch.keys)
+	require.Equal(t, txLog.index.lastWritten(), lastRead.UnixNano())
+}
+
+// Tests that collector.collectChanges reads the full patch of a device that
+// does not write an index only when it has been modified since the last read.
+func TestCollector_collectChanges_NoIndex(t *testing.T) {
+	remoteStore := NewMockRemote()
+	syncPath := "collector/"
+	txLog := makeTransactionLog(ekv.MakeMemstore(), syncPath, remoteStore,
+		NewCountingReader(), t)
+	deviceID := txLog.header.DeviceID
+
+	addMutations(txLog, 0, 5)
+	require.NoError(t, txLog.writePatch(txLog.path, txLog.state))
+
+	c := &collector{
+		syncPath:           syncPath,
+		remote:             remoteStore,
+		encrypt:            txLog.encrypt,
+		lastUpdateRead:     map[InstanceID]time.Time{deviceID: time.Unix(0, 0)},
+		devicePatchTracker: map[InstanceID]*Patch{deviceID: newPatch(deviceID)},
+	}
+
+	patch, lastRead, err := c.collectChanges(deviceID)
+	require.NoError(t, err)
+	require.Equal(t, txLog.state.keys, patch.keys)
+	lastModified, err := remoteStore.GetLastModified(txLog.path)
+	require.NoError(t, err)
+	require.Equal(t, lastModified, lastRead)
+	c.devicePatchTracker[deviceID] = patch
+	c.lastUpdateRead[deviceID] = lastRead
+
+	// The patch is not read again until it is modified
+	remoteStore.data[txLog.path] = []byte("corrupt")
+	patch, lastRead, err = c.collectChanges(deviceID)
+	require.NoError(t, err)
+	require.Equal(t, txLog.state.keys, patch.keys)
+	require.Equal(t, lastModified, lastRead)
+
+	addMutations(txLog, 5, 7)
+	require.NoError(t, txLog.writePatch(txLog.path, txLog.state))
+	patch, lastRead, err = c.collectChanges(deviceID)
+	require.NoError(t, err)
+	require.Equal(t, txLog.state.keys, patch.keys)
+	require.True(t, lastRead.After(lastModified))
+}
+
+// addMutations adds a mutation for the keys in the range [start, end) to the
+// state and pending patches of the remoteWriter.
+func addMutations(rw *remoteWriter, start, end int) {
+	for i := start; i < end; i++ {
+		key := fmt.Sprintf("key%d", i)
+		m := Mutate{
+			Timestamp: rw.clock.Now(),
+			Value:     []byte(fmt.Sprintf("value%d", i)),
+		}
+		rw.state.AddUnsafe(key, m)
+		rw.pending.AddUnsafe(key, m)
+	}
+}
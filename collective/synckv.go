@@ -51,13 +51,23 @@ type versionedKV struct {
 }
 
 // CloneFromRemoteStorage copies state from RemoteStore and
-// instantiates a SynchronizedKV
+// instantiates a SynchronizedKV.
 func CloneFromRemoteStorage(remoteStoragePathPrefix string, deviceSecret []byte,
 	remote RemoteStore, kv ekv.KeyValue,
 	rng *fastRNG.StreamGenerator) (*versionedKV, error) {
+	return CloneFromRemoteStorageWithPolicy(remoteStoragePathPrefix,
+		deviceSecret, remote, kv, nil, rng)
+}
+
+// CloneFromRemoteStorageWithPolicy copies state from RemoteStore and
+// instantiates a SynchronizedKV. Only the state selected by the policy is
+// copied; a nil policy copies everything.
+func CloneFromRemoteStorageWithPolicy(remoteStoragePathPrefix string,
+	deviceSecret []byte, remote RemoteStore, kv ekv.KeyValue,
+	policy *SyncPolicy, rng *fastRNG.StreamGenerator) (*versionedKV, error) {
 
-	rkv, err := SynchronizedKV(remoteStoragePathPrefix, deviceSecret, remote, kv,
-		nil, rng)
+	rkv, err := SynchronizedKVWithPolicy(remoteStoragePathPrefix, deviceSecret,
+		remote, kv, nil, policy, rng)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +81,18 @@ func CloneFromRemoteStorage(remoteStoragePathPrefix string, deviceSecret []byte,
 func SynchronizedKV(remoteStoragePathPrefix string, deviceSecret []byte,
 	remote RemoteStore, kv ekv.KeyValue, synchedPrefixes []string,
 	rng *fastRNG.StreamGenerator) (*versionedKV, error) {
+	return SynchronizedKVWithPolicy(remoteStoragePathPrefix, deviceSecret,
+		remote, kv, synchedPrefixes, nil, rng)
+}
+
+// SynchronizedKVWithPolicy loads or creates a synchronized remote KV that
+// uses a remote RemoteStore to store defined synchronization prefixes to the
+// network. The policy selects which keys under those prefixes are
+// synchronized; a nil policy synchronizes all of them.
+func SynchronizedKVWithPolicy(remoteStoragePathPrefix string,
+	deviceSecret []byte, remote RemoteStore, kv ekv.KeyValue,
+	synchedPrefixes []string, policy *SyncPolicy,
+	rng *fastRNG.StreamGenerator) (*versionedKV, error) {
 
 	rngStream := rng.GetStream()
 	defer rngStream.Close()
@@ -89,11 +111,21 @@ func SynchronizedKV(remoteStoragePathPrefix string, deviceSecret []byte,
 		rngGen: rng,
 	}
 
+	// Loads the shared keys into crypt, so it must be done before the writer
+	// is created
+	devices, err := newDeviceManager(deviceID, remoteStoragePathPrefix,
+		remote, crypt, kv)
+	if err != nil {
+		return nil, err
+	}
+
 	txLog, err := newRemoteWriter(remoteStoragePathPrefix, deviceID, remote,
 		crypt, kv)
 	if err != nil {
 		return nil, err
 	}
+	devices.onRekey = txLog.requestCompaction
+	txLog.setPolicy(policy)
 
 	vkv := newVersionedKV(txLog, kv, synchedPrefixes)
 
@@ -108,6 +140,7 @@ func SynchronizedKV(remoteStoragePathPrefix string, deviceSecret []byte,
 	// instantiated needs a rework.
 	vkv.remote.col = newCollector(deviceID, remoteStoragePathPrefix,
 		remote, vkv.remote, crypt, txLog)
+	vkv.remote.col.devices = devices
 
 	return vkv, nil
 }
@@ -205,7 +238,7 @@ func (r *versionedKV) Delete(key string, version uint64) error {
 // maintaining such a functionality.
 func (r *versionedKV) Set(key string, object *versioned.Object) error {
 	k := r.local.GetFullKey(key, object.Version)
-	if r.inSynchronizedPrefix {
+	if r.inSynchronizedPrefix && !r.remote.txLog.policy.isLocalOnly(k) {
 		jww.INFO.Printf("Setting Remote: %s", k)
 		return r.remote.SetRemote(k, object.Marshal())
 	}
@@ -68,9 +68,26 @@ type remoteWriter struct {
 	// timestamp.
 	state *Patch
 
+	// pending contains the mutations added since the last upload, which are
+	// written to the remote as the next segment
+	pending *Patch
+
+	// index lists the snapshot and segments on the remote
+	index               *patchIndex
+	localIndexKey       string
+	compactionThreshold int
+
+	// the sync path and key ID used to build the segment and index paths
+	syncPath string
+	keyID    string
+
 	//channel over which writes started localy are processed
 	adds chan transaction
 
+	// signals that the full Patch must be uploaded as a new snapshot, such
+	// as after the shared key changes
+	compactRequests chan struct{}
+
 	// call to Write to remote
 	io FileIO
 
@@ -93,6 +110,13 @@ type remoteWriter struct {
 	*notifier
 
 	mb *mutateBuffer
+
+	// clock timestamps every mutation written by this device
+	clock *hybridClock
+
+	// policy selects which mutations are uploaded. All mutations are
+	// uploaded if it is nil.
+	policy *SyncPolicy
 }
 
 type transaction struct {
@@ -124,17 +148,24 @@ func newRemoteWriter(path string, deviceID InstanceID,
 	connected := uint32(0)
 	// Construct a new mutate log
 	tx := &remoteWriter{
-		path:           myPath,
-		header:         newHeader(deviceID),
-		state:          newPatch(deviceID),
-		adds:           make(chan transaction, bufferSize),
-		io:             io,
-		encrypt:        encrypt,
-		kv:             kv,
-		localWriteKey:  makeLocalWriteKey(path),
-		remoteUpToDate: &connected,
-		notifier:       &notifier{},
-		uploadPeriod:   defaultUploadPeriod,
+		path:                myPath,
+		header:              newHeader(deviceID),
+		state:               newPatch(deviceID),
+		pending:             newPatch(deviceID),
+		localIndexKey:       makeLocalIndexKey(path),
+		compactionThreshold: defaultCompactionThreshold,
+		syncPath:            path,
+		keyID:               logKeyID,
+		adds:                make(chan transaction, bufferSize),
+		compactRequests:     make(chan struct{}, 1),
+		io:                  io,
+		encrypt:             encrypt,
+		kv:                  kv,
+		localWriteKey:       makeLocalWriteKey(path),
+		remoteUpToDate:      &connected,
+		notifier:            &notifier{},
+		uploadPeriod:        defaultUploadPeriod,
+		clock:               &hybridClock{},
 	}
 
 	// Attempt to Read stored mutate log
@@ -148,6 +179,9 @@ func newRemoteWriter(path string, deviceID InstanceID,
 	} else {
 		jww.WARN.Printf("No transaction log found, creating a new one")
 	}
+	tx.index = tx.loadIndex()
+	tx.clock.Observe(tx.state.lastTimestamp())
+	tx.clock.Observe(tx.index.lastWritten())
 
 	//attempt to load stored mutateBuffer and handle any extant data
 	mb, remainingMutations := loadBuffer(kv)
@@ -156,6 +190,9 @@ func newRemoteWriter(path string, deviceID InstanceID,
 		for index := range remainingMutations {
 			tx.syncLock.RLock()
 			mutations := remainingMutations[index]
+			for _, m := range mutations {
+				tx.clock.Observe(m.Timestamp)
+			}
 			tx.adds <- transaction{
 				Mutate:    mutations,
 				BuffIndex: index,
@@ -170,13 +207,10 @@ func newRemoteWriter(path string, deviceID InstanceID,
 func (rw *remoteWriter) Runner(s *stoppable.Single) {
 	jww.INFO.Printf("[SYNC] started transaction log (remoteWriter) thread")
 
-	//always Write to remote when we start in order to ensure that any
-	//dropped updates are propogated
+	//always Write a snapshot to remote when we start in order to ensure that
+	//any dropped updates are propogated
 	timer := time.NewTimer(time.Nanosecond)
-	serial, err := rw.state.Serialize()
-	if err != nil {
-		jww.FATAL.Panicf("Failed to serialize transaction: %+v", err)
-	}
+	compact := true
 	running := true
 	var ts time.Time
 	uploadPeriod := rw.uploadPeriod
@@ -185,12 +219,17 @@ func (rw *remoteWriter) Runner(s *stoppable.Single) {
 		case t := <-rw.adds:
 
 			for key, mutate := range t.Mutate {
+				if !rw.policy.synchronizes(key, mutate.Value) {
+					jww.DEBUG.Printf("Not synchronizing change for %s", key)
+					continue
+				}
 				jww.INFO.Printf("Adding change for %s", key)
 				rw.state.AddUnsafe(key, mutate)
+				rw.pending.AddUnsafe(key, mutate)
 			}
 
 			// Write to disk and queue the remote Write
-			serial, err = rw.state.Serialize()
+			serial, err := rw.state.Serialize()
 			if err != nil {
 				jww.FATAL.Panicf("failed to serialize transaction "+
 					"log: %+v", err)
@@ -208,12 +247,17 @@ func (rw *remoteWriter) Runner(s *stoppable.Single) {
 				running = true
 			}
 
+		case <-rw.compactRequests:
+			compact = true
+			if !running {
+				timer = time.NewTimer(time.Nanosecond)
+				running = true
+			}
+
 		case <-timer.C:
 			running = false
-			encrypted := rw.encrypt.Encrypt(serial)
-			file := buildFile(rw.header, encrypted)
 
-			if err = rw.io.Write(rw.path, file); err != nil {
+			if err := rw.upload(compact); err != nil {
 				rw.notify(false)
 				uploadPeriod = expBackoff(uploadPeriod)
 				jww.ERROR.Printf("Failed to update collective state, "+
@@ -222,9 +266,13 @@ func (rw *remoteWriter) Runner(s *stoppable.Single) {
 				timer = time.NewTimer(rw.uploadPeriod)
 				running = true
 			} else {
-				jww.DEBUG.Printf("Wrote patch %s: %d",
-					rw.header.DeviceID, len(rw.state.keys))
-				rw.notify(true)
+				compact = false
+				// Only report that the remote is up to date once every
+				// buffered mutation has been uploaded. Otherwise, the next
+				// add starts the timer for another upload.
+				if len(rw.adds) == 0 {
+					rw.notify(true)
+				}
 				uploadPeriod = defaultUploadPeriod
 				ts = netTime.Now()
 				timer.Stop()
@@ -251,10 +299,8 @@ func (rw *remoteWriter) Write(key string, value []byte) (
 	// be unlocked when the transaction is written to disk
 	rw.syncLock.RLock()
 
-	ts := netTime.Now()
-
 	m := Mutate{
-		Timestamp: ts.UTC().UnixNano(),
+		Timestamp: rw.clock.Now(),
 		Value:     value,
 		Deletion:  false,
 	}
@@ -294,8 +340,7 @@ func (rw *remoteWriter) WriteMap(mapName string,
 	// be unlocked when the transaction is written to disk
 	rw.syncLock.RLock()
 
-	ts := netTime.Now()
-	tsInt := ts.UTC().UnixNano()
+	tsInt := rw.clock.Now()
 
 	//build handling data
 	keys := make([]string, 0, len(elements)+1)
@@ -400,10 +445,8 @@ func (rw *remoteWriter) Delete(key string) (
 	// be unlocked when the transaction is written to disk
 	rw.syncLock.RLock()
 
-	ts := netTime.Now()
-
 	m := Mutate{
-		Timestamp: ts.UTC().UnixNano(),
+		Timestamp: rw.clock.Now(),
 		Value:     nil,
 		Deletion:  true,
 	}
@@ -440,6 +483,15 @@ func (rw *remoteWriter) Read() (patch *Patch, unlock func()) {
 	return patch, unlock
 }
 
+// requestCompaction causes the Runner to upload the full Patch as a new
+// snapshot as soon as possible. It does not block.
+func (rw *remoteWriter) requestCompaction() {
+	select {
+	case rw.compactRequests <- struct{}{}:
+	default:
+	}
+}
+
 func (rw *remoteWriter) RemoteUpToDate() bool {
 	return atomic.LoadUint32(rw.remoteUpToDate) == 1
 }
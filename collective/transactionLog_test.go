@@ -65,18 +65,26 @@ func TestNewOrLoadTransactionLog(t *testing.T) {
 	logPath := getTxLogPath(logFile, crypt.KeyID(deviceID), deviceID)
 	// Construct expected mutate log object
 	expected := &remoteWriter{
-		path:           logPath,
-		header:         newHeader(deviceID),
-		state:          newPatch(deviceID),
-		adds:           txLog.adds, // hack, but new chan won't work
-		io:             remoteStore,
-		encrypt:        crypt,
-		kv:             fs,
-		localWriteKey:  makeLocalWriteKey(logFile),
-		remoteUpToDate: &zero,
-		notifier:       &notifier{},
-		uploadPeriod:   defaultUploadPeriod,
-		mb:             emptyMB,
+		path:                logPath,
+		header:              newHeader(deviceID),
+		state:               newPatch(deviceID),
+		pending:             newPatch(deviceID),
+		index:               &patchIndex{},
+		localIndexKey:       makeLocalIndexKey(logFile),
+		compactionThreshold: defaultCompactionThreshold,
+		syncPath:            logFile,
+		keyID:               crypt.KeyID(deviceID),
+		adds:                txLog.adds, // hack, but new chan won't work
+		compactRequests:     txLog.compactRequests,
+		io:                  remoteStore,
+		encrypt:             crypt,
+		kv:                  fs,
+		localWriteKey:       makeLocalWriteKey(logFile),
+		remoteUpToDate:      &zero,
+		notifier:            &notifier{},
+		uploadPeriod:        defaultUploadPeriod,
+		mb:                  emptyMB,
+		clock:               &hybridClock{},
 	}
 
 	// Ensure constructor generates expected object
@@ -125,10 +133,8 @@ func TestNewOrLoadTransactionLog_Loading(t *testing.T) {
 	ntfy := func(state bool) { ntfyCh <- state }
 	txLog.Register(ntfy)
 
-	stopper := stoppable.NewSingle("txLogRunner")
-	go txLog.Runner(stopper)
-
-	// Insert timestamps
+	// Insert timestamps before starting the Runner so that the remote is only
+	// reported up to date once they have all been uploaded
 	for cnt := 0; cnt < 10; cnt++ {
 		// Construct mutate
 		key, val := "key"+strconv.Itoa(cnt), "val"+strconv.Itoa(cnt)
@@ -136,6 +142,9 @@ func TestNewOrLoadTransactionLog_Loading(t *testing.T) {
 		require.NoError(t, err)
 	}
 
+	stopper := stoppable.NewSingle("txLogRunner")
+	go txLog.Runner(stopper)
+
 	done := false
 	for !done {
 		select {
@@ -164,6 +173,7 @@ func TestNewOrLoadTransactionLog_Loading(t *testing.T) {
 	newTxLog.notifier = txLog.notifier
 	newTxLog.remoteUpToDate = txLog.remoteUpToDate
 	newTxLog.uploadPeriod = txLog.uploadPeriod
+	newTxLog.compactRequests = txLog.compactRequests
 
 	// reset index in mb
 	mb, transactions := loadBuffer(fs)
@@ -204,7 +214,7 @@ func TestTransactionLog_Serialize(t *testing.T) {
 	// Encode data to bas64
 	data64 := base64.RawStdEncoding.EncodeToString(data)
 
-	expected := "eyJrZXkwIjp7IlRpbWVzdGFtcCI6MTQ1MDczNTcyMSwiVmFsdWUiOiJkbUZzTUE9PSIsIkRlbGV0aW9uIjpmYWxzZX0sImtleTEiOnsiVGltZXN0YW1wIjoxMzg3NjYzNzIxLCJWYWx1ZSI6ImRtRnNNUT09IiwiRGVsZXRpb24iOmZhbHNlfSwia2V5MiI6eyJUaW1lc3RhbXAiOjEwNzIwNDQ1MjEsIlZhbHVlIjoiZG1Gc01nPT0iLCJEZWxldGlvbiI6ZmFsc2V9LCJrZXkzIjp7IlRpbWVzdGFtcCI6MTM1NjEyNzcyMSwiVmFsdWUiOiJkbUZzTXc9PSIsIkRlbGV0aW9uIjpmYWxzZX0sImtleTQiOnsiVGltZXN0YW1wIjoxNDE5MTk5NzIxLCJWYWx1ZSI6ImRtRnNOQT09IiwiRGVsZXRpb24iOmZhbHNlfSwia2V5NSI6eyJUaW1lc3RhbXAiOjEwMDg5NzI1MjEsIlZhbHVlIjoiZG1Gc05RPT0iLCJEZWxldGlvbiI6ZmFsc2V9fQ"
+	expected := "eyJrZXkwIjp7IlRpbWVzdGFtcCI6MTQ1MDczNTcyMTAwMDAwMDAwMCwiVmFsdWUiOiJkbUZzTUE9PSIsIkRlbGV0aW9uIjpmYWxzZX0sImtleTEiOnsiVGltZXN0YW1wIjoxMzg3NjYzNzIxMDAwMDAwMDAwLCJWYWx1ZSI6ImRtRnNNUT09IiwiRGVsZXRpb24iOmZhbHNlfSwia2V5MiI6eyJUaW1lc3RhbXAiOjEwNzIwNDQ1MjEwMDAwMDAwMDAsIlZhbHVlIjoiZG1Gc01nPT0iLCJEZWxldGlvbiI6ZmFsc2V9LCJrZXkzIjp7IlRpbWVzdGFtcCI6MTM1NjEyNzcyMTAwMDAwMDAwMCwiVmFsdWUiOiJkbUZzTXc9PSIsIkRlbGV0aW9uIjpmYWxzZX0sImtleTQiOnsiVGltZXN0YW1wIjoxNDE5MTk5NzIxMDAwMDAwMDAwLCJWYWx1ZSI6ImRtRnNOQT09IiwiRGVsZXRpb24iOmZhbHNlfSwia2V5NSI6eyJUaW1lc3RhbXAiOjEwMDg5NzI1MjEwMDAwMDAwMDAsIlZhbHVlIjoiZG1Gc05RPT0iLCJEZWxldGlvbiI6ZmFsc2V9fQ"
 
 	// Ensure encoded data using mock values matches hardcoded data.
 	require.Equal(t, expected, data64)
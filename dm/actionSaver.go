@@ -152,6 +152,15 @@ func getMessageIdKey(msgID message.ID) messageIdKey {
 	return messageIdKey(base64.StdEncoding.EncodeToString(msgID.Marshal()))
 }
 
+// unmarshalMessageIdKey returns the message.ID encoded in the messageIdKey.
+func unmarshalMessageIdKey(key messageIdKey) (message.ID, error) {
+	data, err := base64.StdEncoding.DecodeString(string(key))
+	if err != nil {
+		return message.ID{}, err
+	}
+	return message.UnmarshalID(data)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Storage Functions                                                          //
 ////////////////////////////////////////////////////////////////////////////////
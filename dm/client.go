@@ -21,6 +21,7 @@ import (
 
 	"gitlab.com/elixxir/client/v4/cmix/identity"
 	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
 	"gitlab.com/elixxir/crypto/codename"
 	"gitlab.com/elixxir/crypto/fastRNG"
 	"gitlab.com/elixxir/crypto/nike"
@@ -41,9 +42,14 @@ type dmClient struct {
 	myToken         uint32
 	receiver        EventModel
 
-	st SendTracker
-	nm NickNameManager
-	ps *partnerStore
+	st  SendTracker
+	nm  NickNameManager
+	ps  *partnerStore
+	rms *readMarkerStore
+	dms *disappearingStore
+	em  *ephemeralManager
+	gs  *groupStore
+	mrs *messageRequestStore
 	*notifications
 	as  *ActionSaver
 	net cMixClient
@@ -102,6 +108,32 @@ func newDmClient(myID *codename.PrivateIdentity, receiver EventModel,
 		return nil, err
 	}
 
+	rms, err := newReadMarkerStore(
+		kv, updateReadMarker(receiver, cbs.ReadMarkerUpdate))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load DM read markers")
+	}
+
+	dms, err := newOrLoadDisappearingStore(kv, receiver.DeleteMessage)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load DM disappearing messages")
+	}
+
+	em, err := newOrLoadEphemeralManager(kv, cbs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load DM presence setting")
+	}
+
+	gs, err := newOrLoadGroupStore(kv, receiver)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load DM group conversations")
+	}
+
+	mrs, err := newOrLoadMessageRequestStore(kv, cbs.MessageRequestUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load DM message requests")
+	}
+
 	dmc := &dmClient{
 		me:              myID,
 		selfReceptionID: selfReceptionID,
@@ -113,6 +145,11 @@ func newDmClient(myID *codename.PrivateIdentity, receiver EventModel,
 		st:              tracker,
 		nm:              nickManager,
 		ps:              ps,
+		rms:             rms,
+		dms:             dms,
+		em:              em,
+		gs:              gs,
+		mrs:             mrs,
 		notifications:   n,
 		as:              NewActionSaver(kv),
 		net:             net,
@@ -250,6 +287,20 @@ func (dc *dmClient) GetBlockedPartners() []ed25519.PublicKey {
 	return blockedPartners
 }
 
+// GetDisappearingTimer returns the duration after which messages in the
+// conversation with the partner are deleted. Returns zero if disappearing
+// messages are disabled.
+func (dc *dmClient) GetDisappearingTimer(
+	partnerPubKey ed25519.PublicKey) time.Duration {
+	return dc.dms.getTimer(partnerPubKey)
+}
+
+// StartProcesses starts the thread that deletes expired disappearing messages.
+// This function adheres to the [xxdk.Service] type.
+func (dc *dmClient) StartProcesses() (stoppable.Stoppable, error) {
+	return dc.dms.StartProcesses()
+}
+
 // ExportPrivateIdentity encrypts and exports the private identity to a portable
 // string.
 func (dc *dmClient) ExportPrivateIdentity(password string) ([]byte, error) {
@@ -299,3 +350,11 @@ func (dcb *dummyCallback) NotificationUpdate(
 }
 func (dcb *dummyCallback) BlockedUser(ed25519.PublicKey, bool) {
 }
+func (dcb *dummyCallback) ReadMarkerUpdate(ed25519.PublicKey, time.Time) {
+}
+func (dcb *dummyCallback) TypingUpdate(ed25519.PublicKey, bool, time.Time) {
+}
+func (dcb *dummyCallback) PresenceUpdate(ed25519.PublicKey, bool, time.Time) {
+}
+func (dcb *dummyCallback) MessageRequestUpdate(ed25519.PublicKey, bool) {
+}
@@ -456,6 +456,260 @@ func (x *DirectMessage) GetLocalTimestamp() int64 {
 	return 0
 }
 
+// DisappearingMessages is the payload for a Disappearing MessageType. It sets
+// the duration after which messages in the conversation are deleted. A timer
+// of zero disables disappearing messages.
+type DisappearingMessages struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Timer   int64  `protobuf:"varint,2,opt,name=timer,proto3" json:"timer,omitempty"`
+}
+
+func (x *DisappearingMessages) Reset() {
+	*x = DisappearingMessages{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_directMessages_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisappearingMessages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisappearingMessages) ProtoMessage() {}
+
+func (x *DisappearingMessages) ProtoReflect() protoreflect.Message {
+	mi := &file_directMessages_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisappearingMessages.ProtoReflect.Descriptor instead.
+func (*DisappearingMessages) Descriptor() ([]byte, []int) {
+	return file_directMessages_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DisappearingMessages) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DisappearingMessages) GetTimer() int64 {
+	if x != nil {
+		return x.Timer
+	}
+	return 0
+}
+
+// TypingMessage is the payload for a Typing MessageType. It is an ephemeral
+// signal that the sender started or stopped typing in the conversation.
+type TypingMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Typing  bool   `protobuf:"varint,2,opt,name=typing,proto3" json:"typing,omitempty"`
+}
+
+func (x *TypingMessage) Reset() {
+	*x = TypingMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_directMessages_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypingMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypingMessage) ProtoMessage() {}
+
+func (x *TypingMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_directMessages_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypingMessage.ProtoReflect.Descriptor instead.
+func (*TypingMessage) Descriptor() ([]byte, []int) {
+	return file_directMessages_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TypingMessage) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *TypingMessage) GetTyping() bool {
+	if x != nil {
+		return x.Typing
+	}
+	return false
+}
+
+// PresenceMessage is the payload for a Presence MessageType. It is an ephemeral
+// signal that the sender came online or went offline.
+type PresenceMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Online  bool   `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+}
+
+func (x *PresenceMessage) Reset() {
+	*x = PresenceMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_directMessages_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PresenceMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceMessage) ProtoMessage() {}
+
+func (x *PresenceMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_directMessages_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceMessage.ProtoReflect.Descriptor instead.
+func (*PresenceMessage) Descriptor() ([]byte, []int) {
+	return file_directMessages_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PresenceMessage) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PresenceMessage) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+// GroupMessage is the payload for a Group MessageType. It wraps a message sent
+// to every member of a group DM conversation. The conversation is identified
+// by its members.
+type GroupMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version       uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	MemberPubKeys [][]byte `protobuf:"bytes,2,rep,name=memberPubKeys,proto3" json:"memberPubKeys,omitempty"`
+	MemberTokens  []uint32 `protobuf:"varint,3,rep,packed,name=memberTokens,proto3" json:"memberTokens,omitempty"`
+	PayloadType   uint32   `protobuf:"varint,4,opt,name=payloadType,proto3" json:"payloadType,omitempty"`
+	Payload       []byte   `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (x *GroupMessage) Reset() {
+	*x = GroupMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_directMessages_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupMessage) ProtoMessage() {}
+
+func (x *GroupMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_directMessages_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupMessage.ProtoReflect.Descriptor instead.
+func (*GroupMessage) Descriptor() ([]byte, []int) {
+	return file_directMessages_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GroupMessage) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GroupMessage) GetMemberPubKeys() [][]byte {
+	if x != nil {
+		return x.MemberPubKeys
+	}
+	return nil
+}
+
+func (x *GroupMessage) GetMemberTokens() []uint32 {
+	if x != nil {
+		return x.MemberTokens
+	}
+	return nil
+}
+
+func (x *GroupMessage) GetPayloadType() uint32 {
+	if x != nil {
+		return x.PayloadType
+	}
+	return 0
+}
+
+func (x *GroupMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
 var File_directMessages_proto protoreflect.FileDescriptor
 
 var file_directMessages_proto_rawDesc = []byte{
@@ -505,9 +759,33 @@ var file_directMessages_proto_rawDesc = []byte{
 	0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x26, 0x0a,
 	0x0e, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
 	0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x1e, 0x5a, 0x1c, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x69, 0x78, 0x78, 0x69, 0x72, 0x2f, 0x63, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x2f, 0x64, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x46, 0x0a, 0x14, 0x44, 0x69, 0x73, 0x61, 0x70, 0x70, 0x65,
+	0x61, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x22, 0x41, 0x0a,
+	0x0d, 0x54, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x79, 0x70, 0x69,
+	0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x74, 0x79, 0x70, 0x69, 0x6e, 0x67,
+	0x22, 0x43, 0x0a, 0x0f, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6f,
+	0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0xae, 0x01, 0x0a, 0x0c, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x50,
+	0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0c, 0x6d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0b, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x1e, 0x5a, 0x1c, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x69, 0x78, 0x78, 0x69, 0x72, 0x2f, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x2f, 0x64, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -522,14 +800,18 @@ func file_directMessages_proto_rawDescGZIP() []byte {
 	return file_directMessages_proto_rawDescData
 }
 
-var file_directMessages_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_directMessages_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_directMessages_proto_goTypes = []interface{}{
-	(*Text)(nil),              // 0: dm.Text
-	(*Reaction)(nil),          // 1: dm.Reaction
-	(*ChannelInvitation)(nil), // 2: dm.ChannelInvitation
-	(*SilentMessage)(nil),     // 3: dm.SilentMessage
-	(*DeleteMessage)(nil),     // 4: dm.DeleteMessage
-	(*DirectMessage)(nil),     // 5: dm.DirectMessage
+	(*Text)(nil),                 // 0: dm.Text
+	(*Reaction)(nil),             // 1: dm.Reaction
+	(*ChannelInvitation)(nil),    // 2: dm.ChannelInvitation
+	(*SilentMessage)(nil),        // 3: dm.SilentMessage
+	(*DeleteMessage)(nil),        // 4: dm.DeleteMessage
+	(*DirectMessage)(nil),        // 5: dm.DirectMessage
+	(*DisappearingMessages)(nil), // 6: dm.DisappearingMessages
+	(*TypingMessage)(nil),        // 7: dm.TypingMessage
+	(*PresenceMessage)(nil),      // 8: dm.PresenceMessage
+	(*GroupMessage)(nil),         // 9: dm.GroupMessage
 }
 var file_directMessages_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -617,6 +899,54 @@ func file_directMessages_proto_init() {
 				return nil
 			}
 		}
+		file_directMessages_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisappearingMessages); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_directMessages_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypingMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_directMessages_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresenceMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_directMessages_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -624,7 +954,7 @@ func file_directMessages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_directMessages_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
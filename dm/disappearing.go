@@ -0,0 +1,399 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/stoppable"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+const (
+	// Thread stoppable name
+	disappearingPurgeThreadStoppable = "DisappearingMessagePurgeThread"
+
+	// disappearingPurgeFrequency is how often expired messages are deleted.
+	disappearingPurgeFrequency = time.Minute
+)
+
+// Storage values.
+const (
+	disappearingTimersVer        = 0
+	disappearingTimersKey        = "dmDisappearingTimers"
+	disappearingMessageListVer   = 0
+	disappearingMessageListKey   = "dmDisappearingMessageList"
+	disappearingMessageVer       = 0
+	disappearingMessageKeyPrefix = "dmDisappearingMessage/"
+)
+
+// isDisappearingType returns true if messages of the given MessageType are
+// deleted when disappearing messages are enabled in the conversation.
+func isDisappearingType(mt MessageType) bool {
+	switch mt {
+	case TextType, ReplyType, ReactionType, InvitationType:
+		return true
+	default:
+		return false
+	}
+}
+
+// disappearingTimer is the disappearing message setting of a conversation.
+type disappearingTimer struct {
+	// Timer is the duration after which messages are deleted. Disappearing
+	// messages are disabled when it is zero.
+	Timer time.Duration `json:"timer"`
+
+	// Updated is the timestamp of the message that set the timer. Used to
+	// ignore settings received out of order.
+	Updated time.Time `json:"updated"`
+}
+
+// expiringMessage is a message that will be deleted from the EventModel once
+// it expires.
+type expiringMessage struct {
+	SenderPubKey ed25519.PublicKey `json:"senderPubKey"`
+	Expiry       time.Time         `json:"expiry"`
+}
+
+// disappearingStore tracks the disappearing message timer of each conversation
+// and the messages scheduled to be deleted. Expired messages are deleted from
+// the EventModel by the purge thread.
+type disappearingStore struct {
+	// The timer of each conversation keyed on the partner's public key (see
+	// marshalElementName)
+	timers map[string]disappearingTimer
+
+	// Messages scheduled for deletion
+	messages map[messageIdKey]expiringMessage
+
+	// Deletes the message from the EventModel
+	deleteMessage func(
+		messageID message.ID, senderPubKey ed25519.PublicKey) bool
+
+	kv  versioned.KV
+	mux sync.Mutex
+}
+
+// newOrLoadDisappearingStore loads the disappearing message timers and
+// scheduled messages from storage, if they exist. Otherwise, it initialises an
+// empty disappearingStore.
+func newOrLoadDisappearingStore(kv versioned.KV, deleteMessage func(
+	messageID message.ID, senderPubKey ed25519.PublicKey) bool) (
+	*disappearingStore, error) {
+	ds := &disappearingStore{
+		timers:        make(map[string]disappearingTimer),
+		messages:      make(map[messageIdKey]expiringMessage),
+		deleteMessage: deleteMessage,
+		kv:            kv,
+	}
+
+	obj, err := kv.Get(disappearingTimersKey, disappearingTimersVer)
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	} else if err == nil {
+		if err = json.Unmarshal(obj.Data, &ds.timers); err != nil {
+			return nil, err
+		}
+	}
+
+	keys, err := ds.loadMessageList()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		em, err := ds.loadMessage(key)
+		if err != nil {
+			if !kv.Exists(err) {
+				jww.WARN.Printf("[DM] Disappearing message %s in list not "+
+					"found in storage", key)
+				continue
+			}
+			return nil, err
+		}
+		ds.messages[key] = em
+	}
+
+	return ds, nil
+}
+
+// StartProcesses starts the thread that deletes expired messages. This
+// function adheres to the [xxdk.Service] type.
+//
+// This function always returns a nil error.
+func (ds *disappearingStore) StartProcesses() (stoppable.Stoppable, error) {
+	purgeThreadStop := stoppable.NewSingle(disappearingPurgeThreadStoppable)
+
+	// Start the thread
+	go ds.purgeThread(purgeThreadStop)
+
+	return purgeThreadStop, nil
+}
+
+// purgeThread deletes expired messages on startup and then periodically until
+// stopped.
+func (ds *disappearingStore) purgeThread(stop *stoppable.Single) {
+	jww.INFO.Printf("[DM] Starting disappearing message purge thread with "+
+		"stoppable %s", stop.Name())
+
+	ds.purge(netTime.Now())
+
+	ticker := time.NewTicker(disappearingPurgeFrequency)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop.Quit():
+			jww.INFO.Printf("[DM] Stopping disappearing message purge "+
+				"thread: stoppable %s quit", stop.Name())
+			stop.ToStopped()
+			return
+		case <-ticker.C:
+			ds.purge(netTime.Now())
+		}
+	}
+}
+
+// purge deletes all messages that have expired by the given time from the
+// EventModel and stops tracking them. Messages that fail to be deleted remain
+// scheduled and are retried on the next purge.
+//
+// The EventModel is called without holding the lock so that it can call back
+// into the client.
+func (ds *disappearingStore) purge(now time.Time) {
+	ds.mux.Lock()
+	expired := make(map[messageIdKey]expiringMessage)
+	for key, em := range ds.messages {
+		if !now.Before(em.Expiry) {
+			expired[key] = em
+		}
+	}
+	ds.mux.Unlock()
+
+	if len(expired) == 0 {
+		return
+	}
+
+	// Only stop tracking messages that were deleted or whose ID is invalid and
+	// so can never be deleted
+	var deleted int
+	for key, em := range expired {
+		messageID, err := unmarshalMessageIdKey(key)
+		if err != nil {
+			jww.ERROR.Printf("[DM] Failed to unmarshal disappearing message "+
+				"ID %s: %+v", key, err)
+		} else if ds.deleteMessage(messageID, em.SenderPubKey) {
+			deleted++
+		} else {
+			jww.WARN.Printf("[DM] Failed to delete disappearing message %s; "+
+				"retrying on next purge", messageID)
+			delete(expired, key)
+		}
+	}
+
+	ds.mux.Lock()
+	defer ds.mux.Unlock()
+
+	for key, em := range expired {
+		// Skip messages rescheduled while the lock was released
+		if cur, exists := ds.messages[key]; exists &&
+			!cur.Expiry.Equal(em.Expiry) {
+			continue
+		}
+
+		delete(ds.messages, key)
+		if err := ds.deleteStoredMessage(key); err != nil {
+			jww.ERROR.Printf("[DM] Failed to delete disappearing message %s "+
+				"from storage: %+v", key, err)
+		}
+	}
+
+	jww.INFO.Printf("[DM] Deleted %d disappearing messages.", deleted)
+	if len(expired) == 0 {
+		return
+	}
+	if err := ds.saveMessageList(); err != nil {
+		jww.ERROR.Printf(
+			"[DM] Failed to save disappearing message list: %+v", err)
+	}
+}
+
+// setTimer sets the disappearing message timer of the conversation if the
+// timestamp is newer than that of the current setting. A timer of zero
+// disables disappearing messages. Returns true if the timer was updated.
+func (ds *disappearingStore) setTimer(partnerPubKey ed25519.PublicKey,
+	timer time.Duration, timestamp time.Time) (bool, error) {
+	ds.mux.Lock()
+	defer ds.mux.Unlock()
+
+	elemName := marshalElementName(partnerPubKey)
+	if dt, exists := ds.timers[elemName]; exists &&
+		!timestamp.After(dt.Updated) {
+		return false, nil
+	}
+
+	ds.timers[elemName] = disappearingTimer{
+		Timer:   timer,
+		Updated: timestamp.UTC().Round(0),
+	}
+
+	return true, ds.saveTimers()
+}
+
+// getTimer returns the disappearing message timer of the conversation. Returns
+// zero if disappearing messages are disabled.
+func (ds *disappearingStore) getTimer(
+	partnerPubKey ed25519.PublicKey) time.Duration {
+	ds.mux.Lock()
+	defer ds.mux.Unlock()
+	return ds.timers[marshalElementName(partnerPubKey)].Timer
+}
+
+// schedule schedules the message to be deleted once the conversation's
+// disappearing message timer has elapsed since the timestamp. Does nothing if
+// disappearing messages are disabled or if the message type does not
+// disappear.
+func (ds *disappearingStore) schedule(partnerPubKey,
+	senderPubKey ed25519.PublicKey, messageID message.ID,
+	messageType MessageType, timestamp time.Time) {
+	if !isDisappearingType(messageType) {
+		return
+	}
+
+	ds.mux.Lock()
+	defer ds.mux.Unlock()
+
+	timer := ds.timers[marshalElementName(partnerPubKey)].Timer
+	if timer <= 0 {
+		return
+	}
+
+	key := getMessageIdKey(messageID)
+	em := expiringMessage{
+		SenderPubKey: senderPubKey,
+		Expiry:       timestamp.Add(timer).UTC().Round(0),
+	}
+
+	if err := ds.saveMessage(key, em); err != nil {
+		jww.ERROR.Printf("[DM] Failed to save disappearing message %s: %+v",
+			messageID, err)
+		return
+	}
+
+	_, exists := ds.messages[key]
+	ds.messages[key] = em
+	if !exists {
+		if err := ds.saveMessageList(); err != nil {
+			jww.ERROR.Printf("[DM] Failed to save disappearing message "+
+				"list: %+v", err)
+		}
+	}
+}
+
+// saveTimers stores the timer of every conversation to storage. Must be called
+// under lock.
+func (ds *disappearingStore) saveTimers() error {
+	data, err := json.Marshal(ds.timers)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal disappearing timers")
+	}
+
+	obj := &versioned.Object{
+		Version:   disappearingTimersVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return ds.kv.Set(disappearingTimersKey, obj)
+}
+
+// saveMessageList stores the list of scheduled message IDs to storage. Must be
+// called under lock.
+func (ds *disappearingStore) saveMessageList() error {
+	keys := make([]messageIdKey, 0, len(ds.messages))
+	for key := range ds.messages {
+		keys = append(keys, key)
+	}
+
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal disappearing message list")
+	}
+
+	obj := &versioned.Object{
+		Version:   disappearingMessageListVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return ds.kv.Set(disappearingMessageListKey, obj)
+}
+
+// loadMessageList loads the list of scheduled message IDs from storage.
+// Returns an empty list if none are stored.
+func (ds *disappearingStore) loadMessageList() ([]messageIdKey, error) {
+	obj, err := ds.kv.Get(disappearingMessageListKey, disappearingMessageListVer)
+	if err != nil {
+		if !ds.kv.Exists(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var keys []messageIdKey
+	return keys, json.Unmarshal(obj.Data, &keys)
+}
+
+// saveMessage stores the scheduled message to storage under its own key.
+func (ds *disappearingStore) saveMessage(
+	key messageIdKey, em expiringMessage) error {
+	data, err := json.Marshal(em)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal disappearing message")
+	}
+
+	obj := &versioned.Object{
+		Version:   disappearingMessageVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return ds.kv.Set(makeDisappearingMessageKey(key), obj)
+}
+
+// loadMessage loads the scheduled message from storage.
+func (ds *disappearingStore) loadMessage(
+	key messageIdKey) (expiringMessage, error) {
+	var em expiringMessage
+	obj, err := ds.kv.Get(
+		makeDisappearingMessageKey(key), disappearingMessageVer)
+	if err != nil {
+		return em, err
+	}
+
+	return em, json.Unmarshal(obj.Data, &em)
+}
+
+// deleteStoredMessage deletes the scheduled message from storage.
+func (ds *disappearingStore) deleteStoredMessage(key messageIdKey) error {
+	return ds.kv.Delete(
+		makeDisappearingMessageKey(key), disappearingMessageVer)
+}
+
+// makeDisappearingMessageKey creates the storage key for the scheduled message.
+func makeDisappearingMessageKey(key messageIdKey) string {
+	return disappearingMessageKeyPrefix + string(key)
+}
@@ -0,0 +1,207 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/crypto/codename"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that a disappearingStore loaded via newOrLoadDisappearingStore matches
+// the original.
+func Test_newOrLoadDisappearingStore(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	noDelete := func(message.ID, ed25519.PublicKey) bool { return true }
+	ds, err := newOrLoadDisappearingStore(kv, noDelete)
+	require.NoError(t, err)
+
+	partner, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+	sender, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+	_, err = ds.setTimer(partner, time.Hour, netTime.Now())
+	require.NoError(t, err)
+	ds.schedule(partner, sender, message.ID{1}, TextType, netTime.Now())
+
+	loaded, err := newOrLoadDisappearingStore(kv, noDelete)
+	require.NoError(t, err)
+	require.Equal(t, ds.timers, loaded.timers)
+	require.Equal(t, ds.messages, loaded.messages)
+}
+
+// Tests that disappearingStore.setTimer ignores timers older than the current
+// one.
+func Test_disappearingStore_setTimer(t *testing.T) {
+	ds, err := newOrLoadDisappearingStore(versioned.NewKV(ekv.MakeMemstore()),
+		func(message.ID, ed25519.PublicKey) bool { return true })
+	require.NoError(t, err)
+
+	partner, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+	now := netTime.Now()
+	require.Zero(t, ds.getTimer(partner))
+
+	updated, err := ds.setTimer(partner, time.Hour, now)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, time.Hour, ds.getTimer(partner))
+
+	updated, err = ds.setTimer(partner, time.Minute, now.Add(-time.Second))
+	require.NoError(t, err)
+	require.False(t, updated)
+	require.Equal(t, time.Hour, ds.getTimer(partner))
+
+	updated, err = ds.setTimer(partner, 0, now.Add(time.Second))
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Zero(t, ds.getTimer(partner))
+}
+
+// Tests that disappearingStore.purge only deletes messages scheduled with
+// disappearingStore.schedule once they expire and that they are removed from
+// storage.
+func Test_disappearingStore_schedule_purge(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	partner, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+	deleted := make(map[message.ID]ed25519.PublicKey)
+	var ds *disappearingStore
+	ds, err := newOrLoadDisappearingStore(kv,
+		func(messageID message.ID, senderPubKey ed25519.PublicKey) bool {
+			// Calls back into the store to ensure the lock is not held
+			ds.getTimer(partner)
+			deleted[messageID] = senderPubKey
+			return true
+		})
+	require.NoError(t, err)
+
+	other, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+	now := netTime.Now()
+	_, err = ds.setTimer(partner, time.Hour, now)
+	require.NoError(t, err)
+
+	ds.schedule(partner, partner, message.ID{1}, TextType, now)
+	ds.schedule(partner, partner, message.ID{2}, ReactionType, now.Add(time.Minute))
+	ds.schedule(partner, partner, message.ID{3}, SilentType, now)
+	ds.schedule(other, other, message.ID{4}, TextType, now)
+	require.Len(t, ds.messages, 2)
+
+	ds.purge(now.Add(time.Hour - time.Second))
+	require.Empty(t, deleted)
+
+	ds.purge(now.Add(time.Hour))
+	require.Equal(t,
+		map[message.ID]ed25519.PublicKey{{1}: partner}, deleted)
+
+	loaded, err := newOrLoadDisappearingStore(kv, ds.deleteMessage)
+	require.NoError(t, err)
+	require.Equal(t, ds.messages, loaded.messages)
+
+	ds.purge(now.Add(2 * time.Hour))
+	require.Len(t, deleted, 2)
+	require.Empty(t, ds.messages)
+
+	loaded, err = newOrLoadDisappearingStore(kv, ds.deleteMessage)
+	require.NoError(t, err)
+	require.Empty(t, loaded.messages)
+	_, err = kv.Get(makeDisappearingMessageKey(getMessageIdKey(message.ID{1})),
+		disappearingMessageVer)
+	require.Error(t, err)
+}
+
+// Tests that disappearingStore.purge keeps a message scheduled, in memory and in
+// storage, when it fails to be deleted and retries it on the next purge.
+func Test_disappearingStore_purge_DeleteFailure(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	partner, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+	fail := true
+	var calls int
+	ds, err := newOrLoadDisappearingStore(kv,
+		func(message.ID, ed25519.PublicKey) bool {
+			calls++
+			return !fail
+		})
+	require.NoError(t, err)
+
+	now := netTime.Now()
+	_, err = ds.setTimer(partner, time.Hour, now)
+	require.NoError(t, err)
+	ds.schedule(partner, partner, message.ID{1}, TextType, now)
+
+	ds.purge(now.Add(time.Hour))
+	require.Equal(t, 1, calls)
+	require.Len(t, ds.messages, 1)
+
+	loaded, err := newOrLoadDisappearingStore(kv, ds.deleteMessage)
+	require.NoError(t, err)
+	require.Equal(t, ds.messages, loaded.messages)
+
+	fail = false
+	ds.purge(now.Add(time.Hour))
+	require.Equal(t, 2, calls)
+	require.Empty(t, ds.messages)
+
+	loaded, err = newOrLoadDisappearingStore(kv, ds.deleteMessage)
+	require.NoError(t, err)
+	require.Empty(t, loaded.messages)
+}
+
+// Tests that a timer set with dmClient.SetDisappearingTimer is applied by both
+// partners and that messages sent and received afterwards are scheduled for
+// deletion.
+func TestE2EDMs_Disappearing(t *testing.T) {
+	netA, netB := createLinkedNets(t)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	partner, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	ekvA := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	ekvB := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+
+	receiverA := newMockReceiver()
+	receiverB := newMockReceiver()
+
+	clientA, err := newDmClient(&me, receiverA, NewSendTracker(ekvA),
+		NewNicknameManager(DeriveReceptionID(me.PubKey, me.GetDMToken()), ekvA),
+		newMockNM(), netA, ekvA, crng, nil)
+	require.NoError(t, err)
+	clientB, err := newDmClient(&partner, receiverB, NewSendTracker(ekvB),
+		NewNicknameManager(
+			DeriveReceptionID(partner.PubKey, partner.GetDMToken()), ekvB),
+		newMockNM(), netB, ekvB, crng, nil)
+	require.NoError(t, err)
+
+	params := cmix.GetDefaultCMIXParams()
+	_, _, _, err = clientA.SetDisappearingTimer(
+		partner.PubKey, partner.GetDMToken(), time.Hour, params)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, clientA.GetDisappearingTimer(partner.PubKey))
+	require.Equal(t, time.Hour, clientB.GetDisappearingTimer(me.PubKey))
+
+	msgID, _, _, err :=
+		clientA.SendText(partner.PubKey, partner.GetDMToken(), "Hi", params)
+	require.NoError(t, err)
+	require.Contains(t, clientA.dms.messages, getMessageIdKey(msgID))
+	require.Contains(t, clientB.dms.messages, getMessageIdKey(msgID))
+	require.Equal(t, ed25519.PublicKey(me.PubKey),
+		clientB.dms.messages[getMessageIdKey(msgID)].SenderPubKey)
+}
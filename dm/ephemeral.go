@@ -0,0 +1,352 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"google.golang.org/protobuf/proto"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/crypto/dm"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/crypto/nike/ecdh"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+const (
+	// Versions of the ephemeral message types
+	typingVersion   = 0
+	presenceVersion = 0
+
+	// SendTypingTag is the base tag used when generating a debug tag for
+	// sending a typing indicator.
+	SendTypingTag = "Typing"
+
+	// SendPresenceTag is the base tag used when generating a debug tag for
+	// sending a presence update.
+	SendPresenceTag = "Presence"
+
+	// ephemeralRateLimit is the minimum time between sending the same typing
+	// or presence state to a partner. Changes in state are always sent.
+	ephemeralRateLimit = 3 * time.Second
+
+	// maxEphemeralAge is the maximum age of a received typing or presence
+	// signal. Older signals, such as those picked up when the message history
+	// is replayed, are ignored.
+	maxEphemeralAge = 30 * time.Second
+)
+
+// Storage values.
+const (
+	presenceEnabledVer = 0
+	presenceEnabledKey = "dmPresenceEnabled"
+)
+
+// PresenceDisabledErr is returned when sending a presence update while
+// presence sharing is disabled.
+var PresenceDisabledErr = errors.New("presence sharing is disabled")
+
+// ephemeralKey identifies the last signal of a MessageType sent to a partner.
+type ephemeralKey struct {
+	partner     string
+	messageType MessageType
+}
+
+// ephemeralSignal is the last typing or presence state sent to a partner.
+type ephemeralSignal struct {
+	state bool
+	sent  time.Time
+}
+
+// ephemeralManager rate limits outgoing typing and presence signals and tracks
+// whether the user has opted in to sharing their presence. None of the signals
+// are stored; only the presence setting is saved to storage.
+type ephemeralManager struct {
+	// The last signal sent to each partner for each ephemeral type
+	lastSent map[ephemeralKey]ephemeralSignal
+
+	// Presence is only sent and received when enabled
+	presenceEnabled bool
+
+	typingCB   func(partnerPubKey ed25519.PublicKey, typing bool, ts time.Time)
+	presenceCB func(partnerPubKey ed25519.PublicKey, online bool, ts time.Time)
+
+	kv  versioned.KV
+	mux sync.Mutex
+}
+
+// newOrLoadEphemeralManager loads the presence setting from storage, if it
+// exists, and initialises a new ephemeralManager. Presence is disabled by
+// default.
+func newOrLoadEphemeralManager(kv versioned.KV, cbs Callbacks) (
+	*ephemeralManager, error) {
+	em := &ephemeralManager{
+		lastSent:   make(map[ephemeralKey]ephemeralSignal),
+		typingCB:   cbs.TypingUpdate,
+		presenceCB: cbs.PresenceUpdate,
+		kv:         kv,
+	}
+
+	obj, err := kv.Get(presenceEnabledKey, presenceEnabledVer)
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	} else if err == nil {
+		if err = json.Unmarshal(obj.Data, &em.presenceEnabled); err != nil {
+			return nil, err
+		}
+	}
+
+	return em, nil
+}
+
+// allowSend returns true if the signal should be sent to the partner. A signal
+// is sent if the state differs from the last one sent or if the rate limit
+// has elapsed since it was last sent. If allowed, the signal is recorded as
+// sent.
+func (em *ephemeralManager) allowSend(partnerPubKey ed25519.PublicKey,
+	messageType MessageType, state bool, now time.Time) bool {
+	em.mux.Lock()
+	defer em.mux.Unlock()
+
+	key := ephemeralKey{marshalElementName(partnerPubKey), messageType}
+	if last, exists := em.lastSent[key]; exists && last.state == state &&
+		now.Sub(last.sent) < ephemeralRateLimit {
+		return false
+	}
+
+	em.lastSent[key] = ephemeralSignal{state, now}
+	return true
+}
+
+// reset clears the last signal sent to the partner so that the next signal is
+// not rate limited. Called when sending fails.
+func (em *ephemeralManager) reset(
+	partnerPubKey ed25519.PublicKey, messageType MessageType) {
+	em.mux.Lock()
+	defer em.mux.Unlock()
+	delete(em.lastSent,
+		ephemeralKey{marshalElementName(partnerPubKey), messageType})
+}
+
+// setPresenceEnabled enables or disables presence sharing and saves the
+// setting to storage.
+func (em *ephemeralManager) setPresenceEnabled(enabled bool) error {
+	em.mux.Lock()
+	defer em.mux.Unlock()
+
+	data, err := json.Marshal(enabled)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal presence setting")
+	}
+
+	obj := &versioned.Object{
+		Version:   presenceEnabledVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	if err = em.kv.Set(presenceEnabledKey, obj); err != nil {
+		return err
+	}
+
+	em.presenceEnabled = enabled
+	return nil
+}
+
+// isPresenceEnabled returns true if presence sharing is enabled.
+func (em *ephemeralManager) isPresenceEnabled() bool {
+	em.mux.Lock()
+	defer em.mux.Unlock()
+	return em.presenceEnabled
+}
+
+// SendTyping sends an ephemeral signal to the partner that this user started
+// or stopped typing. The signal is never stored by either EventModel. Repeated
+// signals with the same state are rate limited and silently dropped. Nothing
+// is sent to blocked partners.
+func (dc *dmClient) SendTyping(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, typing bool, params cmix.CMIXParams) error {
+	tag := makeDebugTag(partnerPubKey, nil, SendTypingTag)
+	jww.DEBUG.Printf("[DM][%s] SendTyping(%s, %t)", tag,
+		base64.RawStdEncoding.EncodeToString(partnerPubKey), typing)
+
+	typingMarshaled, err := proto.Marshal(&TypingMessage{
+		Version: typingVersion,
+		Typing:  typing,
+	})
+	if err != nil {
+		return err
+	}
+
+	return dc.sendEphemeral(partnerPubKey, partnerToken, TypingType, typing,
+		typingMarshaled, params.SetDebugTag(tag))
+}
+
+// SendPresence sends an ephemeral signal to the partner that this user came
+// online or went offline. Returns PresenceDisabledErr if presence sharing has
+// not been enabled with EnablePresence. Repeated signals with the same state
+// are rate limited and silently dropped. Nothing is sent to blocked partners.
+func (dc *dmClient) SendPresence(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, online bool, params cmix.CMIXParams) error {
+	if !dc.em.isPresenceEnabled() {
+		return PresenceDisabledErr
+	}
+
+	tag := makeDebugTag(partnerPubKey, nil, SendPresenceTag)
+	jww.DEBUG.Printf("[DM][%s] SendPresence(%s, %t)", tag,
+		base64.RawStdEncoding.EncodeToString(partnerPubKey), online)
+
+	presenceMarshaled, err := proto.Marshal(&PresenceMessage{
+		Version: presenceVersion,
+		Online:  online,
+	})
+	if err != nil {
+		return err
+	}
+
+	return dc.sendEphemeral(partnerPubKey, partnerToken, PresenceType, online,
+		presenceMarshaled, params.SetDebugTag(tag))
+}
+
+// EnablePresence opts in to or out of sharing presence. While disabled,
+// presence updates are neither sent nor passed to the Callbacks when received.
+// The setting is saved to storage.
+func (dc *dmClient) EnablePresence(enabled bool) error {
+	jww.INFO.Printf("[DM] EnablePresence(%t)", enabled)
+	return dc.em.setPresenceEnabled(enabled)
+}
+
+// PresenceEnabled returns true if presence sharing is enabled.
+func (dc *dmClient) PresenceEnabled() bool {
+	return dc.em.isPresenceEnabled()
+}
+
+// sendEphemeral sends the ephemeral message to the partner. Unlike Send, the
+// message is not registered with the SendTracker, so it is never passed to the
+// EventModel on either side.
+func (dc *dmClient) sendEphemeral(partnerEdwardsPubKey ed25519.PublicKey,
+	partnerToken uint32, messageType MessageType, state bool, msg []byte,
+	params cmix.CMIXParams) error {
+	if partnerToken == 0 {
+		return errors.Errorf("invalid dmToken value: %d", partnerToken)
+	}
+
+	if partnerEdwardsPubKey == nil ||
+		partnerEdwardsPubKey.Equal(emptyPubKey) {
+		return errors.Errorf(
+			"invalid public key value: %v", partnerEdwardsPubKey)
+	}
+
+	if dc.IsBlocked(partnerEdwardsPubKey) {
+		jww.DEBUG.Printf("[DM][%s] Not sending %s to blocked partner",
+			params.DebugTag, messageType)
+		return nil
+	}
+
+	if !dc.em.allowSend(
+		partnerEdwardsPubKey, messageType, state, netTime.Now()) {
+		jww.DEBUG.Printf("[DM][%s] Dropping rate limited %s",
+			params.DebugTag, messageType)
+		return nil
+	}
+
+	partnerPubKey := ecdh.Edwards2EcdhNikePublicKey(partnerEdwardsPubKey)
+	partnerID := deriveReceptionID(partnerPubKey.Bytes(), partnerToken)
+
+	sihTag := dm.MakeSenderSihTag(partnerEdwardsPubKey, dc.me.Privkey)
+	mt := messageType.Marshal()
+	service := message.CompressedService{
+		Identifier: partnerEdwardsPubKey,
+		Tags:       []string{sihTag},
+		Metadata:   mt[:],
+	}
+
+	rng := dc.rng.GetStream()
+	msgNonce := make([]byte, messageNonceSize)
+	_, err := rng.Read(msgNonce)
+	rng.Close()
+	if err != nil {
+		dc.em.reset(partnerEdwardsPubKey, messageType)
+		return errors.Errorf("Failed to generate nonce: %+v", err)
+	}
+
+	directMessage := &DirectMessage{
+		DMToken:        dc.myToken,
+		PayloadType:    uint32(messageType),
+		Payload:        msg,
+		Nonce:          msgNonce,
+		LocalTimestamp: netTime.Now().UnixNano(),
+	}
+
+	rnd, _, err := send(dc.net, dc.selfReceptionID, partnerID, partnerPubKey,
+		dc.privateKey, service, partnerToken, directMessage, params, dc.rng)
+	if err != nil {
+		dc.em.reset(partnerEdwardsPubKey, messageType)
+		return errors.Wrapf(err, "failed to send %s", messageType)
+	}
+
+	jww.DEBUG.Printf("[DM][%s] Sent %s to %s on round %d",
+		params.DebugTag, messageType, partnerID, rnd.ID)
+	return nil
+}
+
+// receiveEphemeral is the internal function that handles the reception of
+// typing and presence signals. They are passed to the Callbacks and never to
+// the EventModel. Signals sent by this user from any device, stale signals,
+// and presence updates received while presence sharing is disabled are
+// ignored.
+//
+// Always returns a 0 UUID.
+func (r *receiver) receiveEphemeral(messageID cryptoMessage.ID,
+	messageType MessageType, content []byte,
+	partnerPubKey, senderPubKey ed25519.PublicKey, timestamp time.Time,
+	status Status) (uint64, error) {
+	if status != Received || senderPubKey.Equal(r.c.me.PubKey) {
+		return 0, nil
+	}
+
+	if netTime.Now().Sub(timestamp) > maxEphemeralAge {
+		jww.DEBUG.Printf("[DM] Dropping stale %s %s from %X sent at %s",
+			messageType, messageID, senderPubKey, timestamp)
+		return 0, nil
+	}
+
+	switch messageType {
+	case TypingType:
+		typing := &TypingMessage{}
+		if err := proto.Unmarshal(content, typing); err != nil {
+			return 0, errors.Wrapf(err, "failed to unmarshal DM %s "+
+				"from %x, type %s, ts: %s",
+				messageID, partnerPubKey, messageType, timestamp)
+		}
+		go r.c.em.typingCB(partnerPubKey, typing.Typing, timestamp)
+	case PresenceType:
+		if !r.c.em.isPresenceEnabled() {
+			return 0, nil
+		}
+
+		presence := &PresenceMessage{}
+		if err := proto.Unmarshal(content, presence); err != nil {
+			return 0, errors.Wrapf(err, "failed to unmarshal DM %s "+
+				"from %x, type %s, ts: %s",
+				messageID, partnerPubKey, messageType, timestamp)
+		}
+		go r.c.em.presenceCB(partnerPubKey, presence.Online, timestamp)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,170 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/crypto/codename"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that ephemeralManager.allowSend rate limits repeated signals with the
+// same state but always allows a change in state.
+func Test_ephemeralManager_allowSend(t *testing.T) {
+	em, err := newOrLoadEphemeralManager(
+		versioned.NewKV(ekv.MakeMemstore()), &dummyCallback{})
+	require.NoError(t, err)
+
+	partner, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+	now := netTime.Now()
+
+	require.True(t, em.allowSend(partner, TypingType, true, now))
+	require.False(t, em.allowSend(partner, TypingType, true, now.Add(time.Second)))
+	require.True(t, em.allowSend(partner, PresenceType, true, now))
+	require.True(t, em.allowSend(partner, TypingType, false, now.Add(time.Second)))
+	require.False(t, em.allowSend(partner, TypingType, false, now.Add(time.Second)))
+	require.True(t, em.allowSend(
+		partner, TypingType, false, now.Add(time.Second+ephemeralRateLimit)))
+
+	em.reset(partner, TypingType)
+	require.True(t, em.allowSend(
+		partner, TypingType, false, now.Add(time.Second+ephemeralRateLimit)))
+}
+
+// Tests that the presence setting set with ephemeralManager.setPresenceEnabled
+// is loaded by newOrLoadEphemeralManager.
+func Test_newOrLoadEphemeralManager(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	em, err := newOrLoadEphemeralManager(kv, &dummyCallback{})
+	require.NoError(t, err)
+	require.False(t, em.isPresenceEnabled())
+
+	require.NoError(t, em.setPresenceEnabled(true))
+
+	loaded, err := newOrLoadEphemeralManager(kv, &dummyCallback{})
+	require.NoError(t, err)
+	require.True(t, loaded.isPresenceEnabled())
+}
+
+// Tests that typing and presence signals sent between two clients are passed
+// to the Callbacks of the partner and never to either EventModel, that
+// presence is only shared once enabled, and that nothing is sent to blocked
+// partners.
+func TestE2EDMs_Ephemeral(t *testing.T) {
+	netA, netB := createLinkedNets(t)
+
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+	rng := crng.GetStream()
+	me, _ := codename.GenerateIdentity(rng)
+	partner, _ := codename.GenerateIdentity(rng)
+	rng.Close()
+
+	ekvA := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	ekvB := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+
+	receiverA := newMockReceiver()
+	receiverB := newMockReceiver()
+	cbsA := newMockEphemeralCallbacks()
+	cbsB := newMockEphemeralCallbacks()
+
+	clientA, err := newDmClient(&me, receiverA, NewSendTracker(ekvA),
+		NewNicknameManager(DeriveReceptionID(me.PubKey, me.GetDMToken()), ekvA),
+		newMockNM(), netA, ekvA, crng, cbsA)
+	require.NoError(t, err)
+	clientB, err := newDmClient(&partner, receiverB, NewSendTracker(ekvB),
+		NewNicknameManager(
+			DeriveReceptionID(partner.PubKey, partner.GetDMToken()), ekvB),
+		newMockNM(), netB, ekvB, crng, cbsB)
+	require.NoError(t, err)
+
+	params := cmix.GetDefaultCMIXParams()
+	require.NoError(t, clientA.SendTyping(
+		partner.PubKey, partner.GetDMToken(), true, params))
+	select {
+	case typing := <-cbsB.typing:
+		require.True(t, typing)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for typing update.")
+	}
+
+	// Presence is disabled by default
+	require.ErrorIs(t, clientA.SendPresence(
+		partner.PubKey, partner.GetDMToken(), true, params), PresenceDisabledErr)
+
+	// Presence is not received when the receiver has it disabled
+	require.NoError(t, clientA.EnablePresence(true))
+	require.NoError(t, clientA.SendPresence(
+		partner.PubKey, partner.GetDMToken(), true, params))
+	select {
+	case <-cbsB.presence:
+		t.Fatal("Received presence update while presence is disabled.")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, clientB.EnablePresence(true))
+	require.NoError(t, clientA.SendPresence(
+		partner.PubKey, partner.GetDMToken(), false, params))
+	select {
+	case online := <-cbsB.presence:
+		require.False(t, online)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for presence update.")
+	}
+
+	// Nothing is sent to blocked partners
+	clientA.BlockPartner(partner.PubKey)
+	require.NoError(t, clientA.SendTyping(
+		partner.PubKey, partner.GetDMToken(), false, params))
+	select {
+	case <-cbsB.typing:
+		t.Fatal("Received typing update from partner that blocked us.")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.Empty(t, receiverA.Msgs)
+	require.Empty(t, receiverB.Msgs)
+	require.Empty(t, cbsA.typing)
+	require.Empty(t, cbsA.presence)
+}
+
+// mockEphemeralCallbacks adheres to the Callbacks interface and reports typing
+// and presence updates on channels.
+type mockEphemeralCallbacks struct {
+	dummyCallback
+	typing, presence chan bool
+}
+
+func newMockEphemeralCallbacks() *mockEphemeralCallbacks {
+	return &mockEphemeralCallbacks{
+		typing:   make(chan bool, 10),
+		presence: make(chan bool, 10),
+	}
+}
+
+func (m *mockEphemeralCallbacks) TypingUpdate(
+	_ ed25519.PublicKey, typing bool, _ time.Time) {
+	m.typing <- typing
+}
+
+func (m *mockEphemeralCallbacks) PresenceUpdate(
+	_ ed25519.PublicKey, online bool, _ time.Time) {
+	m.presence <- online
+}
@@ -0,0 +1,660 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/protobuf/proto"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/message"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/client/v4/emoji"
+	"gitlab.com/elixxir/crypto/dm"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/crypto/nike"
+	"gitlab.com/elixxir/crypto/nike/ecdh"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+const (
+	// Version of the GroupMessage
+	groupVersion = 0
+
+	// SendGroupTag is the base tag used when generating a debug tag for
+	// sending a message to a group DM conversation.
+	SendGroupTag = "Group"
+
+	// MinGroupMembers is the minimum number of members, including the user, in
+	// a group DM conversation.
+	MinGroupMembers = 3
+
+	// MaxGroupMembers is the maximum number of members, including the user, in
+	// a group DM conversation. Every message carries the full member list, so
+	// the limit keeps enough of the payload free for the message itself.
+	MaxGroupMembers = 8
+
+	// groupConversationIDSalt is the salt used when deriving the ID of a group
+	// DM conversation.
+	groupConversationIDSalt = "dmGroupConversationIdSalt"
+)
+
+// Storage values.
+const (
+	groupStoreVer = 0
+	groupStoreKey = "dmGroupConversations"
+)
+
+// UnknownGroupConversationErr is returned when sending to a group DM
+// conversation that has not been created or received.
+var UnknownGroupConversationErr = errors.New(
+	"unknown group DM conversation")
+
+// GroupMember is a member of a group DM conversation.
+type GroupMember struct {
+	PubKey ed25519.PublicKey `json:"pubKey"`
+	Token  uint32            `json:"token"`
+}
+
+// GroupEventModel is an EventModel that stores the members of group DM
+// conversations. If the EventModel passed to the Client implements this
+// interface, then the members of every group DM conversation are passed to it
+// when the conversation is created or first received.
+//
+// Messages in a group DM conversation are passed to the EventModel with the
+// conversation ID in place of the partner's public key.
+type GroupEventModel interface {
+	EventModel
+
+	// UpdateGroupConversation is called when a group DM conversation is
+	// created or the first message in it is received. The members are sorted
+	// by public key and include this user.
+	UpdateGroupConversation(
+		conversationID ed25519.PublicKey, members []GroupMember) error
+}
+
+// DeriveGroupConversationID returns the stable ID of the group DM conversation
+// with the given members. The ID depends only on the set of public keys, so
+// every member derives the same ID regardless of the order of the members.
+//
+// The ID is the same length as an Ed25519 public key so that it can be used in
+// place of the partner's public key in the EventModel.
+func DeriveGroupConversationID(members []GroupMember) ed25519.PublicKey {
+	pubKeys := make([][]byte, len(members))
+	for i := range members {
+		pubKeys[i] = members[i].PubKey
+	}
+	sort.Slice(pubKeys, func(i, j int) bool {
+		return bytes.Compare(pubKeys[i], pubKeys[j]) < 0
+	})
+
+	h, err := blake2b.New256(nil)
+	if err != nil {
+		jww.FATAL.Panicf("[DM] Failed to get hash: %+v", err)
+	}
+	h.Write([]byte(groupConversationIDSalt))
+	for _, pubKey := range pubKeys {
+		h.Write(pubKey)
+	}
+
+	return h.Sum(nil)
+}
+
+// normaliseGroupMembers validates the members of a group DM conversation and
+// returns them sorted by public key with duplicates removed.
+func normaliseGroupMembers(members []GroupMember) ([]GroupMember, error) {
+	unique := make(map[string]GroupMember, len(members))
+	for i, m := range members {
+		if len(m.PubKey) != ed25519.PublicKeySize ||
+			m.PubKey.Equal(emptyPubKey) {
+			return nil, errors.Errorf(
+				"invalid public key for member %d: %v", i, m.PubKey)
+		} else if m.Token == 0 {
+			return nil, errors.Errorf("invalid dmToken for member %d: %d",
+				i, m.Token)
+		}
+		unique[string(m.PubKey)] = m
+	}
+
+	if len(unique) < MinGroupMembers || len(unique) > MaxGroupMembers {
+		return nil, errors.Errorf("group DM conversations must have between "+
+			"%d and %d members; received %d",
+			MinGroupMembers, MaxGroupMembers, len(unique))
+	}
+
+	sorted := make([]GroupMember, 0, len(unique))
+	for _, m := range unique {
+		sorted = append(sorted, m)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].PubKey, sorted[j].PubKey) < 0
+	})
+
+	return sorted, nil
+}
+
+// groupStore tracks the members of every group DM conversation this user is a
+// member of.
+type groupStore struct {
+	// The members of each conversation keyed on the conversation ID (see
+	// marshalElementName)
+	groups map[string][]GroupMember
+
+	// Passed the members of new conversations, if the EventModel supports it
+	groupModel GroupEventModel
+
+	kv  versioned.KV
+	mux sync.Mutex
+}
+
+// newOrLoadGroupStore loads the group DM conversations from storage, if they
+// exist. Otherwise, it initialises an empty groupStore.
+func newOrLoadGroupStore(
+	kv versioned.KV, receiver EventModel) (*groupStore, error) {
+	gs := &groupStore{
+		groups: make(map[string][]GroupMember),
+		kv:     kv,
+	}
+	gs.groupModel, _ = receiver.(GroupEventModel)
+
+	obj, err := kv.Get(groupStoreKey, groupStoreVer)
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	} else if err == nil {
+		if err = json.Unmarshal(obj.Data, &gs.groups); err != nil {
+			return nil, err
+		}
+	}
+
+	return gs, nil
+}
+
+// add adds the conversation with the given members if it does not already
+// exist. The members must already be normalised.
+func (gs *groupStore) add(
+	conversationID ed25519.PublicKey, members []GroupMember) error {
+	gs.mux.Lock()
+	defer gs.mux.Unlock()
+
+	elemName := marshalElementName(conversationID)
+	if _, exists := gs.groups[elemName]; exists {
+		return nil
+	}
+
+	gs.groups[elemName] = members
+	if err := gs.save(); err != nil {
+		return err
+	}
+
+	if gs.groupModel != nil {
+		err := gs.groupModel.UpdateGroupConversation(conversationID, members)
+		if err != nil {
+			jww.ERROR.Printf("[DM] Failed to update group conversation %s in "+
+				"event model: %+v", elemName, err)
+		}
+	}
+
+	return nil
+}
+
+// get returns the members of the conversation. Returns false if the
+// conversation does not exist.
+func (gs *groupStore) get(
+	conversationID ed25519.PublicKey) ([]GroupMember, bool) {
+	gs.mux.Lock()
+	defer gs.mux.Unlock()
+	members, exists := gs.groups[marshalElementName(conversationID)]
+	if !exists {
+		return nil, false
+	}
+	return append([]GroupMember{}, members...), true
+}
+
+// save stores every conversation to storage. Must be called under lock.
+func (gs *groupStore) save() error {
+	data, err := json.Marshal(gs.groups)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal group conversations")
+	}
+
+	obj := &versioned.Object{
+		Version:   groupStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return gs.kv.Set(groupStoreKey, obj)
+}
+
+// CreateGroupConversation creates a group DM conversation with the given
+// members and returns its ID. This user is added to the members if not
+// included. Nothing is sent to the members until the first message.
+func (dc *dmClient) CreateGroupConversation(
+	members []GroupMember) (ed25519.PublicKey, error) {
+	members = append(members, GroupMember{dc.me.PubKey, dc.myToken})
+	members, err := normaliseGroupMembers(members)
+	if err != nil {
+		return nil, err
+	}
+
+	conversationID := DeriveGroupConversationID(members)
+	jww.INFO.Printf("[DM] CreateGroupConversation(%s) with %d members",
+		base64.RawStdEncoding.EncodeToString(conversationID), len(members))
+
+	return conversationID, dc.gs.add(conversationID, members)
+}
+
+// GetGroupConversation returns the members of the group DM conversation.
+// Returns false if the conversation does not exist.
+func (dc *dmClient) GetGroupConversation(
+	conversationID ed25519.PublicKey) ([]GroupMember, bool) {
+	return dc.gs.get(conversationID)
+}
+
+// SendGroupText is used to send a formatted message to every member of a group
+// DM conversation.
+func (dc *dmClient) SendGroupText(conversationID ed25519.PublicKey,
+	msg string, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeDebugTag(conversationID, []byte(msg), SendMessageTag)
+	jww.INFO.Printf("[DM][%s] SendGroupText(%s)", tag,
+		base64.RawStdEncoding.EncodeToString(conversationID))
+
+	txtMarshaled, err := proto.Marshal(&Text{
+		Version: textVersion,
+		Text:    msg,
+	})
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return dc.SendGroup(conversationID, TextType, txtMarshaled,
+		params.SetDebugTag(tag))
+}
+
+// SendGroupReply is used to send a formatted reply to every member of a group
+// DM conversation.
+func (dc *dmClient) SendGroupReply(conversationID ed25519.PublicKey,
+	msg string, replyTo cryptoMessage.ID, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeDebugTag(conversationID, []byte(msg), SendReplyTag)
+	jww.INFO.Printf("[DM][%s] SendGroupReply(%s, to %s)", tag,
+		base64.RawStdEncoding.EncodeToString(conversationID), replyTo)
+
+	txtMarshaled, err := proto.Marshal(&Text{
+		Version:        textVersion,
+		Text:           msg,
+		ReplyMessageID: replyTo[:],
+	})
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return dc.SendGroup(conversationID, ReplyType, txtMarshaled,
+		params.SetDebugTag(tag))
+}
+
+// SendGroupReaction is used to send a reaction to a message in a group DM
+// conversation. The reaction must be a single emoji with no other characters,
+// and will be rejected otherwise.
+func (dc *dmClient) SendGroupReaction(conversationID ed25519.PublicKey,
+	reaction string, reactTo cryptoMessage.ID, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	tag := makeDebugTag(conversationID, []byte(reaction), SendReactionTag)
+	jww.INFO.Printf("[DM][%s] SendGroupReaction(%s, to %s)", tag,
+		base64.RawStdEncoding.EncodeToString(conversationID), reactTo)
+
+	if err := emoji.ValidateReaction(reaction); err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	reactMarshaled, err := proto.Marshal(&Reaction{
+		Version:           reactionVersion,
+		Reaction:          reaction,
+		ReactionMessageID: reactTo[:],
+	})
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	return dc.SendGroup(conversationID, ReactionType, reactMarshaled,
+		params.SetDebugTag(tag))
+}
+
+// SendGroup is used to send a raw message to every member of a group DM
+// conversation in a single round. Every member, including this user's other
+// devices, derives the same message ID. In general, it should be wrapped in a
+// function that defines the wire protocol.
+//
+// Returns UnknownGroupConversationErr if the conversation does not exist.
+func (dc *dmClient) SendGroup(conversationID ed25519.PublicKey,
+	messageType MessageType, msg []byte, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	if messageType == GroupType {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{},
+			errors.Errorf("cannot send %s in a group conversation", GroupType)
+	}
+
+	members, exists := dc.gs.get(conversationID)
+	if !exists {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{},
+			UnknownGroupConversationErr
+	}
+
+	groupMsg := &GroupMessage{
+		Version:       groupVersion,
+		MemberPubKeys: make([][]byte, len(members)),
+		MemberTokens:  make([]uint32, len(members)),
+		PayloadType:   uint32(messageType),
+		Payload:       msg,
+	}
+	partners := make([]groupPartner, 0, len(members)-1)
+	for i, m := range members {
+		groupMsg.MemberPubKeys[i] = m.PubKey
+		groupMsg.MemberTokens[i] = m.Token
+		if !m.PubKey.Equal(dc.me.PubKey) {
+			partners = append(partners, dc.makeGroupPartner(m))
+		}
+	}
+
+	groupMarshaled, err := proto.Marshal(groupMsg)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	sendPrint := fmt.Sprintf("[DM][%s] Sending from %s to group %s type %s "+
+		"at %s", params.DebugTag,
+		base64.StdEncoding.EncodeToString(dc.me.PubKey),
+		base64.StdEncoding.EncodeToString(conversationID), messageType,
+		netTime.Now())
+	defer func() { jww.INFO.Println(sendPrint) }()
+
+	rng := dc.rng.GetStream()
+	msgNonce := make([]byte, messageNonceSize)
+	_, err = rng.Read(msgNonce)
+	rng.Close()
+	if err != nil {
+		sendPrint += fmt.Sprintf(", failed to generate nonce: %+v", err)
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{},
+			errors.Errorf("Failed to generate nonce: %+v", err)
+	}
+
+	nickname, _ := dc.nm.GetNickname()
+	directMessage := &DirectMessage{
+		DMToken:        dc.myToken,
+		PayloadType:    uint32(GroupType),
+		Payload:        groupMarshaled,
+		Nickname:       nickname,
+		Nonce:          msgNonce,
+		LocalTimestamp: netTime.Now().UnixNano(),
+	}
+
+	if params.DebugTag == cmix.DefaultDebugTag {
+		params.DebugTag = directMessageDebugTag
+	}
+
+	uuid, err := dc.st.DenotePendingSend(conversationID, dc.me.PubKey, 0,
+		GroupType, directMessage)
+	if err != nil {
+		sendPrint += fmt.Sprintf(", pending send failed %s", err.Error())
+		if errDenote := dc.st.FailedSend(uuid); errDenote != nil {
+			sendPrint += fmt.Sprintf(
+				", failed to denote failed dm send: %s", errDenote.Error())
+		}
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	rnd, ephIDs, err := sendGroup(dc.net, dc.selfReceptionID, dc.publicKey,
+		dc.privateKey, partners, directMessage, params, dc.rng)
+	if err != nil {
+		sendPrint += fmt.Sprintf(", err on send: %+v", err)
+		if errDenote := dc.st.FailedSend(uuid); errDenote != nil {
+			sendPrint += fmt.Sprintf(
+				", failed to denote failed dm send: %s", errDenote.Error())
+		}
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	directMessage.RoundID = uint64(rnd.ID)
+	msgID := deriveGroupMessageID(conversationID, directMessage)
+
+	sendPrint += fmt.Sprintf(
+		", send eph %v rnd %s MsgID %s", ephIDs, rnd.ID, msgID)
+
+	err = dc.st.Sent(uuid, msgID, rnd)
+	if err != nil {
+		sendPrint += fmt.Sprintf(", dm send denote failed: %s ", err.Error())
+	}
+
+	// Schedule the message to be deleted if disappearing messages are enabled
+	dc.dms.schedule(
+		conversationID, dc.me.PubKey, msgID, messageType, netTime.Now())
+	return msgID, rnd, ephIDs[len(ephIDs)-1], err
+}
+
+// groupPartner contains the information needed to send a message to another
+// member of a group DM conversation.
+type groupPartner struct {
+	receptionID *id.ID
+	pubKey      nike.PublicKey
+	service     cmix.Service
+}
+
+// makeGroupPartner derives the reception ID, public key, and service used to
+// send to the member.
+func (dc *dmClient) makeGroupPartner(m GroupMember) groupPartner {
+	pubKey := ecdh.Edwards2EcdhNikePublicKey(m.PubKey)
+	mt := GroupType.Marshal()
+	return groupPartner{
+		receptionID: deriveReceptionID(pubKey.Bytes(), m.Token),
+		pubKey:      pubKey,
+		service: message.CompressedService{
+			Identifier: m.PubKey,
+			Tags:       []string{dm.MakeSenderSihTag(m.PubKey, dc.me.Privkey)},
+			Metadata:   mt[:],
+		},
+	}
+}
+
+// sendGroup sends the message to every partner and to this user's self
+// reception ID in a single round. Every copy of the message has the same round
+// ID so that every member derives the same message ID.
+func sendGroup(net cMixClient, myID *id.ID, myPubKey nike.PublicKey,
+	myPrivateKey nike.PrivateKey, partners []groupPartner,
+	msg *DirectMessage, params cmix.CMIXParams,
+	rngGenerator *fastRNG.StreamGenerator) (rounds.Round,
+	[]ephemeral.Id, error) {
+	recipients := make([]*id.ID, 0, len(partners)+1)
+	for _, p := range partners {
+		recipients = append(recipients, p.receptionID)
+	}
+	recipients = append(recipients, myID)
+
+	assemble := func(rid id.Round) ([]cmix.TargetedCmixMessage, error) {
+		rng := rngGenerator.GetStream()
+		defer rng.Close()
+
+		payloadLen := calcDMPayloadLen(net)
+		msgs := make([]cmix.TargetedCmixMessage, 0, len(recipients))
+
+		// Copy msg to dmMsg, which leaves the original message data alone
+		// for resend purposes
+		dmMsg := proto.Clone(msg).(*DirectMessage)
+		dmMsg.RoundID = uint64(rid)
+		dmSerial, err := proto.Marshal(dmMsg)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range partners {
+			ciphertext := dm.Cipher.Encrypt(
+				dmSerial, myPrivateKey, p.pubKey, rng, payloadLen)
+
+			fpBytes, encryptedPayload, mac, err :=
+				createCMIXFields(ciphertext, payloadLen, rng)
+			if err != nil {
+				return nil, err
+			}
+
+			msgs = append(msgs, cmix.TargetedCmixMessage{
+				Recipient:   p.receptionID,
+				Payload:     encryptedPayload,
+				Fingerprint: format.NewFingerprint(fpBytes),
+				Service:     p.service,
+				Mac:         mac,
+			})
+		}
+
+		// SELF SEND
+		selfMsg := proto.Clone(dmMsg).(*DirectMessage)
+		selfMsg.SelfRoundID = uint64(rid)
+		selfDMSerial, err := proto.Marshal(selfMsg)
+		if err != nil {
+			return nil, err
+		}
+
+		selfCiphertext, err := dm.Cipher.EncryptSelf(
+			selfDMSerial, myPrivateKey, myPubKey, payloadLen)
+		if err != nil {
+			return nil, err
+		}
+
+		fpBytes, encryptedPayload, mac, err :=
+			createCMIXFields(selfCiphertext, payloadLen, rng)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, cmix.TargetedCmixMessage{
+			Recipient:   myID,
+			Payload:     encryptedPayload,
+			Fingerprint: format.NewFingerprint(fpBytes),
+			Service:     createRandomService(rng),
+			Mac:         mac,
+		})
+
+		return msgs, nil
+	}
+
+	return net.SendManyWithAssembler(recipients, assemble, params)
+}
+
+// deriveGroupMessageID derives the message ID of a message in a group DM
+// conversation. Unlike one-to-one messages, the ID is derived from the
+// conversation ID so that it is the same for every member.
+func deriveGroupMessageID(
+	conversationID ed25519.PublicKey, msg *DirectMessage) cryptoMessage.ID {
+	return cryptoMessage.DeriveDirectMessageID(
+		deriveReceptionID(conversationID, 0), msg)
+}
+
+// unmarshalGroupMessage unmarshals the GroupMessage in the payload of the
+// DirectMessage and returns it with its normalised members and conversation
+// ID.
+func unmarshalGroupMessage(payload []byte) (
+	*GroupMessage, []GroupMember, ed25519.PublicKey, error) {
+	groupMsg := &GroupMessage{}
+	if err := proto.Unmarshal(payload, groupMsg); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(groupMsg.MemberPubKeys) != len(groupMsg.MemberTokens) {
+		return nil, nil, nil, errors.Errorf("received %d member public keys "+
+			"and %d member tokens", len(groupMsg.MemberPubKeys),
+			len(groupMsg.MemberTokens))
+	}
+
+	members := make([]GroupMember, len(groupMsg.MemberPubKeys))
+	for i := range groupMsg.MemberPubKeys {
+		members[i] = GroupMember{
+			groupMsg.MemberPubKeys[i], groupMsg.MemberTokens[i]}
+	}
+
+	members, err := normaliseGroupMembers(members)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return groupMsg, members, DeriveGroupConversationID(members), nil
+}
+
+// groupMessageIdFromDirectMessage derives the message ID of a received
+// DirectMessage of GroupType.
+func groupMessageIdFromDirectMessage(
+	msg *DirectMessage) (cryptoMessage.ID, error) {
+	_, _, conversationID, err := unmarshalGroupMessage(msg.GetPayload())
+	if err != nil {
+		return cryptoMessage.ID{}, err
+	}
+	return deriveGroupMessageID(conversationID, msg), nil
+}
+
+// receiveGroupMessage is the internal function that handles the reception of
+// messages sent to a group DM conversation. The sender and this user must both
+// be members. The conversation is added if it is new and the wrapped message is
+// processed with the conversation ID in place of the partner's public key.
+func (r *receiver) receiveGroupMessage(messageID cryptoMessage.ID,
+	messageType MessageType, nickname string, content []byte,
+	senderPubKey ed25519.PublicKey, timestamp time.Time,
+	ephID receptionID.EphemeralIdentity, round rounds.Round,
+	status Status) (uint64, error) {
+	groupMsg, members, conversationID, err := unmarshalGroupMessage(content)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to unmarshal DM %s from %x, "+
+			"type %s, ts: %s, round: %d",
+			messageID, senderPubKey, messageType, timestamp, round.ID)
+	}
+
+	var senderIsMember, selfIsMember bool
+	for _, m := range members {
+		senderIsMember = senderIsMember || m.PubKey.Equal(senderPubKey)
+		selfIsMember = selfIsMember || m.PubKey.Equal(r.c.me.PubKey)
+	}
+	if !senderIsMember || !selfIsMember {
+		return 0, errors.Errorf("dropping group DM %s from %x that does "+
+			"not include the sender and this user as members",
+			messageID, senderPubKey)
+	}
+
+	innerType := MessageType(groupMsg.PayloadType)
+	if innerType == GroupType {
+		return 0, errors.Errorf(
+			"dropping group DM %s from %x that wraps a %s message",
+			messageID, senderPubKey, innerType)
+	}
+
+	tag := makeDebugTag(conversationID, content, SendGroupTag)
+	jww.INFO.Printf("[%s] DM - Received %s in group %s from %s", tag,
+		innerType, base64.StdEncoding.EncodeToString(conversationID),
+		base64.StdEncoding.EncodeToString(senderPubKey))
+
+	if err = r.c.gs.add(conversationID, members); err != nil {
+		return 0, errors.Wrapf(err, "failed to save group conversation %x",
+			conversationID)
+	}
+
+	return r.receiveMessage(messageID, innerType, nickname, groupMsg.Payload,
+		0, conversationID, senderPubKey, timestamp, ephID, round, status)
+}
@@ -0,0 +1,177 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/crypto/codename"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+)
+
+// Tests that DeriveGroupConversationID returns the same ID regardless of the
+// order of the members and a different ID for different members.
+func TestDeriveGroupConversationID(t *testing.T) {
+	members := make([]GroupMember, 4)
+	for i := range members {
+		pubKey, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+		members[i] = GroupMember{pubKey, uint32(i + 1)}
+	}
+
+	id1 := DeriveGroupConversationID(members[:3])
+	id2 := DeriveGroupConversationID(
+		[]GroupMember{members[2], members[0], members[1]})
+	require.Equal(t, id1, id2)
+	require.Len(t, id1, ed25519.PublicKeySize)
+	require.NotEqual(t, id1, DeriveGroupConversationID(members))
+}
+
+// Tests that normaliseGroupMembers removes duplicates, sorts the members, and
+// rejects invalid member lists.
+func Test_normaliseGroupMembers(t *testing.T) {
+	members := make([]GroupMember, MaxGroupMembers+1)
+	for i := range members {
+		pubKey, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+		members[i] = GroupMember{pubKey, uint32(i + 1)}
+	}
+
+	normalised, err := normaliseGroupMembers(
+		[]GroupMember{members[2], members[0], members[1], members[0]})
+	require.NoError(t, err)
+	require.Len(t, normalised, 3)
+	require.Equal(t, DeriveGroupConversationID(members[:3]),
+		DeriveGroupConversationID(normalised))
+	for i := 1; i < len(normalised); i++ {
+		require.Negative(t,
+			bytes.Compare(normalised[i-1].PubKey, normalised[i].PubKey))
+	}
+
+	_, err = normaliseGroupMembers(members[:MinGroupMembers-1])
+	require.Error(t, err)
+	_, err = normaliseGroupMembers(members)
+	require.Error(t, err)
+	_, err = normaliseGroupMembers(append(members[:2:2],
+		GroupMember{members[2].PubKey, 0}))
+	require.Error(t, err)
+	_, err = normaliseGroupMembers(append(members[:2:2],
+		GroupMember{emptyPubKey, 1}))
+	require.Error(t, err)
+}
+
+// Tests that a groupStore loaded via newOrLoadGroupStore matches the original
+// and that new conversations are passed to a GroupEventModel.
+func Test_newOrLoadGroupStore(t *testing.T) {
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	em := &mockGroupEventModel{mockReceiver: newMockReceiver(),
+		groups: make(map[string][]GroupMember)}
+	gs, err := newOrLoadGroupStore(kv, em)
+	require.NoError(t, err)
+
+	members := make([]GroupMember, 3)
+	for i := range members {
+		pubKey, _, _ := ed25519.GenerateKey(csprng.NewSystemRNG())
+		members[i] = GroupMember{pubKey, uint32(i + 1)}
+	}
+	members, err = normaliseGroupMembers(members)
+	require.NoError(t, err)
+	conversationID := DeriveGroupConversationID(members)
+	require.NoError(t, gs.add(conversationID, members))
+	require.Equal(t, members, em.groups[string(conversationID)])
+
+	loaded, err := newOrLoadGroupStore(kv, em)
+	require.NoError(t, err)
+	require.Equal(t, gs.groups, loaded.groups)
+
+	received, exists := loaded.get(conversationID)
+	require.True(t, exists)
+	require.Equal(t, members, received)
+}
+
+// Tests that a message sent with dmClient.SendGroupText is received by every
+// other member of the group with the conversation ID as the partner key and
+// the same message ID, and that members can reply to the conversation once
+// they have received a message in it.
+func TestE2EDMs_Group(t *testing.T) {
+	nets := createLinkedNetsN(t, 3)
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+
+	clients := make([]*dmClient, len(nets))
+	receivers := make([]*mockReceiver, len(nets))
+	members := make([]GroupMember, len(nets))
+	for i := range nets {
+		rng := crng.GetStream()
+		me, _ := codename.GenerateIdentity(rng)
+		rng.Close()
+
+		kv := collective.TestingKV(t, ekv.MakeMemstore(),
+			collective.StandardPrefexs, collective.NewMockRemote())
+		receivers[i] = newMockReceiver()
+		var err error
+		clients[i], err = newDmClient(&me, receivers[i], NewSendTracker(kv),
+			NewNicknameManager(DeriveReceptionID(me.PubKey, me.GetDMToken()), kv),
+			newMockNM(), nets[i], kv, crng, nil)
+		require.NoError(t, err)
+		members[i] = GroupMember{me.PubKey, me.GetDMToken()}
+	}
+
+	params := cmix.GetDefaultCMIXParams()
+	_, _, _, err := clients[0].SendGroupText(
+		DeriveGroupConversationID(members), "Hi", params)
+	require.ErrorIs(t, err, UnknownGroupConversationErr)
+
+	conversationID, err := clients[0].CreateGroupConversation(members[1:])
+	require.NoError(t, err)
+	require.Equal(t, DeriveGroupConversationID(members), conversationID)
+
+	msgID, _, _, err := clients[0].SendGroupText(conversationID, "Hi", params)
+	require.NoError(t, err)
+
+	for i := 1; i < len(clients); i++ {
+		require.Len(t, receivers[i].Msgs, 1)
+		require.Equal(t, "Hi", receivers[i].Msgs[0].Message)
+		require.Equal(t, msgID, receivers[i].Msgs[0].MessageID)
+		require.Equal(t, conversationID, receivers[i].Msgs[0].PubKey)
+
+		received, exists := clients[i].GetGroupConversation(conversationID)
+		require.True(t, exists)
+		require.Len(t, received, len(members))
+	}
+
+	replyID, _, _, err := clients[2].SendGroupReply(
+		conversationID, "Hello", msgID, params)
+	require.NoError(t, err)
+	for _, i := range []int{0, 1} {
+		last := receivers[i].Msgs[len(receivers[i].Msgs)-1]
+		require.Equal(t, "Hello", last.Message)
+		require.Equal(t, replyID, last.MessageID)
+		require.Equal(t, msgID, last.ReplyTo)
+		require.Equal(t, conversationID, last.PubKey)
+	}
+}
+
+// mockGroupEventModel adheres to the GroupEventModel interface and is used for
+// testing.
+type mockGroupEventModel struct {
+	*mockReceiver
+	groups map[string][]GroupMember
+}
+
+func (m *mockGroupEventModel) UpdateGroupConversation(
+	conversationID ed25519.PublicKey, members []GroupMember) error {
+	m.groups[string(conversationID)] = members
+	return nil
+}
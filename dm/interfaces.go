@@ -18,6 +18,7 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix"
 	"gitlab.com/elixxir/client/v4/cmix/message"
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/stoppable"
 	clientNotif "gitlab.com/elixxir/client/v4/notifications"
 	"gitlab.com/elixxir/crypto/codename"
 	"gitlab.com/elixxir/crypto/fastRNG"
@@ -64,6 +65,69 @@ type Client interface {
 	SetMobileNotificationsLevel(
 		partnerPubKey ed25519.PublicKey, level NotificationLevel) error
 
+	// MarkRead marks all messages in the conversation with a timestamp at or
+	// before the given timestamp as read. The read marker is synced to all the
+	// user's devices and only ever moves forward.
+	MarkRead(partnerPubKey ed25519.PublicKey, timestamp time.Time) error
+
+	// GetReadMarker returns the timestamp of the last read message in the
+	// conversation. Returns false if no messages in the conversation have been
+	// marked read.
+	GetReadMarker(partnerPubKey ed25519.PublicKey) (time.Time, bool)
+
+	// GetUnreadCount returns the number of messages from the partner received
+	// after the read marker. Returns ReadMarkersUnsupportedErr if the
+	// EventModel does not implement ReadMarkerEventModel.
+	GetUnreadCount(partnerPubKey ed25519.PublicKey) (uint64, error)
+
+	// GetDisappearingTimer returns the duration after which messages in the
+	// conversation with the partner are deleted. Returns zero if disappearing
+	// messages are disabled.
+	GetDisappearingTimer(partnerPubKey ed25519.PublicKey) time.Duration
+
+	// CreateGroupConversation creates a group DM conversation with the given
+	// members and returns its ID. This user is added to the members if not
+	// included.
+	CreateGroupConversation(members []GroupMember) (ed25519.PublicKey, error)
+
+	// GetGroupConversation returns the members of the group DM conversation.
+	// Returns false if the conversation does not exist.
+	GetGroupConversation(
+		conversationID ed25519.PublicKey) ([]GroupMember, bool)
+
+	// EnableMessageRequests turns the message request inbox on or off. While
+	// enabled, messages from senders with no existing conversation are held
+	// until the request is accepted or declined.
+	EnableMessageRequests(enabled bool) error
+
+	// MessageRequestsEnabled returns true if the message request inbox is
+	// enabled.
+	MessageRequestsEnabled() bool
+
+	// GetMessageRequests returns all pending message requests, oldest first.
+	GetMessageRequests() []MessageRequest
+
+	// AcceptMessageRequest accepts the pending message request from the
+	// partner and passes its held messages to the EventModel. Returns
+	// NoMessageRequestErr if there is no request from the partner.
+	AcceptMessageRequest(partnerPubKey ed25519.PublicKey) error
+
+	// DeclineMessageRequest drops the pending message request from the
+	// partner. If block is true, then the partner is also blocked. Returns
+	// NoMessageRequestErr if there is no request from the partner.
+	DeclineMessageRequest(partnerPubKey ed25519.PublicKey, block bool) error
+
+	// EnablePresence opts in to or out of sharing presence. While disabled,
+	// presence updates are neither sent nor received.
+	EnablePresence(enabled bool) error
+
+	// PresenceEnabled returns true if presence sharing is enabled.
+	PresenceEnabled() bool
+
+	// StartProcesses starts the thread that deletes expired disappearing
+	// messages. This function adheres to the [xxdk.Service] type.
+	StartProcesses() (stoppable.Stoppable, error)
+
 	NickNameManager
 }
 
@@ -119,6 +183,49 @@ type Sender interface {
 		targetMessage cryptoMessage.ID, params cmix.CMIXParams) (
 		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
 
+	// SetDisappearingTimer sets the duration after which messages in the
+	// conversation are deleted and sends it to the partner so that it is
+	// applied on both sides. A timer of zero disables disappearing messages.
+	SetDisappearingTimer(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		timer time.Duration, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendGroupText is used to send a formatted message to every member of a
+	// group DM conversation.
+	SendGroupText(conversationID ed25519.PublicKey, msg string,
+		params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendGroupReply is used to send a formatted reply to every member of a
+	// group DM conversation.
+	SendGroupReply(conversationID ed25519.PublicKey, msg string,
+		replyTo cryptoMessage.ID, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendGroupReaction is used to send a reaction to a message in a group DM
+	// conversation.
+	SendGroupReaction(conversationID ed25519.PublicKey, reaction string,
+		reactTo cryptoMessage.ID, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendGroup is used to send a raw message to every member of a group DM
+	// conversation. Every member derives the same message ID.
+	SendGroup(conversationID ed25519.PublicKey, messageType MessageType,
+		msg []byte, params cmix.CMIXParams) (
+		cryptoMessage.ID, rounds.Round, ephemeral.Id, error)
+
+	// SendTyping sends an ephemeral signal to the partner that this user
+	// started or stopped typing. Repeated signals with the same state are rate
+	// limited and nothing is sent to blocked partners.
+	SendTyping(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		typing bool, params cmix.CMIXParams) error
+
+	// SendPresence sends an ephemeral signal to the partner that this user
+	// came online or went offline. Returns PresenceDisabledErr if presence
+	// sharing is disabled.
+	SendPresence(partnerPubKey ed25519.PublicKey, partnerToken uint32,
+		online bool, params cmix.CMIXParams) error
+
 	// Send is used to send a raw message. In general, it
 	// should be wrapped in a function that defines the wire protocol.
 	//
@@ -344,4 +451,25 @@ type Callbacks interface {
 	// unblocked. It is also called on initial registration for every blocked
 	// user.
 	BlockedUser(user ed25519.PublicKey, blocked bool)
+
+	// ReadMarkerUpdate is called when the read marker of a conversation is
+	// loaded or advances on this or another of the user's devices. All
+	// messages with a timestamp at or before lastRead have been read.
+	ReadMarkerUpdate(partnerPubKey ed25519.PublicKey, lastRead time.Time)
+
+	// TypingUpdate is called when the partner starts or stops typing. Typing
+	// indicators are ephemeral and are never passed to the EventModel.
+	TypingUpdate(partnerPubKey ed25519.PublicKey, typing bool,
+		timestamp time.Time)
+
+	// PresenceUpdate is called when the partner comes online or goes offline.
+	// It is only called while presence sharing is enabled. Presence updates
+	// are ephemeral and are never passed to the EventModel.
+	PresenceUpdate(partnerPubKey ed25519.PublicKey, online bool,
+		timestamp time.Time)
+
+	// MessageRequestUpdate is called when a message from an unknown partner is
+	// held in a message request (pending is true) and when the request is
+	// accepted or declined (pending is false).
+	MessageRequestUpdate(partnerPubKey ed25519.PublicKey, pending bool)
 }
\ No newline at end of file
@@ -20,8 +20,10 @@ import (
 	"gitlab.com/elixxir/client/v4/cmix/rounds"
 	cryptoMessage "gitlab.com/elixxir/crypto/message"
 	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/elixxir/primitives/states"
 	"gitlab.com/xx_network/primitives/id"
 	"gitlab.com/xx_network/primitives/id/ephemeral"
+	"gitlab.com/xx_network/primitives/netTime"
 )
 
 // createLinkedNets links 2 clients together.
@@ -34,6 +36,20 @@ func createLinkedNets(t testing.TB) (*mockClient, *mockClient) {
 	return client1, client2
 }
 
+// createLinkedNetsN links n clients together. Messages are delivered to
+// whichever client has a processor registered for the recipient.
+func createLinkedNetsN(t testing.TB, n int) []*mockClient {
+	clients := make([]*mockClient, n)
+	for i := range clients {
+		clients[i] = newMockClient(t)
+	}
+	for i := range clients {
+		clients[i].otherClient = clients[(i+1)%n]
+		clients[i].peers = clients
+	}
+	return clients
+}
+
 // newMockClient creates a client that can send messages
 func newMockClient(t testing.TB) *mockClient {
 	return &mockClient{
@@ -48,6 +64,7 @@ type mockClient struct {
 	rndID       uint64
 	processors  map[id.ID]message.Processor
 	otherClient *mockClient
+	peers       []*mockClient
 	t testing.TB
 }
 
@@ -66,16 +83,16 @@ func (mc *mockClient) SendManyWithAssembler(recipients []*id.ID,
 	jww.INFO.Printf(
 		"SendManyWithAssembler: %s, %s", recipients[0], recipients[1])
 	mc.rndID += 1
-	id1, _, _, err := ephemeral.GetId(recipients[0], 8, time.Now().Unix())
-	if err != nil {
-		return rounds.Round{}, nil, err
-	}
-	id2, _, _, err := ephemeral.GetId(recipients[1], 8, time.Now().Unix())
-	if err != nil {
-		return rounds.Round{}, nil, err
+	ids := make([]ephemeral.Id, len(recipients))
+	for i := range recipients {
+		var err error
+		ids[i], _, _, err = ephemeral.GetId(recipients[i], 8, time.Now().Unix())
+		if err != nil {
+			return rounds.Round{}, nil, err
+		}
 	}
-	ids := []ephemeral.Id{id1, id2}
-	rnd := rounds.Round{ID: id.Round(mc.rndID)}
+	rnd := rounds.Round{ID: id.Round(mc.rndID),
+		Timestamps: map[states.Round]time.Time{states.QUEUED: netTime.Now()}}
 	msgs, err := assembler(rnd.ID)
 	if err != nil {
 		mc.t.Fatal(err)
@@ -96,13 +113,32 @@ func (mc *mockClient) SendManyWithAssembler(recipients []*id.ID,
 				EphId:  ids[i],
 				Source: recipients[i],
 			}
-			clients[i].processors[*recipients[i]].Process(
-				msg, []string{}, []byte{}, recID, rnd)
+			processor := mc.getProcessor(clients, i, recipients[i])
+			if processor == nil {
+				continue
+			}
+			processor.Process(msg, []string{}, []byte{}, recID, rnd)
 		}
 	}
 	return rounds.Round{ID: id.Round(mc.rndID)}, ids, nil
 }
 
+// getProcessor returns the processor registered for the recipient. When the
+// client is linked to peers, it is looked up on every peer. Otherwise, the
+// first recipient is the linked client and the second is this client.
+func (mc *mockClient) getProcessor(
+	clients []*mockClient, i int, recipient *id.ID) message.Processor {
+	if mc.peers == nil {
+		return clients[i].processors[*recipient]
+	}
+	for _, peer := range mc.peers {
+		if processor, exists := peer.processors[*recipient]; exists {
+			return processor
+		}
+	}
+	return nil
+}
+
 func (mc *mockClient) AddIdentity(*id.ID, time.Time, bool, message.Processor) {}
 func (mc *mockClient) AddIdentityWithHistory(id *id.ID, _ time.Time, _ time.Time,
 	_ bool, processor message.Processor) {
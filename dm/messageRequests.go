@@ -0,0 +1,516 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+const (
+	// maxHeldMessages is the maximum number of messages held for a single
+	// pending message request. Any further messages from the sender are
+	// dropped until the request is accepted.
+	maxHeldMessages = 32
+
+	// maxPendingRequests is the maximum number of senders with a pending
+	// message request. Once reached, the oldest request is dropped to make
+	// room for a new sender.
+	maxPendingRequests = 256
+)
+
+// Storage values.
+const (
+	messageRequestsVer      = 0
+	messageRequestsKey      = "dmMessageRequests"
+	messageRequestHeldVer   = 0
+	messageRequestKeyPrefix = "dmMessageRequest/"
+)
+
+// NoMessageRequestErr is returned when accepting or declining a message request
+// that does not exist.
+var NoMessageRequestErr = errors.New("no pending message request from partner")
+
+// MessageRequest describes the pending messages from a sender that this user
+// has no existing conversation with.
+type MessageRequest struct {
+	// PubKey is the Ed25519 public key of the sender.
+	PubKey ed25519.PublicKey `json:"pubKey"`
+
+	// Token is the DM token of the sender.
+	Token uint32 `json:"token"`
+
+	// Nickname is the nickname on the most recent message held, if any.
+	Nickname string `json:"nickname"`
+
+	// Timestamp is the time the first message was received.
+	Timestamp time.Time `json:"timestamp"`
+
+	// NumMessages is the number of messages held for the request.
+	NumMessages int `json:"numMessages"`
+}
+
+// heldMessage is a received message held in a pending message request. It
+// contains everything needed to process the message once the request is
+// accepted.
+type heldMessage struct {
+	MessageID   cryptoMessage.ID `json:"messageID"`
+	MessageType MessageType      `json:"messageType"`
+	Nickname    string           `json:"nickname"`
+	Payload     []byte           `json:"payload"`
+	Token       uint32           `json:"token"`
+	Timestamp   time.Time        `json:"timestamp"`
+	RoundID     id.Round         `json:"roundID"`
+}
+
+// messageRequestDisk is the storage representation of the messageRequestStore.
+// The held messages of each pending sender are stored under their own key (see
+// makeMessageRequestKey).
+type messageRequestDisk struct {
+	Enabled  bool                `json:"enabled"`
+	Pending  []string            `json:"pending"`
+	Accepted map[string]struct{} `json:"accepted"`
+}
+
+// messageRequestStore holds the first messages from senders with no existing
+// conversation until the user accepts or declines them. It only holds
+// messages while message requests are enabled.
+type messageRequestStore struct {
+	// Held messages keyed on the sender's public key (see marshalElementName)
+	pending map[string][]heldMessage
+
+	// Senders whose message requests have been accepted
+	accepted map[string]struct{}
+
+	enabled bool
+	cb      func(partnerPubKey ed25519.PublicKey, pending bool)
+	kv      versioned.KV
+	mux     sync.Mutex
+}
+
+// newOrLoadMessageRequestStore loads the messageRequestStore from storage, if
+// it exists. Otherwise, it initialises a new messageRequestStore with message
+// requests disabled.
+func newOrLoadMessageRequestStore(kv versioned.KV,
+	cb func(partnerPubKey ed25519.PublicKey, pending bool)) (
+	*messageRequestStore, error) {
+	mrs := &messageRequestStore{
+		pending:  make(map[string][]heldMessage),
+		accepted: make(map[string]struct{}),
+		cb:       cb,
+		kv:       kv,
+	}
+
+	obj, err := kv.Get(messageRequestsKey, messageRequestsVer)
+	if err != nil && kv.Exists(err) {
+		return nil, err
+	} else if err == nil {
+		var disk messageRequestDisk
+		if err = json.Unmarshal(obj.Data, &disk); err != nil {
+			return nil, err
+		}
+		mrs.enabled = disk.Enabled
+		if disk.Accepted != nil {
+			mrs.accepted = disk.Accepted
+		}
+
+		for _, elemName := range disk.Pending {
+			held, err2 := mrs.loadHeld(elemName)
+			if err2 != nil {
+				if !kv.Exists(err2) {
+					jww.WARN.Printf("[DM] Message request from %s in list "+
+						"not found in storage", elemName)
+					continue
+				}
+				return nil, err2
+			}
+			mrs.pending[elemName] = held
+		}
+	}
+
+	return mrs, nil
+}
+
+// setEnabled enables or disables message requests and saves the setting to
+// storage.
+func (mrs *messageRequestStore) setEnabled(enabled bool) error {
+	mrs.mux.Lock()
+	defer mrs.mux.Unlock()
+
+	old := mrs.enabled
+	mrs.enabled = enabled
+	if err := mrs.save(); err != nil {
+		mrs.enabled = old
+		return err
+	}
+	return nil
+}
+
+// isEnabled returns true if message requests are enabled.
+func (mrs *messageRequestStore) isEnabled() bool {
+	mrs.mux.Lock()
+	defer mrs.mux.Unlock()
+	return mrs.enabled
+}
+
+// isAccepted returns true if a message request from the sender was accepted.
+func (mrs *messageRequestStore) isAccepted(pubKey ed25519.PublicKey) bool {
+	mrs.mux.Lock()
+	defer mrs.mux.Unlock()
+	_, exists := mrs.accepted[marshalElementName(pubKey)]
+	return exists
+}
+
+// hold adds the message to the pending message request from the sender.
+// Messages over the maxHeldMessages limit and messages already held are
+// dropped. If the sender is new and there are already maxPendingRequests
+// pending requests, then the oldest request is dropped.
+func (mrs *messageRequestStore) hold(
+	pubKey ed25519.PublicKey, msg heldMessage) error {
+	mrs.mux.Lock()
+	defer mrs.mux.Unlock()
+
+	elemName := marshalElementName(pubKey)
+	held, exists := mrs.pending[elemName]
+	if len(held) >= maxHeldMessages {
+		jww.WARN.Printf("[DM] Dropping message %s from %s: message request "+
+			"limit of %d messages reached", msg.MessageID, elemName,
+			maxHeldMessages)
+		return nil
+	}
+	for _, m := range held {
+		if m.MessageID.Equals(msg.MessageID) {
+			return nil
+		}
+	}
+
+	newHeld := append(held[:len(held):len(held)], msg)
+	if err := mrs.saveHeld(elemName, newHeld); err != nil {
+		return err
+	}
+	mrs.pending[elemName] = newHeld
+
+	if !exists {
+		if len(mrs.pending) > maxPendingRequests {
+			mrs.dropOldest(elemName)
+		}
+		if err := mrs.save(); err != nil {
+			delete(mrs.pending, elemName)
+			if err2 := mrs.deleteHeld(elemName); err2 != nil {
+				jww.ERROR.Printf("[DM] Failed to delete message request "+
+					"from %s from storage: %+v", elemName, err2)
+			}
+			return err
+		}
+	}
+
+	go mrs.cb(pubKey, true)
+	return nil
+}
+
+// dropOldest drops the pending message request, other than the one from keep,
+// whose first message was received the earliest. The updated list of senders
+// must be saved by the caller. It must be called while the lock is held.
+func (mrs *messageRequestStore) dropOldest(keep string) {
+	var oldest string
+	var oldestTs time.Time
+	for elemName, held := range mrs.pending {
+		if elemName == keep {
+			continue
+		} else if len(held) == 0 {
+			oldest = elemName
+			break
+		}
+		if oldest == "" || held[0].Timestamp.Before(oldestTs) {
+			oldest, oldestTs = elemName, held[0].Timestamp
+		}
+	}
+
+	jww.WARN.Printf("[DM] Dropping message request from %s: limit of %d "+
+		"pending message requests reached", oldest, maxPendingRequests)
+
+	delete(mrs.pending, oldest)
+	if err := mrs.deleteHeld(oldest); err != nil {
+		jww.ERROR.Printf("[DM] Failed to delete message request from %s "+
+			"from storage: %+v", oldest, err)
+	}
+
+	if pubKey, err := unmarshalElementName(oldest); err == nil {
+		go mrs.cb(pubKey, false)
+	}
+}
+
+// remove deletes the pending message request from the sender and returns its
+// held messages. If accept is true, then the sender is marked as accepted.
+// Returns NoMessageRequestErr if there is no request from the sender.
+func (mrs *messageRequestStore) remove(
+	pubKey ed25519.PublicKey, accept bool) ([]heldMessage, error) {
+	mrs.mux.Lock()
+	defer mrs.mux.Unlock()
+
+	elemName := marshalElementName(pubKey)
+	held, exists := mrs.pending[elemName]
+	if !exists {
+		return nil, NoMessageRequestErr
+	}
+
+	delete(mrs.pending, elemName)
+	if accept {
+		mrs.accepted[elemName] = struct{}{}
+	}
+	if err := mrs.save(); err != nil {
+		mrs.pending[elemName] = held
+		delete(mrs.accepted, elemName)
+		return nil, err
+	}
+
+	if err := mrs.deleteHeld(elemName); err != nil {
+		jww.ERROR.Printf("[DM] Failed to delete message request from %s "+
+			"from storage: %+v", elemName, err)
+	}
+
+	go mrs.cb(pubKey, false)
+	return held, nil
+}
+
+// getAll returns all pending message requests sorted by the time their first
+// message was received.
+func (mrs *messageRequestStore) getAll() []MessageRequest {
+	mrs.mux.Lock()
+	defer mrs.mux.Unlock()
+
+	requests := make([]MessageRequest, 0, len(mrs.pending))
+	for elemName, held := range mrs.pending {
+		pubKey, err := unmarshalElementName(elemName)
+		if err != nil || len(held) == 0 {
+			jww.ERROR.Printf("[DM] Skipping invalid message request %q: %+v",
+				elemName, err)
+			continue
+		}
+
+		last := held[len(held)-1]
+		requests = append(requests, MessageRequest{
+			PubKey:      pubKey,
+			Token:       last.Token,
+			Nickname:    last.Nickname,
+			Timestamp:   held[0].Timestamp,
+			NumMessages: len(held),
+		})
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].Timestamp.Before(requests[j].Timestamp)
+	})
+
+	return requests
+}
+
+// save stores the setting, the list of pending senders, and the accepted
+// senders in storage. It must be called while the lock is held.
+func (mrs *messageRequestStore) save() error {
+	pending := make([]string, 0, len(mrs.pending))
+	for elemName := range mrs.pending {
+		pending = append(pending, elemName)
+	}
+
+	data, err := json.Marshal(messageRequestDisk{
+		Enabled:  mrs.enabled,
+		Pending:  pending,
+		Accepted: mrs.accepted,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal message requests")
+	}
+
+	obj := &versioned.Object{
+		Version:   messageRequestsVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return mrs.kv.Set(messageRequestsKey, obj)
+}
+
+// saveHeld stores the held messages of the sender's message request in storage.
+func (mrs *messageRequestStore) saveHeld(
+	elemName string, held []heldMessage) error {
+	data, err := json.Marshal(held)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal held messages")
+	}
+
+	obj := &versioned.Object{
+		Version:   messageRequestHeldVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return mrs.kv.Set(makeMessageRequestKey(elemName), obj)
+}
+
+// loadHeld loads the held messages of the sender's message request from
+// storage.
+func (mrs *messageRequestStore) loadHeld(elemName string) ([]heldMessage, error) {
+	obj, err := mrs.kv.Get(
+		makeMessageRequestKey(elemName), messageRequestHeldVer)
+	if err != nil {
+		return nil, err
+	}
+
+	var held []heldMessage
+	return held, json.Unmarshal(obj.Data, &held)
+}
+
+// deleteHeld deletes the held messages of the sender's message request from
+// storage.
+func (mrs *messageRequestStore) deleteHeld(elemName string) error {
+	return mrs.kv.Delete(
+		makeMessageRequestKey(elemName), messageRequestHeldVer)
+}
+
+// makeMessageRequestKey creates the storage key for the held messages of the
+// sender's message request.
+func makeMessageRequestKey(elemName string) string {
+	return messageRequestKeyPrefix + elemName
+}
+
+// EnableMessageRequests turns the message request inbox on or off. While
+// enabled, messages from senders with no existing conversation are held until
+// the request is accepted or declined. Requests already pending are kept when
+// disabled.
+func (dc *dmClient) EnableMessageRequests(enabled bool) error {
+	return dc.mrs.setEnabled(enabled)
+}
+
+// MessageRequestsEnabled returns true if the message request inbox is enabled.
+func (dc *dmClient) MessageRequestsEnabled() bool {
+	return dc.mrs.isEnabled()
+}
+
+// GetMessageRequests returns all pending message requests, oldest first.
+func (dc *dmClient) GetMessageRequests() []MessageRequest {
+	return dc.mrs.getAll()
+}
+
+// AcceptMessageRequest accepts the pending message request from the partner.
+// The held messages are passed to the EventModel, which creates the
+// conversation, and all further messages from the partner are received
+// normally. Returns NoMessageRequestErr if there is no request from the
+// partner.
+func (dc *dmClient) AcceptMessageRequest(partnerPubKey ed25519.PublicKey) error {
+	held, err := dc.mrs.remove(partnerPubKey, true)
+	if err != nil {
+		return err
+	}
+
+	jww.INFO.Printf("[DM] Accepted message request from %s with %d messages",
+		base64.RawStdEncoding.EncodeToString(partnerPubKey), len(held))
+
+	r := &receiver{c: dc, api: dc.receiver, sendTracker: dc.st}
+	for _, m := range held {
+		uuid, err2 := r.receiveMessage(m.MessageID, m.MessageType, m.Nickname,
+			m.Payload, m.Token, partnerPubKey, partnerPubKey, m.Timestamp,
+			receptionID.EphemeralIdentity{}, rounds.Round{ID: m.RoundID},
+			Received)
+		if err2 != nil {
+			jww.WARN.Printf("[DM] Error processing held message %s from "+
+				"accepted message request (UUID: %d): %+v",
+				m.MessageID, uuid, err2)
+		}
+	}
+
+	return nil
+}
+
+// DeclineMessageRequest drops the pending message request from the partner
+// and all of its held messages. If block is true, then the partner is also
+// blocked via BlockPartner. Returns NoMessageRequestErr if there is no request
+// from the partner.
+func (dc *dmClient) DeclineMessageRequest(
+	partnerPubKey ed25519.PublicKey, block bool) error {
+	held, err := dc.mrs.remove(partnerPubKey, false)
+	if err != nil {
+		return err
+	}
+
+	jww.INFO.Printf("[DM] Declined message request from %s with %d "+
+		"messages (block: %t)",
+		base64.RawStdEncoding.EncodeToString(partnerPubKey), len(held), block)
+
+	if block {
+		dc.BlockPartner(partnerPubKey)
+	}
+
+	return nil
+}
+
+// holdMessageRequest holds the received message in a message request if
+// message requests are enabled and the sender is unknown. A sender is known if
+// this user has a conversation with them in the EventModel, previously
+// accepted their request, or, for group messages, is already in the group
+// conversation. Typing and presence signals from unknown senders are dropped.
+//
+// Returns true if the message was held or dropped and should not be processed.
+func (r *receiver) holdMessageRequest(msgID cryptoMessage.ID,
+	messageType MessageType, nick string, payload []byte, token uint32,
+	senderPubKey ed25519.PublicKey, ts time.Time, round rounds.Round) bool {
+	mrs := r.c.mrs
+	if !mrs.isEnabled() || senderPubKey.Equal(r.c.me.PubKey) ||
+		mrs.isAccepted(senderPubKey) ||
+		r.api.GetConversation(senderPubKey) != nil {
+		return false
+	}
+
+	if messageType == GroupType {
+		_, _, conversationID, err := unmarshalGroupMessage(payload)
+		if err == nil {
+			if _, exists := r.c.gs.get(conversationID); exists {
+				return false
+			}
+		}
+	}
+
+	if messageType == TypingType || messageType == PresenceType {
+		return true
+	}
+
+	jww.INFO.Printf("[DM] Holding %s message %s from unknown sender %s in "+
+		"message requests", messageType, msgID,
+		base64.RawStdEncoding.EncodeToString(senderPubKey))
+
+	err := mrs.hold(senderPubKey, heldMessage{
+		MessageID:   msgID,
+		MessageType: messageType,
+		Nickname:    nick,
+		Payload:     payload,
+		Token:       token,
+		Timestamp:   ts,
+		RoundID:     round.ID,
+	})
+	if err != nil {
+		jww.ERROR.Printf("[DM] Failed to hold message %s from %s in message "+
+			"requests: %+v", msgID,
+			base64.RawStdEncoding.EncodeToString(senderPubKey), err)
+	}
+
+	return true
+}
@@ -0,0 +1,214 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/cmix"
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/crypto/codename"
+	"gitlab.com/elixxir/crypto/fastRNG"
+	cryptoMessage "gitlab.com/elixxir/crypto/message"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/crypto/csprng"
+)
+
+// Tests that messageRequestStore.hold holds messages up to maxHeldMessages
+// without duplicates and that the store is loaded from storage.
+func Test_messageRequestStore_hold_load(t *testing.T) {
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	mrs, err := newOrLoadMessageRequestStore(
+		kv, func(ed25519.PublicKey, bool) {})
+	require.NoError(t, err)
+	require.False(t, mrs.isEnabled())
+	require.NoError(t, mrs.setEnabled(true))
+
+	sender := ed25519.PublicKey("sender")
+	start := time.Unix(1700000000, 0)
+	for i := 0; i < maxHeldMessages+5; i++ {
+		msg := heldMessage{
+			MessageID: cryptoMessage.ID{byte(i)},
+			Nickname:  "nick",
+			Token:     42,
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}
+		require.NoError(t, mrs.hold(sender, msg))
+
+		// Holding the same message again is ignored
+		require.NoError(t, mrs.hold(sender, msg))
+	}
+
+	expected := []MessageRequest{{
+		PubKey:      sender,
+		Token:       42,
+		Nickname:    "nick",
+		Timestamp:   start,
+		NumMessages: maxHeldMessages,
+	}}
+	require.Equal(t, expected, mrs.getAll())
+
+	loaded, err := newOrLoadMessageRequestStore(
+		kv, func(ed25519.PublicKey, bool) {})
+	require.NoError(t, err)
+	require.True(t, loaded.isEnabled())
+	require.Len(t, loaded.getAll(), 1)
+	require.Equal(t, expected[0].PubKey, loaded.getAll()[0].PubKey)
+	require.Equal(t, expected[0].NumMessages, loaded.getAll()[0].NumMessages)
+
+	held, err := loaded.remove(sender, true)
+	require.NoError(t, err)
+	require.Len(t, held, maxHeldMessages)
+	require.True(t, loaded.isAccepted(sender))
+	require.Empty(t, loaded.getAll())
+
+	_, err = loaded.remove(sender, true)
+	require.ErrorIs(t, err, NoMessageRequestErr)
+}
+
+// Tests that messageRequestStore.hold drops the oldest message request once
+// maxPendingRequests is reached and that each request is stored under its own
+// key.
+func Test_messageRequestStore_hold_maxPendingRequests(t *testing.T) {
+	kv := collective.TestingKV(t, ekv.MakeMemstore(),
+		collective.StandardPrefexs, collective.NewMockRemote())
+	mrs, err := newOrLoadMessageRequestStore(
+		kv, func(ed25519.PublicKey, bool) {})
+	require.NoError(t, err)
+	require.NoError(t, mrs.setEnabled(true))
+
+	start := time.Unix(1700000000, 0)
+	senders := make([]ed25519.PublicKey, maxPendingRequests+1)
+	for i := range senders {
+		senders[i] = ed25519.PublicKey(fmt.Sprintf("sender%d", i))
+		msg := heldMessage{
+			MessageID: cryptoMessage.ID{byte(i), byte(i >> 8)},
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}
+		require.NoError(t, mrs.hold(senders[i], msg))
+	}
+
+	requests := mrs.getAll()
+	require.Len(t, requests, maxPendingRequests)
+	require.Equal(t, senders[1], requests[0].PubKey)
+	require.Equal(t, senders[maxPendingRequests],
+		requests[maxPendingRequests-1].PubKey)
+
+	_, err = kv.Get(makeMessageRequestKey(marshalElementName(senders[0])),
+		messageRequestHeldVer)
+	require.Error(t, err)
+	_, err = kv.Get(makeMessageRequestKey(marshalElementName(senders[1])),
+		messageRequestHeldVer)
+	require.NoError(t, err)
+
+	loaded, err := newOrLoadMessageRequestStore(
+		kv, func(ed25519.PublicKey, bool) {})
+	require.NoError(t, err)
+	loadedRequests := loaded.getAll()
+	require.Len(t, loadedRequests, maxPendingRequests)
+	for i := range requests {
+		require.Equal(t, requests[i].PubKey, loadedRequests[i].PubKey)
+	}
+}
+
+// Tests that, with message requests enabled, messages from an unknown sender
+// are held until accepted, that accepting passes them to the EventModel, and
+// that declining drops them and optionally blocks the sender.
+func TestE2EDMs_MessageRequests(t *testing.T) {
+	nets := createLinkedNetsN(t, 3)
+	crng := fastRNG.NewStreamGenerator(100, 5, csprng.NewSystemRNG)
+
+	ids := make([]codename.PrivateIdentity, len(nets))
+	clients := make([]*dmClient, len(nets))
+	receivers := make([]*mockRequestReceiver, len(nets))
+	for i := range nets {
+		rng := crng.GetStream()
+		ids[i], _ = codename.GenerateIdentity(rng)
+		rng.Close()
+
+		kv := collective.TestingKV(t, ekv.MakeMemstore(),
+			collective.StandardPrefexs, collective.NewMockRemote())
+		receivers[i] = &mockRequestReceiver{newMockReceiver()}
+		var err error
+		clients[i], err = newDmClient(&ids[i], receivers[i],
+			NewSendTracker(kv), NewNicknameManager(DeriveReceptionID(
+				ids[i].PubKey, ids[i].GetDMToken()), kv),
+			newMockNM(), nets[i], kv, crng, nil)
+		require.NoError(t, err)
+	}
+	me, a, b := clients[0], clients[1], clients[2]
+	meRecv := receivers[0]
+	require.NoError(t, me.EnableMessageRequests(true))
+	require.True(t, me.MessageRequestsEnabled())
+
+	params := cmix.GetDefaultCMIXParams()
+	token := ids[0].GetDMToken()
+	for _, text := range []string{"Hi", "Are you there?"} {
+		_, _, _, err := a.SendText(ids[0].PubKey, token, text, params)
+		require.NoError(t, err)
+	}
+	_, _, _, err := b.SendText(ids[0].PubKey, token, "Spam", params)
+	require.NoError(t, err)
+
+	// Nothing reaches the EventModel until accepted
+	require.Empty(t, meRecv.Msgs)
+	requests := me.GetMessageRequests()
+	require.Len(t, requests, 2)
+	numMessages := map[string]int{}
+	for _, r := range requests {
+		numMessages[string(r.PubKey)] = r.NumMessages
+	}
+	require.Equal(t, 2, numMessages[string(ids[1].PubKey)])
+	require.Equal(t, 1, numMessages[string(ids[2].PubKey)])
+
+	// Accepting passes the held messages to the EventModel
+	require.NoError(t, me.AcceptMessageRequest(ids[1].PubKey))
+	require.Len(t, meRecv.Msgs, 2)
+	require.Equal(t, "Hi", meRecv.Msgs[0].Message)
+	require.Equal(t, "Are you there?", meRecv.Msgs[1].Message)
+	require.Equal(t, ids[1].PubKey, meRecv.Msgs[0].PubKey)
+
+	// Further messages from the accepted sender are received normally
+	_, _, _, err = a.SendText(ids[0].PubKey, token, "Thanks", params)
+	require.NoError(t, err)
+	require.Len(t, meRecv.Msgs, 3)
+
+	// Declining drops the held messages and blocks the sender
+	require.NoError(t, me.DeclineMessageRequest(ids[2].PubKey, true))
+	require.Empty(t, me.GetMessageRequests())
+	require.True(t, me.IsBlocked(ids[2].PubKey))
+	_, _, _, err = b.SendText(ids[0].PubKey, token, "Spam again", params)
+	require.NoError(t, err)
+	require.Len(t, meRecv.Msgs, 3)
+	require.Empty(t, me.GetMessageRequests())
+
+	require.ErrorIs(t,
+		me.AcceptMessageRequest(ids[2].PubKey), NoMessageRequestErr)
+}
+
+// mockRequestReceiver is a mockReceiver that only has conversations with
+// partners it has received messages from.
+type mockRequestReceiver struct {
+	*mockReceiver
+}
+
+func (m *mockRequestReceiver) GetConversation(
+	pubKey ed25519.PublicKey) *ModelConversation {
+	for _, msg := range m.Msgs {
+		if msg.PubKey.Equal(pubKey) {
+			return &ModelConversation{Pubkey: pubKey}
+		}
+	}
+	return nil
+}
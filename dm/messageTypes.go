@@ -37,6 +37,28 @@ const (
 	// DeleteType denotes that the message contains the ID of a message to
 	// delete.
 	DeleteType MessageType = 6
+
+	// DisappearingType denotes that the message sets the duration after which
+	// messages in the conversation are deleted.
+	DisappearingType MessageType = 7
+
+	// TypingType denotes that the message is an ephemeral signal that the
+	// sender started or stopped typing. It is never passed to the EventModel.
+	TypingType MessageType = 8
+
+	// PresenceType denotes that the message is an ephemeral signal that the
+	// sender came online or went offline. It is never passed to the
+	// EventModel.
+	PresenceType MessageType = 9
+
+	// GroupType denotes that the message wraps a message of another type sent
+	// to every member of a group DM conversation.
+	GroupType MessageType = 10
+
+	// FileTransferType denotes that the message contains the information
+	// about a file uploaded via channelsFileTransfer that the recipient can
+	// download.
+	FileTransferType MessageType = 11
 )
 
 // String returns a human-readable version of [MessageType], used for debugging
@@ -55,6 +77,16 @@ func (mt MessageType) String() string {
 		return "Invitation"
 	case DeleteType:
 		return "Delete"
+	case DisappearingType:
+		return "Disappearing"
+	case TypingType:
+		return "Typing"
+	case PresenceType:
+		return "Presence"
+	case GroupType:
+		return "Group"
+	case FileTransferType:
+		return "FileTransfer"
 	default:
 		return "Unknown messageType " + strconv.Itoa(int(mt))
 	}
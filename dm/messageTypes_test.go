@@ -17,8 +17,13 @@ func TestMessageType_String_Consistency(t *testing.T) {
 	expectedStrings := map[MessageType]string{
 		TextType: "Text", ReplyType: "Reply", ReactionType: "Reaction",
 		SilentType: "Silent", InvitationType: "Invitation", DeleteType: "Delete",
-		DeleteType + 1: fmt.Sprintf("Unknown messageType %d", DeleteType+1),
-		DeleteType + 2: fmt.Sprintf("Unknown messageType %d", DeleteType+2),
+		DisappearingType: "Disappearing", TypingType: "Typing",
+		PresenceType: "Presence", GroupType: "Group",
+		FileTransferType: "FileTransfer",
+		FileTransferType + 1: fmt.Sprintf(
+			"Unknown messageType %d", FileTransferType+1),
+		FileTransferType + 2: fmt.Sprintf(
+			"Unknown messageType %d", FileTransferType+2),
 	}
 
 	for mt, expected := range expectedStrings {
@@ -33,7 +38,9 @@ func TestMessageType_String_Consistency(t *testing.T) {
 // via UnmarshalMessageType matches the original.
 func TestMessageType_Marshal_UnmarshalMessageType(t *testing.T) {
 	tests := []MessageType{
-		TextType, ReplyType, ReactionType, SilentType, InvitationType, DeleteType}
+		TextType, ReplyType, ReactionType, SilentType, InvitationType, DeleteType,
+		DisappearingType, TypingType, PresenceType, GroupType,
+		FileTransferType}
 
 	for _, mt := range tests {
 		data := mt.Marshal()
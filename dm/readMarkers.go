@@ -0,0 +1,226 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file                                                               //
+////////////////////////////////////////////////////////////////////////////////
+
+package dm
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/client/v4/collective"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Storage values.
+const (
+	readMarkerMapName    = "dmReadMarkerMap"
+	readMarkerMapVersion = 0
+	readMarkerStoreVer   = 0
+)
+
+// ReadMarkersUnsupportedErr is returned when getting the unread count of a
+// conversation when the EventModel does not implement ReadMarkerEventModel.
+var ReadMarkersUnsupportedErr = errors.New(
+	"the event model does not support read markers")
+
+// ReadMarkerEventModel is an EventModel that stores the read marker of each
+// conversation and counts the messages received after it. If the EventModel
+// passed to the Client implements this interface, then every read marker set
+// on any of the user's devices is passed to it.
+type ReadMarkerEventModel interface {
+	EventModel
+
+	// MarkRead is called whenever the read marker of a conversation advances.
+	// All messages in the conversation with a timestamp at or before the
+	// marker are read.
+	MarkRead(partnerPubKey ed25519.PublicKey, timestamp time.Time) error
+
+	// GetUnreadCount returns the number of messages from the partner received
+	// after the read marker.
+	GetUnreadCount(partnerPubKey ed25519.PublicKey) (uint64, error)
+}
+
+// readMarkerStore tracks the timestamp of the last read message in each
+// conversation. Markers are stored in the remote KV so that they are synced
+// between all the user's devices. A marker only ever moves forward in time.
+type readMarkerStore struct {
+	byPartner map[string]time.Time
+	callback  func(partnerPubKey ed25519.PublicKey, lastRead time.Time)
+	remote    versioned.KV
+	mux       sync.RWMutex
+}
+
+// newReadMarkerStore loads the read markers from the remote KV and listens for
+// changes made on other devices. The callback is called for every loaded marker
+// and every time a marker advances.
+func newReadMarkerStore(kv versioned.KV, callback func(
+	partnerPubKey ed25519.PublicKey, lastRead time.Time)) (
+	*readMarkerStore, error) {
+	remote, err := kv.Prefix(collective.StandardRemoteSyncPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	rms := &readMarkerStore{
+		byPartner: make(map[string]time.Time),
+		callback:  callback,
+		remote:    remote,
+	}
+
+	err = rms.remote.ListenOnRemoteMap(
+		readMarkerMapName, readMarkerMapVersion, rms.mapUpdate, false)
+	if err != nil && rms.remote.Exists(err) {
+		return nil, err
+	}
+
+	return rms, nil
+}
+
+// markRead advances the read marker of the conversation to the timestamp. It
+// does nothing if the current marker is at or after the timestamp.
+func (rms *readMarkerStore) markRead(
+	partnerPubKey ed25519.PublicKey, timestamp time.Time) error {
+	rms.mux.Lock()
+	defer rms.mux.Unlock()
+
+	elemName := marshalElementName(partnerPubKey)
+	if lastRead, exists := rms.byPartner[elemName]; exists &&
+		!timestamp.After(lastRead) {
+		return nil
+	}
+
+	data, err := json.Marshal(timestamp)
+	if err != nil {
+		return err
+	}
+
+	obj := &versioned.Object{
+		Version:   readMarkerStoreVer,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	err = rms.remote.StoreMapElement(
+		readMarkerMapName, elemName, obj, readMarkerMapVersion)
+	if err != nil {
+		return err
+	}
+
+	rms.byPartner[elemName] = timestamp
+	go rms.callback(partnerPubKey, timestamp)
+
+	return nil
+}
+
+// get returns the read marker of the conversation. Returns false if no
+// messages in the conversation have been read.
+func (rms *readMarkerStore) get(
+	partnerPubKey ed25519.PublicKey) (lastRead time.Time, exists bool) {
+	rms.mux.RLock()
+	defer rms.mux.RUnlock()
+
+	lastRead, exists = rms.byPartner[marshalElementName(partnerPubKey)]
+	return lastRead, exists
+}
+
+// mapUpdate is called when the read markers are updated on another device.
+// Markers are only applied if they are newer than the local marker.
+func (rms *readMarkerStore) mapUpdate(edits map[string]versioned.ElementEdit) {
+	rms.mux.Lock()
+	defer rms.mux.Unlock()
+
+	for elemName, edit := range edits {
+		pubKey, err := unmarshalElementName(elemName)
+		if err != nil {
+			jww.ERROR.Printf("[DM] Failed to parse read marker element "+
+				"name %s: %+v", elemName, err)
+			continue
+		}
+
+		switch edit.Operation {
+		case versioned.Deleted:
+			delete(rms.byPartner, elemName)
+			continue
+		case versioned.Created, versioned.Updated, versioned.Loaded:
+		default:
+			jww.WARN.Printf("[DM] Failed to handle read marker update %s, "+
+				"bad operation: %s, skipping", elemName, edit.Operation)
+			continue
+		}
+
+		var timestamp time.Time
+		if err = json.Unmarshal(edit.NewElement.Data, &timestamp); err != nil {
+			jww.ERROR.Printf("[DM] Failed to parse read marker for element "+
+				"name %q: %+v", elemName, err)
+			continue
+		}
+
+		if lastRead, exists := rms.byPartner[elemName]; exists &&
+			!timestamp.After(lastRead) {
+			continue
+		}
+
+		rms.byPartner[elemName] = timestamp
+		go rms.callback(pubKey, timestamp)
+	}
+}
+
+// MarkRead marks all messages in the conversation with a timestamp at or
+// before the given timestamp as read. The read marker is synced to all the
+// user's devices. It only ever moves forward; marking an older timestamp does
+// nothing.
+func (dc *dmClient) MarkRead(
+	partnerPubKey ed25519.PublicKey, timestamp time.Time) error {
+	jww.INFO.Printf("[DM] MarkRead with %X at %s", partnerPubKey, timestamp)
+	return dc.rms.markRead(partnerPubKey, timestamp)
+}
+
+// GetReadMarker returns the timestamp of the last read message in the
+// conversation. Returns false if no messages in the conversation have been
+// marked read.
+func (dc *dmClient) GetReadMarker(
+	partnerPubKey ed25519.PublicKey) (time.Time, bool) {
+	return dc.rms.get(partnerPubKey)
+}
+
+// GetUnreadCount returns the number of messages from the partner received
+// after the read marker.
+//
+// Returns ReadMarkersUnsupportedErr if the EventModel does not implement
+// ReadMarkerEventModel.
+func (dc *dmClient) GetUnreadCount(
+	partnerPubKey ed25519.PublicKey) (uint64, error) {
+	model, ok := dc.receiver.(ReadMarkerEventModel)
+	if !ok {
+		return 0, ReadMarkersUnsupportedErr
+	}
+	return model.GetUnreadCount(partnerPubKey)
+}
+
+// updateReadMarker returns the callback registered on the readMarkerStore. It
+// passes each marker to the EventModel, if it stores read markers, and to the
+// UI.
+func updateReadMarker(receiver EventModel,
+	cb func(partnerPubKey ed25519.PublicKey, lastRead time.Time)) func(
+	partnerPubKey ed25519.PublicKey, lastRead time.Time) {
+	return func(partnerPubKey ed25519.PublicKey, lastRead time.Time) {
+		if model, ok := receiver.(ReadMarkerEventModel); ok {
+			if err := model.MarkRead(partnerPubKey, lastRead); err != nil {
+				jww.ERROR.Printf("[DM] Failed to mark conversation with %X "+
+					"read at %s in event model: %+v",
+					partnerPubKey, lastRead, err)
+			}
+		}
+		cb(partnerPubKey, lastRead)
+	}
+}
@@ -79,6 +79,15 @@ func (dp *dmProcessor) Process(msg format.Message, _ []string, _ []byte,
 
 	msgID := message.DeriveDirectMessageID(myID, directMsg)
 
+	// Messages to group conversations are derived from the conversation ID so
+	// that every member has the same message ID
+	if MessageType(directMsg.PayloadType) == GroupType {
+		if msgID, err = groupMessageIdFromDirectMessage(directMsg); err != nil {
+			jww.ERROR.Printf("[DM] Failed to parse group DM: %+v", err)
+			return
+		}
+	}
+
 	// Check if we sent the message and ignore triggering if we sent
 	// This will happen when DMing with oneself, but the receive self
 	// processor will update the status to delivered, so we do nothing here.
@@ -124,6 +133,12 @@ func (dp *dmProcessor) Process(msg format.Message, _ []string, _ []byte,
 	// partner Token is the sender Token
 	partnerToken := senderToken
 
+	// Hold messages from unknown senders until the message request is accepted
+	if dp.r.holdMessageRequest(msgID, messageType, directMsg.Nickname,
+		directMsg.Payload, partnerToken, pubSigningKey, ts, round) {
+		return
+	}
+
 	// Process the receivedMessage. This is already in an instanced event;
 	// no new thread is needed.
 	// Note that in the non-self send case, the partner key and sender
@@ -180,6 +195,15 @@ func (sp *selfProcessor) Process(msg format.Message, _ []string, _ []byte,
 
 	msgID := message.DeriveDirectMessageID(partnerID, directMsg)
 
+	// Messages to group conversations are derived from the conversation ID so
+	// that every member has the same message ID
+	if MessageType(directMsg.PayloadType) == GroupType {
+		if msgID, err = groupMessageIdFromDirectMessage(directMsg); err != nil {
+			jww.ERROR.Printf("[DM] Failed to parse group DM (self): %+v", err)
+			return
+		}
+	}
+
 	// Check if we sent the message and ignore triggering if we
 	// sent, but mark the message as delivered
 	if sp.r.sendTracker.CheckIfSent(msgID, round) {
@@ -253,7 +277,7 @@ func (r *receiver) GetProcessor() *dmProcessor {
 func (r *receiver) receiveMessage(msgID message.ID, messageType MessageType,
 	nick string, plaintext []byte, partnerDMToken uint32,
 	partnerPubKey, senderPubKey ed25519.PublicKey, ts time.Time,
-	_ receptionID.EphemeralIdentity, round rounds.Round,
+	ephID receptionID.EphemeralIdentity, round rounds.Round,
 	status Status) (uint64, error) {
 
 	// If this message was already deleted, then drop it
@@ -261,6 +285,13 @@ func (r *receiver) receiveMessage(msgID message.ID, messageType MessageType,
 		return 0, nil
 	}
 
+	// Schedule the message to be deleted if disappearing messages are enabled
+	// in the conversation. Messages sent by this client are scheduled once
+	// their message ID is known.
+	if status == Received {
+		r.c.dms.schedule(partnerPubKey, senderPubKey, msgID, messageType, ts)
+	}
+
 	switch messageType {
 	case TextType:
 		return r.receiveTextMessage(msgID, messageType,
@@ -281,6 +312,19 @@ func (r *receiver) receiveMessage(msgID message.ID, messageType MessageType,
 	case DeleteType:
 		return r.deleteMessage(msgID, messageType, plaintext, partnerPubKey,
 			senderPubKey, 0, ts, round)
+	case DisappearingType:
+		return r.receiveDisappearing(msgID, messageType, nick, plaintext,
+			partnerDMToken, partnerPubKey, senderPubKey, 0, ts, round, status)
+	case TypingType, PresenceType:
+		return r.receiveEphemeral(msgID, messageType, plaintext,
+			partnerPubKey, senderPubKey, ts, status)
+	case FileTransferType:
+		return r.receiveFileTransfer(msgID, messageType,
+			nick, plaintext, partnerDMToken, partnerPubKey,
+			senderPubKey, 0, ts, round, status)
+	case GroupType:
+		return r.receiveGroupMessage(msgID, messageType, nick, plaintext,
+			senderPubKey, ts, ephID, round, status)
 	default:
 		return r.api.Receive(msgID, nick, plaintext,
 			partnerPubKey, senderPubKey,
@@ -429,6 +473,31 @@ func (r *receiver) receiveInvitation(messageID message.ID,
 		timestamp, round, InvitationType, status), nil
 }
 
+// receiveFileTransfer is the internal function that handles the reception of
+// file links. The content is the JSON of channelsFileTransfer.FileInfo and is
+// passed as is to the EventModel so that the user can initiate the download.
+//
+// Messages that do not contain valid JSON are dropped.
+func (r *receiver) receiveFileTransfer(messageID message.ID,
+	messageType MessageType, nickname string, content []byte,
+	dmToken uint32, partnerPubKey, senderPubKey ed25519.PublicKey,
+	codeset uint8, timestamp time.Time, round rounds.Round,
+	status Status) (uint64, error) {
+	if !json.Valid(content) {
+		return 0, errors.Errorf("Failed to parse file info in DM %s "+
+			"with %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	tag := makeDebugTag(partnerPubKey, content, SendFileTag)
+	jww.INFO.Printf("[%s] DM - Received file with partner %s ",
+		tag, base64.StdEncoding.EncodeToString(partnerPubKey))
+
+	return r.api.Receive(messageID, nickname, content,
+		partnerPubKey, senderPubKey, dmToken, codeset,
+		timestamp, round, FileTransferType, status), nil
+}
+
 // deleteMessage processes a request to delete a message. If the target message,
 // exists, then it is deleted. If it does not exist, then it is added to the
 // action savor.
@@ -473,6 +542,57 @@ func (r *receiver) deleteMessage(messageID message.ID, messageType MessageType,
 	return 0, nil
 }
 
+// receiveDisappearing is the internal function that handles the reception of
+// disappearing message timers. The timer is applied to the conversation if it
+// is newer than the current timer and the message is passed to the EventModel
+// so that the UI can show the change.
+func (r *receiver) receiveDisappearing(messageID message.ID,
+	messageType MessageType, nickname string, content []byte,
+	dmToken uint32, partnerPubKey, senderPubKey ed25519.PublicKey,
+	codeset uint8, timestamp time.Time, round rounds.Round,
+	status Status) (uint64, error) {
+	disappearing := &DisappearingMessages{}
+	if err := proto.Unmarshal(content, disappearing); err != nil {
+		return 0, errors.Wrapf(err, "failed to unmarshal DM %s "+
+			"with %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	timer := time.Duration(disappearing.Timer)
+	if timer < 0 {
+		return 0, errors.Errorf("invalid disappearing message timer %s in "+
+			"DM %s with %x, type %s, ts: %s, round: %d", timer, messageID,
+			partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	tag := makeDebugTag(partnerPubKey, content, SendDisappearingTag)
+	jww.INFO.Printf("[%s] DM - Received disappearing message timer %s with "+
+		"partner %s", tag, timer,
+		base64.StdEncoding.EncodeToString(partnerPubKey))
+
+	// Timers sent by this client are applied once they are sent. The round
+	// timestamp is used so that both sides order settings identically.
+	if status == Received {
+		_, err := r.c.dms.setTimer(
+			partnerPubKey, timer, round.Timestamps[states.QUEUED])
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to save disappearing "+
+				"message timer from DM %s with %x", messageID, partnerPubKey)
+		}
+	}
+
+	disappearingJSON, err := json.Marshal(disappearing)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to json marshal DM %s "+
+			"with %x, type %s, ts: %s, round: %d",
+			messageID, partnerPubKey, messageType, timestamp, round.ID)
+	}
+
+	return r.api.Receive(messageID, nickname, disappearingJSON,
+		partnerPubKey, senderPubKey, dmToken, codeset,
+		timestamp, round, DisappearingType, status), nil
+}
+
 // This helper does the opposite of "createCMIXFields" in send.go
 func reconstructCiphertext(msg format.Message) []byte {
 	var res []byte
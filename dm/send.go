@@ -13,6 +13,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
@@ -27,6 +28,7 @@ import (
 	"gitlab.com/elixxir/crypto/nike"
 	"gitlab.com/elixxir/crypto/nike/ecdh"
 	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/elixxir/primitives/states"
 	"gitlab.com/xx_network/primitives/id"
 	"gitlab.com/xx_network/primitives/id/ephemeral"
 	"gitlab.com/xx_network/primitives/netTime"
@@ -41,6 +43,7 @@ const (
 	invitationVersion = 0
 	silentVersion     = 0
 	deleteVersion     = 0
+	disappearVersion  = 0
 
 	// SendMessageTag is the base tag used when generating a debug tag for
 	// sending a message.
@@ -66,6 +69,14 @@ const (
 	// delete message.
 	DeleteMessageTag = "Delete"
 
+	// SendDisappearingTag is the base tag used when generating a debug tag for
+	// setting the disappearing message timer.
+	SendDisappearingTag = "Disappearing"
+
+	// SendFileTag is the base tag used when generating a debug tag for
+	// sending a file link.
+	SendFileTag = "File"
+
 	directMessageDebugTag = "dm"
 	// The size of the nonce used in the message ID.
 	messageNonceSize = 4
@@ -286,6 +297,55 @@ func (dc *dmClient) DeleteMessage(partnerPubKey ed25519.PublicKey,
 		partnerPubKey, partnerToken, DeleteType, deleteMarshaled, params)
 }
 
+// SetDisappearingTimer sets the duration after which messages in the
+// conversation are deleted and sends it to the partner so that it is applied
+// on both sides. A timer of zero disables disappearing messages.
+func (dc *dmClient) SetDisappearingTimer(partnerPubKey ed25519.PublicKey,
+	partnerToken uint32, timer time.Duration, params cmix.CMIXParams) (
+	cryptoMessage.ID, rounds.Round, ephemeral.Id, error) {
+	if timer < 0 {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{},
+			errors.Errorf("invalid disappearing message timer: %s", timer)
+	}
+
+	tag := makeDebugTag(partnerPubKey, []byte(timer.String()),
+		SendDisappearingTag)
+	jww.INFO.Printf("[DM][%s] SetDisappearingTimer(%s, %s)", tag,
+		base64.RawStdEncoding.EncodeToString(partnerPubKey), timer)
+
+	disappearing := &DisappearingMessages{
+		Version: disappearVersion,
+		Timer:   int64(timer),
+	}
+
+	params = params.SetDebugTag(tag)
+	disappearingMarshaled, err := proto.Marshal(disappearing)
+	if err != nil {
+		return cryptoMessage.ID{}, rounds.Round{}, ephemeral.Id{}, err
+	}
+
+	msgID, rnd, ephID, err := dc.Send(partnerPubKey, partnerToken,
+		DisappearingType, disappearingMarshaled, params)
+	if err != nil {
+		return msgID, rnd, ephID, err
+	}
+
+	// Stamp the timer with the round timestamp, as is done by the partner on
+	// reception, so that both sides order settings identically
+	ts := rnd.Timestamps[states.QUEUED]
+	if ts.IsZero() {
+		ts = netTime.Now()
+	}
+
+	_, err = dc.dms.setTimer(partnerPubKey, timer, ts)
+	if err != nil {
+		return msgID, rnd, ephID, errors.Wrap(err,
+			"failed to save disappearing message timer")
+	}
+
+	return msgID, rnd, ephID, nil
+}
+
 // Send is used to send a raw direct message to a DM partner. In general, it
 // should be wrapped in a function that defines the wire protocol.
 //
@@ -428,6 +488,10 @@ func (dc *dmClient) Send(partnerEdwardsPubKey ed25519.PublicKey,
 		sendPrint += fmt.Sprintf(", dm send denote failed: %s ",
 			err.Error())
 	}
+
+	// Schedule the message to be deleted if disappearing messages are enabled
+	dc.dms.schedule(
+		partnerEdwardsPubKey, dc.me.PubKey, msgID, messageType, netTime.Now())
 	return msgID, rndID, ephIDs[1], err
 
 }
@@ -0,0 +1,78 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in WASM.
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/channels"
+	cft "gitlab.com/elixxir/client/v4/channelsFileTransfer"
+	"gitlab.com/elixxir/crypto/fileTransfer"
+)
+
+// Happy path test for receiving, updating, getting, and deleting a File.
+func TestImpl_ReceiveFile(t *testing.T) {
+	m, err := newImpl("TestImpl_ReceiveFile", &dummyCallbacks{}, true)
+	require.NoError(t, err)
+
+	testTs := time.Now()
+	fileData := []byte("TestImpl_ReceiveFile")
+	fileLink := []byte("fileLink")
+	fID := fileTransfer.NewID(fileData)
+
+	// Insert a downloading file with no data
+	err = m.ReceiveFile(fID, fileLink, nil, testTs, cft.Downloading)
+	require.NoError(t, err)
+
+	storedFile, err := m.GetFile(fID)
+	require.NoError(t, err)
+	require.Equal(t, fID, storedFile.ID)
+	require.Equal(t, fileLink, storedFile.Link)
+	require.Empty(t, storedFile.Data)
+	require.Equal(t, cft.Downloading, storedFile.Status)
+
+	// Complete the download
+	newTs := time.Now()
+	newStatus := cft.Complete
+	err = m.UpdateFile(fID, nil, fileData, &newTs, &newStatus)
+	require.NoError(t, err)
+
+	updatedFile, err := m.GetFile(fID)
+	require.NoError(t, err)
+	require.Equal(t, fileLink, updatedFile.Link)
+	require.Equal(t, fileData, updatedFile.Data)
+	require.True(t, updatedFile.Timestamp.Equal(newTs))
+	require.Equal(t, newStatus, updatedFile.Status)
+
+	// Delete the file
+	require.NoError(t, m.DeleteFile(fID))
+	_, err = m.GetFile(fID)
+	require.True(t, channels.CheckNoMessageErr(err), "%+v", err)
+}
+
+// Tests that impl.UpdateFile and impl.DeleteFile return channels.NoMessageErr
+// for a file that does not exist.
+func TestImpl_UpdateFile_DeleteFile_NoFile(t *testing.T) {
+	m, err := newImpl("TestImpl_UpdateFile_DeleteFile_NoFile",
+		&dummyCallbacks{}, true)
+	require.NoError(t, err)
+
+	fID := fileTransfer.NewID([]byte("TestImpl_UpdateFile_DeleteFile_NoFile"))
+	status := cft.Complete
+
+	err = m.UpdateFile(fID, nil, nil, nil, &status)
+	require.True(t, channels.CheckNoMessageErr(err), "%+v", err)
+
+	err = m.DeleteFile(fID)
+	require.True(t, channels.CheckNoMessageErr(err), "%+v", err)
+}
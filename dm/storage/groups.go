@@ -0,0 +1,69 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gitlab.com/elixxir/client/v4/dm"
+)
+
+// UpdateGroupConversation is called when a group DM conversation is created or
+// the first message in it is received. It replaces the stored members of the
+// conversation.
+func (i *impl) UpdateGroupConversation(
+	conversationID ed25519.PublicKey, members []dm.GroupMember) error {
+	parentErr := "failed to UpdateGroupConversation"
+
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("conversation_pub_key = ?", []byte(conversationID)).
+			Delete(&GroupMember{}).Error
+		if err != nil {
+			return err
+		}
+
+		rows := make([]GroupMember, len(members))
+		for j, m := range members {
+			rows[j] = GroupMember{
+				ConversationPubKey: conversationID,
+				PubKey:             m.PubKey,
+				Token:              m.Token,
+			}
+		}
+		return tx.Create(&rows).Error
+	})
+	cancel()
+
+	if err != nil {
+		return errors.WithMessage(err, parentErr)
+	}
+	return nil
+}
+
+// GetGroupMembers returns the members of the group DM conversation sorted by
+// public key. Returns an empty list if the conversation does not exist.
+func (i *impl) GetGroupMembers(
+	conversationID ed25519.PublicKey) ([]dm.GroupMember, error) {
+	var rows []GroupMember
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Order("pub_key").Find(&rows,
+		"conversation_pub_key = ?", []byte(conversationID)).Error
+	cancel()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to GetGroupMembers")
+	}
+
+	members := make([]dm.GroupMember, len(rows))
+	for j, row := range rows {
+		members[j] = dm.GroupMember{PubKey: row.PubKey, Token: row.Token}
+	}
+	return members, nil
+}
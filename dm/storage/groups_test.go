@@ -0,0 +1,64 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"reflect"
+	"testing"
+
+	"gitlab.com/elixxir/client/v4/dm"
+)
+
+// Tests that the members stored with impl.UpdateGroupConversation are returned
+// by impl.GetGroupMembers and that updating the conversation replaces them.
+func TestImpl_UpdateGroupConversation_GetGroupMembers(t *testing.T) {
+	model, err := newImpl(
+		"TestImpl_UpdateGroupConversation", &dummyCallbacks{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conversationID := ed25519.PublicKey("conversationID")
+	members := []dm.GroupMember{
+		{PubKey: ed25519.PublicKey("a"), Token: 1},
+		{PubKey: ed25519.PublicKey("b"), Token: 2},
+		{PubKey: ed25519.PublicKey("c"), Token: 3},
+	}
+
+	received, err := model.GetGroupMembers(conversationID)
+	if err != nil {
+		t.Fatalf("Failed to get members: %+v", err)
+	} else if len(received) != 0 {
+		t.Errorf("Members returned for unknown conversation: %+v", received)
+	}
+
+	if err = model.UpdateGroupConversation(conversationID, members); err != nil {
+		t.Fatalf("Failed to update group conversation: %+v", err)
+	}
+
+	received, err = model.GetGroupMembers(conversationID)
+	if err != nil {
+		t.Fatalf("Failed to get members: %+v", err)
+	} else if !reflect.DeepEqual(members, received) {
+		t.Errorf("Unexpected members.\nexpected: %+v\nreceived: %+v",
+			members, received)
+	}
+
+	members = members[1:]
+	if err = model.UpdateGroupConversation(conversationID, members); err != nil {
+		t.Fatalf("Failed to update group conversation: %+v", err)
+	}
+
+	received, err = model.GetGroupMembers(conversationID)
+	if err != nil {
+		t.Fatalf("Failed to get members: %+v", err)
+	} else if !reflect.DeepEqual(members, received) {
+		t.Errorf("Unexpected members after update."+
+			"\nexpected: %+v\nreceived: %+v", members, received)
+	}
+}
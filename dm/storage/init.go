@@ -19,6 +19,7 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	cft "gitlab.com/elixxir/client/v4/channelsFileTransfer"
 	"gitlab.com/elixxir/client/v4/dm"
 	"gitlab.com/elixxir/crypto/message"
 )
@@ -51,11 +52,26 @@ type impl struct {
 	cbs Callbacks
 }
 
-// NewEventModel initializes the [dm.EventModel] interface with appropriate backend.
-func NewEventModel(dbFilePath string, cbs Callbacks) (dm.EventModel, error) {
+// EventModel is the [dm.ReadMarkerEventModel], [dm.GroupEventModel], and
+// [channelsFileTransfer.FileEventModel] implemented by this package.
+type EventModel interface {
+	dm.ReadMarkerEventModel
+	dm.GroupEventModel
+	cft.FileEventModel
+
+	// GetGroupMembers returns the members of the group DM conversation sorted
+	// by public key. Returns an empty list if the conversation does not exist.
+	GetGroupMembers(conversationID ed25519.PublicKey) ([]dm.GroupMember, error)
+}
+
+// NewEventModel initializes the [EventModel] interface with appropriate backend.
+func NewEventModel(dbFilePath string, cbs Callbacks) (EventModel, error) {
 	useTemporary := len(dbFilePath) == 0
 	model, err := newImpl(dbFilePath, cbs, useTemporary)
-	return dm.EventModel(model), err
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
 }
 
 // If useTemporary is set to true, this will use an in-RAM database.
@@ -105,7 +121,8 @@ func newImpl(dbFilePath string, cbs Callbacks, useTemporary bool) (*impl, error)
 
 	// Initialize the database schema
 	// WARNING: Order is important. Do not change without database testing
-	err = db.AutoMigrate(&Conversation{}, &Message{})
+	err = db.AutoMigrate(
+		&Conversation{}, &Message{}, &ReadMarker{}, &GroupMember{}, &File{})
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +33,63 @@ func (Message) TableName() string {
 	return "dm_messages"
 }
 
+// ReadMarker defines the SQL representation of the read marker of a
+// Conversation. All messages in the conversation with a timestamp at or before
+// LastRead are read.
+//
+// A ReadMarker is not tied to a Conversation because read markers synced from
+// other devices may arrive before the first message of the conversation.
+type ReadMarker struct {
+	ConversationPubKey []byte    `gorm:"primaryKey;not null;autoIncrement:false"`
+	LastRead           time.Time `gorm:"not null"`
+}
+
+// TableName overrides the table name used by ReadMarker.
+func (ReadMarker) TableName() string {
+	return "dm_read_markers"
+}
+
+// GroupMember defines the SQL representation of a member of a group DM
+// Conversation. The ConversationPubKey is the ID of the group conversation.
+//
+// A GroupMember is not tied to a Conversation because the members of a group
+// conversation are stored before its first message.
+type GroupMember struct {
+	ConversationPubKey []byte `gorm:"primaryKey;not null;autoIncrement:false"`
+	PubKey             []byte `gorm:"primaryKey;not null;autoIncrement:false"`
+	Token              uint32 `gorm:"not null"`
+}
+
+// TableName overrides the table name used by GroupMember.
+func (GroupMember) TableName() string {
+	return "dm_group_members"
+}
+
+// File defines the SQL representation of a single file sent or received in a
+// direct message. Files are tracked independently of the Message that contains
+// their file link.
+type File struct {
+	// Id is a unique identifier for a given File.
+	Id []byte `gorm:"primaryKey;not null;autoIncrement:false"`
+
+	// Data stores the actual contents of the File.
+	Data []byte
+
+	// Link contains all the information needed to download the file data.
+	Link []byte
+
+	// Timestamp is the last time the file data, link, or status was modified.
+	Timestamp time.Time `gorm:"not null"`
+
+	// Status of the file in the event model.
+	Status uint8 `gorm:"not null"`
+}
+
+// TableName overrides the table name used by File.
+func (File) TableName() string {
+	return "dm_files"
+}
+
 // Conversation defines the IndexedDb representation of a single
 // message exchange between two recipients.
 // A Conversation has many Message objects.
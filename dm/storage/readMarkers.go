@@ -0,0 +1,81 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gitlab.com/elixxir/client/v4/dm"
+)
+
+// MarkRead is called whenever the read marker of a conversation advances. All
+// messages in the conversation with a timestamp at or before the marker are
+// read. The marker is never moved backwards.
+func (i *impl) MarkRead(
+	partnerPubKey ed25519.PublicKey, timestamp time.Time) error {
+	parentErr := "failed to MarkRead"
+
+	// Build a transaction to prevent race conditions
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		marker := &ReadMarker{}
+		err := tx.Limit(1).Find(marker,
+			"conversation_pub_key = ?", []byte(partnerPubKey)).Error
+		if err != nil {
+			return err
+		} else if marker.ConversationPubKey != nil &&
+			!timestamp.After(marker.LastRead) {
+			return nil
+		}
+
+		return tx.Save(&ReadMarker{
+			ConversationPubKey: partnerPubKey,
+			LastRead:           timestamp,
+		}).Error
+	})
+	cancel()
+
+	if err != nil {
+		return errors.WithMessage(err, parentErr)
+	}
+	return nil
+}
+
+// GetUnreadCount returns the number of messages from the partner received after
+// the read marker. Messages sent by this user and reactions are not counted. If
+// the conversation has no read marker, then every message is unread.
+func (i *impl) GetUnreadCount(partnerPubKey ed25519.PublicKey) (uint64, error) {
+	parentErr := "failed to GetUnreadCount"
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	query := i.db.WithContext(ctx).Model(&Message{}).
+		Where("conversation_pub_key = ? AND sender_pub_key = ? AND type != ?",
+			[]byte(partnerPubKey), []byte(partnerPubKey),
+			uint16(dm.ReactionType))
+
+	marker := &ReadMarker{}
+	err := i.db.WithContext(ctx).Limit(1).Find(marker,
+		"conversation_pub_key = ?", []byte(partnerPubKey)).Error
+	if err != nil {
+		return 0, errors.WithMessage(err, parentErr)
+	} else if marker.ConversationPubKey != nil {
+		query = query.Where("timestamp > ?", marker.LastRead)
+	}
+
+	var count int64
+	if err = query.Count(&count).Error; err != nil {
+		return 0, errors.WithMessage(err, parentErr)
+	}
+
+	return uint64(count), nil
+}
@@ -0,0 +1,75 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/dm"
+	"gitlab.com/elixxir/crypto/message"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.GetUnreadCount counts the partner's messages after the read
+// marker set by impl.MarkRead and that the marker never moves backwards.
+func TestImpl_MarkRead_GetUnreadCount(t *testing.T) {
+	model, err := newImpl("TestImpl_MarkRead", &dummyCallbacks{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partnerKey := ed25519.PublicKey("partnerKey")
+	myKey := ed25519.PublicKey("myKey")
+	start := time.Unix(1700000000, 0)
+	msgs := []struct {
+		sender ed25519.PublicKey
+		mType  dm.MessageType
+	}{
+		{partnerKey, dm.TextType},
+		{partnerKey, dm.TextType},
+		{myKey, dm.TextType},          // Sent messages are not counted
+		{partnerKey, dm.ReactionType}, // Reactions are not counted
+		{partnerKey, dm.TextType},
+	}
+	for j, m := range msgs {
+		msgID := message.DeriveChannelMessageID(&id.ID{}, uint64(j), []byte("m"))
+		_, err = model.receiveWrapper(msgID, nil, "nick", "text", partnerKey,
+			m.sender, 0, 0, start.Add(time.Duration(j)*time.Minute),
+			rounds.Round{ID: id.Round(j)}, m.mType, dm.Received)
+		if err != nil {
+			t.Fatalf("Failed to receive message %d: %+v", j, err)
+		}
+	}
+
+	tests := []struct {
+		markRead time.Time
+		expected uint64
+	}{
+		{time.Time{}, 3},
+		{start, 2},
+		{start.Add(4 * time.Minute), 0},
+		{start.Add(time.Minute), 0}, // Older marker is ignored
+	}
+	for j, tt := range tests {
+		if !tt.markRead.IsZero() {
+			if err = model.MarkRead(partnerKey, tt.markRead); err != nil {
+				t.Fatalf("Failed to mark read (%d): %+v", j, err)
+			}
+		}
+
+		count, err2 := model.GetUnreadCount(partnerKey)
+		if err2 != nil {
+			t.Fatalf("Failed to get unread count (%d): %+v", j, err2)
+		} else if count != tt.expected {
+			t.Errorf("Unexpected unread count (%d).\nexpected: %d\nreceived: %d",
+				j, tt.expected, count)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// adminServiceTag is the internal service tag that admin messages are sent and
+// received on. It is registered for every group by the manager.
+const adminServiceTag = "admin"
+
+// adminMessageVersion is the version of the AdminMessage.
+const adminMessageVersion = 0
+
+// The types of AdminMessage.
+const (
+	adminRename uint32 = iota + 1
+	adminSetAdmins
+	adminNotice
+)
+
+// Error messages.
+const (
+	// manager.SetAdmin
+	notCreatorErr   = "only the creator of group %s can change its admins"
+	adminCreatorErr = "the creator is always an admin of group %s"
+
+	// manager.getAdminGroup
+	notAdminErr = "only an admin of group %s can change it"
+
+	// manager.signAdminMessage
+	signAdminMessageErr = "failed to sign admin message for group %s: %+v"
+
+	// verifyCharter
+	unmarshalCharterErr = "failed to unmarshal charter: %+v"
+	charterTypeErr      = "charter has admin message type %d"
+	charterGroupIdErr   = "charter for group %s does not match group %s"
+
+	// adminProcessor.process
+	unmarshalAdminMessageErr = "failed to unmarshal admin message: %+v"
+	adminGroupIdErr          = "admin message for group %s received in group %s"
+	adminNoGroupErr          = "admin message for unknown group %s"
+	adminSenderErr           = "admin message from %s who is not an admin of group %s"
+	adminNotCreatorErr       = "admin list change from %s who is not the creator of group %s"
+	unknownAdminMessageErr   = "unknown admin message type %d"
+)
+
+// AdminCallbacks contains the callbacks called when an admin changes the group
+// metadata or sends a notice. The change has already been verified and applied
+// to the stored group when the callback is called.
+type AdminCallbacks interface {
+	// GroupRenamed is called when an admin renames the group.
+	GroupRenamed(msg MessageReceive, name []byte, round rounds.Round)
+
+	// AdminsChanged is called when the group creator changes the admins. The
+	// list does not include the creator, who is always an admin.
+	AdminsChanged(msg MessageReceive, admins []*id.ID, round rounds.Round)
+
+	// ReceiveAdminNotice is called when an admin sends a notice to the group.
+	ReceiveAdminNotice(msg MessageReceive, notice string, round rounds.Round)
+}
+
+// RegisterAdminCallbacks registers the callbacks that are called when admin
+// messages are received. Only one set of callbacks can be registered; calling
+// this again replaces them.
+func (m *manager) RegisterAdminCallbacks(cbs AdminCallbacks) {
+	m.adminMux.Lock()
+	defer m.adminMux.Unlock()
+	m.adminCbs = cbs
+}
+
+// SetAdmin makes the member an admin of the group or removes their admin role.
+// Only the group creator can change the admins. The new list of admins is sent
+// to the group and saved locally.
+func (m *manager) SetAdmin(groupID, memberID *id.ID, admin bool) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	g, exists := m.GetGroup(groupID)
+	if !exists {
+		return rounds.Round{}, time.Time{}, group.MessageID{},
+			errors.Errorf(updateNoGroupErr, groupID)
+	} else if !g.IsCreator(m.getReceptionIdentity().ID) {
+		return rounds.Round{}, time.Time{}, group.MessageID{},
+			errors.Errorf(notCreatorErr, groupID)
+	} else if g.IsCreator(memberID) {
+		return rounds.Round{}, time.Time{}, group.MessageID{},
+			errors.Errorf(adminCreatorErr, groupID)
+	} else if !isMember(g.Members, memberID) {
+		return rounds.Round{}, time.Time{}, group.MessageID{},
+			errors.Errorf(memberNotFoundErr, memberID, groupID)
+	}
+
+	admins := make([]*id.ID, 0, len(g.Admins)+1)
+	for _, uid := range g.Admins {
+		if !uid.Cmp(memberID) {
+			admins = append(admins, uid)
+		}
+	}
+	if admin {
+		admins = append(admins, memberID)
+	}
+
+	// The admin list is sent as a new charter so that admins can prove their
+	// role to members who have not yet joined
+	charter, err := m.signCharter(g, admins)
+	if err != nil {
+		return rounds.Round{}, time.Time{}, group.MessageID{}, err
+	}
+
+	r, ts, msgID, err := m.Send(g.ID, adminServiceTag, charter)
+	if err != nil {
+		return r, ts, msgID, err
+	}
+
+	g.Admins = admins
+	g.Charter = charter
+	if err = m.updateGroup(g); err != nil {
+		return r, ts, msgID, err
+	}
+
+	jww.INFO.Printf("[GC] Set admin status of %s in group %s to %t.",
+		memberID, groupID, admin)
+	return r, ts, msgID, nil
+}
+
+// RenameGroup changes the name of the group. Only admins can rename the group.
+// The new name is sent to the group and saved locally.
+func (m *manager) RenameGroup(groupID *id.ID, name []byte) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	g, err := m.getAdminGroup(groupID)
+	if err != nil {
+		return rounds.Round{}, time.Time{}, group.MessageID{}, err
+	}
+
+	r, ts, msgID, err := m.sendAdminMessage(g, &AdminMessage{
+		Type: adminRename,
+		Name: name,
+	})
+	if err != nil {
+		return r, ts, msgID, err
+	}
+
+	g.Name = name
+	if err = m.updateGroup(g); err != nil {
+		return r, ts, msgID, err
+	}
+
+	jww.INFO.Printf("[GC] Renamed group %s to %q.", groupID, name)
+	return r, ts, msgID, nil
+}
+
+// SendAdminNotice sends a notice to the group that is signed by the admin so
+// that members can trust it came from an admin. Only admins can send notices.
+func (m *manager) SendAdminNotice(groupID *id.ID, notice string) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	g, err := m.getAdminGroup(groupID)
+	if err != nil {
+		return rounds.Round{}, time.Time{}, group.MessageID{}, err
+	}
+
+	return m.sendAdminMessage(g, &AdminMessage{
+		Type:   adminNotice,
+		Notice: notice,
+	})
+}
+
+// getAdminGroup returns the group with the given ID. An error is returned if
+// the group does not exist or if the user is not one of its admins.
+func (m *manager) getAdminGroup(groupID *id.ID) (gs.Group, error) {
+	g, exists := m.GetGroup(groupID)
+	if !exists {
+		return gs.Group{}, errors.Errorf(updateNoGroupErr, groupID)
+	}
+
+	if !g.IsAdmin(m.getReceptionIdentity().ID) {
+		return gs.Group{}, errors.Errorf(notAdminErr, groupID)
+	}
+
+	return g, nil
+}
+
+// sendAdminMessage signs the admin message and sends it to the group on the
+// admin service tag.
+func (m *manager) sendAdminMessage(g gs.Group, msg *AdminMessage) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	payload, err := m.signAdminMessage(g, msg)
+	if err != nil {
+		return rounds.Round{}, time.Time{}, group.MessageID{}, err
+	}
+
+	return m.Send(g.ID, adminServiceTag, payload)
+}
+
+// signAdminMessage signs the admin message for the group and marshals it.
+func (m *manager) signAdminMessage(g gs.Group, msg *AdminMessage) ([]byte,
+	error) {
+	msg.Version = adminMessageVersion
+	msg.GroupID = g.ID.Marshal()
+	msg.Timestamp = netTime.Now().UnixNano()
+
+	signature, pubKey, salt, err := m.sign(adminMessageDigest(msg))
+	if err != nil {
+		return nil, errors.Errorf(signAdminMessageErr, g.ID, err)
+	}
+	msg.Signature, msg.SignerPubKey, msg.SignerSalt = signature, pubKey, salt
+
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, errors.Errorf(protoMarshalErr, err)
+	}
+
+	return payload, nil
+}
+
+// signCharter returns a new charter for the group listing the admins. A
+// charter is a marshalled AdminMessage that sets the admins and is signed by
+// the creator. Admins include it in the group requests they send so that new
+// members, who cannot yet know the admins, can verify that the sender is one.
+func (m *manager) signCharter(g gs.Group, admins []*id.ID) ([]byte, error) {
+	return m.signAdminMessage(g, &AdminMessage{
+		Type:   adminSetAdmins,
+		Admins: marshalIDs(admins),
+	})
+}
+
+// verifyCharter verifies that the charter for the group was signed by its
+// creator and returns the admins it lists.
+func verifyCharter(charter []byte, creatorID, groupID *id.ID) ([]*id.ID,
+	error) {
+	msg := &AdminMessage{}
+	if err := proto.Unmarshal(charter, msg); err != nil {
+		return nil, errors.Errorf(unmarshalCharterErr, err)
+	} else if msg.GetType() != adminSetAdmins {
+		return nil, errors.Errorf(charterTypeErr, msg.GetType())
+	}
+
+	charterGroupID, err := id.Unmarshal(msg.GetGroupID())
+	if err != nil {
+		return nil, errors.Errorf(unmarshalGroupIdErr, err)
+	} else if !charterGroupID.Cmp(groupID) {
+		return nil, errors.Errorf(charterGroupIdErr, charterGroupID, groupID)
+	}
+
+	err = verifySignature(creatorID, msg.GetSignature(), msg.GetSignerPubKey(),
+		msg.GetSignerSalt(), adminMessageDigest(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	admins, err := unmarshalIDs(msg.GetAdmins())
+	if err != nil {
+		return nil, errors.Errorf(unmarshalAdminsErr, err)
+	}
+
+	return admins, nil
+}
+
+// updateGroup saves the changed group metadata to storage and the event model.
+// Unlike replaceGroup, the services are not re-registered because the group key
+// is unchanged.
+func (m *manager) updateGroup(g gs.Group) error {
+	if err := m.gs.Update(g); err != nil {
+		return errors.Errorf(updateGroupErr, g.ID, err)
+	}
+
+	if m.ev != nil {
+		m.ev.JoinGroup(g)
+	}
+
+	return nil
+}
+
+// memberAdmins returns the admins that are members of the group. Admins that
+// have been removed from the group lose their role.
+func memberAdmins(admins []*id.ID, membership group.Membership) []*id.ID {
+	var newAdmins []*id.ID
+	for _, admin := range admins {
+		if isMember(membership, admin) && !membership[0].ID.Cmp(admin) {
+			newAdmins = append(newAdmins, admin)
+		}
+	}
+	return newAdmins
+}
+
+// adminProcessor adheres to the Processor interface and processes the admin
+// messages received on the adminServiceTag.
+type adminProcessor struct {
+	m *manager
+}
+
+// Process verifies and applies the admin message. Invalid messages are logged
+// and dropped.
+func (ap *adminProcessor) Process(decryptedMsg MessageReceive, _ format.Message,
+	_ []string, _ []byte, _ receptionID.EphemeralIdentity, round rounds.Round) {
+	if err := ap.process(decryptedMsg, round); err != nil {
+		jww.ERROR.Printf("[GC] Failed to process admin message %s from %s in "+
+			"group %s: %+v", decryptedMsg.ID, decryptedMsg.SenderID,
+			decryptedMsg.GroupID, err)
+	}
+}
+
+// process verifies that the admin message was signed by its sender and that
+// the sender has permission to make the change before applying it and calling
+// the registered callbacks.
+func (ap *adminProcessor) process(
+	decryptedMsg MessageReceive, round rounds.Round) error {
+	msg := &AdminMessage{}
+	if err := proto.Unmarshal(decryptedMsg.Payload, msg); err != nil {
+		return errors.Errorf(unmarshalAdminMessageErr, err)
+	}
+
+	groupID, err := id.Unmarshal(msg.GetGroupID())
+	if err != nil {
+		return errors.Errorf(unmarshalGroupIdErr, err)
+	} else if !groupID.Cmp(decryptedMsg.GroupID) {
+		return errors.Errorf(adminGroupIdErr, groupID, decryptedMsg.GroupID)
+	}
+
+	// Get the latest copy of the group since the reception processor holds the
+	// group as it was when the service was added
+	g, exists := ap.m.GetGroup(groupID)
+	if !exists {
+		return errors.Errorf(adminNoGroupErr, groupID)
+	}
+
+	sender := decryptedMsg.SenderID
+	if !g.IsAdmin(sender) {
+		return errors.Errorf(adminSenderErr, sender, groupID)
+	}
+
+	err = verifySignature(sender, msg.GetSignature(), msg.GetSignerPubKey(),
+		msg.GetSignerSalt(), adminMessageDigest(msg))
+	if err != nil {
+		return err
+	}
+
+	ap.m.adminMux.RLock()
+	cbs := ap.m.adminCbs
+	ap.m.adminMux.RUnlock()
+
+	switch msg.GetType() {
+	case adminRename:
+		g.Name = msg.GetName()
+		if err = ap.m.updateGroup(g); err != nil {
+			return err
+		}
+		jww.INFO.Printf("[GC] Group %s renamed to %q by %s.",
+			groupID, g.Name, sender)
+		if cbs != nil {
+			cbs.GroupRenamed(decryptedMsg, g.Name, round)
+		}
+
+	case adminSetAdmins:
+		if !g.IsCreator(sender) {
+			return errors.Errorf(adminNotCreatorErr, sender, groupID)
+		}
+		admins, err := unmarshalIDs(msg.GetAdmins())
+		if err != nil {
+			return err
+		}
+		g.Admins = memberAdmins(admins, g.Members)
+		g.Charter = decryptedMsg.Payload
+		if err = ap.m.updateGroup(g); err != nil {
+			return err
+		}
+		jww.INFO.Printf("[GC] Admins of group %s set to %v.", groupID, g.Admins)
+		if cbs != nil {
+			cbs.AdminsChanged(decryptedMsg, g.Admins, round)
+		}
+
+	case adminNotice:
+		if cbs != nil {
+			cbs.ReceiveAdminNotice(decryptedMsg, msg.GetNotice(), round)
+		}
+
+	default:
+		return errors.Errorf(unknownAdminMessageErr, msg.GetType())
+	}
+
+	return nil
+}
+
+func (ap *adminProcessor) String() string {
+	return fmt.Sprintf("GroupChatAdminProcessor(%s)", adminServiceTag)
+}
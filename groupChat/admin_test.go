@@ -0,0 +1,482 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"gitlab.com/elixxir/client/v4/catalog"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/e2e/receive"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/contact"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/elixxir/crypto/rsa"
+	"gitlab.com/elixxir/primitives/states"
+	"gitlab.com/xx_network/crypto/xx"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that the admin changes made by the group creator with manager.SetAdmin,
+// manager.RenameGroup, and manager.SendAdminNotice are applied locally and are
+// verified and passed to the AdminCallbacks when received.
+func Test_manager_AdminMessages_Receive(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+	g, _, _, err := m.MakeGroup(memberIDs[:5], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+
+	cbs := &mockAdminCallbacks{}
+	m.RegisterAdminCallbacks(cbs)
+	reception := &receptionProcessor{
+		m:   m,
+		g:   g,
+		p:   &adminProcessor{m},
+		tag: adminServiceTag,
+	}
+	timestamps := map[states.Round]time.Time{
+		states.PRECOMPUTING: netTime.Now().Round(0)}
+
+	// receive passes the last sent message to the reception processor
+	receive := func() {
+		messages := m.getCMix().(*testNetworkManager).receptionMessages
+		reception.Process(messages[len(messages)-1][0], nil, nil,
+			receptionID.EphemeralIdentity{},
+			rounds.Round{ID: 5, Timestamps: timestamps})
+	}
+
+	_, _, _, err = m.SetAdmin(g.ID, memberIDs[1], true)
+	if err != nil {
+		t.Fatalf("SetAdmin returned an error: %+v", err)
+	}
+	newG, _ := m.GetGroup(g.ID)
+	if !newG.IsAdmin(memberIDs[1]) {
+		t.Errorf("Member %s not made admin: %v", memberIDs[1], newG.Admins)
+	}
+	admins, err := verifyCharter(newG.Charter, g.Creator(), g.ID)
+	if err != nil {
+		t.Errorf("Failed to verify charter: %+v", err)
+	} else if !reflect.DeepEqual(admins, []*id.ID{memberIDs[1]}) {
+		t.Errorf("Unexpected admins in charter: %v", admins)
+	}
+	receive()
+	if !reflect.DeepEqual(cbs.admins, []*id.ID{memberIDs[1]}) {
+		t.Errorf("Unexpected admins received: %v", cbs.admins)
+	}
+
+	_, _, _, err = m.RenameGroup(g.ID, []byte("new name"))
+	if err != nil {
+		t.Fatalf("RenameGroup returned an error: %+v", err)
+	}
+	newG, _ = m.GetGroup(g.ID)
+	if string(newG.Name) != "new name" {
+		t.Errorf("Group not renamed: %q", newG.Name)
+	}
+	receive()
+	if string(cbs.name) != "new name" || cbs.round.ID != 5 {
+		t.Errorf("Unexpected name received: %q", cbs.name)
+	}
+
+	_, _, noticeID, err := m.SendAdminNotice(g.ID, "Notice.")
+	if err != nil {
+		t.Fatalf("SendAdminNotice returned an error: %+v", err)
+	}
+	receive()
+	if cbs.notice != "Notice." || cbs.msg.ID != noticeID {
+		t.Errorf("Unexpected notice received: %q %s (expected %s)",
+			cbs.notice, cbs.msg.ID, noticeID)
+	}
+}
+
+// Error path: Tests that manager.SetAdmin and manager.RenameGroup return an
+// error when the user is not the creator or an admin.
+func Test_manager_SetAdmin_RenameGroup_PermissionErrors(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestManagerWithStore(prng, 1, 0, nil, t)
+
+	expectedErr := strings.SplitN(notCreatorErr, "%", 2)[0]
+	_, _, _, err := m.SetAdmin(g.ID, g.Members[1].ID, true)
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("SetAdmin did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+
+	expectedErr = strings.SplitN(notAdminErr, "%", 2)[0]
+	_, _, _, err = m.RenameGroup(g.ID, []byte("new name"))
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("RenameGroup did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+
+	if n := len(m.getCMix().(*testNetworkManager).sendMessages); n != 0 {
+		t.Errorf("%d messages sent without permission.", n)
+	}
+}
+
+// Error path: Tests that adminProcessor.process rejects admin messages from
+// members that are not admins and admin messages whose signature does not
+// belong to the sender, and does not change the group.
+func Test_adminProcessor_process_ForgeryErrors(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+	g, _, _, err := m.MakeGroup(memberIDs[:5], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+	g.Admins = []*id.ID{memberIDs[1]}
+	if err = m.gs.Update(g); err != nil {
+		t.Fatalf("Failed to update group: %+v", err)
+	}
+
+	// The message is signed by the user, so it is only valid from the user
+	msg := &AdminMessage{
+		Type:      adminRename,
+		GroupID:   g.ID.Marshal(),
+		Name:      []byte("forged name"),
+		Timestamp: netTime.Now().UnixNano(),
+	}
+	msg.Signature, msg.SignerPubKey, msg.SignerSalt, err =
+		m.sign(adminMessageDigest(msg))
+	if err != nil {
+		t.Fatalf("Failed to sign admin message: %+v", err)
+	}
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to marshal admin message: %+v", err)
+	}
+
+	ap := &adminProcessor{m}
+	tests := []struct {
+		sender *id.ID
+		err    string
+	}{
+		{memberIDs[2], adminSenderErr},
+		{memberIDs[1], signerIdErr},
+	}
+	for i, tt := range tests {
+		expectedErr := strings.SplitN(tt.err, "%", 2)[0]
+		err = ap.process(MessageReceive{
+			GroupID:  g.ID,
+			Payload:  payload,
+			SenderID: tt.sender,
+		}, rounds.Round{})
+		if err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Errorf("process did not return the expected error (%d)."+
+				"\nexpected: %s\nreceived: %+v", i, expectedErr, err)
+		}
+	}
+
+	if newG, _ := m.GetGroup(g.ID); string(newG.Name) != "name" {
+		t.Errorf("Forged rename was applied: %q", newG.Name)
+	}
+}
+
+// Tests that an admin that is not the creator can add a member with
+// manager.AddMember, that the creator remains the leader, and that the
+// membership update is signed by the admin.
+func Test_manager_AddMember_Admin(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestManagerWithStore(prng, 1, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+	myID := m.getReceptionIdentity().ID
+	g.Admins = []*id.ID{myID}
+	if err := m.gs.Update(g); err != nil {
+		t.Fatalf("Failed to update group: %+v", err)
+	}
+
+	_, _, err := m.AddMember(g.ID, memberIDs[0])
+	if err != nil {
+		t.Fatalf("AddMember returned an error: %+v", err)
+	}
+
+	newG, _ := m.GetGroup(g.ID)
+	if !isMember(newG.Members, memberIDs[0]) {
+		t.Errorf("New member not added to group: %s", newG.Members)
+	} else if !newG.IsCreator(g.Members[0].ID) {
+		t.Errorf("Creator changed from %s to %s.",
+			g.Members[0].ID, newG.Members[0].ID)
+	} else if !newG.IsAdmin(myID) {
+		t.Errorf("User lost admin role: %v", newG.Admins)
+	}
+
+	msgs := m.getE2eHandler().(*testE2eManager).e2eMessages
+	if len(msgs) != len(newG.Members)-1 {
+		t.Fatalf("Unexpected number of messages sent."+
+			"\nexpected: %d\nreceived: %d", len(newG.Members)-1, len(msgs))
+	}
+	for i, msg := range msgs {
+		if msg.Recipient.Cmp(myID) {
+			t.Errorf("Message %d sent to self.", i)
+		}
+		request := &Request{}
+		if err = proto.Unmarshal(msg.Payload, request); err != nil {
+			t.Fatalf("Failed to unmarshal request %d: %+v", i, err)
+		}
+		if err = verifyRequest(request, myID, false); err != nil {
+			t.Errorf("Request %d has an invalid signature: %+v", i, err)
+		}
+	}
+}
+
+// Error path: Tests that manager.RemoveMember returns an error when an admin
+// that is not the creator tries to remove another admin.
+func Test_manager_RemoveMember_AdminError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestManagerWithStore(prng, 1, 0, nil, t)
+	g.Admins = []*id.ID{m.getReceptionIdentity().ID, g.Members[1].ID}
+	if err := m.gs.Update(g); err != nil {
+		t.Fatalf("Failed to update group: %+v", err)
+	}
+	expectedErr := strings.SplitN(removeAdminErr, "%", 2)[0]
+
+	_, status, err := m.RemoveMember(g.ID, g.Members[1].ID)
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("RemoveMember did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	} else if status != NotSent {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			NotSent, status)
+	}
+}
+
+// Error path: Tests that manager.readMembershipUpdate rejects an unsigned
+// update from an admin that is not the creator.
+func Test_manager_readMembershipUpdate_UnsignedAdminError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestMembershipManager(prng, t)
+	g.Admins = []*id.ID{g.Members[1].ID}
+	if err := m.gs.Update(g); err != nil {
+		t.Fatalf("Failed to update group: %+v", err)
+	}
+	expectedErr := strings.SplitN(unsignedRequestErr, "%", 2)[0]
+
+	msg := newMembershipUpdateMsg(g.Members[1].ID, &Request{
+		Members: g.Members[:len(g.Members)-1].Serialize(),
+		GroupID: g.ID.Marshal(),
+	}, t)
+
+	err := m.readMembershipUpdate(msg)
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("readMembershipUpdate did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+
+	if newG, _ := m.GetGroup(g.ID); len(newG.Members) != len(g.Members) {
+		t.Errorf("Unsigned update was applied: %s", newG.Members)
+	}
+}
+
+// Tests that verifyRequestSender accepts a request signed by an admin listed in
+// a charter signed by the creator and rejects it once tampered with, when
+// unsigned, or when the charter is missing, is for another group, does not list
+// the sender, or is not signed by the creator.
+func Test_verifyRequestSender(t *testing.T) {
+	m, g := newTestManager(t)
+	myID := m.getReceptionIdentity().ID
+	creator := newTestSigner(43, t)
+	mem := newTestMembershipWithLeader(creator.id, g, t)
+	admins := []*id.ID{myID}
+
+	newRequest := func(charter []byte) *Request {
+		request := &Request{
+			Name:    g.Name,
+			Members: mem.Serialize(),
+			Admins:  marshalIDs(admins),
+			Charter: charter,
+		}
+		if err := m.signRequest(request); err != nil {
+			t.Fatalf("Failed to sign request: %+v", err)
+		}
+		return request
+	}
+	request := newRequest(creator.signCharter(g.ID, admins, t))
+
+	verified, err := verifyRequestSender(request, myID, g.ID, mem)
+	if err != nil {
+		t.Errorf("verifyRequestSender returned an error: %+v", err)
+	} else if !reflect.DeepEqual(admins, verified) {
+		t.Errorf("Unexpected admins.\nexpected: %s\nreceived: %s",
+			admins, verified)
+	}
+
+	// The creator's admin list is trusted without a charter
+	verified, err = verifyRequestSender(
+		&Request{Members: mem.Serialize(), Admins: marshalIDs(admins)},
+		creator.id, g.ID, mem)
+	if err != nil {
+		t.Errorf("verifyRequestSender returned an error for the creator: %+v",
+			err)
+	} else if !reflect.DeepEqual(admins, verified) {
+		t.Errorf("Unexpected admins from creator.\nexpected: %s\nreceived: %s",
+			admins, verified)
+	}
+
+	tampered := proto.Clone(request).(*Request)
+	tampered.Name = []byte("tampered")
+	unsigned := proto.Clone(request).(*Request)
+	unsigned.Signature = nil
+	forged, err := m.signCharter(gs.Group{ID: g.ID}, admins)
+	if err != nil {
+		t.Fatalf("Failed to sign charter: %+v", err)
+	}
+	otherID := id.NewIdFromString("otherGroup", id.Group, t)
+	tests := []struct {
+		request *Request
+		groupID *id.ID
+		err     string
+	}{
+		{tampered, g.ID, verifySignatureErr},
+		{unsigned, g.ID, unsignedRequestErr},
+		{newRequest(nil), g.ID, requestNoCharterErr},
+		{request, otherID, charterGroupIdErr},
+		{newRequest(creator.signCharter(g.ID, nil, t)), g.ID, requestNotAdminErr},
+		{newRequest(forged), g.ID, signerIdErr},
+	}
+	for i, tt := range tests {
+		expectedErr := strings.SplitN(tt.err, "%", 2)[0]
+		_, err = verifyRequestSender(tt.request, myID, tt.groupID, mem)
+		if err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Errorf("verifyRequestSender did not return the expected error "+
+				"(%d).\nexpected: %s\nreceived: %+v", i, expectedErr, err)
+		}
+	}
+}
+
+// Error path: Tests that manager.readRequest rejects a group request from a
+// member that is not the creator and lists themselves as an admin without a
+// charter from the creator.
+func Test_manager_readRequest_SelfListedAdminError(t *testing.T) {
+	m, g := newTestManager(t)
+	myID := m.getReceptionIdentity().ID
+	expectedErr := strings.SplitN(requestNoCharterErr, "%", 2)[0]
+
+	request := &Request{
+		Name:    g.Name,
+		Members: g.Members.Serialize(),
+		Admins:  marshalIDs([]*id.ID{myID}),
+	}
+	if err := m.signRequest(request); err != nil {
+		t.Fatalf("Failed to sign request: %+v", err)
+	}
+	payload, err := proto.Marshal(request)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %+v", err)
+	}
+
+	_, err = m.readRequest(receive.Message{
+		Payload:     payload,
+		MessageType: catalog.GroupCreationRequest,
+		Sender:      myID,
+	})
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("readRequest did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+}
+
+// testSigner is an identity, other than the one of the test manager, that
+// signs as the creator of a group.
+type testSigner struct {
+	id      *id.ID
+	privKey rsa.PrivateKey
+	salt    []byte
+}
+
+// newTestSigner generates a new testSigner from the seed.
+func newTestSigner(seed int64, t testing.TB) *testSigner {
+	prng := rand.New(rand.NewSource(seed))
+	privKey, err := rsa.GetScheme().Generate(prng, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %+v", err)
+	}
+	salt := make([]byte, 32)
+	prng.Read(salt)
+	uid, err := xx.NewID(privKey.Public(), salt, id.User)
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %+v", err)
+	}
+	return &testSigner{uid, privKey, salt}
+}
+
+// signCharter returns a charter for the group listing the admins signed by the
+// testSigner.
+func (ts *testSigner) signCharter(
+	groupID *id.ID, admins []*id.ID, t testing.TB) []byte {
+	msg := &AdminMessage{
+		Version:   adminMessageVersion,
+		Type:      adminSetAdmins,
+		GroupID:   groupID.Marshal(),
+		Admins:    marshalIDs(admins),
+		Timestamp: netTime.Now().UnixNano(),
+	}
+
+	opts := getSignatureOpts()
+	signature, err := ts.privKey.SignPSS(
+		rand.New(rand.NewSource(42)), opts.Hash, adminMessageDigest(msg), opts)
+	if err != nil {
+		t.Fatalf("Failed to sign charter: %+v", err)
+	}
+	msg.Signature = signature
+	msg.SignerPubKey = ts.privKey.Public().MarshalWire()
+	msg.SignerSalt = ts.salt
+
+	charter, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to marshal charter: %+v", err)
+	}
+	return charter
+}
+
+// newTestMembershipWithLeader returns the membership of the group with the
+// leader replaced.
+func newTestMembershipWithLeader(
+	leader *id.ID, g gs.Group, t testing.TB) group.Membership {
+	mem, err := group.NewMembership(
+		contact.Contact{ID: leader, DhPubKey: g.Members[0].DhKey},
+		participantContacts(g)...)
+	if err != nil {
+		t.Fatalf("Failed to create membership: %+v", err)
+	}
+	return mem
+}
+
+// mockAdminCallbacks adheres to the AdminCallbacks interface and stores the
+// values of the last call to each callback.
+type mockAdminCallbacks struct {
+	msg    MessageReceive
+	round  rounds.Round
+	name   []byte
+	admins []*id.ID
+	notice string
+}
+
+func (m *mockAdminCallbacks) GroupRenamed(
+	msg MessageReceive, name []byte, round rounds.Round) {
+	m.msg, m.name, m.round = msg, name, round
+}
+
+func (m *mockAdminCallbacks) AdminsChanged(
+	msg MessageReceive, admins []*id.ID, round rounds.Round) {
+	m.msg, m.admins, m.round = msg, admins, round
+}
+
+func (m *mockAdminCallbacks) ReceiveAdminNotice(
+	msg MessageReceive, notice string, round rounds.Round) {
+	m.msg, m.notice, m.round = msg, notice, round
+}
@@ -37,8 +37,9 @@ type testE2eManager struct {
 }
 
 type testE2eMessage struct {
-	Recipient *id.ID
-	Payload   []byte
+	Recipient   *id.ID
+	Payload     []byte
+	MessageType catalog.MessageType
 }
 
 func (tnm *testE2eManager) AddPartner(partnerID *id.ID, partnerPubKey,
@@ -71,7 +72,7 @@ func (tnm *testE2eManager) GetE2eMsg(i int) testE2eMessage {
 	return tnm.e2eMessages[i]
 }
 
-func (tnm *testE2eManager) SendE2E(_ catalog.MessageType, recipient *id.ID,
+func (tnm *testE2eManager) SendE2E(mt catalog.MessageType, recipient *id.ID,
 	payload []byte, _ clientE2E.Params) (cryptoE2e.SendReport, error) {
 	tnm.Lock()
 	defer tnm.Unlock()
@@ -84,8 +85,9 @@ func (tnm *testE2eManager) SendE2E(_ catalog.MessageType, recipient *id.ID,
 	}
 
 	tnm.e2eMessages = append(tnm.e2eMessages, testE2eMessage{
-		Recipient: recipient,
-		Payload:   payload,
+		Recipient:   recipient,
+		Payload:     payload,
+		MessageType: mt,
 	})
 
 	return cryptoE2e.SendReport{RoundList: []id.Round{0, 1, 2, 3}}, nil
@@ -0,0 +1,79 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// NoMessageErr must be returned by EventModel methods (such as
+// EventModel.GetMessage) when the message cannot be found.
+var NoMessageErr = errors.New("message does not exist [EV]")
+
+// EventModel is an optional interface that stores the messages sent and
+// received in each group so that group conversations persist between restarts.
+// It is registered with the manager using NewManagerWithEventModel.
+//
+// All messages are passed to the EventModel, regardless of their service tag,
+// before they are passed to the Processor registered for that tag.
+type EventModel interface {
+	// JoinGroup is called whenever a group is joined or its membership
+	// changes. It is also called for every stored group when the manager is
+	// created.
+	JoinGroup(g gs.Group)
+
+	// LeaveGroup is called whenever a group is left. All messages for the
+	// group should be deleted.
+	LeaveGroup(groupID *id.ID)
+
+	// ReceiveMessage is called whenever a message is sent or received in a
+	// group. Messages sent by the user are passed in once the send succeeds.
+	//
+	// Returns a non-negative unique UUID for the message that can be referenced
+	// at a later time.
+	ReceiveMessage(groupID *id.ID, messageID group.MessageID, senderID *id.ID,
+		tag string, content []byte, timestamp time.Time,
+		round rounds.Round) uint64
+
+	// GetMessage returns the message with the given group.MessageID.
+	//
+	// Returns an error if the message cannot be gotten. It must return
+	// NoMessageErr if the message does not exist.
+	GetMessage(messageID group.MessageID) (ModelMessage, error)
+}
+
+// ModelMessage contains a group message stored in the EventModel.
+type ModelMessage struct {
+	UUID      uint64          `json:"uuid"`
+	GroupID   *id.ID          `json:"groupID"`
+	MessageID group.MessageID `json:"messageID"`
+	SenderID  *id.ID          `json:"senderID"`
+	Tag       string          `json:"tag"`
+	Content   []byte          `json:"content"`
+	Timestamp time.Time       `json:"timestamp"`
+	Round     id.Round        `json:"round"`
+}
+
+// CheckNoMessageErr determines if the error returned by an EventModel function
+// indicates that the message or item does not exist. It returns true if the
+// error contains NoMessageErr.
+func CheckNoMessageErr(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, NoMessageErr) ||
+		strings.Contains(err.Error(), NoMessageErr.Error())
+}
@@ -0,0 +1,131 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/elixxir/primitives/states"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that sent and received messages are passed to the EventModel and that
+// joining and leaving a group are passed to the EventModel.
+func Test_manager_EventModel(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestManagerWithStore(prng, 1, 0, nil, t)
+	ev := newMockEventModel()
+	m.ev = ev
+
+	messageBytes := []byte("Group chat message.")
+	r, _, msgID, err := m.Send(g.ID, "", messageBytes)
+	if err != nil {
+		t.Fatalf("Send returned an error: %+v", err)
+	}
+
+	sent, exists := ev.messages[msgID]
+	if !exists {
+		t.Fatalf("Sent message %s not passed to EventModel.", msgID)
+	}
+	if !sent.SenderID.Cmp(m.getReceptionIdentity().ID) ||
+		sent.Tag != defaultServiceTag ||
+		!bytes.Equal(sent.Content, messageBytes) {
+		t.Errorf("Unexpected sent message: %+v", sent)
+	}
+
+	// Receive the sent message on another tag
+	delete(ev.messages, msgID)
+	reception := &receptionProcessor{
+		m:   m,
+		g:   g,
+		p:   &testProcessor{make(chan MessageReceive, 10)},
+		tag: "tag",
+	}
+	cMixMsg := m.getCMix().(*testNetworkManager).receptionMessages[0][0]
+	timestamps := map[states.Round]time.Time{
+		states.PRECOMPUTING: netTime.Now().Round(0)}
+	reception.Process(cMixMsg, nil, nil, receptionID.EphemeralIdentity{},
+		rounds.Round{ID: r.ID, Timestamps: timestamps})
+
+	received, exists := ev.messages[msgID]
+	if !exists {
+		t.Fatalf("Received message %s not passed to EventModel.", msgID)
+	}
+	if !received.GroupID.Cmp(g.ID) || received.Tag != "tag" ||
+		received.Round != r.ID || !bytes.Equal(received.Content, messageBytes) {
+		t.Errorf("Unexpected received message: %+v", received)
+	}
+
+	// Join and leave a group
+	newG := newTestGroupWithUser(m.getE2eGroup(), m.getReceptionIdentity().ID,
+		m.getE2eHandler().GetHistoricalDHPubkey(), randCycInt(prng), prng, t)
+	if err = m.JoinGroup(newG); err != nil {
+		t.Fatalf("Failed to join group: %+v", err)
+	}
+	if _, exists = ev.groups[*newG.ID]; !exists {
+		t.Errorf("Joined group %s not passed to EventModel.", newG.ID)
+	}
+
+	if err = m.LeaveGroup(newG.ID); err != nil {
+		t.Fatalf("Failed to leave group: %+v", err)
+	}
+	if _, exists = ev.groups[*newG.ID]; exists {
+		t.Errorf("Left group %s not removed from EventModel.", newG.ID)
+	}
+}
+
+// mockEventModel adheres to the EventModel interface.
+type mockEventModel struct {
+	groups   map[id.ID]gs.Group
+	messages map[group.MessageID]ModelMessage
+}
+
+func newMockEventModel() *mockEventModel {
+	return &mockEventModel{
+		groups:   make(map[id.ID]gs.Group),
+		messages: make(map[group.MessageID]ModelMessage),
+	}
+}
+
+func (m *mockEventModel) JoinGroup(g gs.Group) { m.groups[*g.ID] = g }
+
+func (m *mockEventModel) LeaveGroup(groupID *id.ID) {
+	delete(m.groups, *groupID)
+}
+
+func (m *mockEventModel) ReceiveMessage(groupID *id.ID,
+	messageID group.MessageID, senderID *id.ID, tag string, content []byte,
+	timestamp time.Time, round rounds.Round) uint64 {
+	m.messages[messageID] = ModelMessage{
+		UUID:      uint64(len(m.messages)),
+		GroupID:   groupID,
+		MessageID: messageID,
+		SenderID:  senderID,
+		Tag:       tag,
+		Content:   content,
+		Timestamp: timestamp,
+		Round:     round.ID,
+	}
+	return uint64(len(m.messages))
+}
+
+func (m *mockEventModel) GetMessage(
+	messageID group.MessageID) (ModelMessage, error) {
+	if msg, exists := m.messages[messageID]; exists {
+		return msg, nil
+	}
+	return ModelMessage{}, NoMessageErr
+}
@@ -35,8 +35,14 @@ const (
 	kvGetGroupErr = "failed to get group %s from storage: %+v"
 	membershipErr = "failed to deserialize member list: %+v"
 	dhKeyListErr  = "failed to deserialize DH key list: %+v"
+	adminIdErr    = "failed to unmarshal admin ID: %+v"
 )
 
+// adminsMarker separates the DH key list from the list of admins in the
+// serialized Group. It is the length of an ID so that groups serialized before
+// admins were added, which end in the DH key list, can still be deserialized.
+var adminsMarker = make([]byte, id.ArrIDLen)
+
 // Group contains the membership list, the cryptographic information, and the
 // identifying information of a group chat.
 type Group struct {
@@ -49,6 +55,8 @@ type Group struct {
 	Created     time.Time         // Timestamp of when the group was created
 	Members     group.Membership  // Sorted list of members in group
 	DhKeys      DhKeyList         // List of shared DH keys
+	Admins      []*id.ID          // Admins other than the creator
+	Charter     []byte            // Creator-signed group ID and admins
 }
 
 // NewGroup creates a new Group from copies of the given data.
@@ -86,6 +94,12 @@ func (g Group) DeepCopy() Group {
 		Created:     g.Created,
 		Members:     g.Members.DeepCopy(),
 		DhKeys:      make(map[id.ID]*cyclic.Int, len(g.Members)-1),
+		Admins:      copyIDs(g.Admins),
+	}
+
+	if g.Charter != nil {
+		newGrp.Charter = make([]byte, len(g.Charter))
+		copy(newGrp.Charter, g.Charter)
 	}
 
 	copy(newGrp.Name, g.Name)
@@ -98,6 +112,34 @@ func (g Group) DeepCopy() Group {
 	return newGrp
 }
 
+// Creator returns the ID of the member that created the group. The creator is
+// always the first member and is always an admin.
+func (g Group) Creator() *id.ID {
+	return g.Members[0].ID
+}
+
+// IsCreator returns true if the user created the group.
+func (g Group) IsCreator(uid *id.ID) bool {
+	return g.Members[0].ID.Cmp(uid)
+}
+
+// IsAdmin returns true if the user is the creator of the group or one of its
+// admins. Only admins can change the group name or membership or send admin
+// notices.
+func (g Group) IsAdmin(uid *id.ID) bool {
+	if g.IsCreator(uid) {
+		return true
+	}
+
+	for _, admin := range g.Admins {
+		if admin.Cmp(uid) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // store saves an individual Group to storage keying on the group ID.
 func (g Group) store(kv versioned.KV) error {
 	obj := &versioned.Object{
@@ -125,11 +167,15 @@ func removeGroup(groupID *id.ID, kv versioned.KV) error {
 }
 
 // Serialize serializes the Group and returns the byte slice. The serialized
-// data follows the following format.
-// +----------+----------+----------+----------+------------+-------------+-----------------+-------------+---------+-------------+----------+----------+
-// | Name len |   Name   |    ID    |    Key   | IdPreimage | KeyPreimage | InitMessage len | InitMessage | Created | Members len | Members  |  DhKeys  |
-// | 8 bytes  | variable | 33 bytes | 32 bytes |  32 bytes  |  32 bytes   |     8 bytes     |  variable   | 8 bytes |   8 bytes   | variable | variable |
-// +----------+----------+----------+----------+------------+-------------+-----------------+-------------+---------+-------------+----------+----------+
+// data follows the following format. The admins marker, admins length, and
+// admins are only included if the group has admins other than the creator or a
+// charter. The charter length and charter are only included if the group has a
+// charter.
+// +----------+----------+----------+----------+------------+-------------+-----------------+-------------+---------+-------------+----------+----------+---------------+------------+-----------+-------------+----------+
+// | Name len |   Name   |    ID    |    Key   | IdPreimage | KeyPreimage | InitMessage len | InitMessage | Created | Members len | Members  |  DhKeys  | Admins marker | Admins len |  Admins   | Charter len | Charter  |
+// | 8 bytes  | variable | 33 bytes | 32 bytes |  32 bytes  |  32 bytes   |     8 bytes     |  variable   | 8 bytes |   8 bytes   | variable | variable |   33 bytes    |  8 bytes   | 33 bytes  |   8 bytes   | variable |
+// |          |          |          |          |            |             |                 |             |         |             |          |          |               |            | per admin |             |          |
+// +----------+----------+----------+----------+------------+-------------+-----------------+-------------+---------+-------------+----------+----------+---------------+------------+-----------+-------------+----------+
 func (g Group) Serialize() []byte {
 	buff := bytes.NewBuffer(nil)
 
@@ -172,6 +218,25 @@ func (g Group) Serialize() []byte {
 	// Write DH key list
 	buff.Write(g.DhKeys.Serialize())
 
+	// Write marker, number of admins, and admins
+	if len(g.Admins) > 0 || len(g.Charter) > 0 {
+		buff.Write(adminsMarker)
+		b = make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(len(g.Admins)))
+		buff.Write(b)
+		for _, admin := range g.Admins {
+			buff.Write(admin.Marshal())
+		}
+	}
+
+	// Write length of charter and charter
+	if len(g.Charter) > 0 {
+		b = make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(len(g.Charter)))
+		buff.Write(b)
+		buff.Write(g.Charter)
+	}
+
 	return buff.Bytes()
 }
 
@@ -223,14 +288,75 @@ func DeserializeGroup(data []byte) (Group, error) {
 	}
 
 	// get DH key list
-	g.DhKeys, err = DeserializeDhKeyList(buff.Bytes())
+	dhKeyData, adminData := splitAdmins(buff.Bytes())
+	g.DhKeys, err = DeserializeDhKeyList(dhKeyData)
 	if err != nil {
 		return Group{}, errors.Errorf(dhKeyListErr, err)
 	}
 
+	// get admins
+	if len(adminData) >= 8 {
+		adminBuff := bytes.NewBuffer(adminData)
+		adminsLen := binary.LittleEndian.Uint64(adminBuff.Next(8))
+		for i := uint64(0); i < adminsLen; i++ {
+			admin, err := id.Unmarshal(adminBuff.Next(id.ArrIDLen))
+			if err != nil {
+				return Group{}, errors.Errorf(adminIdErr, err)
+			}
+			g.Admins = append(g.Admins, admin)
+		}
+
+		// get charter
+		if adminBuff.Len() >= 8 {
+			charterLen := binary.LittleEndian.Uint64(adminBuff.Next(8))
+			if charterLen > 0 {
+				g.Charter = adminBuff.Next(int(charterLen))
+			}
+		}
+	}
+
 	return g, err
 }
 
+// splitAdmins splits the serialized DH key list from the serialized admins
+// that follow the admins marker. If there is no marker, then all the data
+// belongs to the DH key list.
+func splitAdmins(data []byte) (dhKeys, admins []byte) {
+	for i := 0; i+id.ArrIDLen <= len(data); {
+		if bytes.Equal(data[i:i+id.ArrIDLen], adminsMarker) {
+			return data[:i], data[i+id.ArrIDLen:]
+		}
+
+		// Skip the ID, key length, and key of the DH key entry
+		i += id.ArrIDLen
+		if i+8 > len(data) {
+			break
+		}
+		keyLen := binary.LittleEndian.Uint64(data[i : i+8])
+		i += 8
+		if keyLen > uint64(len(data)-i) {
+			break
+		}
+		i += int(keyLen)
+	}
+
+	return data, nil
+}
+
+// copyIDs returns a deep copy of the list of IDs.
+func copyIDs(ids []*id.ID) []*id.ID {
+	if ids == nil {
+		return nil
+	}
+
+	newIDs := make([]*id.ID, len(ids))
+	for i, uid := range ids {
+		newIDs[i] = uid.DeepCopy()
+	}
+
+	return newIDs
+}
+
 // groupStoreKey generates a unique key to save and load a Group to/from
 // storage.
 func groupStoreKey(groupID *id.ID) string {
@@ -255,6 +381,7 @@ func (g Group) GoString() string {
 		"Created:" + g.Created.String(),
 		"Members:" + g.Members.String(),
 		"DhKeys:" + g.DhKeys.GoString(),
+		"Admins:" + fmt.Sprintf("%v", g.Admins),
 	}
 
 	return "{" + strings.Join(str, ", ") + "}"
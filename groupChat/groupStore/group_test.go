@@ -219,6 +219,59 @@ func TestGroup_Serialize_DeserializeGroup_NilGroup(t *testing.T) {
 	}
 }
 
+// Tests that a group with admins that is serialized and deserialized matches
+// the original and that the admins do not corrupt the DH key list.
+func TestGroup_Serialize_DeserializeGroup_Admins(t *testing.T) {
+	grp := createTestGroup(rand.New(rand.NewSource(42)), t)
+	grp.Admins = []*id.ID{grp.Members[1].ID, grp.Members[2].ID}
+
+	newGrp, err := DeserializeGroup(grp.Serialize())
+	if err != nil {
+		t.Errorf("DeserializeGroup returned an error: %+v", err)
+	}
+
+	if !reflect.DeepEqual(grp, newGrp) {
+		t.Errorf("Deserialized group does not match original."+
+			"\nexpected: %#v\nreceived: %#v", grp, newGrp)
+	}
+}
+
+// Tests that a group with a charter, with and without admins, that is
+// serialized and deserialized matches the original.
+func TestGroup_Serialize_DeserializeGroup_Charter(t *testing.T) {
+	grp := createTestGroup(rand.New(rand.NewSource(42)), t)
+	grp.Charter = []byte("charter")
+
+	for _, admins := range [][]*id.ID{nil, {grp.Members[1].ID}} {
+		grp.Admins = admins
+		newGrp, err := DeserializeGroup(grp.Serialize())
+		if err != nil {
+			t.Errorf("DeserializeGroup returned an error: %+v", err)
+		}
+
+		if !reflect.DeepEqual(grp, newGrp) {
+			t.Errorf("Deserialized group does not match original."+
+				"\nexpected: %#v\nreceived: %#v", grp, newGrp)
+		}
+	}
+}
+
+// Tests that Group.IsAdmin returns true for the creator and the admins only.
+func TestGroup_IsAdmin(t *testing.T) {
+	grp := createTestGroup(rand.New(rand.NewSource(42)), t)
+	grp.Admins = []*id.ID{grp.Members[2].ID}
+
+	for i, m := range grp.Members {
+		expected := i == 0 || i == 2
+		if grp.IsAdmin(m.ID) != expected {
+			t.Errorf("IsAdmin returned %t for member %d.", !expected, i)
+		}
+		if grp.IsCreator(m.ID) != (i == 0) {
+			t.Errorf("IsCreator returned %t for member %d.", i != 0, i)
+		}
+	}
+}
+
 // Error path: error returned when the group membership is too small.
 func TestDeserializeGroup_DeserializeMembershipError(t *testing.T) {
 	grp := Group{}
@@ -294,7 +347,9 @@ func TestGroup_GoString(t *testing.T) {
 		"3RqsBM4ux44bC6+uiBuCp1EQikLtPJA8qkNGWnhiBhYD: 4967151805... in GRP: 6SsQ/HAHUn..., " +
 		"55ai4SlwXic/BckjJoKOKwVuOBdljhBhSYlH/fNEQQ4D: 3187530437... in GRP: 6SsQ/HAHUn..., " +
 		"9PkZKU50joHnnku9b+NM3LqEPujWPoxP/hzr6lRtj6wD: 4832738218... in GRP: 6SsQ/HAHUn..." +
-		"}}"
+		"}, " +
+		"Admins:[]" +
+		"}"
 
 	if grp.GoString() != expected {
 		t.Errorf("GoString failed to return the expected string."+
@@ -314,7 +369,8 @@ func TestGroup_GoString_NilGroup(t *testing.T) {
 		"InitMessage:\"\", " +
 		"Created:0001-01-01 00:00:00 +0000 UTC, " +
 		"Members:{<nil>}, " +
-		"DhKeys:{}" +
+		"DhKeys:{}, " +
+		"Admins:[]" +
 		"}"
 
 	if grp.GoString() != expected {
@@ -0,0 +1,255 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupStore
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Storage values.
+const (
+	// Key used to identify the list of Invites in storage.
+	inviteListStorageKey = "GroupChatInviteList"
+	inviteListVersion    = 0
+)
+
+// Error messages.
+const (
+	inviteListMarshalErr   = "failed to marshal invite list: %+v"
+	inviteListUnmarshalErr = "failed to unmarshal invite list: %+v"
+)
+
+// InviteLifetime is the duration after which an invite that has been sent or
+// received but not answered is considered expired.
+const InviteLifetime = 7 * 24 * time.Hour
+
+// InviteStatus is the status of a group invite for a single member.
+type InviteStatus uint8
+
+const (
+	// InviteSent is the status of an invite sent by the group leader that the
+	// member has not responded to.
+	InviteSent InviteStatus = iota + 1
+
+	// InviteReceived is the status of an invite received by the member that
+	// they have not yet joined or declined.
+	InviteReceived
+
+	// InviteJoined is the status of an invite that the member accepted.
+	InviteJoined
+
+	// InviteDeclined is the status of an invite that the member declined.
+	InviteDeclined
+
+	// InviteRevoked is the status of an invite that the group leader revoked
+	// before it was accepted.
+	InviteRevoked
+
+	// InviteExpired is the status of a sent or received invite that has not
+	// been answered within the InviteLifetime. It is never stored; it is only
+	// returned in place of InviteSent or InviteReceived.
+	InviteExpired
+)
+
+// String returns a human-readable version of the InviteStatus, used for
+// debugging and logging. This function adheres to the fmt.Stringer interface.
+func (is InviteStatus) String() string {
+	switch is {
+	case InviteSent:
+		return "Sent"
+	case InviteReceived:
+		return "Received"
+	case InviteJoined:
+		return "Joined"
+	case InviteDeclined:
+		return "Declined"
+	case InviteRevoked:
+		return "Revoked"
+	case InviteExpired:
+		return "Expired"
+	default:
+		return "Unknown InviteStatus " + strconv.Itoa(int(is))
+	}
+}
+
+// Invite tracks the status of the invite of a single member to a group. The
+// group leader tracks an invite for every member it invites and each member
+// tracks the invite it received.
+type Invite struct {
+	GroupID  *id.ID       `json:"groupID"`
+	LeaderID *id.ID       `json:"leaderID"`
+	MemberID *id.ID       `json:"memberID"`
+	Status   InviteStatus `json:"status"`
+	Updated  time.Time    `json:"updated"`
+}
+
+// withExpiry returns a copy of the invite with its status set to InviteExpired
+// if it has not been answered within the InviteLifetime.
+func (i Invite) withExpiry() Invite {
+	if (i.Status == InviteSent || i.Status == InviteReceived) &&
+		netTime.Since(i.Updated) > InviteLifetime {
+		i.Status = InviteExpired
+	}
+	return i
+}
+
+// SetInvite sets the status of the invite of the member to the group and saves
+// it to storage.
+func (s *Store) SetInvite(
+	groupID, leaderID, memberID *id.ID, status InviteStatus) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, exists := s.invites[*groupID]; !exists {
+		s.invites[*groupID] = make(map[id.ID]Invite)
+	}
+	s.invites[*groupID][*memberID] = Invite{
+		GroupID:  groupID.DeepCopy(),
+		LeaderID: leaderID.DeepCopy(),
+		MemberID: memberID.DeepCopy(),
+		Status:   status,
+		Updated:  netTime.Now().Round(0),
+	}
+
+	return s.saveInvites()
+}
+
+// GetInvite returns the invite of the member to the group. Returns false if
+// no invite is tracked.
+func (s *Store) GetInvite(groupID, memberID *id.ID) (Invite, bool) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	invite, exists := s.invites[*groupID][*memberID]
+	if !exists {
+		return Invite{}, false
+	}
+
+	return invite.withExpiry(), true
+}
+
+// GetInvites returns all invites tracked for the group.
+func (s *Store) GetInvites(groupID *id.ID) []Invite {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	invites := make([]Invite, 0, len(s.invites[*groupID]))
+	for _, invite := range s.invites[*groupID] {
+		invites = append(invites, invite.withExpiry())
+	}
+
+	return invites
+}
+
+// PendingInvites returns all invites received by the user that have not been
+// joined, declined, revoked, or expired.
+func (s *Store) PendingInvites() []Invite {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	var invites []Invite
+	for _, members := range s.invites {
+		invite, exists := members[*s.user.ID]
+		if exists && invite.withExpiry().Status == InviteReceived {
+			invites = append(invites, invite)
+		}
+	}
+
+	return invites
+}
+
+// RemoveInvite removes the invite of the member to the group from memory and
+// storage.
+func (s *Store) RemoveInvite(groupID, memberID *id.ID) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, exists := s.invites[*groupID][*memberID]; !exists {
+		return nil
+	}
+
+	delete(s.invites[*groupID], *memberID)
+	if len(s.invites[*groupID]) == 0 {
+		delete(s.invites, *groupID)
+	}
+
+	return s.saveInvites()
+}
+
+// RemoveInvites removes all invites tracked for the group from memory and
+// storage.
+func (s *Store) RemoveInvites(groupID *id.ID) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, exists := s.invites[*groupID]; !exists {
+		return nil
+	}
+
+	delete(s.invites, *groupID)
+
+	return s.saveInvites()
+}
+
+// saveInvites saves the list of all invites to storage.
+func (s *Store) saveInvites() error {
+	invites := make([]Invite, 0, len(s.invites))
+	for _, members := range s.invites {
+		for _, invite := range members {
+			invites = append(invites, invite)
+		}
+	}
+
+	data, err := json.Marshal(invites)
+	if err != nil {
+		return errors.Errorf(inviteListMarshalErr, err)
+	}
+
+	obj := &versioned.Object{
+		Version:   inviteListVersion,
+		Timestamp: netTime.Now(),
+		Data:      data,
+	}
+
+	return s.kv.Set(inviteListStorageKey, obj)
+}
+
+// loadInvites loads the list of all invites from storage. If no invites are
+// saved, an empty list is returned.
+func loadInvites(kv versioned.KV) (map[id.ID]map[id.ID]Invite, error) {
+	invites := make(map[id.ID]map[id.ID]Invite)
+
+	obj, err := kv.Get(inviteListStorageKey, inviteListVersion)
+	if err != nil {
+		if kv.Exists(err) {
+			return nil, err
+		}
+		return invites, nil
+	}
+
+	var inviteList []Invite
+	if err = json.Unmarshal(obj.Data, &inviteList); err != nil {
+		return nil, errors.Errorf(inviteListUnmarshalErr, err)
+	}
+
+	for _, invite := range inviteList {
+		if _, exists := invites[*invite.GroupID]; !exists {
+			invites[*invite.GroupID] = make(map[id.ID]Invite)
+		}
+		invites[*invite.GroupID][*invite.MemberID] = invite
+	}
+
+	return invites, nil
+}
@@ -0,0 +1,170 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupStore
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"gitlab.com/elixxir/client/v4/collective/versioned"
+	"gitlab.com/elixxir/ekv"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Consistency test of InviteStatus.String.
+func TestInviteStatus_String(t *testing.T) {
+	expectedStrings := map[InviteStatus]string{
+		InviteSent: "Sent", InviteReceived: "Received", InviteJoined: "Joined",
+		InviteDeclined: "Declined", InviteRevoked: "Revoked",
+		InviteExpired:     "Expired",
+		InviteExpired + 1: fmt.Sprintf("Unknown InviteStatus %d", InviteExpired+1),
+	}
+
+	for status, expected := range expectedStrings {
+		if status.String() != expected {
+			t.Errorf("Stringer failed on test.\nexpected: %s\nreceived: %s",
+				expected, status)
+		}
+	}
+}
+
+// Tests that an invite set with Store.SetInvite can be retrieved with
+// Store.GetInvite and Store.GetInvites and is loaded from storage.
+func TestStore_SetInvite_GetInvite(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	user := randMember(prng)
+	store, err := NewStore(kv, user)
+	if err != nil {
+		t.Fatalf("Failed to create store: %+v", err)
+	}
+
+	groupID := id.NewIdFromString("group", id.Group, t)
+	leaderID := id.NewIdFromString("leader", id.User, t)
+	memberIDs := []*id.ID{
+		id.NewIdFromString("member1", id.User, t),
+		id.NewIdFromString("member2", id.User, t),
+	}
+
+	for _, memberID := range memberIDs {
+		err = store.SetInvite(groupID, leaderID, memberID, InviteSent)
+		if err != nil {
+			t.Fatalf("SetInvite returned an error: %+v", err)
+		}
+	}
+	err = store.SetInvite(groupID, leaderID, memberIDs[1], InviteJoined)
+	if err != nil {
+		t.Fatalf("SetInvite returned an error: %+v", err)
+	}
+
+	newStore, err := LoadStore(kv, user)
+	if err != nil {
+		t.Fatalf("Failed to load store: %+v", err)
+	}
+
+	for _, s := range []*Store{store, newStore} {
+		if invites := s.GetInvites(groupID); len(invites) != len(memberIDs) {
+			t.Errorf("Unexpected number of invites.\nexpected: %d"+
+				"\nreceived: %d", len(memberIDs), len(invites))
+		}
+
+		for i, expected := range []InviteStatus{InviteSent, InviteJoined} {
+			invite, exists := s.GetInvite(groupID, memberIDs[i])
+			if !exists {
+				t.Fatalf("Invite for member %d not found.", i)
+			}
+			if invite.Status != expected || !invite.LeaderID.Cmp(leaderID) {
+				t.Errorf("Unexpected invite for member %d: %+v", i, invite)
+			}
+		}
+	}
+}
+
+// Tests that Store.GetInvite returns InviteExpired for a sent invite that has
+// not been answered within the InviteLifetime and that it is not returned by
+// Store.PendingInvites.
+func TestStore_GetInvite_Expired(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	user := randMember(prng)
+	store, err := NewStore(versioned.NewKV(ekv.MakeMemstore()), user)
+	if err != nil {
+		t.Fatalf("Failed to create store: %+v", err)
+	}
+
+	groupID := id.NewIdFromString("group", id.Group, t)
+	leaderID := id.NewIdFromString("leader", id.User, t)
+	err = store.SetInvite(groupID, leaderID, user.ID, InviteReceived)
+	if err != nil {
+		t.Fatalf("SetInvite returned an error: %+v", err)
+	}
+
+	if pending := store.PendingInvites(); len(pending) != 1 {
+		t.Errorf("Unexpected number of pending invites."+
+			"\nexpected: %d\nreceived: %d", 1, len(pending))
+	}
+
+	invite := store.invites[*groupID][*user.ID]
+	invite.Updated = netTime.Now().Add(-InviteLifetime - time.Minute)
+	store.invites[*groupID][*user.ID] = invite
+
+	invite, _ = store.GetInvite(groupID, user.ID)
+	if invite.Status != InviteExpired {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			InviteExpired, invite.Status)
+	}
+	if pending := store.PendingInvites(); len(pending) != 0 {
+		t.Errorf("Expired invite returned as pending: %+v", pending)
+	}
+}
+
+// Tests that Store.RemoveInvite and Store.RemoveInvites remove invites from
+// memory and storage.
+func TestStore_RemoveInvites(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	user := randMember(prng)
+	store, err := NewStore(kv, user)
+	if err != nil {
+		t.Fatalf("Failed to create store: %+v", err)
+	}
+
+	groupID := id.NewIdFromString("group", id.Group, t)
+	leaderID := id.NewIdFromString("leader", id.User, t)
+	memberIDs := []*id.ID{
+		id.NewIdFromString("member1", id.User, t),
+		id.NewIdFromString("member2", id.User, t),
+	}
+	for _, memberID := range memberIDs {
+		err = store.SetInvite(groupID, leaderID, memberID, InviteSent)
+		if err != nil {
+			t.Fatalf("SetInvite returned an error: %+v", err)
+		}
+	}
+
+	if err = store.RemoveInvite(groupID, memberIDs[0]); err != nil {
+		t.Fatalf("RemoveInvite returned an error: %+v", err)
+	}
+	if _, exists := store.GetInvite(groupID, memberIDs[0]); exists {
+		t.Errorf("Invite for %s not removed.", memberIDs[0])
+	}
+
+	if err = store.RemoveInvites(groupID); err != nil {
+		t.Fatalf("RemoveInvites returned an error: %+v", err)
+	}
+
+	newStore, err := LoadStore(kv, user)
+	if err != nil {
+		t.Fatalf("Failed to load store: %+v", err)
+	}
+	if invites := newStore.GetInvites(groupID); len(invites) != 0 {
+		t.Errorf("Invites not removed from storage: %+v", invites)
+	}
+}
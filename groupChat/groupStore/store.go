@@ -37,6 +37,7 @@ const (
 	maxGroupsErr      = "failed to add new group, max number of groups (%d) reached"
 	groupExistsErr    = "group with ID %s already exists"
 	groupRemoveErr    = "failed to remove group with ID %s, group not found in memory"
+	groupUpdateErr    = "failed to update group with ID %s, group not found in memory"
 	saveListRemoveErr = "failed to save new group ID list after removing group %s"
 	setUserPanic      = "Store.SetUser is for testing only. Got %T"
 )
@@ -48,6 +49,10 @@ const MaxGroupChats = 64
 // Store stores the list of Groups that a user is a part of.
 type Store struct {
 	list map[id.ID]Group
+
+	// Invites for each group keyed on group ID and then member ID
+	invites map[id.ID]map[id.ID]Invite
+
 	user group.Member
 	kv   versioned.KV
 	mux  sync.RWMutex
@@ -60,9 +65,10 @@ func NewStore(kv versioned.KV, user group.Member) (*Store, error) {
 		return nil, err
 	}
 	s := &Store{
-		list: make(map[id.ID]Group),
-		user: user.DeepCopy(),
-		kv:   kv,
+		list:    make(map[id.ID]Group),
+		invites: make(map[id.ID]map[id.ID]Invite),
+		user:    user.DeepCopy(),
+		kv:      kv,
 	}
 
 	return s, s.save()
@@ -108,11 +114,18 @@ func loadStore(data []byte, kv versioned.KV, user group.Member) (*Store, error)
 	// Deserialize list of group IDs
 	groupIDs := deserializeGroupIdList(data)
 
+	// Load the invites
+	invites, err := loadInvites(kv)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the Store
 	s := &Store{
-		list: make(map[id.ID]Group, len(groupIDs)),
-		user: user.DeepCopy(),
-		kv:   kv,
+		list:    make(map[id.ID]Group, len(groupIDs)),
+		invites: invites,
+		user:    user.DeepCopy(),
+		kv:      kv,
 	}
 
 	// Load each Group from storage into the map
@@ -184,7 +197,8 @@ func (s *Store) save() error {
 		}
 	}
 
-	return nil
+	// Store invites
+	return s.saveInvites()
 }
 
 // Len returns the number of groups stored.
@@ -224,6 +238,24 @@ func (s *Store) Add(g Group) error {
 	return g.store(s.kv)
 }
 
+// Update replaces the existing group with the same ID with the given group and
+// saves it to storage. An error is returned if the group does not exist.
+func (s *Store) Update(g Group) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	// Return an error if the group does not exist in the map
+	if _, exists := s.list[*g.ID]; !exists {
+		return errors.Errorf(groupUpdateErr, g.ID)
+	}
+
+	// Replace the group in the map
+	s.list[*g.ID] = g.DeepCopy()
+
+	// Store the group to storage
+	return g.store(s.kv)
+}
+
 // Remove removes the group with the corresponding ID from memory and storage.
 // An error is returned if the group cannot be found in memory or storage.
 func (s *Store) Remove(groupID *id.ID) error {
@@ -31,9 +31,10 @@ func TestNewStore(t *testing.T) {
 	user := randMember(prng)
 
 	expectedStore := &Store{
-		list: make(map[id.ID]Group),
-		user: user,
-		kv:   expectedKv,
+		list:    make(map[id.ID]Group),
+		invites: make(map[id.ID]map[id.ID]Invite),
+		user:    user,
+		kv:      expectedKv,
 	}
 
 	store, err := NewStore(kv, user)
@@ -394,6 +395,64 @@ func TestStore_Remove_RemoveGroupNotInMemoryError(t *testing.T) {
 	}
 }
 
+// Tests that Store.Update replaces the group in memory and in storage.
+func TestStore_Update(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	user := randMember(prng)
+
+	store, err := NewStore(kv, user)
+	if err != nil {
+		t.Fatalf("Failed to create store: %+v", err)
+	}
+
+	grp := createTestGroup(prng, t)
+	if err = store.Add(grp); err != nil {
+		t.Fatalf("Failed to add group: %+v", err)
+	}
+
+	// Change the key and membership of the group
+	updated := createTestGroup(prng, t)
+	updated.ID = grp.ID
+	if err = store.Update(updated); err != nil {
+		t.Errorf("Update returned an error: %+v", err)
+	}
+
+	if !reflect.DeepEqual(updated, store.list[*grp.ID]) {
+		t.Errorf("Group not updated in memory.\nexpected: %#v\nreceived: %#v",
+			updated, store.list[*grp.ID])
+	}
+
+	loaded, err := loadGroup(grp.ID, store.kv)
+	if err != nil {
+		t.Fatalf("Failed to load group: %+v", err)
+	}
+	if !reflect.DeepEqual(updated, loaded) {
+		t.Errorf("Group not updated in storage.\nexpected: %#v\nreceived: %#v",
+			updated, loaded)
+	}
+}
+
+// Error path: shows that Store.Update returns an error when no group with the
+// given ID is found in the map.
+func TestStore_Update_GroupNotInMemoryError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	kv := versioned.NewKV(ekv.MakeMemstore())
+	user := randMember(prng)
+	expectedErr := strings.SplitN(groupUpdateErr, "%", 2)[0]
+
+	store, err := NewStore(kv, user)
+	if err != nil {
+		t.Fatalf("Failed to create store: %+v", err)
+	}
+
+	err = store.Update(createTestGroup(prng, t))
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("Update did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+}
+
 // Unit test of Store.GroupIDs.
 func TestStore_GroupIDs(t *testing.T) {
 	prng := rand.New(rand.NewSource(42))
@@ -11,11 +11,29 @@
 // group, the group leader must have an authenticated channel with all members
 // of the group.
 //
-// Once a group is created, neither the leader nor other members can add or
-// remove users to the group. Only members can leave a group themselves.
+// Only admins can add or remove members once a group is created. The creator
+// of the group is always an admin and can make other members admins; admins
+// can also rename the group and send admin notices. Every change is signed by
+// the admin that made it and verified by each member on receipt, so members
+// cannot forge changes to the group. Each membership change rekeys the group
+// and the new key is sent to all remaining members, so removed members cannot
+// read messages sent afterward. Members can also leave a group themselves.
+//
+// The status of each invite is tracked: the leader tracks whether each member's
+// invite was sent, joined, declined, or revoked, and members track the invites
+// they receive. Invites that are not answered within
+// groupStore.InviteLifetime expire.
 //
 // When a message is sent to the group, the sender will send an individual
 // message to every member of the group.
+//
+// Messages are opaque payloads sent on a service tag. Typed text, reply,
+// reaction, and delete messages can be sent with the typed send functions and
+// received by registering NewTypedProcessor on the TypedServiceTag.
+//
+// Sent and received messages can optionally be stored in an EventModel, such as
+// the SQLite implementation in groupChat/storage, by creating the manager with
+// NewManagerWithEventModel.
 
 package groupChat
 
@@ -65,12 +83,91 @@ type GroupChat interface {
 	// LeaveGroup removes a group from a list of groups the user is a part of.
 	LeaveGroup(groupID *id.ID) error
 
+	// AddMember adds a member to the GroupChat and rekeys it. Only admins can
+	// add members and must have an authenticated channel with the new
+	// member. The new member is sent a GroupChat request and all other members
+	// are sent the new membership and key. Returns the rounds the messages
+	// were sent on and the status of the sends.
+	AddMember(groupID, memberID *id.ID) ([]id.Round, RequestStatus, error)
+
+	// RemoveMember removes a member from the GroupChat and rekeys it so that
+	// the removed member cannot read messages sent afterward. Only admins can
+	// remove members and only the creator can remove other admins. Returns the
+	// rounds the messages were sent on and the status of the sends.
+	RemoveMember(groupID, memberID *id.ID) ([]id.Round, RequestStatus, error)
+
+	/* ===== Admins ========================================================= */
+
+	// SetAdmin makes the member an admin of the GroupChat or removes their
+	// admin role. Only the creator can change the admins.
+	SetAdmin(groupID, memberID *id.ID, admin bool) (
+		rounds.Round, time.Time, group.MessageID, error)
+
+	// RenameGroup changes the name of the GroupChat for all members. Only
+	// admins can rename the group.
+	RenameGroup(groupID *id.ID, name []byte) (
+		rounds.Round, time.Time, group.MessageID, error)
+
+	// SendAdminNotice sends a notice signed by the admin to all GroupChat
+	// members. Only admins can send notices.
+	SendAdminNotice(groupID *id.ID, notice string) (
+		rounds.Round, time.Time, group.MessageID, error)
+
+	// RegisterAdminCallbacks registers the callbacks that are called when an
+	// admin change or notice is received. Replaces any existing callbacks.
+	RegisterAdminCallbacks(cbs AdminCallbacks)
+
+	/* ===== Invites ======================================================== */
+
+	// DeclineInvite declines the invite to the GroupChat and notifies the
+	// leader. Returns an error if the group has already been joined or the
+	// invite was revoked.
+	DeclineInvite(g gs.Group) error
+
+	// RevokeInvite revokes the invite of a member who has not yet joined or
+	// declined the GroupChat. The member is removed and the group is rekeyed.
+	// Only admins can revoke invites. Returns the rounds the messages were
+	// sent on and the status of the sends.
+	RevokeInvite(groupID, memberID *id.ID) ([]id.Round, RequestStatus, error)
+
+	// GetInvites returns the status of every invite tracked for the GroupChat.
+	// The leader tracks the invites of all members; members only track their
+	// own.
+	GetInvites(groupID *id.ID) []gs.Invite
+
+	// PendingInvites returns all received invites that have not been joined,
+	// declined, revoked, or expired.
+	PendingInvites() []gs.Invite
+
 	// Send sends a message to all GroupChat members using Cmix.SendManyCMIX.
 	// The send fails if the message is too long. Returns the ID of the round
 	// sent on and the timestamp of the message send.
 	Send(groupID *id.ID, tag string, message []byte) (
 		rounds.Round, time.Time, group.MessageID, error)
 
+	/* ===== Typed Messages ================================================= */
+
+	// SendText sends a text message to all GroupChat members on the
+	// TypedServiceTag. Returns the round sent on, the timestamp, and the ID of
+	// the message.
+	SendText(groupID *id.ID, text string) (
+		rounds.Round, time.Time, group.MessageID, error)
+
+	// SendReply sends a text message to all GroupChat members as a reply to
+	// the message with the given ID.
+	SendReply(groupID *id.ID, text string, replyTo group.MessageID) (
+		rounds.Round, time.Time, group.MessageID, error)
+
+	// SendReaction sends a reaction to the message with the given ID to all
+	// GroupChat members. The reaction must be a single emoji.
+	SendReaction(groupID *id.ID, reaction string, reactTo group.MessageID) (
+		rounds.Round, time.Time, group.MessageID, error)
+
+	// SendDelete asks all GroupChat members to delete the message with the
+	// given ID. Members only honour the request for the user's own messages.
+	SendDelete(groupID *id.ID, messageID group.MessageID) (
+		rounds.Round, time.Time, group.MessageID, error)
+
 	// GetGroups returns a list of all registered GroupChat IDs.
 	GetGroups() []*id.ID
 
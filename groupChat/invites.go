@@ -0,0 +1,265 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/catalog"
+	"gitlab.com/elixxir/client/v4/e2e/receive"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Error messages.
+const (
+	// manager.DeclineInvite
+	declineJoinedErr = "cannot decline group %s that has already been joined"
+	declineStatusErr = "cannot decline invite to group %s with status %s"
+	setInviteErr     = "failed to save invite to group %s for %s: %+v"
+
+	// manager.RevokeInvite
+	inviteNotFoundErr = "no invite to group %s found for %s"
+	revokeStatusErr   = "cannot revoke invite to group %s for %s with status %s"
+
+	// manager.JoinGroup
+	joinInviteStatusErr = "cannot join group %s with invite status %s"
+
+	// manager.readInviteResponse
+	responseMessageTypeErr = "message not of type GroupInviteResponse"
+	responseNoGroupErr     = "invite response for unknown group %s"
+	responseNotLeaderErr   = "invite response for group %s which the user does not lead"
+	responseNotMemberErr   = "invite response for group %s from %s who is not a member"
+)
+
+// DeclineInvite declines the invite to the group. The leader is notified so
+// that they can track the status of the invite. Returns an error if the group
+// has already been joined or the invite was revoked.
+func (m *manager) DeclineInvite(g gs.Group) error {
+	if _, exists := m.GetGroup(g.ID); exists {
+		return errors.Errorf(declineJoinedErr, g.ID)
+	}
+
+	myID := m.getReceptionIdentity().ID
+	invite, exists := m.gs.GetInvite(g.ID, myID)
+	if exists && (invite.Status == gs.InviteJoined ||
+		invite.Status == gs.InviteRevoked) {
+		return errors.Errorf(declineStatusErr, g.ID, invite.Status)
+	}
+
+	leaderID := g.Members[0].ID
+	err := m.gs.SetInvite(g.ID, leaderID, myID, gs.InviteDeclined)
+	if err != nil {
+		return errors.Errorf(setInviteErr, g.ID, myID, err)
+	}
+
+	jww.INFO.Printf("[GC] Declined invite to group %q with ID %s.",
+		g.Name, g.ID)
+
+	return m.sendInviteResponse(g.ID, leaderID, false)
+}
+
+// RevokeInvite revokes the invite of the member to the group before they have
+// joined or declined it. The member is removed from the group and the group is
+// rekeyed as in RemoveMember. Only group admins can revoke invites.
+func (m *manager) RevokeInvite(groupID, memberID *id.ID) (
+	[]id.Round, RequestStatus, error) {
+	if _, err := m.getAdminGroup(groupID); err != nil {
+		return nil, NotSent, err
+	}
+
+	invite, exists := m.gs.GetInvite(groupID, memberID)
+	if !exists {
+		return nil, NotSent, errors.Errorf(inviteNotFoundErr, groupID, memberID)
+	} else if invite.Status != gs.InviteSent &&
+		invite.Status != gs.InviteExpired {
+		return nil, NotSent,
+			errors.Errorf(revokeStatusErr, groupID, memberID, invite.Status)
+	}
+
+	rounds, status, err := m.RemoveMember(groupID, memberID)
+
+	// Mark the invite as revoked once the member has been removed, even if
+	// some of the membership updates failed to send
+	if g, exists := m.GetGroup(groupID); exists && !isMember(g.Members, memberID) {
+		setErr := m.gs.SetInvite(
+			groupID, g.Members[0].ID, memberID, gs.InviteRevoked)
+		if setErr != nil {
+			jww.ERROR.Printf("[GC] "+setInviteErr, groupID, memberID, setErr)
+		}
+		jww.INFO.Printf("[GC] Revoked invite to group %s for %s.",
+			groupID, memberID)
+	}
+
+	return rounds, status, err
+}
+
+// GetInvites returns the status of every invite tracked for the group. The
+// leader tracks the invites of every member; members only track their own.
+func (m *manager) GetInvites(groupID *id.ID) []gs.Invite {
+	return m.gs.GetInvites(groupID)
+}
+
+// PendingInvites returns all received invites that have not been joined,
+// declined, revoked, or expired.
+func (m *manager) PendingInvites() []gs.Invite {
+	return m.gs.PendingInvites()
+}
+
+// markInvitesSent sets the invite status of every member of the group that has
+// not joined to sent.
+func (m *manager) markInvitesSent(g gs.Group, memberIDs []*id.ID) {
+	for _, memberID := range memberIDs {
+		invite, exists := m.gs.GetInvite(g.ID, memberID)
+		if exists && invite.Status == gs.InviteJoined {
+			continue
+		}
+
+		err := m.gs.SetInvite(g.ID, g.Members[0].ID, memberID, gs.InviteSent)
+		if err != nil {
+			jww.ERROR.Printf("[GC] "+setInviteErr, g.ID, memberID, err)
+		}
+	}
+}
+
+// checkJoinInvite returns an error if the user cannot join the group because
+// their invite was revoked or has expired. Groups without a tracked invite can
+// always be joined. Returns true if the invite is tracked.
+func (m *manager) checkJoinInvite(groupID *id.ID) (bool, error) {
+	invite, exists := m.gs.GetInvite(groupID, m.getReceptionIdentity().ID)
+	if !exists {
+		return false, nil
+	}
+
+	if invite.Status == gs.InviteRevoked || invite.Status == gs.InviteExpired {
+		return true, errors.Errorf(joinInviteStatusErr, groupID, invite.Status)
+	}
+
+	return true, nil
+}
+
+// acceptInvite marks the user's invite to the joined group as joined and
+// notifies the leader. Errors are logged because the group has already been
+// joined.
+func (m *manager) acceptInvite(g gs.Group) {
+	myID := m.getReceptionIdentity().ID
+	leaderID := g.Members[0].ID
+	if err := m.gs.SetInvite(g.ID, leaderID, myID, gs.InviteJoined); err != nil {
+		jww.ERROR.Printf("[GC] "+setInviteErr, g.ID, myID, err)
+	}
+
+	if err := m.sendInviteResponse(g.ID, leaderID, true); err != nil {
+		jww.ERROR.Printf("[GC] Failed to notify leader %s of joining group "+
+			"%s: %+v", leaderID, g.ID, err)
+	}
+}
+
+// sendInviteResponse sends the response to the invite to the group leader.
+func (m *manager) sendInviteResponse(
+	groupID, leaderID *id.ID, accepted bool) error {
+	response, err := proto.Marshal(&InviteResponse{
+		GroupID:  groupID.Marshal(),
+		Accepted: accepted,
+	})
+	if err != nil {
+		return errors.Errorf(protoMarshalErr, err)
+	}
+
+	_, err = m.sendE2eRequest(catalog.GroupInviteResponse, leaderID, response)
+	return err
+}
+
+// markInviteRevoked marks the user's received invite to the group as revoked
+// when the leader removes the user before they joined. Returns true if the
+// invite was revoked.
+func (m *manager) markInviteRevoked(groupID, senderID *id.ID) bool {
+	myID := m.getReceptionIdentity().ID
+	invite, exists := m.gs.GetInvite(groupID, myID)
+	if !exists || !invite.LeaderID.Cmp(senderID) ||
+		invite.Status == gs.InviteJoined {
+		return false
+	}
+
+	err := m.gs.SetInvite(groupID, invite.LeaderID, myID, gs.InviteRevoked)
+	if err != nil {
+		jww.ERROR.Printf("[GC] "+setInviteErr, groupID, myID, err)
+		return false
+	}
+
+	return true
+}
+
+// Adheres to receive.Listener interface
+type inviteResponseListener struct {
+	m *manager
+}
+
+// Hear waits for invite responses from invited members.
+func (l *inviteResponseListener) Hear(item receive.Message) {
+	jww.DEBUG.Print("[GC] Group invite response received message.")
+
+	if err := l.m.readInviteResponse(item); err != nil {
+		jww.WARN.Printf(
+			"[GC] Failed to read message as group invite response: %+v", err)
+	}
+}
+
+// Name returns a name, used for debugging
+func (l *inviteResponseListener) Name() string {
+	return catalog.GroupRq + "-inviteResponse"
+}
+
+// readInviteResponse updates the status of the sender's invite to the group.
+// Responses to revoked invites are ignored. An error is returned if the
+// response is invalid or the user is not the group leader.
+func (m *manager) readInviteResponse(msg receive.Message) error {
+	if msg.MessageType != catalog.GroupInviteResponse {
+		return errors.New(responseMessageTypeErr)
+	}
+
+	response := &InviteResponse{}
+	if err := proto.Unmarshal(msg.Payload, response); err != nil {
+		return errors.Errorf(protoUnmarshalErr, err)
+	}
+
+	groupID, err := id.Unmarshal(response.GetGroupID())
+	if err != nil {
+		return errors.Errorf(unmarshalGroupIdErr, err)
+	}
+
+	g, exists := m.GetGroup(groupID)
+	if !exists {
+		return errors.Errorf(responseNoGroupErr, groupID)
+	} else if !g.Members[0].ID.Cmp(m.getReceptionIdentity().ID) {
+		return errors.Errorf(responseNotLeaderErr, groupID)
+	} else if !isMember(g.Members, msg.Sender) {
+		return errors.Errorf(responseNotMemberErr, groupID, msg.Sender)
+	}
+
+	invite, exists := m.gs.GetInvite(groupID, msg.Sender)
+	if exists && invite.Status == gs.InviteRevoked {
+		jww.DEBUG.Printf("[GC] Ignoring response to revoked invite to group "+
+			"%s from %s.", groupID, msg.Sender)
+		return nil
+	}
+
+	status := gs.InviteDeclined
+	if response.GetAccepted() {
+		status = gs.InviteJoined
+	}
+
+	err = m.gs.SetInvite(groupID, g.Members[0].ID, msg.Sender, status)
+	if err != nil {
+		return errors.Errorf(setInviteErr, groupID, msg.Sender, err)
+	}
+
+	jww.INFO.Printf("[GC] Set invite status of %s to group %q with ID %s "+
+		"to %s.", msg.Sender, g.Name, groupID, status)
+	return nil
+}
@@ -0,0 +1,317 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"gitlab.com/elixxir/client/v4/catalog"
+	"gitlab.com/elixxir/client/v4/e2e/receive"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that manager.MakeGroup marks the invite of every member as sent and
+// that manager.readInviteResponse updates the status when members respond.
+func Test_manager_MakeGroup_readInviteResponse(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+
+	g, _, _, err := m.MakeGroup(memberIDs[:3], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+
+	invites := m.GetInvites(g.ID)
+	if len(invites) != 3 {
+		t.Fatalf("Unexpected number of invites.\nexpected: %d\nreceived: %d",
+			3, len(invites))
+	}
+	for _, invite := range invites {
+		if invite.Status != gs.InviteSent {
+			t.Errorf("Unexpected status for %s.\nexpected: %s\nreceived: %s",
+				invite.MemberID, gs.InviteSent, invite.Status)
+		}
+	}
+
+	for i, accepted := range []bool{true, false} {
+		msg := newInviteResponseMsg(memberIDs[i], g.ID, accepted, t)
+		if err = m.readInviteResponse(msg); err != nil {
+			t.Fatalf("readInviteResponse returned an error: %+v", err)
+		}
+	}
+
+	for i, expected := range []gs.InviteStatus{
+		gs.InviteJoined, gs.InviteDeclined, gs.InviteSent} {
+		invite, _ := m.gs.GetInvite(g.ID, memberIDs[i])
+		if invite.Status != expected {
+			t.Errorf("Unexpected status for member %d."+
+				"\nexpected: %s\nreceived: %s", i, expected, invite.Status)
+		}
+	}
+}
+
+// Error path: Tests that manager.readInviteResponse returns an error for a
+// response from a user who is not a member of the group.
+func Test_manager_readInviteResponse_NotMemberError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+
+	g, _, _, err := m.MakeGroup(memberIDs[:3], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+	expectedErr := strings.SplitN(responseNotMemberErr, "%", 2)[0]
+
+	msg := newInviteResponseMsg(memberIDs[5], g.ID, true, t)
+	err = m.readInviteResponse(msg)
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("readInviteResponse did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+}
+
+// Tests that manager.JoinGroup marks a received invite as joined and notifies
+// the leader.
+func Test_manager_JoinGroup_AcceptInvite(t *testing.T) {
+	m, g := newTestManager(t)
+	myID := m.getReceptionIdentity().ID
+	err := m.gs.SetInvite(g.ID, g.Members[0].ID, myID, gs.InviteReceived)
+	if err != nil {
+		t.Fatalf("Failed to set invite: %+v", err)
+	}
+
+	if pending := m.PendingInvites(); len(pending) != 1 {
+		t.Errorf("Unexpected number of pending invites."+
+			"\nexpected: %d\nreceived: %d", 1, len(pending))
+	}
+
+	if err = m.JoinGroup(g); err != nil {
+		t.Fatalf("JoinGroup returned an error: %+v", err)
+	}
+
+	invite, _ := m.gs.GetInvite(g.ID, myID)
+	if invite.Status != gs.InviteJoined {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			gs.InviteJoined, invite.Status)
+	}
+	if pending := m.PendingInvites(); len(pending) != 0 {
+		t.Errorf("Joined invite still pending: %+v", pending)
+	}
+
+	checkInviteResponse(m, g.Members[0].ID, g.ID, true, t)
+}
+
+// Error path: Tests that manager.JoinGroup returns an error when the invite was
+// revoked.
+func Test_manager_JoinGroup_RevokedInviteError(t *testing.T) {
+	m, g := newTestManager(t)
+	myID := m.getReceptionIdentity().ID
+	err := m.gs.SetInvite(g.ID, g.Members[0].ID, myID, gs.InviteRevoked)
+	if err != nil {
+		t.Fatalf("Failed to set invite: %+v", err)
+	}
+	expectedErr := strings.SplitN(joinInviteStatusErr, "%", 2)[0]
+
+	err = m.JoinGroup(g)
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("JoinGroup did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+
+	if _, exists := m.GetGroup(g.ID); exists {
+		t.Errorf("Group %s joined with revoked invite.", g.ID)
+	}
+}
+
+// Tests that manager.DeclineInvite marks the invite as declined and notifies
+// the leader.
+func Test_manager_DeclineInvite(t *testing.T) {
+	m, g := newTestManager(t)
+	myID := m.getReceptionIdentity().ID
+	err := m.gs.SetInvite(g.ID, g.Members[0].ID, myID, gs.InviteReceived)
+	if err != nil {
+		t.Fatalf("Failed to set invite: %+v", err)
+	}
+
+	if err = m.DeclineInvite(g); err != nil {
+		t.Fatalf("DeclineInvite returned an error: %+v", err)
+	}
+
+	invite, _ := m.gs.GetInvite(g.ID, myID)
+	if invite.Status != gs.InviteDeclined {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			gs.InviteDeclined, invite.Status)
+	}
+
+	checkInviteResponse(m, g.Members[0].ID, g.ID, false, t)
+}
+
+// Error path: Tests that manager.DeclineInvite returns an error for a group
+// that has already been joined.
+func Test_manager_DeclineInvite_JoinedError(t *testing.T) {
+	m, g := newTestManager(t)
+	if err := m.JoinGroup(g); err != nil {
+		t.Fatalf("Failed to join group: %+v", err)
+	}
+	expectedErr := strings.SplitN(declineJoinedErr, "%", 2)[0]
+
+	err := m.DeclineInvite(g)
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("DeclineInvite did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+}
+
+// Tests that manager.RevokeInvite removes the invited member from the group and
+// marks their invite as revoked.
+func Test_manager_RevokeInvite(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+
+	g, _, _, err := m.MakeGroup(memberIDs[:3], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+
+	_, status, err := m.RevokeInvite(g.ID, memberIDs[1])
+	if err != nil {
+		t.Fatalf("RevokeInvite returned an error: %+v", err)
+	} else if status != AllSent {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			AllSent, status)
+	}
+
+	newG, _ := m.GetGroup(g.ID)
+	if isMember(newG.Members, memberIDs[1]) {
+		t.Errorf("Member %s not removed from group.", memberIDs[1])
+	}
+
+	invite, _ := m.gs.GetInvite(g.ID, memberIDs[1])
+	if invite.Status != gs.InviteRevoked {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			gs.InviteRevoked, invite.Status)
+	}
+}
+
+// Error path: Tests that manager.RevokeInvite returns an error for a member who
+// has already joined the group.
+func Test_manager_RevokeInvite_JoinedError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+
+	g, _, _, err := m.MakeGroup(memberIDs[:3], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+	err = m.readInviteResponse(newInviteResponseMsg(memberIDs[0], g.ID, true, t))
+	if err != nil {
+		t.Fatalf("Failed to read invite response: %+v", err)
+	}
+	expectedErr := strings.SplitN(revokeStatusErr, "%", 2)[0]
+
+	_, status, err := m.RevokeInvite(g.ID, memberIDs[0])
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("RevokeInvite did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	} else if status != NotSent {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			NotSent, status)
+	}
+
+	newG, _ := m.GetGroup(g.ID)
+	if !isMember(newG.Members, memberIDs[0]) {
+		t.Errorf("Joined member %s removed from group.", memberIDs[0])
+	}
+}
+
+// Tests that manager.readMembershipUpdate marks a received invite to a group
+// that has not been joined as revoked when the leader removes the user.
+func Test_manager_readMembershipUpdate_RevokedInvite(t *testing.T) {
+	m, g := newTestManager(t)
+	myID := m.getReceptionIdentity().ID
+	err := m.gs.SetInvite(g.ID, g.Members[0].ID, myID, gs.InviteReceived)
+	if err != nil {
+		t.Fatalf("Failed to set invite: %+v", err)
+	}
+
+	var newMembership group.Membership
+	for _, member := range g.Members {
+		if !member.ID.Cmp(myID) {
+			newMembership = append(newMembership, member)
+		}
+	}
+
+	msg := newMembershipUpdateMsg(g.Members[0].ID, &Request{
+		Members: newMembership.Serialize(),
+		GroupID: g.ID.Marshal(),
+	}, t)
+	if err = m.readMembershipUpdate(msg); err != nil {
+		t.Fatalf("readMembershipUpdate returned an error: %+v", err)
+	}
+
+	invite, _ := m.gs.GetInvite(g.ID, myID)
+	if invite.Status != gs.InviteRevoked {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			gs.InviteRevoked, invite.Status)
+	}
+}
+
+// checkInviteResponse checks that the last E2E message sent was an invite
+// response to the leader for the group.
+func checkInviteResponse(m *manager, leaderID, groupID *id.ID, accepted bool,
+	t *testing.T) {
+	msgs := m.getE2eHandler().(*testE2eManager).e2eMessages
+	if len(msgs) == 0 {
+		t.Fatalf("No invite response sent.")
+	}
+
+	msg := msgs[len(msgs)-1]
+	if msg.MessageType != catalog.GroupInviteResponse {
+		t.Errorf("Unexpected message type.\nexpected: %s\nreceived: %s",
+			catalog.GroupInviteResponse, msg.MessageType)
+	}
+	if !msg.Recipient.Cmp(leaderID) {
+		t.Errorf("Invite response not sent to leader.\nexpected: %s"+
+			"\nreceived: %s", leaderID, msg.Recipient)
+	}
+
+	response := &InviteResponse{}
+	if err := proto.Unmarshal(msg.Payload, response); err != nil {
+		t.Fatalf("Failed to unmarshal invite response: %+v", err)
+	}
+	if !bytes.Equal(response.GetGroupID(), groupID.Marshal()) ||
+		response.GetAccepted() != accepted {
+		t.Errorf("Unexpected invite response: %+v", response)
+	}
+}
+
+// newInviteResponseMsg returns a GroupInviteResponse message from the sender.
+func newInviteResponseMsg(
+	sender, groupID *id.ID, accepted bool, t *testing.T) receive.Message {
+	payload, err := proto.Marshal(
+		&InviteResponse{GroupID: groupID.Marshal(), Accepted: accepted})
+	if err != nil {
+		t.Fatalf("Failed to marshal invite response: %+v", err)
+	}
+
+	return receive.Message{
+		Payload:     payload,
+		MessageType: catalog.GroupInviteResponse,
+		Sender:      sender,
+	}
+}
@@ -25,6 +25,7 @@ const (
 	// NewManager
 	newGroupStoreErr     = "failed to create new group store: %+v"
 	errAddDefaultService = "could not add default service: %+v"
+	errAddAdminService   = "could not add admin service: %+v"
 
 	// manager.JoinGroup
 	joinGroupErr = "failed to join new group %s: %+v"
@@ -47,12 +48,27 @@ type manager struct {
 	// Callback that is called when a new group request is received
 	requestFunc RequestCallback
 
+	// Optional event model that stores sent and received messages; may be nil
+	ev EventModel
+
+	// Callbacks called when admin messages are received; may be nil
+	adminCbs AdminCallbacks
+	adminMux sync.RWMutex
+
 	user groupE2e
 }
 
 // NewManager creates a new group chat manager
 func NewManager(user groupE2e,
 	requestFunc RequestCallback, receiveFunc Processor) (GroupChat, error) {
+	return NewManagerWithEventModel(user, requestFunc, receiveFunc, nil)
+}
+
+// NewManagerWithEventModel creates a new group chat manager that stores all
+// sent and received group messages in the EventModel. The event model may be
+// nil.
+func NewManagerWithEventModel(user groupE2e, requestFunc RequestCallback,
+	receiveFunc Processor, ev EventModel) (GroupChat, error) {
 
 	// Initialize a member object
 	handler := user.GetE2E()
@@ -73,13 +89,29 @@ func NewManager(user groupE2e,
 		gs:          gStore,
 		services:    make(map[string]Processor),
 		requestFunc: requestFunc,
+		ev:          ev,
 		user:        user,
 	}
 
+	// Ensure that every stored group exists in the event model
+	if ev != nil {
+		for _, g := range gStore.Groups() {
+			ev.JoinGroup(g)
+		}
+	}
+
 	// Register listener for incoming e2e group chat requests
 	handler.RegisterListener(
 		&id.ZeroUser, catalog.GroupCreationRequest, &requestListener{m})
 
+	// Register listener for incoming e2e group membership updates
+	handler.RegisterListener(&id.ZeroUser, catalog.GroupMembershipUpdate,
+		&membershipUpdateListener{m})
+
+	// Register listener for incoming e2e group invite responses
+	handler.RegisterListener(&id.ZeroUser, catalog.GroupInviteResponse,
+		&inviteResponseListener{m})
+
 	// Register notifications listener for incoming e2e group chat requests
 	err = handler.AddService(catalog.GroupRq, nil)
 	if err != nil {
@@ -91,20 +123,40 @@ func NewManager(user groupE2e,
 		return nil, errors.Errorf(errAddDefaultService, err)
 	}
 
+	err = m.AddService(adminServiceTag, &adminProcessor{m})
+	if err != nil {
+		return nil, errors.Errorf(errAddAdminService, err)
+	}
+
 	return m, nil
 }
 
 // JoinGroup adds the group to storage, and enables requisite services.
-// An error is returned if the user is already part of the group or if the
-// maximum number of groups have already been joined.
+// An error is returned if the user is already part of the group, if the
+// maximum number of groups have already been joined, or if the invite to the
+// group was revoked or has expired. If the invite was received, the leader is
+// notified that it was accepted.
 func (m *manager) JoinGroup(g gs.Group) error {
-	if err := m.gs.Add(g); err != nil {
+	invited, err := m.checkJoinInvite(g.ID)
+	if err != nil {
+		return errors.Errorf(joinGroupErr, g.ID, err)
+	}
+
+	if err = m.gs.Add(g); err != nil {
 		return errors.Errorf(joinGroupErr, g.ID, err)
 	}
 
 	// Add all services for this group
 	m.addAllServices(g)
 
+	if m.ev != nil {
+		m.ev.JoinGroup(g)
+	}
+
+	if invited {
+		m.acceptInvite(g)
+	}
+
 	jww.INFO.Printf("[GC] Joined group %q with ID %s.", g.Name, g.ID)
 	return nil
 }
@@ -117,6 +169,15 @@ func (m *manager) LeaveGroup(groupID *id.ID) error {
 
 	m.deleteAllServices(groupID)
 
+	if err := m.gs.RemoveInvites(groupID); err != nil {
+		jww.ERROR.Printf("[GC] Failed to remove invites for group %s: %+v",
+			groupID, err)
+	}
+
+	if m.ev != nil {
+		m.ev.LeaveGroup(groupID)
+	}
+
 	jww.INFO.Printf("[GC] Left group with ID %s.", groupID)
 	return nil
 }
@@ -0,0 +1,412 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/catalog"
+	"gitlab.com/elixxir/client/v4/e2e/receive"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/contact"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Error messages.
+const (
+	// manager.AddMember and manager.RemoveMember
+	updateNoGroupErr  = "no group found with ID %s"
+	memberExistsErr   = "%s is already a member of group %s"
+	memberNotFoundErr = "%s is not a member of group %s"
+	removeLeaderErr   = "the group creator cannot be removed from group %s"
+	removeAdminErr    = "only the group creator can remove admin %s from group %s"
+	updateGroupErr    = "failed to update group %s: %+v"
+
+	// manager.readMembershipUpdate
+	updateMessageTypeErr = "message not of type GroupMembershipUpdate"
+	updateGroupIdErr     = "membership update contains no group ID"
+	updateNotAdminErr    = "membership update for group %s from %s who is not an admin"
+	updateLeaderErr      = "membership update for group %s changes the leader"
+	updateRemoveAdminErr = "membership update for group %s from %s removes an admin"
+)
+
+// AddMember adds the member to the group and rekeys it. Only group admins can
+// add members and must have an authenticated channel with the new member. The
+// new member is sent a group request and all other members are sent the new
+// membership and key. Returns the rounds the messages were sent on.
+func (m *manager) AddMember(groupID, memberID *id.ID) (
+	[]id.Round, RequestStatus, error) {
+	g, err := m.getAdminGroup(groupID)
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	if isMember(g.Members, memberID) {
+		return nil, NotSent, errors.Errorf(memberExistsErr, memberID, groupID)
+	}
+
+	partner, err := m.getE2eHandler().GetPartner(memberID)
+	if err != nil {
+		return nil, NotSent, errors.Errorf(getPartnerErr, memberID, err)
+	}
+	newMember := contact.Contact{
+		ID:       partner.PartnerId(),
+		DhPubKey: partner.PartnerRootPublicKey(),
+	}
+
+	mem, err := newMembership(g, append(participantContacts(g), newMember))
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	dkl := g.DhKeys.DeepCopy()
+	dkl.Add(partner.MyRootPrivateKey(), group.Member{
+		ID:    newMember.ID,
+		DhKey: newMember.DhPubKey,
+	}, m.getE2eGroup())
+
+	newG, err := m.rekeyGroup(g, mem, dkl)
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	jww.INFO.Printf("[GC] Added member %s to group %s.", memberID, groupID)
+
+	request, err := m.marshalRequest(newG)
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	// Existing members are sent the new membership and the new member is sent
+	// a group request
+	recipients := make([]requestRecipient, 0, len(newG.Members)-1)
+	for _, member := range newG.Members {
+		if member.ID.Cmp(m.getReceptionIdentity().ID) {
+			continue
+		}
+		mt := catalog.GroupMembershipUpdate
+		if member.ID.Cmp(memberID) {
+			mt = catalog.GroupCreationRequest
+		}
+		recipients = append(recipients,
+			requestRecipient{member.ID, mt, request})
+	}
+
+	rounds, status, err := m.sendRequestsTo(newG, recipients)
+	if err == nil {
+		m.markInvitesSent(newG, []*id.ID{memberID})
+	}
+
+	return rounds, status, err
+}
+
+// RemoveMember removes the member from the group and rekeys it so that the
+// removed member cannot read any messages sent afterward. Only group admins can
+// remove members and only the creator can remove other admins. The creator
+// cannot be removed. All remaining members are sent the new membership and key;
+// the removed member is only sent the new membership. Returns the rounds the
+// messages were sent on.
+func (m *manager) RemoveMember(groupID, memberID *id.ID) (
+	[]id.Round, RequestStatus, error) {
+	g, err := m.getAdminGroup(groupID)
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	if g.IsCreator(memberID) {
+		return nil, NotSent, errors.Errorf(removeLeaderErr, groupID)
+	} else if !isMember(g.Members, memberID) {
+		return nil, NotSent, errors.Errorf(memberNotFoundErr, memberID, groupID)
+	} else if g.IsAdmin(memberID) &&
+		!g.IsCreator(m.getReceptionIdentity().ID) {
+		return nil, NotSent, errors.Errorf(removeAdminErr, memberID, groupID)
+	}
+
+	participants := make([]contact.Contact, 0, len(g.Members)-2)
+	for _, c := range participantContacts(g) {
+		if !c.ID.Cmp(memberID) {
+			participants = append(participants, c)
+		}
+	}
+
+	mem, err := newMembership(g, participants)
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	dkl := g.DhKeys.DeepCopy()
+	delete(dkl, *memberID)
+
+	newG, err := m.rekeyGroup(g, mem, dkl)
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	jww.INFO.Printf("[GC] Removed member %s from group %s.", memberID, groupID)
+
+	if err = m.gs.RemoveInvite(groupID, memberID); err != nil {
+		jww.ERROR.Printf("[GC] Failed to remove invite to group %s for %s: "+
+			"%+v", groupID, memberID, err)
+	}
+
+	request, err := m.marshalRequest(newG)
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	// The removed member is only told of the new membership so that they can
+	// leave the group; they must not receive the new key preimage
+	notice, err := m.marshalSignedRequest(&Request{
+		Members: newG.Members.Serialize(),
+		GroupID: newG.ID.Marshal(),
+	})
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	recipients := make([]requestRecipient, 0, len(newG.Members))
+	for _, member := range newG.Members {
+		if member.ID.Cmp(m.getReceptionIdentity().ID) {
+			continue
+		}
+		recipients = append(recipients, requestRecipient{
+			member.ID, catalog.GroupMembershipUpdate, request})
+	}
+	recipients = append(recipients, requestRecipient{
+		memberID, catalog.GroupMembershipUpdate, notice})
+
+	return m.sendRequestsTo(newG, recipients)
+}
+
+// newMembership builds a new membership for the group with the creator as its
+// leader and the given participants. The existing DH keys of the participants
+// are reused so that an admin does not need a partnership with every member.
+func newMembership(
+	g gs.Group, participants []contact.Contact) (group.Membership, error) {
+	if len(participants) < group.MinParticipants {
+		return nil, errors.Errorf(
+			minMembersErr, len(participants), group.MinParticipants)
+	} else if len(participants) > group.MaxParticipants {
+		return nil, errors.Errorf(
+			maxMembersErr, len(participants), group.MaxParticipants)
+	}
+
+	leader := contact.Contact{ID: g.Members[0].ID, DhPubKey: g.Members[0].DhKey}
+	mem, err := group.NewMembership(leader, participants...)
+	if err != nil {
+		return nil, errors.Errorf(makeMembershipErr, err)
+	}
+
+	return mem, nil
+}
+
+// rekeyGroup generates a new group key for the new membership and DH key list
+// and replaces the group in storage. The group ID, name, and initial message
+// are unchanged. Admins that are no longer members lose their role.
+func (m *manager) rekeyGroup(
+	g gs.Group, mem group.Membership, dkl gs.DhKeyList) (gs.Group, error) {
+	// Generate a new key preimage so that removed members cannot derive the
+	// new key
+	rng := m.getRng().GetStream()
+	keyPreimage, err := group.NewKeyPreimage(rng)
+	rng.Close()
+	if err != nil {
+		return gs.Group{}, errors.Errorf(newKeyPreimageErr, err)
+	}
+
+	newG := gs.NewGroup(g.Name, g.ID, group.NewKey(keyPreimage, mem),
+		g.IdPreimage, keyPreimage, g.InitMessage, g.Created, mem, dkl)
+	newG.Admins = memberAdmins(g.Admins, mem)
+	newG.Charter = g.Charter
+
+	// The creator signs a new charter when an admin is removed so that the
+	// removed admin cannot add members who have not yet joined
+	if len(newG.Admins) != len(g.Admins) &&
+		g.IsCreator(m.getReceptionIdentity().ID) {
+		if newG.Charter, err = m.signCharter(newG, newG.Admins); err != nil {
+			return gs.Group{}, err
+		}
+	}
+
+	if err = m.replaceGroup(newG); err != nil {
+		return gs.Group{}, err
+	}
+
+	return newG, nil
+}
+
+// replaceGroup replaces the stored group with the updated one and re-registers
+// all services so that messages are processed with the new key.
+func (m *manager) replaceGroup(g gs.Group) error {
+	if err := m.gs.Update(g); err != nil {
+		return errors.Errorf(updateGroupErr, g.ID, err)
+	}
+
+	m.deleteAllServices(g.ID)
+	m.addAllServices(g)
+
+	if m.ev != nil {
+		m.ev.JoinGroup(g)
+	}
+
+	return nil
+}
+
+// participantIDs returns the IDs of all members of the group except the leader.
+func participantIDs(g gs.Group) []*id.ID {
+	memberIDs := make([]*id.ID, 0, len(g.Members)-1)
+	for _, member := range g.Members[1:] {
+		memberIDs = append(memberIDs, member.ID)
+	}
+	return memberIDs
+}
+
+// participantContacts returns the contacts of all members of the group except
+// the leader.
+func participantContacts(g gs.Group) []contact.Contact {
+	contacts := make([]contact.Contact, 0, len(g.Members)-1)
+	for _, member := range g.Members[1:] {
+		contacts = append(contacts,
+			contact.Contact{ID: member.ID, DhPubKey: member.DhKey})
+	}
+	return contacts
+}
+
+// isMember returns true if the user is in the membership.
+func isMember(membership group.Membership, uid *id.ID) bool {
+	for _, member := range membership {
+		if member.ID.Cmp(uid) {
+			return true
+		}
+	}
+	return false
+}
+
+// Adheres to receive.Listener interface
+type membershipUpdateListener struct {
+	m *manager
+}
+
+// Hear waits for membership updates from group admins.
+func (l *membershipUpdateListener) Hear(item receive.Message) {
+	jww.DEBUG.Print("[GC] Group membership update received message.")
+
+	if err := l.m.readMembershipUpdate(item); err != nil {
+		jww.WARN.Printf(
+			"[GC] Failed to read message as group membership update: %+v", err)
+	}
+}
+
+// Name returns a name, used for debugging
+func (l *membershipUpdateListener) Name() string {
+	return catalog.GroupRq + "-membershipUpdate"
+}
+
+// readMembershipUpdate applies the membership update to the existing group. If
+// the user is no longer a member, they leave the group. An error is returned if
+// the update is invalid, was not sent by a group admin, or is not signed by the
+// admin that sent it. Only the creator can change the admins or remove one.
+func (m *manager) readMembershipUpdate(msg receive.Message) error {
+	if msg.MessageType != catalog.GroupMembershipUpdate {
+		return errors.New(updateMessageTypeErr)
+	}
+
+	request := &Request{}
+	if err := proto.Unmarshal(msg.Payload, request); err != nil {
+		return errors.Errorf(protoUnmarshalErr, err)
+	}
+
+	if len(request.GetGroupID()) == 0 {
+		return errors.New(updateGroupIdErr)
+	}
+	groupID, err := id.Unmarshal(request.GetGroupID())
+	if err != nil {
+		return errors.Errorf(unmarshalGroupIdErr, err)
+	}
+
+	// Updates for groups that have not been joined are ignored, unless the
+	// leader removed the user before they joined; new members are sent a group
+	// request instead
+	g, exists := m.GetGroup(groupID)
+	if !exists {
+		membership, err := group.DeserializeMembership(request.GetMembers())
+		if err == nil && !isMember(membership, m.getReceptionIdentity().ID) &&
+			m.markInviteRevoked(groupID, msg.Sender) {
+			jww.INFO.Printf("[GC] Invite to group %s revoked by leader.",
+				groupID)
+			return nil
+		}
+
+		jww.DEBUG.Printf("[GC] Ignoring membership update for unknown "+
+			"group %s.", groupID)
+		return nil
+	}
+
+	// Only admins can change the membership and must sign the change
+	if !g.IsAdmin(msg.Sender) {
+		return errors.Errorf(updateNotAdminErr, groupID, msg.Sender)
+	}
+	isCreator := g.IsCreator(msg.Sender)
+	if err = verifyRequest(request, msg.Sender, isCreator); err != nil {
+		return err
+	}
+
+	membership, err := group.DeserializeMembership(request.GetMembers())
+	if err != nil {
+		return errors.Errorf(deserializeMembershipErr, err)
+	} else if !membership[0].ID.Cmp(g.Members[0].ID) {
+		return errors.Errorf(updateLeaderErr, groupID)
+	}
+
+	// Only the creator can remove admins
+	if !isCreator {
+		for _, admin := range g.Admins {
+			if !isMember(membership, admin) {
+				return errors.Errorf(updateRemoveAdminErr, groupID, msg.Sender)
+			}
+		}
+	}
+
+	// Leave the group if the user was removed
+	if !isMember(membership, m.getReceptionIdentity().ID) {
+		jww.INFO.Printf("[GC] Removed from group %q with ID %s by %s.",
+			g.Name, groupID, msg.Sender)
+		return m.LeaveGroup(groupID)
+	}
+
+	// Only the creator can change the admins and the charter listing them
+	admins, charter := g.Admins, g.Charter
+	if isCreator {
+		if admins, err = unmarshalIDs(request.GetAdmins()); err != nil {
+			return errors.Errorf(unmarshalAdminsErr, err)
+		}
+		if len(request.GetCharter()) > 0 {
+			_, err = verifyCharter(request.GetCharter(), msg.Sender, groupID)
+			if err != nil {
+				return err
+			}
+			charter = request.GetCharter()
+		}
+	}
+
+	newG, err := m.groupFromRequest(request, msg.Sender, groupID, admins)
+	if err != nil {
+		return err
+	}
+	newG.Charter = charter
+
+	if err = m.replaceGroup(newG); err != nil {
+		return err
+	}
+
+	jww.INFO.Printf("[GC] Updated membership of group %q with ID %s to %d "+
+		"members.", newG.Name, newG.ID, len(newG.Members))
+	return nil
+}
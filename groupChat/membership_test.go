@@ -0,0 +1,352 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/cloudflare/circl/dh/sidh"
+	"github.com/golang/protobuf/proto"
+	"gitlab.com/elixxir/client/v4/catalog"
+	sessionImport "gitlab.com/elixxir/client/v4/e2e/ratchet/partner/session"
+	"gitlab.com/elixxir/client/v4/e2e/receive"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	util "gitlab.com/elixxir/client/v4/storage/utility"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that manager.AddMember adds the member, rekeys the group, sends a group
+// request to the new member, and sends the membership update to all others.
+func Test_manager_AddMember(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+
+	g, _, _, err := m.MakeGroup(memberIDs[:5], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+	sent := len(m.getE2eHandler().(*testE2eManager).e2eMessages)
+
+	_, status, err := m.AddMember(g.ID, memberIDs[5])
+	if err != nil {
+		t.Fatalf("AddMember returned an error: %+v", err)
+	} else if status != AllSent {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			AllSent, status)
+	}
+
+	newG, exists := m.GetGroup(g.ID)
+	if !exists {
+		t.Fatalf("Group %s not found.", g.ID)
+	}
+	if len(newG.Members) != len(g.Members)+1 ||
+		!isMember(newG.Members, memberIDs[5]) {
+		t.Errorf("New member not added to group: %s", newG.Members)
+	}
+	if _, exists = newG.DhKeys[*memberIDs[5]]; !exists {
+		t.Errorf("No DH key generated for new member.")
+	}
+	if newG.Key == g.Key || newG.KeyPreimage == g.KeyPreimage {
+		t.Errorf("Group was not rekeyed.")
+	}
+
+	msgs := m.getE2eHandler().(*testE2eManager).e2eMessages[sent:]
+	if len(msgs) != len(newG.Members)-1 {
+		t.Fatalf("Unexpected number of messages sent."+
+			"\nexpected: %d\nreceived: %d", len(newG.Members)-1, len(msgs))
+	}
+	for i, msg := range msgs {
+		expected := catalog.GroupMembershipUpdate
+		if msg.Recipient.Cmp(memberIDs[5]) {
+			expected = catalog.GroupCreationRequest
+		}
+		if msg.MessageType != expected {
+			t.Errorf("Unexpected message type to %s (%d)."+
+				"\nexpected: %s\nreceived: %s",
+				msg.Recipient, i, expected, msg.MessageType)
+		}
+
+		request := &Request{}
+		if err = proto.Unmarshal(msg.Payload, request); err != nil {
+			t.Fatalf("Failed to unmarshal request (%d): %+v", i, err)
+		}
+		if !bytes.Equal(g.ID.Marshal(), request.GetGroupID()) {
+			t.Errorf("Request does not contain group ID (%d).", i)
+		}
+		if !bytes.Equal(newG.KeyPreimage.Bytes(), request.GetKeyPreimage()) {
+			t.Errorf("Request does not contain new key preimage (%d).", i)
+		}
+	}
+}
+
+// Error path: tests that manager.AddMember returns an error when the user is
+// not a group admin.
+func Test_manager_AddMember_NotAdminError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestManagerWithStore(prng, 1, 0, nil, t)
+	expectedErr := strings.SplitN(notAdminErr, "%", 2)[0]
+
+	_, status, err := m.AddMember(g.ID, id.NewIdFromString("new", id.User, t))
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("AddMember did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	} else if status != NotSent {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			NotSent, status)
+	}
+}
+
+// Tests that manager.RemoveMember removes the member, rekeys the group, sends
+// the new key to all remaining members, and only sends the new membership to
+// the removed member.
+func Test_manager_RemoveMember(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+
+	g, _, _, err := m.MakeGroup(memberIDs[:5], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+	sent := len(m.getE2eHandler().(*testE2eManager).e2eMessages)
+	removed := memberIDs[2]
+
+	_, status, err := m.RemoveMember(g.ID, removed)
+	if err != nil {
+		t.Fatalf("RemoveMember returned an error: %+v", err)
+	} else if status != AllSent {
+		t.Errorf("Unexpected status.\nexpected: %s\nreceived: %s",
+			AllSent, status)
+	}
+
+	newG, exists := m.GetGroup(g.ID)
+	if !exists {
+		t.Fatalf("Group %s not found.", g.ID)
+	}
+	if len(newG.Members) != len(g.Members)-1 || isMember(newG.Members, removed) {
+		t.Errorf("Member not removed from group: %s", newG.Members)
+	}
+	if _, exists = newG.DhKeys[*removed]; exists {
+		t.Errorf("DH key for removed member not deleted.")
+	}
+	if newG.Key == g.Key || newG.KeyPreimage == g.KeyPreimage {
+		t.Errorf("Group was not rekeyed.")
+	}
+
+	msgs := m.getE2eHandler().(*testE2eManager).e2eMessages[sent:]
+	if len(msgs) != len(newG.Members) {
+		t.Fatalf("Unexpected number of messages sent."+
+			"\nexpected: %d\nreceived: %d", len(newG.Members), len(msgs))
+	}
+	for i, msg := range msgs {
+		if msg.MessageType != catalog.GroupMembershipUpdate {
+			t.Errorf("Unexpected message type to %s (%d)."+
+				"\nexpected: %s\nreceived: %s", msg.Recipient, i,
+				catalog.GroupMembershipUpdate, msg.MessageType)
+		}
+
+		request := &Request{}
+		if err = proto.Unmarshal(msg.Payload, request); err != nil {
+			t.Fatalf("Failed to unmarshal request (%d): %+v", i, err)
+		}
+
+		if msg.Recipient.Cmp(removed) {
+			if len(request.GetKeyPreimage()) != 0 {
+				t.Errorf("Removed member was sent the new key preimage.")
+			}
+		} else if !bytes.Equal(
+			newG.KeyPreimage.Bytes(), request.GetKeyPreimage()) {
+			t.Errorf("Request does not contain new key preimage (%d).", i)
+		}
+	}
+}
+
+// Error path: tests that manager.RemoveMember returns an error when removing
+// the leader or a user who is not a member.
+func Test_manager_RemoveMember_InvalidMemberError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, _ := newTestManagerWithStore(prng, 0, 0, nil, t)
+	memberIDs, _, _ := addPartners(m, t)
+
+	g, _, _, err := m.MakeGroup(memberIDs[:5], []byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Failed to make group: %+v", err)
+	}
+
+	tests := []struct {
+		memberID *id.ID
+		err      string
+	}{
+		{m.getReceptionIdentity().ID, removeLeaderErr},
+		{memberIDs[6], memberNotFoundErr},
+	}
+
+	for i, tt := range tests {
+		expectedErr := strings.SplitN(tt.err, "%", 2)[0]
+		_, _, err = m.RemoveMember(g.ID, tt.memberID)
+		if err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Errorf("RemoveMember did not return the expected error (%d)."+
+				"\nexpected: %s\nreceived: %+v", i, expectedErr, err)
+		}
+	}
+}
+
+// Tests that manager.readMembershipUpdate updates the group with the new
+// membership and key received from the leader.
+func Test_manager_readMembershipUpdate(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestMembershipManager(prng, t)
+
+	// Remove a member other than the leader and the user
+	var newMembership group.Membership
+	for i, member := range g.Members {
+		if i != 1 || member.ID.Cmp(m.getReceptionIdentity().ID) {
+			newMembership = append(newMembership, member)
+		}
+	}
+	keyPreimage, err := group.NewKeyPreimage(prng)
+	if err != nil {
+		t.Fatalf("Failed to generate key preimage: %+v", err)
+	}
+
+	msg := newMembershipUpdateMsg(g.Members[0].ID, &Request{
+		Name:        g.Name,
+		IdPreimage:  g.IdPreimage.Bytes(),
+		KeyPreimage: keyPreimage.Bytes(),
+		Members:     newMembership.Serialize(),
+		Message:     g.InitMessage,
+		Created:     g.Created.UnixNano(),
+		GroupID:     g.ID.Marshal(),
+	}, t)
+
+	if err = m.readMembershipUpdate(msg); err != nil {
+		t.Fatalf("readMembershipUpdate returned an error: %+v", err)
+	}
+
+	newG, exists := m.GetGroup(g.ID)
+	if !exists {
+		t.Fatalf("Group %s not found.", g.ID)
+	}
+	if newG.Members.String() != newMembership.String() {
+		t.Errorf("Unexpected membership.\nexpected: %s\nreceived: %s",
+			newMembership, newG.Members)
+	}
+	if newG.KeyPreimage != keyPreimage ||
+		newG.Key != group.NewKey(keyPreimage, newMembership) {
+		t.Errorf("Group was not rekeyed.")
+	}
+	if len(newG.DhKeys) != len(newMembership)-1 {
+		t.Errorf("Unexpected number of DH keys.\nexpected: %d\nreceived: %d",
+			len(newMembership)-1, len(newG.DhKeys))
+	}
+}
+
+// Tests that manager.readMembershipUpdate removes the group when the user is
+// no longer a member.
+func Test_manager_readMembershipUpdate_Removed(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestMembershipManager(prng, t)
+
+	var newMembership group.Membership
+	for _, member := range g.Members {
+		if !member.ID.Cmp(m.getReceptionIdentity().ID) {
+			newMembership = append(newMembership, member)
+		}
+	}
+
+	msg := newMembershipUpdateMsg(g.Members[0].ID, &Request{
+		Members: newMembership.Serialize(),
+		GroupID: g.ID.Marshal(),
+	}, t)
+
+	if err := m.readMembershipUpdate(msg); err != nil {
+		t.Fatalf("readMembershipUpdate returned an error: %+v", err)
+	}
+
+	if _, exists := m.GetGroup(g.ID); exists {
+		t.Errorf("Group %s not removed.", g.ID)
+	}
+}
+
+// Error path: tests that manager.readMembershipUpdate returns an error and does
+// not modify the group when the update is not sent by an admin.
+func Test_manager_readMembershipUpdate_NotAdminError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestMembershipManager(prng, t)
+	expectedErr := strings.SplitN(updateNotAdminErr, "%", 2)[0]
+
+	msg := newMembershipUpdateMsg(g.Members[1].ID, &Request{
+		Members: g.Members[:len(g.Members)-1].Serialize(),
+		GroupID: g.ID.Marshal(),
+	}, t)
+
+	err := m.readMembershipUpdate(msg)
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("readMembershipUpdate did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+
+	newG, _ := m.GetGroup(g.ID)
+	if newG.Members.String() != g.Members.String() {
+		t.Errorf("Group membership was modified.")
+	}
+}
+
+// newTestMembershipManager returns a manager that is a member of the returned
+// group and has an authenticated channel with its leader.
+func newTestMembershipManager(prng *rand.Rand, t *testing.T) (
+	*manager, gs.Group) {
+	m, g := newTestManager(t)
+	if err := m.gs.Add(g); err != nil {
+		t.Fatalf("Failed to add group: %+v", err)
+	}
+
+	myVariant := sidh.KeyVariantSidhA
+	mySIDHPrivKey := util.NewSIDHPrivateKey(myVariant)
+	mySIDHPubKey := util.NewSIDHPublicKey(myVariant)
+	_ = mySIDHPrivKey.Generate(prng)
+	mySIDHPrivKey.GeneratePublicKey(mySIDHPubKey)
+
+	theirVariant := sidh.KeyVariant(sidh.KeyVariantSidhB)
+	theirSIDHPrivKey := util.NewSIDHPrivateKey(theirVariant)
+	theirSIDHPubKey := util.NewSIDHPublicKey(theirVariant)
+	_ = theirSIDHPrivKey.Generate(prng)
+	theirSIDHPrivKey.GeneratePublicKey(theirSIDHPubKey)
+
+	_, _ = m.getE2eHandler().AddPartner(
+		g.Members[0].ID,
+		g.Members[0].DhKey,
+		m.getE2eHandler().GetHistoricalDHPrivkey(),
+		theirSIDHPubKey, mySIDHPrivKey,
+		sessionImport.GetDefaultParams(),
+		sessionImport.GetDefaultParams(),
+	)
+
+	return m, g
+}
+
+// newMembershipUpdateMsg returns a GroupMembershipUpdate message containing the
+// marshalled request.
+func newMembershipUpdateMsg(
+	sender *id.ID, request *Request, t *testing.T) receive.Message {
+	payload, err := proto.Marshal(request)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %+v", err)
+	}
+
+	return receive.Message{
+		Payload:     payload,
+		MessageType: catalog.GroupMembershipUpdate,
+		Sender:      sender,
+	}
+}
@@ -0,0 +1,49 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"strconv"
+)
+
+// MessageType is the type of typed message content sent to a group. It is
+// stored in the TypedMessage that wraps the content.
+type MessageType uint16
+
+const (
+	// TextType is the default type for a message. It denotes that the message
+	// only contains text.
+	TextType MessageType = 1
+
+	// ReplyType denotes that the message is a reply to another message.
+	ReplyType MessageType = 2
+
+	// ReactionType denotes that the message is a reaction to another message.
+	ReactionType MessageType = 3
+
+	// DeleteType denotes that the message contains the ID of a message to
+	// delete.
+	DeleteType MessageType = 4
+)
+
+// String returns a human-readable version of [MessageType], used for debugging
+// and logging. This function adheres to the [fmt.Stringer] interface.
+func (mt MessageType) String() string {
+	switch mt {
+	case TextType:
+		return "Text"
+	case ReplyType:
+		return "Reply"
+	case ReactionType:
+		return "Reaction"
+	case DeleteType:
+		return "Delete"
+	default:
+		return "Unknown messageType " + strconv.Itoa(int(mt))
+	}
+}
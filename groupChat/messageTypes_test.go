@@ -0,0 +1,30 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Consistency test of MessageType.String.
+func TestMessageType_String_Consistency(t *testing.T) {
+	expectedStrings := map[MessageType]string{
+		TextType: "Text", ReplyType: "Reply", ReactionType: "Reaction",
+		DeleteType:     "Delete",
+		DeleteType + 1: fmt.Sprintf("Unknown messageType %d", DeleteType+1),
+		DeleteType + 2: fmt.Sprintf("Unknown messageType %d", DeleteType+2),
+	}
+
+	for mt, expected := range expectedStrings {
+		if mt.String() != expected {
+			t.Errorf("Stringer failed on test.\nexpected: %s\nreceived: %s",
+				expected, mt)
+		}
+	}
+}
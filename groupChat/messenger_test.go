@@ -8,6 +8,10 @@
 package groupChat
 
 import (
+	"math/rand"
+	"sync"
+	"testing"
+
 	"gitlab.com/elixxir/client/v4/cmix"
 	"gitlab.com/elixxir/client/v4/collective/versioned"
 	clientE2E "gitlab.com/elixxir/client/v4/e2e"
@@ -16,15 +20,51 @@ import (
 	"gitlab.com/elixxir/client/v4/xxdk"
 	"gitlab.com/elixxir/crypto/cyclic"
 	"gitlab.com/elixxir/crypto/fastRNG"
+	"gitlab.com/elixxir/crypto/rsa"
 	"gitlab.com/xx_network/crypto/csprng"
+	"gitlab.com/xx_network/crypto/xx"
 	"gitlab.com/xx_network/primitives/id"
-	"math/rand"
-	"testing"
 )
 
+// testIdentity is the RSA key, salt, and ID of the user of the mock. It is
+// generated once because generating RSA keys is slow.
+var testIdentity struct {
+	once    sync.Once
+	rsaPem  []byte
+	salt    []byte
+	id      *id.ID
+	initErr error
+}
+
+// getTestIdentity returns the RSA key PEM, salt, and ID used by the mock so
+// that the user can sign group changes.
+func getTestIdentity(t testing.TB) ([]byte, []byte, *id.ID) {
+	testIdentity.once.Do(func() {
+		prng := rand.New(rand.NewSource(42))
+		privKey, err := rsa.GetScheme().Generate(prng, 1024)
+		if err != nil {
+			testIdentity.initErr = err
+			return
+		}
+		testIdentity.rsaPem = privKey.MarshalPem()
+		testIdentity.salt = make([]byte, 32)
+		prng.Read(testIdentity.salt)
+		testIdentity.id, testIdentity.initErr =
+			xx.NewID(privKey.Public(), testIdentity.salt, id.User)
+	})
+	if testIdentity.initErr != nil {
+		t.Fatalf("Failed to generate test identity: %+v",
+			testIdentity.initErr)
+	}
+
+	return testIdentity.rsaPem, testIdentity.salt, testIdentity.id.DeepCopy()
+}
+
 // mockE2e implementation for groupE2e interface
 type mockE2e struct {
 	receptionId *id.ID
+	rsaPem      []byte
+	salt        []byte
 	net         cmix.Client
 	e2e         clientE2E.Handler
 	e2eGroup    *cyclic.Group
@@ -33,7 +73,7 @@ type mockE2e struct {
 }
 
 func newMockE2e(t testing.TB, kv versioned.KV) groupE2e {
-	receptionId := id.NewIdFromString("test", id.User, t)
+	rsaPem, salt, receptionId := getTestIdentity(t)
 	mockCmix := newTestNetworkManager(0)
 	prng := rand.New(rand.NewSource(42))
 	e2eHandler := newTestE2eManager(randCycInt(prng), t)
@@ -43,6 +83,8 @@ func newMockE2e(t testing.TB, kv versioned.KV) groupE2e {
 
 	return mockE2e{
 		receptionId: receptionId,
+		rsaPem:      rsaPem,
+		salt:        salt,
 		net:         mockCmix,
 		e2e:         e2eHandler,
 		e2eGroup:    grp,
@@ -52,7 +94,7 @@ func newMockE2e(t testing.TB, kv versioned.KV) groupE2e {
 }
 
 func newMockE2eWithStore(t testing.TB, sendErr int) groupE2e {
-	receptionId := id.NewIdFromString("test", id.User, t)
+	rsaPem, salt, receptionId := getTestIdentity(t)
 	mockCmix := newTestNetworkManager(sendErr)
 	prng := rand.New(rand.NewSource(42))
 	grp := getGroup()
@@ -61,6 +103,8 @@ func newMockE2eWithStore(t testing.TB, sendErr int) groupE2e {
 
 	return mockE2e{
 		receptionId: receptionId,
+		rsaPem:      rsaPem,
+		salt:        salt,
 		net:         mockCmix,
 		e2e: &testE2eManager{
 			e2eMessages: []testE2eMessage{},
@@ -87,9 +131,11 @@ func (m mockE2e) GetReceptionIdentity() xxdk.ReceptionIdentity {
 	keyData, _ := m.e2e.GetHistoricalDHPrivkey().MarshalJSON()
 	groupData, _ := getGroup().MarshalJSON()
 	return xxdk.ReceptionIdentity{
-		ID:           m.receptionId,
-		DHKeyPrivate: keyData,
-		E2eGrp:       groupData,
+		ID:            m.receptionId,
+		RSAPrivatePem: m.rsaPem,
+		Salt:          m.salt,
+		DHKeyPrivate:  keyData,
+		E2eGrp:        groupData,
 	}
 }
 
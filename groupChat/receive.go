@@ -36,9 +36,10 @@ const (
 
 // Adheres to message.Processor interface for reception processing.
 type receptionProcessor struct {
-	m *manager
-	g gs.Group
-	p Processor
+	m   *manager
+	g   gs.Group
+	p   Processor
+	tag string
 }
 
 // Process incoming group chat messages.
@@ -80,6 +81,11 @@ func (p *receptionProcessor) Process(message format.Message, _ []string, _ []byt
 		"%s in group %q with ID %s at %s.", result.ID, result.SenderID,
 		p.g.Name, p.g.ID, result.Timestamp)
 
+	if p.m.ev != nil {
+		p.m.ev.ReceiveMessage(result.GroupID, result.ID, result.SenderID,
+			p.tag, result.Payload, result.Timestamp, round)
+	}
+
 	// Send the decrypted message and original message to the processor
 	p.p.Process(result, message, nil, nil, receptionID, round)
 }
@@ -15,6 +15,7 @@ import (
 	"gitlab.com/elixxir/client/v4/e2e/receive"
 	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
 	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
 	"time"
 )
 
@@ -23,6 +24,12 @@ const (
 	sendMessageTypeErr       = "message not of type GroupCreationRequest"
 	protoUnmarshalErr        = "failed to unmarshal request: %+v"
 	deserializeMembershipErr = "failed to deserialize membership: %+v"
+	unmarshalGroupIdErr      = "failed to unmarshal group ID: %+v"
+	unmarshalAdminsErr       = "failed to unmarshal group admins: %+v"
+	requestNotAdminErr       = "group request from %s who is not an admin"
+	requestNoCharterErr      = "group request from %s who is not the creator has no charter"
+	groupIdLeaderErr         = "group request for stored group %s has a different leader %s"
+	groupIdNoCharterErr      = "group request with ID %s from %s who is not the leader has no charter"
 )
 
 // Adheres to receive.Listener interface
@@ -47,6 +54,12 @@ func (l *requestListener) Hear(item receive.Message) {
 		jww.INFO.Printf(
 			"[GC] Received group request for group %s with ID %s.", g.Name, g.ID)
 
+		myID := l.m.getReceptionIdentity().ID
+		err = l.m.gs.SetInvite(g.ID, g.Members[0].ID, myID, gs.InviteReceived)
+		if err != nil {
+			jww.ERROR.Printf("[GC] "+setInviteErr, g.ID, myID, err)
+		}
+
 		l.m.requestFunc(g)
 	}
 }
@@ -57,7 +70,8 @@ func (l *requestListener) Name() string {
 }
 
 // readRequest returns the group described in the group request message. An
-// error is returned if the request is of the wrong type or cannot be read.
+// error is returned if the request is of the wrong type, cannot be read, or was
+// not sent and signed by the creator or one of the admins of the group.
 func (m *manager) readRequest(msg receive.Message) (gs.Group, error) {
 	// Return an error if the message is not of the right type
 	if msg.MessageType != catalog.GroupCreationRequest {
@@ -71,21 +85,134 @@ func (m *manager) readRequest(msg receive.Message) (gs.Group, error) {
 		return gs.Group{}, errors.Errorf(protoUnmarshalErr, err)
 	}
 
+	membership, err := group.DeserializeMembership(request.GetMembers())
+	if err != nil {
+		return gs.Group{}, errors.Errorf(deserializeMembershipErr, err)
+	}
+
+	groupID, err := m.requestGroupID(request, msg.Sender, membership)
+	if err != nil {
+		return gs.Group{}, err
+	}
+
+	admins, err := verifyRequestSender(request, msg.Sender, groupID, membership)
+	if err != nil {
+		return gs.Group{}, err
+	}
+
+	g, err := m.groupFromRequest(request, msg.Sender, groupID, admins)
+	if err != nil {
+		return gs.Group{}, err
+	}
+	g.Charter = request.GetCharter()
+
+	return g, nil
+}
+
+// requestGroupID returns the ID of the group described in the request from the
+// sender. The ID is only included in the request once the membership has
+// changed since the group was created; otherwise, it is derived from the ID
+// preimage and members.
+//
+// An included ID cannot be derived, so it is only accepted from the leader of
+// the group or with a charter for the ID signed by the leader. If a group with
+// the ID is already stored, the leader must also be the stored leader. This
+// prevents a sender from claiming the ID of another group.
+func (m *manager) requestGroupID(request *Request, senderID *id.ID,
+	membership group.Membership) (*id.ID, error) {
+	if len(request.GetGroupID()) == 0 {
+		var idPreimage group.IdPreimage
+		copy(idPreimage[:], request.GetIdPreimage())
+		return group.NewID(idPreimage, membership), nil
+	}
+
+	groupID, err := id.Unmarshal(request.GetGroupID())
+	if err != nil {
+		return nil, errors.Errorf(unmarshalGroupIdErr, err)
+	}
+
+	leader := membership[0].ID
+	if g, exists := m.GetGroup(groupID); exists &&
+		!g.Members[0].ID.Cmp(leader) {
+		return nil, errors.Errorf(groupIdLeaderErr, groupID, leader)
+	}
+
+	if !leader.Cmp(senderID) {
+		if len(request.GetCharter()) == 0 {
+			return nil, errors.Errorf(groupIdNoCharterErr, groupID, senderID)
+		}
+		_, err = verifyCharter(request.GetCharter(), leader, groupID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return groupID, nil
+}
+
+// verifyRequestSender returns the admins of the group described in the request
+// from the sender. An error is returned if the sender is not the creator or one
+// of the admins, or if the request is not signed by the sender.
+//
+// The admins listed in the request are only trusted if it was sent by the
+// creator. Requests from other admins must include a charter signed by the
+// creator, and the admins are taken from the charter, so that a member cannot
+// make themselves an admin of a group that the recipient has not yet joined.
+func verifyRequestSender(request *Request, senderID, groupID *id.ID,
+	membership group.Membership) ([]*id.ID, error) {
+	isCreator := membership[0].ID.Cmp(senderID)
+	if err := verifyRequest(request, senderID, isCreator); err != nil {
+		return nil, err
+	}
+
+	var admins []*id.ID
+	var err error
+	if isCreator {
+		admins, err = unmarshalIDs(request.GetAdmins())
+		if err != nil {
+			return nil, errors.Errorf(unmarshalAdminsErr, err)
+		}
+	} else if len(request.GetCharter()) == 0 {
+		return nil, errors.Errorf(requestNoCharterErr, senderID)
+	} else {
+		admins, err = verifyCharter(
+			request.GetCharter(), membership[0].ID, groupID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	g := gs.Group{Members: membership, Admins: memberAdmins(admins, membership)}
+	if !g.IsAdmin(senderID) {
+		return nil, errors.Errorf(requestNotAdminErr, senderID)
+	}
+
+	return g.Admins, nil
+}
+
+// groupFromRequest builds the group with the given ID and admins described in
+// the request sent by the sender, generating the DH keys with each member. The
+// caller is responsible for verifying the ID and admins.
+func (m *manager) groupFromRequest(request *Request, senderID, groupID *id.ID,
+	admins []*id.ID) (gs.Group, error) {
 	// Deserialize membership list
 	membership, err := group.DeserializeMembership(request.GetMembers())
 	if err != nil {
 		return gs.Group{}, errors.Errorf(deserializeMembershipErr, err)
 	}
 
-	// get the relationship with the group leader
-	partner, err := m.getE2eHandler().GetPartner(membership[0].ID)
+	// get the relationship with the sender, which is either the group leader
+	// or an admin
+	partner, err := m.getE2eHandler().GetPartner(senderID)
 	if err != nil {
 		return gs.Group{}, errors.Errorf(getPrivKeyErr, err)
 	}
 
 	// Replace leader's public key with the one from the partnership
 	leaderPubKey := membership[0].DhKey.DeepCopy()
-	membership[0].DhKey = partner.PartnerRootPublicKey()
+	if membership[0].ID.Cmp(senderID) {
+		membership[0].DhKey = partner.PartnerRootPublicKey()
+	}
 
 	// Generate the DH keys with each group member
 	privKey := partner.MyRootPrivateKey()
@@ -101,14 +228,15 @@ func (m *manager) readRequest(msg receive.Message) (gs.Group, error) {
 	var keyPreimage group.KeyPreimage
 	copy(keyPreimage[:], request.GetKeyPreimage())
 
-	// Create group ID and key
-	groupID := group.NewID(idPreimage, membership)
+	// Create group key
 	groupKey := group.NewKey(keyPreimage, membership)
 
 	// Convert created timestamp from nanoseconds to time.Time
 	created := time.Unix(0, request.GetCreated())
 
 	// Return the new group
-	return gs.NewGroup(request.GetName(), groupID, groupKey, idPreimage,
-		keyPreimage, request.GetMessage(), created, membership, dkl), nil
+	g := gs.NewGroup(request.GetName(), groupID, groupKey, idPreimage,
+		keyPreimage, request.GetMessage(), created, membership, dkl)
+	g.Admins = memberAdmins(admins, membership)
+	return g, nil
 }
@@ -15,6 +15,8 @@ import (
 	"gitlab.com/elixxir/client/v4/e2e/receive"
 	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
 	util "gitlab.com/elixxir/client/v4/storage/utility"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
 	"math/rand"
 	"reflect"
 	"strings"
@@ -107,6 +109,7 @@ func TestRequestListener_Hear_GroupExists(t *testing.T) {
 	listener := requestListener{m: m}
 
 	msg := receive.Message{
+		Sender:      g.Members[0].ID,
 		Payload:     requestMarshaled,
 		MessageType: catalog.GroupCreationRequest,
 	}
@@ -184,6 +187,7 @@ func Test_manager_readRequest(t *testing.T) {
 	}
 
 	msg := receive.Message{
+		Sender:      g.Members[0].ID,
 		Payload:     requestMarshaled,
 		MessageType: catalog.GroupCreationRequest,
 	}
@@ -253,3 +257,79 @@ func Test_manager_readRequest_DeserializeMembershipError(t *testing.T) {
 			"\nexpected: %s\nreceived: %+v", expectedErr, err)
 	}
 }
+
+// Tests that manager.requestGroupID accepts an included group ID from the
+// leader or with a charter for the ID signed by the leader, and rejects it
+// without a charter, with a charter for another group, or when the stored group
+// with the ID has a different leader.
+func Test_manager_requestGroupID(t *testing.T) {
+	m, g := newTestManager(t)
+	myID := m.getReceptionIdentity().ID
+	creator := newTestSigner(43, t)
+	mem := newTestMembershipWithLeader(creator.id, g, t)
+	otherID := id.NewIdFromString("otherGroup", id.Group, t)
+
+	newRequest := func(groupID *id.ID, charter []byte) *Request {
+		return &Request{
+			IdPreimage: g.IdPreimage.Bytes(),
+			Members:    mem.Serialize(),
+			GroupID:    groupID.Marshal(),
+			Charter:    charter,
+		}
+	}
+
+	// The ID is derived from the members when it is not included
+	received, err := m.requestGroupID(
+		&Request{IdPreimage: g.IdPreimage.Bytes()}, myID, mem)
+	if err != nil {
+		t.Errorf("requestGroupID returned an error: %+v", err)
+	} else if expected := group.NewID(g.IdPreimage, mem); !expected.Cmp(received) {
+		t.Errorf("Unexpected derived group ID.\nexpected: %s\nreceived: %s",
+			expected, received)
+	}
+
+	valid := []struct {
+		request *Request
+		sender  *id.ID
+	}{
+		{newRequest(g.ID, nil), creator.id},
+		{newRequest(g.ID, creator.signCharter(g.ID, nil, t)), myID},
+	}
+	for i, tt := range valid {
+		received, err = m.requestGroupID(tt.request, tt.sender, mem)
+		if err != nil {
+			t.Errorf("requestGroupID returned an error (%d): %+v", i, err)
+		} else if !g.ID.Cmp(received) {
+			t.Errorf("Unexpected group ID (%d).\nexpected: %s\nreceived: %s",
+				i, g.ID, received)
+		}
+	}
+
+	tests := []struct {
+		request *Request
+		err     string
+	}{
+		{newRequest(g.ID, nil), groupIdNoCharterErr},
+		{newRequest(g.ID, creator.signCharter(otherID, nil, t)),
+			charterGroupIdErr},
+	}
+	for i, tt := range tests {
+		expectedErr := strings.SplitN(tt.err, "%", 2)[0]
+		_, err = m.requestGroupID(tt.request, myID, mem)
+		if err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Errorf("requestGroupID did not return the expected error (%d)."+
+				"\nexpected: %s\nreceived: %+v", i, expectedErr, err)
+		}
+	}
+
+	// A stored group can only be claimed by its stored leader
+	if err = m.gs.Add(g); err != nil {
+		t.Fatalf("Failed to add group: %+v", err)
+	}
+	expectedErr := strings.SplitN(groupIdLeaderErr, "%", 2)[0]
+	_, err = m.requestGroupID(newRequest(g.ID, nil), creator.id, mem)
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("requestGroupID did not return the expected error."+
+			"\nexpected: %s\nreceived: %+v", expectedErr, err)
+	}
+}
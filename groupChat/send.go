@@ -80,6 +80,11 @@ func (m *manager) Send(groupID *id.ID, tag string, message []byte) (
 
 	jww.DEBUG.Printf("[GC] Sent message to %d members in group %s at %s.",
 		len(groupMessages), groupID, timeNow)
+
+	if m.ev != nil {
+		m.ev.ReceiveMessage(groupID, msgId, m.getReceptionIdentity().ID, tag,
+			message, timeNow, rid)
+	}
 	return rid, timeNow, msgId, nil
 }
 
@@ -24,6 +24,7 @@ import (
 const (
 	resendGroupIdErr      = "cannot resend request to nonexistent group with ID %s"
 	protoMarshalErr       = "failed to form outgoing group chat request: %+v"
+	signRequestErr        = "failed to sign outgoing group chat request: %+v"
 	sendE2eErr            = "failed to send group request via E2E to member %s: %+v"
 	sendRequestAllErr     = "failed to send all %d group request messages: %s"
 	sendRequestPartialErr = "failed to send %d/%d group request messages: %s"
@@ -45,32 +46,86 @@ func (m *manager) ResendRequest(groupID *id.ID) ([]id.Round, RequestStatus, erro
 // leader/sender
 func (m *manager) sendRequests(g gs.Group) ([]id.Round, RequestStatus, error) {
 	// Build request message
-	requestMarshaled, err := proto.Marshal(&Request{
+	requestMarshaled, err := m.marshalRequest(g)
+	if err != nil {
+		return nil, NotSent, err
+	}
+
+	// Send request to each member in the group except the leader/sender
+	recipients := make([]requestRecipient, 0, len(g.Members)-1)
+	for _, member := range g.Members[1:] {
+		recipients = append(recipients, requestRecipient{
+			member.ID, catalog.GroupCreationRequest, requestMarshaled})
+	}
+
+	rounds, status, err := m.sendRequestsTo(g, recipients)
+	if err == nil {
+		m.markInvitesSent(g, participantIDs(g))
+	}
+
+	return rounds, status, err
+}
+
+// requestRecipient describes a single E2E group request message to send.
+type requestRecipient struct {
+	memberID    *id.ID
+	messageType catalog.MessageType
+	request     []byte
+}
+
+// marshalRequest builds, signs, and marshals the Request for the given group.
+// The group ID is only included when it can no longer be derived from the ID
+// preimage and membership (i.e., after the membership has changed).
+func (m *manager) marshalRequest(g gs.Group) ([]byte, error) {
+	request := &Request{
 		Name:        g.Name,
 		IdPreimage:  g.IdPreimage.Bytes(),
 		KeyPreimage: g.KeyPreimage.Bytes(),
 		Members:     g.Members.Serialize(),
 		Message:     g.InitMessage,
 		Created:     g.Created.UnixNano(),
-	})
+		Admins:      marshalIDs(g.Admins),
+		Charter:     g.Charter,
+	}
+	if !group.NewID(g.IdPreimage, g.Members).Cmp(g.ID) {
+		request.GroupID = g.ID.Marshal()
+	}
+
+	return m.marshalSignedRequest(request)
+}
+
+// marshalSignedRequest signs the request with the user's identity so that
+// members can verify it was sent by an admin and marshals it.
+func (m *manager) marshalSignedRequest(request *Request) ([]byte, error) {
+	if err := m.signRequest(request); err != nil {
+		return nil, errors.Errorf(signRequestErr, err)
+	}
+
+	requestMarshaled, err := proto.Marshal(request)
 	if err != nil {
-		return nil, NotSent, errors.Errorf(protoMarshalErr, err)
+		return nil, errors.Errorf(protoMarshalErr, err)
 	}
 
+	return requestMarshaled, nil
+}
+
+// sendRequestsTo sends each request to its recipient in parallel and blocks
+// until all sends return.
+func (m *manager) sendRequestsTo(g gs.Group, recipients []requestRecipient) (
+	[]id.Round, RequestStatus, error) {
 	// Create channel to return the results of each send on
-	n := len(g.Members) - 1
+	n := len(recipients)
 	type sendResults struct {
 		rounds []id.Round
 		err    error
 	}
 	resultsChan := make(chan sendResults, n)
 
-	// Send request to each member in the group except the leader/sender
-	for _, member := range g.Members[1:] {
-		go func(member group.Member) {
-			rounds, err := m.sendRequest(member.ID, requestMarshaled)
+	for _, r := range recipients {
+		go func(r requestRecipient) {
+			rounds, err := m.sendE2eRequest(r.messageType, r.memberID, r.request)
 			resultsChan <- sendResults{rounds, err}
-		}(member)
+		}(r)
 	}
 
 	// Block until each send returns
@@ -90,7 +145,7 @@ func (m *manager) sendRequests(g gs.Group) ([]id.Round, RequestStatus, error) {
 	}
 
 	// If all sends returned an error, then return AllFail with a list of errors
-	if len(errs) == n {
+	if n > 0 && len(errs) == n {
 		return nil, AllFail,
 			errors.Errorf(sendRequestAllErr, len(errs), strings.Join(errs, "\n"))
 	}
@@ -108,7 +163,7 @@ func (m *manager) sendRequests(g gs.Group) ([]id.Round, RequestStatus, error) {
 
 	jww.DEBUG.Printf(
 		"[GC] Sent group request to %d members in group %q with ID %s.",
-		len(g.Members), g.Name, g.ID)
+		n, g.Name, g.ID)
 
 	// If all sends succeeded, return a list of roundIDs
 	return roundList, AllSent, nil
@@ -116,12 +171,24 @@ func (m *manager) sendRequests(g gs.Group) ([]id.Round, RequestStatus, error) {
 
 // sendRequest sends the group request to the user via E2E.
 func (m *manager) sendRequest(memberID *id.ID, request []byte) ([]id.Round, error) {
+	return m.sendE2eRequest(catalog.GroupCreationRequest, memberID, request)
+}
+
+// sendE2eRequest sends the group request of the given message type to the user
+// via E2E.
+func (m *manager) sendE2eRequest(mt catalog.MessageType, memberID *id.ID,
+	request []byte) ([]id.Round, error) {
 	p := e2e.GetDefaultParams()
 	p.LastServiceTag = catalog.GroupRq
 	p.DebugTag = "group.Request"
+	switch mt {
+	case catalog.GroupMembershipUpdate:
+		p.DebugTag = "group.MembershipUpdate"
+	case catalog.GroupInviteResponse:
+		p.DebugTag = "group.InviteResponse"
+	}
 
-	sendReport, err := m.getE2eHandler().SendE2E(
-		catalog.GroupCreationRequest, memberID, request, p)
+	sendReport, err := m.getE2eHandler().SendE2E(mt, memberID, request, p)
 	if err != nil {
 		return nil, errors.Errorf(sendE2eErr, memberID, err)
 	}
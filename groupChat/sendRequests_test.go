@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"github.com/cloudflare/circl/dh/sidh"
 	"github.com/golang/protobuf/proto"
+	"gitlab.com/elixxir/client/v4/catalog"
 	sessionImport "gitlab.com/elixxir/client/v4/e2e/ratchet/partner/session"
 	util "gitlab.com/elixxir/client/v4/storage/utility"
 	"gitlab.com/elixxir/crypto/diffieHellman"
@@ -93,6 +94,15 @@ func Test_manager_ResendRequest(t *testing.T) {
 			t.Errorf("Failed to unmarshal proto message (%d): %+v", i, err)
 		}
 
+		// The signature is randomized so it is verified and then removed
+		err = verifyRequest(testRequest, m.getReceptionIdentity().ID, false)
+		if err != nil {
+			t.Errorf("Message %d has an invalid signature: %+v", i, err)
+		}
+		testRequest.Signature = nil
+		testRequest.SignerPubKey = nil
+		testRequest.SignerSalt = nil
+
 		if expected.String() != testRequest.String() {
 			t.Errorf("Message %d has unexpected payload."+
 				"\nexpected: %s\nreceived: %s", i, expected, testRequest)
@@ -189,6 +199,15 @@ func Test_manager_sendRequests(t *testing.T) {
 			t.Errorf("Failed to unmarshal proto message (%d): %+v", i, err)
 		}
 
+		// The signature is randomized so it is verified and then removed
+		err = verifyRequest(testRequest, m.getReceptionIdentity().ID, false)
+		if err != nil {
+			t.Errorf("Message %d has an invalid signature: %+v", i, err)
+		}
+		testRequest.Signature = nil
+		testRequest.SignerPubKey = nil
+		testRequest.SignerSalt = nil
+
 		if expected.String() != testRequest.String() {
 			t.Errorf("Message %d has unexpected payload."+
 				"\nexpected: %s\nreceived: %s", i, expected, testRequest)
@@ -294,8 +313,9 @@ func Test_manager_sendRequest(t *testing.T) {
 		t.Errorf("sendRequest() returned an error: %+v", err)
 	}
 	expected := testE2eMessage{
-		Recipient: g.Members[0].ID,
-		Payload:   []byte("request message"),
+		Recipient:   g.Members[0].ID,
+		Payload:     []byte("request message"),
+		MessageType: catalog.GroupCreationRequest,
 	}
 
 	received := m.getE2eHandler().(*testE2eManager).GetE2eMsg(0)
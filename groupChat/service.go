@@ -40,7 +40,7 @@ func (m *manager) AddService(tag string, p Processor) error {
 	for _, g := range m.gs.Groups() {
 		newService := makeService(g.ID, tag)
 		m.getCMix().AddService(m.getReceptionIdentity().ID, newService,
-			&receptionProcessor{m, g, p})
+			&receptionProcessor{m, g, p, tag})
 	}
 
 	return nil
@@ -62,7 +62,7 @@ func (m *manager) RemoveService(tag string) error {
 	for _, g := range m.gs.Groups() {
 		toDelete := makeService(g.ID, tag)
 		m.getCMix().DeleteService(m.getReceptionIdentity().ID, toDelete,
-			&receptionProcessor{m, g, oldProcess})
+			&receptionProcessor{m, g, oldProcess, tag})
 	}
 
 	return nil
@@ -73,7 +73,7 @@ func (m *manager) addAllServices(g gs.Group) {
 	for tag, p := range m.services {
 		newService := makeService(g.ID, tag)
 		m.getCMix().AddService(m.getReceptionIdentity().ID, newService,
-			&receptionProcessor{m, g, p})
+			&receptionProcessor{m, g, p, tag})
 	}
 }
 
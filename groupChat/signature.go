@@ -0,0 +1,184 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"crypto"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"gitlab.com/elixxir/crypto/rsa"
+	"gitlab.com/xx_network/crypto/xx"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Error messages.
+const (
+	// manager.sign
+	getRsaKeyErr = "failed to get RSA private key of reception identity: %+v"
+	signErr      = "failed to sign: %+v"
+
+	// verifySignature
+	unmarshalSignerKeyErr = "failed to unmarshal public key of %s: %+v"
+	signerIdErr           = "public key and salt do not match ID of %s"
+	verifySignatureErr    = "invalid signature from %s: %+v"
+
+	// verifyRequest
+	unsignedRequestErr = "unsigned request from %s who is not the group creator"
+
+	// unmarshalIDs
+	unmarshalIDsLenErr = "length of ID list %d is not a multiple of %d"
+)
+
+// getSignatureOpts returns the options used to sign and verify group changes.
+// The hash is set explicitly so that signatures are compatible across
+// platforms.
+func getSignatureOpts() *rsa.PSSOptions {
+	opts := rsa.NewDefaultPSSOptions()
+	opts.Hash = crypto.SHA256
+	return opts
+}
+
+// sign signs the digest with the RSA private key of the user's reception
+// identity. Returns the signature and the public key and salt needed to verify
+// that the key belongs to the user's ID.
+func (m *manager) sign(digest []byte) (signature, pubKey, salt []byte, err error) {
+	identity := m.getReceptionIdentity()
+	privKey, err := identity.GetRSAPrivateKey()
+	if err != nil {
+		return nil, nil, nil, errors.Errorf(getRsaKeyErr, err)
+	}
+
+	opts := getSignatureOpts()
+	rng := m.getRng().GetStream()
+	signature, err = privKey.SignPSS(rng, opts.Hash, digest, opts)
+	rng.Close()
+	if err != nil {
+		return nil, nil, nil, errors.Errorf(signErr, err)
+	}
+
+	return signature, privKey.Public().MarshalWire(), identity.Salt, nil
+}
+
+// verifySignature verifies that the public key and salt belong to the signer
+// and that the signature over the digest is valid.
+func verifySignature(
+	signerID *id.ID, signature, pubKeyBytes, salt, digest []byte) error {
+	pubKey, err := rsa.GetScheme().UnmarshalPublicKeyWire(pubKeyBytes)
+	if err != nil {
+		return errors.Errorf(unmarshalSignerKeyErr, signerID, err)
+	}
+
+	// Verify the signer's ID against the public key sent with the signature
+	wireID, err := xx.NewID(pubKey, salt, id.User)
+	if err != nil || !signerID.Cmp(wireID) {
+		return errors.Errorf(signerIdErr, signerID)
+	}
+
+	opts := getSignatureOpts()
+	err = pubKey.VerifyPSS(opts.Hash, digest, signature, opts)
+	if err != nil {
+		return errors.Errorf(verifySignatureErr, signerID, err)
+	}
+
+	return nil
+}
+
+// signRequest signs the request with the user's identity.
+func (m *manager) signRequest(request *Request) error {
+	signature, pubKey, salt, err := m.sign(requestDigest(request))
+	if err != nil {
+		return err
+	}
+
+	request.Signature = signature
+	request.SignerPubKey = pubKey
+	request.SignerSalt = salt
+	return nil
+}
+
+// verifyRequest verifies the signature on the request from the sender. Requests
+// from clients that do not sign them are only accepted from the group creator,
+// whose identity is already authenticated by the E2E channel.
+func verifyRequest(request *Request, senderID *id.ID, isCreator bool) error {
+	if len(request.GetSignature()) == 0 {
+		if isCreator {
+			return nil
+		}
+		return errors.Errorf(unsignedRequestErr, senderID)
+	}
+
+	return verifySignature(senderID, request.GetSignature(),
+		request.GetSignerPubKey(), request.GetSignerSalt(),
+		requestDigest(request))
+}
+
+// requestDigest returns the digest of every field of the request that is
+// signed.
+func requestDigest(request *Request) []byte {
+	created := make([]byte, 8)
+	binary.LittleEndian.PutUint64(created, uint64(request.GetCreated()))
+
+	return hashFields(request.GetName(), request.GetIdPreimage(),
+		request.GetKeyPreimage(), request.GetMembers(), request.GetMessage(),
+		created, request.GetGroupID(), request.GetAdmins(),
+		request.GetCharter())
+}
+
+// adminMessageDigest returns the digest of every field of the admin message
+// that is signed.
+func adminMessageDigest(msg *AdminMessage) []byte {
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint32(b, msg.GetVersion())
+	binary.LittleEndian.PutUint32(b[4:], msg.GetType())
+	binary.LittleEndian.PutUint64(b[8:], uint64(msg.GetTimestamp()))
+
+	return hashFields(b, msg.GetGroupID(), msg.GetName(),
+		[]byte(msg.GetNotice()), msg.GetAdmins())
+}
+
+// hashFields hashes each field prefixed with its length so that the boundaries
+// between fields cannot be moved without changing the digest.
+func hashFields(fields ...[]byte) []byte {
+	h := getSignatureOpts().Hash.New()
+	for _, field := range fields {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(len(field)))
+		h.Write(b)
+		h.Write(field)
+	}
+	return h.Sum(nil)
+}
+
+// marshalIDs concatenates the marshalled IDs.
+func marshalIDs(ids []*id.ID) []byte {
+	b := make([]byte, 0, len(ids)*id.ArrIDLen)
+	for _, uid := range ids {
+		b = append(b, uid.Marshal()...)
+	}
+	return b
+}
+
+// unmarshalIDs splits the concatenated IDs into a list. Returns nil if there
+// are no IDs.
+func unmarshalIDs(b []byte) ([]*id.ID, error) {
+	if len(b)%id.ArrIDLen != 0 {
+		return nil, errors.Errorf(unmarshalIDsLenErr, len(b), id.ArrIDLen)
+	}
+
+	var ids []*id.ID
+	for i := 0; i < len(b); i += id.ArrIDLen {
+		uid, err := id.Unmarshal(b[i : i+id.ArrIDLen])
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uid)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,161 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gorm.io/gorm"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/groupChat"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+const (
+	// Can be provided to SqlLite to create a temporary, in-memory DB.
+	temporaryDbPath = "file:%s?mode=memory&cache=shared"
+
+	// Determines maximum runtime (in seconds) of DB queries.
+	dbTimeout = 3 * time.Second
+)
+
+// newContext builds a context for database operations.
+func newContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), dbTimeout)
+}
+
+// JoinGroup is called whenever a group is joined or its membership changes.
+// Creates or updates the Group.
+func (i *impl) JoinGroup(g gs.Group) {
+	parentErr := errors.New("failed to JoinGroup")
+
+	newGroup := &Group{
+		Id:      g.ID.Marshal(),
+		Name:    g.Name,
+		Members: g.Members.Serialize(),
+	}
+
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Save(newGroup).Error
+	cancel()
+
+	if err != nil {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Unable to save Group: %+v", err))
+		return
+	}
+	jww.DEBUG.Printf("Successfully joined group: %s", g.ID)
+}
+
+// LeaveGroup is called whenever a group is left.
+// Deletes the Group and all Message associated with it.
+func (i *impl) LeaveGroup(groupID *id.ID) {
+	parentErr := errors.New("failed to LeaveGroup")
+
+	// Also deletes associated Messages due to CASCADE
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Delete(&Group{Id: groupID.Marshal()}).Error
+	cancel()
+
+	if err != nil {
+		jww.ERROR.Printf("%+v", errors.WithMessagef(parentErr,
+			"Unable to delete Group: %+v", err))
+		return
+	}
+	jww.DEBUG.Printf("Successfully deleted group: %s", groupID)
+}
+
+// ReceiveMessage is called whenever a message is sent or received in a group.
+// Creates the Message.
+func (i *impl) ReceiveMessage(groupID *id.ID, messageID group.MessageID,
+	senderID *id.ID, tag string, content []byte, timestamp time.Time,
+	round rounds.Round) uint64 {
+	msgToInsert := &Message{
+		MessageId: messageID.Bytes(),
+		GroupId:   groupID.Marshal(),
+		SenderId:  senderID.Marshal(),
+		Tag:       tag,
+		Text:      content,
+		Timestamp: timestamp,
+		Round:     int64(round.ID),
+	}
+
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Create(msgToInsert).Error
+	cancel()
+
+	if err != nil {
+		jww.ERROR.Printf("Failed to receive message: %+v", err)
+		return 0
+	}
+
+	go i.cbs.MessageReceived(uint64(msgToInsert.Id), groupID)
+	return uint64(msgToInsert.Id)
+}
+
+// GetMessage returns the [groupChat.ModelMessage] with the given
+// [group.MessageID].
+//
+// Returns an error if the message cannot be gotten. It must return
+// groupChat.NoMessageErr if the message does not exist.
+func (i *impl) GetMessage(messageID group.MessageID) (
+	groupChat.ModelMessage, error) {
+	parentErr := "failed to GetMessage"
+
+	result := &Message{}
+	ctx, cancel := newContext()
+	err := i.db.WithContext(ctx).Take(
+		result, "message_id = ?", messageID.Bytes()).Error
+	cancel()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return groupChat.ModelMessage{},
+				errors.WithMessage(groupChat.NoMessageErr, parentErr)
+		}
+		return groupChat.ModelMessage{}, errors.WithMessage(err, parentErr)
+	}
+
+	modelMsg, err := buildModelMessage(result)
+	if err != nil {
+		return groupChat.ModelMessage{}, errors.WithMessage(err, parentErr)
+	}
+	return modelMsg, nil
+}
+
+// buildModelMessage is a private helper that converts a stored Message into a
+// [groupChat.ModelMessage].
+func buildModelMessage(msg *Message) (groupChat.ModelMessage, error) {
+	groupID, err := id.Unmarshal(msg.GroupId)
+	if err != nil {
+		return groupChat.ModelMessage{}, err
+	}
+
+	senderID, err := id.Unmarshal(msg.SenderId)
+	if err != nil {
+		return groupChat.ModelMessage{}, err
+	}
+
+	var messageID group.MessageID
+	copy(messageID[:], msg.MessageId)
+
+	return groupChat.ModelMessage{
+		UUID:      uint64(msg.Id),
+		GroupID:   groupID,
+		MessageID: messageID,
+		SenderID:  senderID,
+		Tag:       msg.Tag,
+		Content:   msg.Text,
+		Timestamp: msg.Timestamp,
+		Round:     id.Round(msg.Round),
+	}, nil
+}
@@ -0,0 +1,122 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/groupChat"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+type dummyCallbacks struct{}
+
+func (d *dummyCallbacks) MessageReceived(uint64, *id.ID) {}
+
+// Series of interdependent smoke tests of the impl object and its methods.
+func TestImpl(t *testing.T) {
+	model, err := newImpl("TestImpl", &dummyCallbacks{}, true)
+	require.NoError(t, err)
+
+	groupID := id.NewIdFromString("group", id.Group, t)
+	senderID := id.NewIdFromString("sender", id.User, t)
+	model.JoinGroup(gs.Group{ID: groupID, Name: []byte("name")})
+
+	// Receive a message
+	msgID := group.NewMessageID(groupID, []byte("TestImpl"))
+	ts := time.Unix(1700000000, 0).UTC()
+	uuid := model.ReceiveMessage(groupID, msgID, senderID, "default",
+		[]byte("hello"), ts, rounds.Round{ID: 5})
+	require.NotZero(t, uuid)
+
+	msg, err := model.GetMessage(msgID)
+	require.NoError(t, err)
+	expected := groupChat.ModelMessage{
+		UUID:      uuid,
+		GroupID:   groupID,
+		MessageID: msgID,
+		SenderID:  senderID,
+		Tag:       "default",
+		Content:   []byte("hello"),
+		Timestamp: ts,
+		Round:     5,
+	}
+	require.Equal(t, expected.Timestamp.Unix(), msg.Timestamp.Unix())
+	msg.Timestamp = expected.Timestamp
+	require.Equal(t, expected, msg)
+
+	// Receiving the same message twice does not add a second message
+	require.Zero(t, model.ReceiveMessage(groupID, msgID, senderID, "default",
+		[]byte("hello"), ts, rounds.Round{ID: 5}))
+
+	// Joining again (e.g., on a membership change) keeps the messages
+	model.JoinGroup(gs.Group{ID: groupID, Name: []byte("new name")})
+	_, err = model.GetMessage(msgID)
+	require.NoError(t, err)
+
+	// Leaving deletes all the messages in the group
+	model.LeaveGroup(groupID)
+	_, err = model.GetMessage(msgID)
+	require.True(t, groupChat.CheckNoMessageErr(err), "%+v", err)
+}
+
+// Tests that messages for a group that has not been joined are not stored.
+func TestImpl_ReceiveMessage_NoGroup(t *testing.T) {
+	model, err := newImpl("TestImpl_ReceiveMessage_NoGroup",
+		&dummyCallbacks{}, true)
+	require.NoError(t, err)
+
+	groupID := id.NewIdFromString("group", id.Group, t)
+	msgID := group.NewMessageID(groupID, []byte("message"))
+	uuid := model.ReceiveMessage(groupID, msgID,
+		id.NewIdFromString("sender", id.User, t), "default", []byte("hello"),
+		time.Now(), rounds.Round{})
+	require.Zero(t, uuid)
+
+	_, err = model.GetMessage(msgID)
+	require.True(t, groupChat.CheckNoMessageErr(err), "%+v", err)
+}
+
+// Tests that stored messages are still available after the database is closed
+// and reopened.
+func TestNewEventModel_Reopen(t *testing.T) {
+	dbFilePath := filepath.Join(t.TempDir(), "groupChat.db")
+	model, err := NewEventModel(dbFilePath, &dummyCallbacks{})
+	require.NoError(t, err)
+
+	groupID := id.NewIdFromString("group", id.Group, t)
+	model.JoinGroup(gs.Group{ID: groupID})
+	msgID := group.NewMessageID(groupID, []byte("message"))
+	uuid := model.ReceiveMessage(groupID, msgID,
+		id.NewIdFromString("sender", id.User, t), "default", []byte("hello"),
+		time.Now(), rounds.Round{})
+	require.NotZero(t, uuid)
+
+	sqlDb, err := model.(*impl).db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDb.Close())
+
+	model, err = NewEventModel(dbFilePath, &dummyCallbacks{})
+	require.NoError(t, err)
+	msg, err := model.GetMessage(msgID)
+	require.NoError(t, err)
+	require.Equal(t, uuid, msg.UUID)
+
+	results, err := model.SearchMessages(SearchParams{Text: "hello"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
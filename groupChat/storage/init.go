@@ -0,0 +1,122 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// Handles low-level database control and interfaces.
+
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"gitlab.com/elixxir/client/v4/groupChat"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Callbacks contains callbacks that are used by this event model implementation.
+type Callbacks interface {
+	// MessageReceived is called any time a message is sent or received.
+	MessageReceived(uuid uint64, groupID *id.ID)
+}
+
+// impl implements the groupChat.EventModel interface with an underlying DB.
+type impl struct {
+	db  *gorm.DB // Stored database connection
+	cbs Callbacks
+}
+
+// EventModel is the [groupChat.EventModel] implemented by this package, with
+// the ability to page through and search stored messages.
+type EventModel interface {
+	groupChat.EventModel
+
+	// SearchMessages returns the stored messages that match the given
+	// parameters, ordered from newest to oldest.
+	SearchMessages(params SearchParams) ([]groupChat.ModelMessage, error)
+}
+
+// NewEventModel initializes the [EventModel] interface with appropriate backend.
+func NewEventModel(dbFilePath string, cbs Callbacks) (EventModel, error) {
+	useTemporary := len(dbFilePath) == 0
+	model, err := newImpl(dbFilePath, cbs, useTemporary)
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// If useTemporary is set to true, this will use an in-RAM database.
+func newImpl(dbFilePath string, cbs Callbacks, useTemporary bool) (*impl, error) {
+
+	if useTemporary {
+		dbFilePath = fmt.Sprintf(temporaryDbPath, dbFilePath)
+		jww.WARN.Printf("No database file path specified! " +
+			"Using temporary in-memory database")
+	}
+
+	// Create the database connection
+	jww.INFO.Printf("Opening DB file at %s...", dbFilePath)
+	db, err := gorm.Open(sqlite.Open(dbFilePath), &gorm.Config{
+		Logger: logger.New(jww.TRACE, logger.Config{LogLevel: logger.Info}),
+	})
+	if err != nil {
+		return nil, errors.Errorf("Unable to initialize database backend: %+v", err)
+	}
+
+	// Enable foreign keys because they are disabled in SQLite by default
+	if err = db.Exec("PRAGMA foreign_keys = ON", nil).Error; err != nil {
+		return nil, err
+	}
+
+	// Enable Write Ahead Logging to enable multiple DB connections
+	if err = db.Exec("PRAGMA journal_mode = WAL;", nil).Error; err != nil {
+		return nil, err
+	}
+
+	// Get and configure the internal database ConnPool
+	sqlDb, err := db.DB()
+	if err != nil {
+		return nil, errors.Errorf(
+			"Unable to configure database connection pool: %+v", err)
+	}
+
+	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
+	sqlDb.SetMaxIdleConns(5)
+	// SetMaxOpenConns sets the maximum number of open connections to the Database.
+	sqlDb.SetMaxOpenConns(10)
+	// SetConnMaxLifetime sets the maximum amount of time a connection may be idle.
+	sqlDb.SetConnMaxIdleTime(5 * time.Minute)
+	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
+	sqlDb.SetConnMaxLifetime(10 * time.Minute)
+
+	// Initialize the database schema
+	// WARNING: Order is important. Do not change without database testing
+	err = db.AutoMigrate(&Group{}, &Message{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize the full-text search index
+	err = initSearchIndex(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the interface
+	di := &impl{
+		db:  db,
+		cbs: cbs,
+	}
+
+	jww.INFO.Println("Database backend initialized successfully!")
+	return di, nil
+}
@@ -0,0 +1,36 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+package storage
+
+import (
+	"time"
+)
+
+// Message defines the SQL representation of a single group Message.
+//
+// A Message belongs to one Group.
+type Message struct {
+	Id        int64  `gorm:"primaryKey;autoIncrement:true"`
+	MessageId []byte `gorm:"uniqueIndex;not null"`
+	GroupId   []byte `gorm:"index;not null"`
+	SenderId  []byte `gorm:"index;not null"`
+	Tag       string `gorm:"not null"`
+	Text      []byte
+	Timestamp time.Time `gorm:"index;not null"`
+	Round     int64     `gorm:"not null"`
+}
+
+// Group defines the SQL representation of a single Group.
+//
+// A Group has many Message.
+type Group struct {
+	Id      []byte `gorm:"primaryKey;not null;autoIncrement:false"`
+	Name    []byte
+	Members []byte
+
+	Messages []Message `gorm:"constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,99 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2023 Privategrity Corporation                                   /
+//                                                                             /
+// All rights reserved.                                                        /
+////////////////////////////////////////////////////////////////////////////////
+
+// sqlite requires cgo, which is not available in wasm
+//go:build !js || !wasm
+
+package storage
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	gs "gitlab.com/elixxir/client/v4/groupChat/groupStore"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// Tests that impl.SearchMessages filters messages by text, group, sender, tag,
+// and time, pages through results, and that messages of left groups are
+// removed from the index.
+func Test_impl_SearchMessages(t *testing.T) {
+	model, err := newImpl("Test_impl_SearchMessages", &dummyCallbacks{}, true)
+	require.NoError(t, err)
+
+	groupA, groupB := id.NewIdFromString("groupA", id.Group, t),
+		id.NewIdFromString("groupB", id.Group, t)
+	for _, groupID := range []*id.ID{groupA, groupB} {
+		model.JoinGroup(gs.Group{ID: groupID})
+	}
+
+	alice, bob := id.NewIdFromString("alice", id.User, t),
+		id.NewIdFromString("bob", id.User, t)
+	start := time.Unix(1700000000, 0)
+	msgs := []struct {
+		groupID  *id.ID
+		text     string
+		senderID *id.ID
+		tag      string
+	}{
+		{groupA, "hello world", alice, "default"},
+		{groupA, "Hello there", bob, "default"},
+		{groupB, "goodbye world", alice, "default"},
+		{groupB, "world", bob, "file"},
+		{groupA, `"quoted" world`, bob, "default"},
+	}
+	msgIDs := make([]group.MessageID, len(msgs))
+	for j, m := range msgs {
+		msgIDs[j] = group.NewMessageID(m.groupID, []byte(strconv.Itoa(j)))
+		model.ReceiveMessage(m.groupID, msgIDs[j], m.senderID, m.tag,
+			[]byte(m.text), start.Add(time.Duration(j)*time.Minute),
+			rounds.Round{})
+	}
+
+	tests := []struct {
+		params   SearchParams
+		expected []int
+	}{
+		{SearchParams{Text: "world"}, []int{4, 3, 2, 0}},
+		{SearchParams{Text: "HELLO"}, []int{1, 0}},
+		{SearchParams{Text: "hello world"}, []int{0}},
+		{SearchParams{Text: `"quoted"`}, []int{4}},
+		{SearchParams{Text: "world", GroupID: groupA}, []int{4, 0}},
+		{SearchParams{Text: "world", SenderID: bob}, []int{4, 3}},
+		{SearchParams{Tag: "file"}, []int{3}},
+		{SearchParams{Start: start.Add(time.Minute),
+			End: start.Add(3 * time.Minute)}, []int{2, 1}},
+		{SearchParams{GroupID: groupA, Limit: 2}, []int{4, 1}},
+		{SearchParams{GroupID: groupA, Limit: 2, Offset: 2}, []int{0}},
+		{SearchParams{Text: "missing"}, nil},
+	}
+
+	for j, tt := range tests {
+		results, err := model.SearchMessages(tt.params)
+		require.NoError(t, err, "%d", j)
+
+		var received []int
+		for _, r := range results {
+			for k, msgID := range msgIDs {
+				if r.MessageID == msgID {
+					received = append(received, k)
+				}
+			}
+		}
+		require.Equal(t, tt.expected, received, "%d: %+v", j, tt.params)
+	}
+
+	// Leaving a group removes its messages from the index
+	model.LeaveGroup(groupB)
+	results, err := model.SearchMessages(SearchParams{Text: "world"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+}
@@ -0,0 +1,257 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/client/v4/emoji"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/xx_network/primitives/id"
+)
+
+// TypedServiceTag is the service tag that all typed group messages are sent
+// on. Register NewTypedProcessor on this tag with GroupChat.AddService to
+// receive them.
+const TypedServiceTag = "typed"
+
+// The versions of the typed message payloads.
+const (
+	typedMessageVersion = 0
+	textVersion         = 0
+	reactionVersion     = 0
+	deleteVersion       = 0
+)
+
+// Error messages.
+const (
+	// newTypedMessage
+	marshalTypedPayloadErr = "failed to marshal %s payload: %+v"
+
+	// typedProcessor.Process
+	unmarshalTypedMessageErr = "failed to unmarshal typed group message: %+v"
+	unmarshalTypedPayloadErr = "failed to unmarshal %s payload: %+v"
+	unknownTypedMessageErr   = "unknown typed group message type %s"
+
+	// unmarshalMessageID
+	messageIdLenErr = "message ID length %d != %d required"
+)
+
+// SendText sends a text message to all group members on the TypedServiceTag.
+// Returns the round sent on, the timestamp, and the ID of the message.
+func (m *manager) SendText(groupID *id.ID, text string) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	return m.sendTyped(groupID, TextType, &Text{
+		Version: textVersion,
+		Text:    text,
+	})
+}
+
+// SendReply sends a text message to all group members as a reply to the
+// message with the given ID. If the replied to message does not exist, then
+// other members will treat the reply as a normal text message.
+func (m *manager) SendReply(groupID *id.ID, text string,
+	replyTo group.MessageID) (rounds.Round, time.Time, group.MessageID, error) {
+	return m.sendTyped(groupID, ReplyType, &Text{
+		Version:        textVersion,
+		Text:           text,
+		ReplyMessageID: replyTo.Bytes(),
+	})
+}
+
+// SendReaction sends a reaction to the message with the given ID to all group
+// members. The reaction must be a single emoji with no other characters and
+// will be rejected otherwise.
+func (m *manager) SendReaction(groupID *id.ID, reaction string,
+	reactTo group.MessageID) (rounds.Round, time.Time, group.MessageID, error) {
+	if err := emoji.ValidateReaction(reaction); err != nil {
+		return rounds.Round{}, time.Time{}, group.MessageID{}, err
+	}
+
+	return m.sendTyped(groupID, ReactionType, &Reaction{
+		Version:           reactionVersion,
+		Reaction:          reaction,
+		ReactionMessageID: reactTo.Bytes(),
+	})
+}
+
+// SendDelete asks all group members to delete the message with the given ID.
+// Members should only honour the request if the message was sent by the user.
+func (m *manager) SendDelete(groupID *id.ID, messageID group.MessageID) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	return m.sendTyped(groupID, DeleteType, &Delete{
+		Version:   deleteVersion,
+		MessageID: messageID.Bytes(),
+	})
+}
+
+// sendTyped wraps the payload in a TypedMessage of the given type and sends it
+// to the group on the TypedServiceTag.
+func (m *manager) sendTyped(groupID *id.ID, mt MessageType,
+	payload proto.Message) (rounds.Round, time.Time, group.MessageID, error) {
+	jww.INFO.Printf("[GC] Sending %s message to group %s.", mt, groupID)
+
+	message, err := newTypedMessage(mt, payload)
+	if err != nil {
+		return rounds.Round{}, time.Time{}, group.MessageID{}, err
+	}
+
+	return m.Send(groupID, TypedServiceTag, message)
+}
+
+// newTypedMessage marshals the payload and wraps it in a marshalled
+// TypedMessage of the given type.
+func newTypedMessage(mt MessageType, payload proto.Message) ([]byte, error) {
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, errors.Errorf(marshalTypedPayloadErr, mt, err)
+	}
+
+	message, err := proto.Marshal(&TypedMessage{
+		Version:     typedMessageVersion,
+		PayloadType: uint32(mt),
+		Payload:     payloadBytes,
+	})
+	if err != nil {
+		return nil, errors.Errorf(protoMarshalErr, err)
+	}
+
+	return message, nil
+}
+
+// TypedCallbacks is called for each typed group message received on the
+// TypedServiceTag. Each callback receives the decrypted group message, whose
+// payload is the marshalled TypedMessage, and its decoded contents.
+type TypedCallbacks interface {
+	// ReceiveText is called when a text message is received.
+	ReceiveText(msg MessageReceive, text string, round rounds.Round)
+
+	// ReceiveReply is called when a reply to another message is received. The
+	// replied to message may not exist, in which case the reply should be
+	// treated as a normal text message.
+	ReceiveReply(msg MessageReceive, text string, replyTo group.MessageID,
+		round rounds.Round)
+
+	// ReceiveReaction is called when a reaction to another message is
+	// received. Reactions that are not a single emoji are dropped before
+	// reaching this callback.
+	ReceiveReaction(msg MessageReceive, reaction string,
+		reactTo group.MessageID, round rounds.Round)
+
+	// ReceiveDelete is called when a request to delete a message is received.
+	// The message should only be deleted if it was sent by the same member
+	// that sent the request.
+	ReceiveDelete(msg MessageReceive, messageID group.MessageID,
+		round rounds.Round)
+}
+
+// NewTypedProcessor returns a Processor that decodes typed group messages and
+// passes them to the matching callback. It should be registered on the
+// TypedServiceTag.
+func NewTypedProcessor(cbs TypedCallbacks) Processor {
+	return &typedProcessor{cbs}
+}
+
+// typedProcessor adheres to the Processor interface.
+type typedProcessor struct {
+	cbs TypedCallbacks
+}
+
+// Process decodes the typed message and passes it to the callbacks. Invalid
+// messages are logged and dropped.
+func (tp *typedProcessor) Process(decryptedMsg MessageReceive, _ format.Message,
+	_ []string, _ []byte, _ receptionID.EphemeralIdentity, round rounds.Round) {
+	if err := tp.process(decryptedMsg, round); err != nil {
+		jww.ERROR.Printf("[GC] Failed to process typed message %s from %s in "+
+			"group %s: %+v", decryptedMsg.ID, decryptedMsg.SenderID,
+			decryptedMsg.GroupID, err)
+	}
+}
+
+// process decodes the typed message and calls the callback for its type.
+func (tp *typedProcessor) process(
+	decryptedMsg MessageReceive, round rounds.Round) error {
+	typed := &TypedMessage{}
+	if err := proto.Unmarshal(decryptedMsg.Payload, typed); err != nil {
+		return errors.Errorf(unmarshalTypedMessageErr, err)
+	}
+
+	mt := MessageType(typed.GetPayloadType())
+	switch mt {
+	case TextType, ReplyType:
+		txt := &Text{}
+		if err := proto.Unmarshal(typed.GetPayload(), txt); err != nil {
+			return errors.Errorf(unmarshalTypedPayloadErr, mt, err)
+		}
+
+		// Replies without a valid message ID are treated as text
+		if mt == ReplyType {
+			replyTo, err := unmarshalMessageID(txt.GetReplyMessageID())
+			if err == nil {
+				tp.cbs.ReceiveReply(decryptedMsg, txt.GetText(), replyTo, round)
+				return nil
+			}
+			jww.WARN.Printf("[GC] Treating reply %s as text: %+v",
+				decryptedMsg.ID, err)
+		}
+		tp.cbs.ReceiveText(decryptedMsg, txt.GetText(), round)
+
+	case ReactionType:
+		react := &Reaction{}
+		if err := proto.Unmarshal(typed.GetPayload(), react); err != nil {
+			return errors.Errorf(unmarshalTypedPayloadErr, mt, err)
+		}
+		if err := emoji.ValidateReaction(react.GetReaction()); err != nil {
+			return err
+		}
+		reactTo, err := unmarshalMessageID(react.GetReactionMessageID())
+		if err != nil {
+			return err
+		}
+		tp.cbs.ReceiveReaction(decryptedMsg, react.GetReaction(), reactTo, round)
+
+	case DeleteType:
+		del := &Delete{}
+		if err := proto.Unmarshal(typed.GetPayload(), del); err != nil {
+			return errors.Errorf(unmarshalTypedPayloadErr, mt, err)
+		}
+		messageID, err := unmarshalMessageID(del.GetMessageID())
+		if err != nil {
+			return err
+		}
+		tp.cbs.ReceiveDelete(decryptedMsg, messageID, round)
+
+	default:
+		return errors.Errorf(unknownTypedMessageErr, mt)
+	}
+
+	return nil
+}
+
+func (tp *typedProcessor) String() string {
+	return fmt.Sprintf("GroupChatTypedProcessor(%s)", TypedServiceTag)
+}
+
+// unmarshalMessageID unmarshalls the bytes into a group.MessageID. Returns an
+// error if the bytes are not the correct length.
+func unmarshalMessageID(b []byte) (group.MessageID, error) {
+	var messageID group.MessageID
+	if len(b) != group.MessageIdLen {
+		return messageID, errors.Errorf(
+			messageIdLenErr, len(b), group.MessageIdLen)
+	}
+	copy(messageID[:], b)
+	return messageID, nil
+}
@@ -0,0 +1,193 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+package groupChat
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"gitlab.com/elixxir/client/v4/cmix/identity/receptionID"
+	"gitlab.com/elixxir/client/v4/cmix/rounds"
+	"gitlab.com/elixxir/crypto/group"
+	"gitlab.com/elixxir/primitives/format"
+	"gitlab.com/elixxir/primitives/states"
+	"gitlab.com/xx_network/primitives/netTime"
+)
+
+// Tests that each typed message sent with the typed send functions is received
+// by the typed processor and passed to the correct callback.
+func Test_manager_SendTyped_Receive(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestManagerWithStore(prng, 1, 0, nil, t)
+	cbs := &mockTypedCallbacks{}
+	reception := &receptionProcessor{
+		m:   m,
+		g:   g,
+		p:   NewTypedProcessor(cbs),
+		tag: TypedServiceTag,
+	}
+	timestamps := map[states.Round]time.Time{
+		states.PRECOMPUTING: netTime.Now().Round(0)}
+
+	// receive passes the last sent message to the reception processor
+	receive := func() {
+		messages := m.getCMix().(*testNetworkManager).receptionMessages
+		reception.Process(messages[len(messages)-1][0], nil, nil,
+			receptionID.EphemeralIdentity{},
+			rounds.Round{ID: 5, Timestamps: timestamps})
+	}
+
+	_, _, textID, err := m.SendText(g.ID, "Hello, group.")
+	if err != nil {
+		t.Fatalf("SendText returned an error: %+v", err)
+	}
+	receive()
+	if cbs.text != "Hello, group." || cbs.msg.ID != textID || cbs.round.ID != 5 {
+		t.Errorf("Unexpected text received: %q %s (expected %s)",
+			cbs.text, cbs.msg.ID, textID)
+	}
+
+	_, _, replyID, err := m.SendReply(g.ID, "Reply.", textID)
+	if err != nil {
+		t.Fatalf("SendReply returned an error: %+v", err)
+	}
+	receive()
+	if cbs.text != "Reply." || cbs.msg.ID != replyID || cbs.replyTo != textID {
+		t.Errorf("Unexpected reply received: %q %s to %s",
+			cbs.text, cbs.msg.ID, cbs.replyTo)
+	}
+
+	_, _, _, err = m.SendReaction(g.ID, "👍", textID)
+	if err != nil {
+		t.Fatalf("SendReaction returned an error: %+v", err)
+	}
+	receive()
+	if cbs.reaction != "👍" || cbs.reactTo != textID {
+		t.Errorf("Unexpected reaction received: %q to %s",
+			cbs.reaction, cbs.reactTo)
+	}
+
+	_, _, _, err = m.SendDelete(g.ID, replyID)
+	if err != nil {
+		t.Fatalf("SendDelete returned an error: %+v", err)
+	}
+	receive()
+	if cbs.deleted != replyID ||
+		!cbs.msg.SenderID.Cmp(m.getReceptionIdentity().ID) {
+		t.Errorf("Unexpected delete received: %s from %s",
+			cbs.deleted, cbs.msg.SenderID)
+	}
+}
+
+// Error path: Tests that manager.SendReaction returns an error for a reaction
+// that is not a single emoji and does not send anything.
+func Test_manager_SendReaction_InvalidReactionError(t *testing.T) {
+	prng := rand.New(rand.NewSource(42))
+	m, g := newTestManagerWithStore(prng, 1, 0, nil, t)
+
+	_, _, _, err := m.SendReaction(g.ID, "not an emoji", group.MessageID{})
+	if err == nil {
+		t.Error("SendReaction did not return an error for an invalid reaction.")
+	}
+
+	if n := len(m.getCMix().(*testNetworkManager).sendMessages); n != 0 {
+		t.Errorf("%d messages sent for an invalid reaction.", n)
+	}
+}
+
+// Tests that a reply with an invalid message ID is passed to
+// TypedCallbacks.ReceiveText.
+func Test_typedProcessor_Process_InvalidReplyAsText(t *testing.T) {
+	cbs := &mockTypedCallbacks{}
+	tp := NewTypedProcessor(cbs)
+
+	payload, err := newTypedMessage(
+		ReplyType, &Text{Text: "Reply.", ReplyMessageID: []byte{1, 2, 3}})
+	if err != nil {
+		t.Fatalf("Failed to create typed message: %+v", err)
+	}
+
+	tp.Process(MessageReceive{Payload: payload}, format.Message{}, nil, nil,
+		receptionID.EphemeralIdentity{}, rounds.Round{})
+
+	if cbs.text != "Reply." || cbs.replyTo != (group.MessageID{}) {
+		t.Errorf("Reply with invalid message ID not received as text: %q %s",
+			cbs.text, cbs.replyTo)
+	}
+}
+
+// Error path: Tests that typedProcessor.process returns an error for an
+// unknown message type.
+func Test_typedProcessor_process_UnknownTypeError(t *testing.T) {
+	tp := &typedProcessor{&mockTypedCallbacks{}}
+	payload, err := proto.Marshal(&TypedMessage{PayloadType: 99})
+	if err != nil {
+		t.Fatalf("Failed to marshal typed message: %+v", err)
+	}
+
+	expectedErr := strings.SplitN(unknownTypedMessageErr, "%", 2)[0]
+	err = tp.process(MessageReceive{Payload: payload}, rounds.Round{})
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Errorf("Unexpected error for unknown type.\nexpected: %s\nreceived: %+v",
+			expectedErr, err)
+	}
+}
+
+// Error path: Tests that typedProcessor.process returns an error for a
+// reaction that is not a single emoji.
+func Test_typedProcessor_process_InvalidReactionError(t *testing.T) {
+	cbs := &mockTypedCallbacks{}
+	tp := &typedProcessor{cbs}
+	payload, err := newTypedMessage(ReactionType, &Reaction{
+		Reaction: "ab", ReactionMessageID: make([]byte, group.MessageIdLen)})
+	if err != nil {
+		t.Fatalf("Failed to create typed message: %+v", err)
+	}
+
+	if err = tp.process(MessageReceive{Payload: payload}, rounds.Round{}); err == nil {
+		t.Error("process did not return an error for an invalid reaction.")
+	}
+	if cbs.reaction != "" {
+		t.Errorf("Invalid reaction passed to callback: %q", cbs.reaction)
+	}
+}
+
+// mockTypedCallbacks adheres to the TypedCallbacks interface and stores the
+// values of the last call to each callback.
+type mockTypedCallbacks struct {
+	msg      MessageReceive
+	round    rounds.Round
+	text     string
+	replyTo  group.MessageID
+	reaction string
+	reactTo  group.MessageID
+	deleted  group.MessageID
+}
+
+func (m *mockTypedCallbacks) ReceiveText(
+	msg MessageReceive, text string, round rounds.Round) {
+	m.msg, m.text, m.round = msg, text, round
+}
+
+func (m *mockTypedCallbacks) ReceiveReply(msg MessageReceive, text string,
+	replyTo group.MessageID, round rounds.Round) {
+	m.msg, m.text, m.replyTo, m.round = msg, text, replyTo, round
+}
+
+func (m *mockTypedCallbacks) ReceiveReaction(msg MessageReceive,
+	reaction string, reactTo group.MessageID, round rounds.Round) {
+	m.msg, m.reaction, m.reactTo, m.round = msg, reaction, reactTo, round
+}
+
+func (m *mockTypedCallbacks) ReceiveDelete(msg MessageReceive,
+	messageID group.MessageID, round rounds.Round) {
+	m.msg, m.deleted, m.round = msg, messageID, round
+}
@@ -52,12 +52,97 @@ func (w *Wrapper) LeaveGroup(groupID *id.ID) error {
 	return w.gc.LeaveGroup(groupID)
 }
 
+// AddMember calls GroupChat.AddMember.
+func (w *Wrapper) AddMember(groupID, memberID *id.ID) (
+	[]id.Round, RequestStatus, error) {
+	return w.gc.AddMember(groupID, memberID)
+}
+
+// RemoveMember calls GroupChat.RemoveMember.
+func (w *Wrapper) RemoveMember(groupID, memberID *id.ID) (
+	[]id.Round, RequestStatus, error) {
+	return w.gc.RemoveMember(groupID, memberID)
+}
+
+// SetAdmin calls GroupChat.SetAdmin.
+func (w *Wrapper) SetAdmin(groupID, memberID *id.ID, admin bool) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	return w.gc.SetAdmin(groupID, memberID, admin)
+}
+
+// RenameGroup calls GroupChat.RenameGroup.
+func (w *Wrapper) RenameGroup(groupID *id.ID, name []byte) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	return w.gc.RenameGroup(groupID, name)
+}
+
+// SendAdminNotice calls GroupChat.SendAdminNotice.
+func (w *Wrapper) SendAdminNotice(groupID *id.ID, notice string) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	return w.gc.SendAdminNotice(groupID, notice)
+}
+
+// RegisterAdminCallbacks calls GroupChat.RegisterAdminCallbacks.
+func (w *Wrapper) RegisterAdminCallbacks(cbs AdminCallbacks) {
+	w.gc.RegisterAdminCallbacks(cbs)
+}
+
 // Send calls GroupChat.Send.
 func (w *Wrapper) Send(groupID *id.ID, message []byte, tag string) (
 	rounds.Round, time.Time, group.MessageID, error) {
 	return w.gc.Send(groupID, tag, message)
 }
 
+// DeclineInvite calls GroupChat.DeclineInvite.
+func (w *Wrapper) DeclineInvite(g gs.Group) error {
+	return w.gc.DeclineInvite(g)
+}
+
+// RevokeInvite calls GroupChat.RevokeInvite.
+func (w *Wrapper) RevokeInvite(groupID, memberID *id.ID) (
+	[]id.Round, RequestStatus, error) {
+	return w.gc.RevokeInvite(groupID, memberID)
+}
+
+// GetInvites calls GroupChat.GetInvites.
+func (w *Wrapper) GetInvites(groupID *id.ID) []gs.Invite {
+	return w.gc.GetInvites(groupID)
+}
+
+// PendingInvites calls GroupChat.PendingInvites.
+func (w *Wrapper) PendingInvites() []gs.Invite {
+	return w.gc.PendingInvites()
+}
+
+// SendText calls GroupChat.SendText.
+func (w *Wrapper) SendText(groupID *id.ID, text string) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	return w.gc.SendText(groupID, text)
+}
+
+// SendReply calls GroupChat.SendReply.
+func (w *Wrapper) SendReply(groupID *id.ID, text string,
+	replyTo group.MessageID) (rounds.Round, time.Time, group.MessageID, error) {
+	return w.gc.SendReply(groupID, text, replyTo)
+}
+
+// SendReaction calls GroupChat.SendReaction.
+func (w *Wrapper) SendReaction(groupID *id.ID, reaction string,
+	reactTo group.MessageID) (rounds.Round, time.Time, group.MessageID, error) {
+	return w.gc.SendReaction(groupID, reaction, reactTo)
+}
+
+// SendDelete calls GroupChat.SendDelete.
+func (w *Wrapper) SendDelete(groupID *id.ID, messageID group.MessageID) (
+	rounds.Round, time.Time, group.MessageID, error) {
+	return w.gc.SendDelete(groupID, messageID)
+}
+
+// AddService calls GroupChat.AddService.
+func (w *Wrapper) AddService(tag string, p Processor) error {
+	return w.gc.AddService(tag, p)
+}
+
 // GetGroups calls GroupChat.GetGroups.
 func (w *Wrapper) GetGroups() []*id.ID {
 	return w.gc.GetGroups()
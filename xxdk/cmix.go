@@ -149,6 +149,17 @@ const baseNewSynchronizedCmixErr = "failed to create cmix from remote"
 //     synchronization.
 func NewSynchronizedCmix(ndfJSON, storageDir, remoteStoragePathPrefix string,
 	password []byte, remote collective.RemoteStore) error {
+	return NewSynchronizedCmixWithPolicy(ndfJSON, storageDir,
+		remoteStoragePathPrefix, password, remote, nil)
+}
+
+// NewSynchronizedCmixWithPolicy clones a Cmix from remote storage, only
+// synchronizing the keys selected by the [collective.SyncPolicy]. A nil policy
+// synchronizes every key. The same policy should be passed to
+// [OpenSynchronizedCmixWithPolicy] when the Cmix is opened.
+func NewSynchronizedCmixWithPolicy(ndfJSON, storageDir,
+	remoteStoragePathPrefix string, password []byte,
+	remote collective.RemoteStore, policy *collective.SyncPolicy) error {
 	jww.INFO.Printf("NewSynchronizedCmix(dir: %s)", storageDir)
 	rngStreamGen := fastRNG.NewStreamGenerator(12, 1024,
 		csprng.NewSystemRNG)
@@ -164,8 +175,8 @@ func NewSynchronizedCmix(ndfJSON, storageDir, remoteStoragePathPrefix string,
 			baseNewSynchronizedCmixErr)
 	}
 
-	rkv, err := collective.CloneFromRemoteStorage(remoteStoragePathPrefix,
-		password, remote, kv, rngStreamGen)
+	rkv, err := collective.CloneFromRemoteStorageWithPolicy(
+		remoteStoragePathPrefix, password, remote, kv, policy, rngStreamGen)
 	if err != nil {
 		return errors.Wrapf(err, "%s: CloneFromRemoteStorage",
 			baseNewSynchronizedCmixErr)
@@ -206,12 +217,24 @@ func OpenCmix(storageDir string, password []byte) (*Cmix, error) {
 func OpenSynchronizedCmix(storageDir, remoteStoragePathPrefix string,
 	password []byte, remote collective.RemoteStore,
 	synchedPrefixes []string) (*Cmix, error) {
+	return OpenSynchronizedCmixWithPolicy(storageDir, remoteStoragePathPrefix,
+		password, remote, synchedPrefixes, nil)
+}
+
+// OpenSynchronizedCmixWithPolicy creates client storage that synchronizes the
+// keys selected by the [collective.SyncPolicy] but does not connect to the
+// network or login. A nil policy synchronizes every key under the
+// synchronized prefixes.
+func OpenSynchronizedCmixWithPolicy(storageDir, remoteStoragePathPrefix string,
+	password []byte, remote collective.RemoteStore, synchedPrefixes []string,
+	policy *collective.SyncPolicy) (*Cmix, error) {
 
 	jww.INFO.Printf("OpenSynchronizedCmix(%s)", storageDir)
 	rngStreamGen := fastRNG.NewStreamGenerator(12, 1024,
 		csprng.NewSystemRNG)
-	storageKV, err := SynchronizedKV(storageDir, remoteStoragePathPrefix, password,
-		remote, synchedPrefixes, rngStreamGen)
+	storageKV, err := SynchronizedKVWithPolicy(storageDir,
+		remoteStoragePathPrefix, password, remote, synchedPrefixes, policy,
+		rngStreamGen)
 	if err != nil {
 		return nil, err
 	}
@@ -315,10 +338,21 @@ func LoadCmix(storageDir string, password []byte, parameters CMIXParams) (
 // a remote synchronization storage object and starts the network.
 func LoadSynchronizedCmix(storageDir, remoteStoragePathPrefix string, password []byte, remote collective.RemoteStore,
 	synchedPrefixes []string, parameters CMIXParams) (*Cmix, error) {
+	return LoadSynchronizedCmixWithPolicy(storageDir, remoteStoragePathPrefix,
+		password, remote, synchedPrefixes, nil, parameters)
+}
+
+// LoadSynchronizedCmixWithPolicy initializes a Cmix object from existing
+// storage using a remote synchronization storage object, which only
+// synchronizes the keys selected by the [collective.SyncPolicy], and starts the
+// network.
+func LoadSynchronizedCmixWithPolicy(storageDir, remoteStoragePathPrefix string,
+	password []byte, remote collective.RemoteStore, synchedPrefixes []string,
+	policy *collective.SyncPolicy, parameters CMIXParams) (*Cmix, error) {
 	jww.INFO.Printf("LoadSynchronizedCmix()")
 
-	c, err := OpenSynchronizedCmix(storageDir, remoteStoragePathPrefix,
-		password, remote, synchedPrefixes)
+	c, err := OpenSynchronizedCmixWithPolicy(storageDir,
+		remoteStoragePathPrefix, password, remote, synchedPrefixes, policy)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +34,18 @@ func SynchronizedKV(storageDir, remoteStoragePathPrefix string, password []byte,
 	remote collective.RemoteStore,
 	synchedPrefixes []string,
 	rng *fastRNG.StreamGenerator) (versioned.KV, error) {
+	return SynchronizedKVWithPolicy(storageDir, remoteStoragePathPrefix,
+		password, remote, synchedPrefixes, nil, rng)
+}
+
+// SynchronizedKVWithPolicy creates a filesystem based KV that synchronizes
+// with a remote storage system the keys selected by the
+// [collective.SyncPolicy]. A nil policy synchronizes every key under the
+// synchronized prefixes.
+func SynchronizedKVWithPolicy(storageDir, remoteStoragePathPrefix string,
+	password []byte, remote collective.RemoteStore, synchedPrefixes []string,
+	policy *collective.SyncPolicy,
+	rng *fastRNG.StreamGenerator) (versioned.KV, error) {
 	passwordStr := string(password)
 	localKV, err := ekv.NewFilestore(storageDir, passwordStr)
 	if err != nil {
@@ -41,6 +53,6 @@ func SynchronizedKV(storageDir, remoteStoragePathPrefix string, password []byte,
 			"failed to create storage session")
 	}
 
-	return collective.SynchronizedKV(remoteStoragePathPrefix, password,
-		remote, localKV, synchedPrefixes, rng)
+	return collective.SynchronizedKVWithPolicy(remoteStoragePathPrefix,
+		password, remote, localKV, synchedPrefixes, policy, rng)
 }